@@ -0,0 +1,325 @@
+// Command dbase-gen generates Scan and ToRow methods for structs, which convert rows without reflection.
+// The structs are marked with a dbase:gen comment and their fields are mapped by the dbase tag, like Row.ToStruct:
+//
+//	//go:generate go run github.com/Valentin-Kaiser/go-dbase/cmd/dbase-gen
+//
+//	//dbase:gen
+//	type Employee struct {
+//		ID    int32     `dbase:"EMPLOYEEID"`
+//		Name  string    `dbase:"LASTNAME"`
+//		Hired time.Time `dbase:"HIREDATE"`
+//		Notes *string   `dbase:"NOTES"`
+//	}
+//
+// The generated methods are:
+//
+//	func (v *Employee) Scan(row *dbase.Row) error
+//	func (v *Employee) ToRow(file *dbase.File) (*dbase.Row, error)
+//
+// Fields without tag use the field name as column name and fields tagged with "-" are skipped.
+// Supported field types are string, []byte, bool, the integer and float types, time.Time and pointers to them,
+// nil pointers are NULL. Columns the table does not have are skipped.
+// Integers that do not fit into the field or column type are returned as error instead of wrapping.
+//
+// Usage:
+//
+//	dbase-gen [-dir <dir>] [-output <file>]
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// marker is the comment line that marks a struct for generation
+const marker = "dbase:gen"
+
+// scanned is a struct field mapped to a column
+type scanned struct {
+	Name    string // Name of the struct field
+	Column  string // Name of the column
+	Kind    string // Accessor suffix, e.g. "Int" for IntAt and SetIntAt
+	Type    string // Go type of the field without pointer
+	Pointer bool   // The field is a pointer, nil is NULL
+}
+
+// accessors maps the supported Go types to the accessors of the row
+var accessors = map[string]string{
+	"string":    "String",
+	"[]byte":    "Bytes",
+	"bool":      "Bool",
+	"int":       "Int",
+	"int8":      "Int",
+	"int16":     "Int",
+	"int32":     "Int",
+	"int64":     "Int",
+	"uint":      "Int",
+	"uint8":     "Int",
+	"uint16":    "Int",
+	"uint32":    "Int",
+	"uint64":    "Int",
+	"float32":   "Float",
+	"float64":   "Float",
+	"time.Time": "Time",
+}
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the package")
+	output := flag.String("output", "dbase_gen.go", "name of the generated file in the directory")
+	flag.Parse()
+	err := run(*dir, *output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dbase-gen:", err)
+		os.Exit(1)
+	}
+}
+
+// run generates the methods for the marked structs of the package in dir
+func run(dir string, output string) error {
+	fset := token.NewFileSet()
+	packages, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go") && info.Name() != output
+	}, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+	if len(packages) != 1 {
+		return fmt.Errorf("expected one package in %s, found %d", dir, len(packages))
+	}
+	var pkg *ast.Package
+	for _, p := range packages {
+		pkg = p
+	}
+	names := make([]string, 0, len(pkg.Files))
+	for name := range pkg.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var body bytes.Buffer
+	count := 0
+	checked := false
+	for _, name := range names {
+		for _, decl := range pkg.Files[name].Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				structType, ok := typeSpec.Type.(*ast.StructType)
+				if !ok || !(marked(typeSpec.Doc) || len(gen.Specs) == 1 && marked(gen.Doc)) {
+					continue
+				}
+				fields, err := structFields(structType)
+				if err != nil {
+					return fmt.Errorf("%s: %w", typeSpec.Name.Name, err)
+				}
+				if len(fields) == 0 {
+					return fmt.Errorf("%s: no exported fields", typeSpec.Name.Name)
+				}
+				if generate(&body, typeSpec.Name.Name, fields) {
+					checked = true
+				}
+				count++
+			}
+		}
+	}
+	if count == 0 {
+		return fmt.Errorf("no struct marked with //%s in %s", marker, dir)
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by dbase-gen. DO NOT EDIT.\n\npackage %s\n\n", pkg.Name)
+	if checked {
+		fmt.Fprintf(&buf, "import (\n\"fmt\"\n\n\"github.com/Valentin-Kaiser/go-dbase/dbase\"\n)\n")
+	} else {
+		fmt.Fprintf(&buf, "import \"github.com/Valentin-Kaiser/go-dbase/dbase\"\n")
+	}
+	buf.Write(body.Bytes())
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("formatting the generated code failed with error: %w", err)
+	}
+	return os.WriteFile(filepath.Join(dir, output), src, 0644)
+}
+
+// marked returns true if the comment contains the marker line
+func marked(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, comment := range doc.List {
+		if strings.TrimSpace(strings.TrimPrefix(comment.Text, "//")) == marker {
+			return true
+		}
+	}
+	return false
+}
+
+// structFields returns the fields of the struct mapped to columns
+func structFields(structType *ast.StructType) ([]*scanned, error) {
+	fields := make([]*scanned, 0)
+	for _, field := range structType.Fields.List {
+		column := ""
+		if field.Tag != nil {
+			tag, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+			column = reflect.StructTag(tag).Get("dbase")
+		}
+		if column == "-" {
+			continue
+		}
+		typ := field.Type
+		pointer := false
+		if star, ok := typ.(*ast.StarExpr); ok {
+			typ = star.X
+			pointer = true
+		}
+		typeName := exprString(typ)
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			kind, ok := accessors[typeName]
+			if !ok {
+				return nil, fmt.Errorf("field %s has the unsupported type %s", name.Name, typeName)
+			}
+			mapped := &scanned{Name: name.Name, Column: column, Kind: kind, Type: typeName, Pointer: pointer}
+			if len(mapped.Column) == 0 {
+				mapped.Column = name.Name
+			}
+			fields = append(fields, mapped)
+		}
+	}
+	return fields, nil
+}
+
+// exprString returns the type expression as written, e.g. "time.Time" or "[]byte"
+func exprString(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return exprString(e.X) + "." + e.Sel.Name
+	case *ast.ArrayType:
+		if e.Len == nil {
+			return "[]" + exprString(e.Elt)
+		}
+	}
+	return fmt.Sprintf("%T", expr)
+}
+
+// generate writes the column index and the Scan and ToRow methods of the struct.
+// Returns true if range checks were generated, which need the fmt package.
+func generate(buf *bytes.Buffer, name string, fields []*scanned) bool {
+	checked := false
+	index := strings.ToLower(name[:1]) + name[1:] + "Columns"
+	columns := make([]string, len(fields))
+	for i, field := range fields {
+		columns[i] = strconv.Quote(field.Column)
+	}
+	fmt.Fprintf(buf, "\n// %s are the columns of %s\nvar %s = dbase.NewColumnIndex(%s)\n", index, name, index, strings.Join(columns, ", "))
+
+	fmt.Fprintf(buf, "\n// Scan sets the fields of %s to the values of the row\n", name)
+	fmt.Fprintf(buf, "func (v *%s) Scan(row *dbase.Row) error {\n", name)
+	fmt.Fprintf(buf, "positions := %s.RowPositions(row)\n", index)
+	for i, field := range fields {
+		fmt.Fprintf(buf, "if p := positions[%d]; p >= 0 {\n", i)
+		if field.Pointer {
+			fmt.Fprintf(buf, "if row.IsNilAt(p) {\nv.%s = nil\n} else {\n", field.Name)
+		}
+		fmt.Fprintf(buf, "value, err := row.%sAt(p)\nif err != nil {\nreturn err\n}\n", field.Kind)
+		if check := scanCheck(field); len(check) > 0 {
+			fmt.Fprintf(buf, "if %s {\nreturn fmt.Errorf(\"value %%d of column %%s overflows %s\", value, %s)\n}\n", check, field.Type, strconv.Quote(field.Column))
+			checked = true
+		}
+		value := "value"
+		if !sameType(field) {
+			value = field.Type + "(value)"
+		}
+		switch {
+		case field.Pointer && value == "value":
+			fmt.Fprintf(buf, "v.%s = &value\n}\n", field.Name)
+		case field.Pointer:
+			fmt.Fprintf(buf, "converted := %s\nv.%s = &converted\n}\n", value, field.Name)
+		default:
+			fmt.Fprintf(buf, "v.%s = %s\n", field.Name, value)
+		}
+		fmt.Fprintf(buf, "}\n")
+	}
+	fmt.Fprintf(buf, "return nil\n}\n")
+
+	fmt.Fprintf(buf, "\n// ToRow returns a new row of the table with the values of %s\n", name)
+	fmt.Fprintf(buf, "func (v *%s) ToRow(file *dbase.File) (*dbase.Row, error) {\n", name)
+	fmt.Fprintf(buf, "row := file.NewRow()\npositions := %s.Positions(file)\n", index)
+	for i, field := range fields {
+		fmt.Fprintf(buf, "if p := positions[%d]; p >= 0 {\n", i)
+		value := "v." + field.Name
+		if field.Pointer {
+			value = "*" + value
+		}
+		if !sameType(field) {
+			value = setterType(field) + "(" + value + ")"
+		}
+		check := ""
+		if field.Type == "uint" || field.Type == "uint64" {
+			// Values above the maximum of int64 would wrap
+			ref := "v." + field.Name
+			if field.Pointer {
+				ref = "*" + ref
+			}
+			check = fmt.Sprintf("if uint64(%s) > 1<<63-1 {\nreturn nil, fmt.Errorf(\"value %%d of field %s overflows int64\", %s)\n}\n", ref, field.Name, ref)
+			checked = true
+		}
+		if field.Pointer {
+			fmt.Fprintf(buf, "var err error\nif v.%s == nil {\nerr = row.SetNilAt(p)\n} else {\n%serr = row.Set%sAt(p, %s)\n}\n", field.Name, check, field.Kind, value)
+		} else {
+			fmt.Fprintf(buf, "%serr := row.Set%sAt(p, %s)\n", check, field.Kind, value)
+		}
+		fmt.Fprintf(buf, "if err != nil {\nreturn nil, err\n}\n}\n")
+	}
+	fmt.Fprintf(buf, "err := row.Increment()\nif err != nil {\nreturn nil, err\n}\nreturn row, nil\n}\n")
+	return checked
+}
+
+// scanCheck returns the condition under which the value of the accessor does not fit into the field, empty if it always fits
+func scanCheck(field *scanned) string {
+	if field.Kind != "Int" || sameType(field) {
+		return ""
+	}
+	switch field.Type {
+	case "uint64":
+		return "value < 0"
+	case "uint", "uint8", "uint16", "uint32":
+		return fmt.Sprintf("value < 0 || uint64(%s(value)) != uint64(value)", field.Type)
+	}
+	return fmt.Sprintf("int64(%s(value)) != value", field.Type)
+}
+
+// sameType returns true if the field has the type of the accessor and needs no conversion
+func sameType(field *scanned) bool {
+	return field.Type == setterType(field)
+}
+
+// setterType returns the Go type of the accessor of the field
+func setterType(field *scanned) string {
+	switch field.Kind {
+	case "Int":
+		return "int64"
+	case "Float":
+		return "float64"
+	}
+	return field.Type
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Valentin-Kaiser/go-dbase/dbase"
+)
+
+const browseHelp = `Enter or n: next page, p: previous page, g <recno>: go to record, / <text>: search below the first row,
+h <column>...: hide columns, s [column]...: show columns (all without names), m <recno> <column>: view a memo, q: quit`
+
+// memoDumpLimit is the number of bytes of binary memos shown as hex dump
+const memoDumpLimit = 1024
+
+// browser pages through a table in the terminal.
+// Each page is drawn after a command line was entered, so no terminal mode has to be changed.
+type browser struct {
+	file    *dbase.File
+	name    string
+	out     io.Writer
+	clear   bool            // Clear the screen before each page
+	height  uint32          // Rows per page
+	top     uint32          // Position of the first row of the page
+	hidden  map[string]bool // Hidden column names
+	message string          // Shown below the page, e.g. the result of a search
+}
+
+// runBrowse opens the table read-only and pages through it until q is entered or in ends
+func runBrowse(path string, in io.Reader, out io.Writer, clear bool) error {
+	file, err := dbase.OpenTable(&dbase.Config{
+		Filename:        path,
+		ReadOnly:        true,
+		TrimSpaces:      true,
+		SkipMissingMemo: true,
+	})
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	b := &browser{file: file, name: path, out: out, clear: clear, height: 20, hidden: make(map[string]bool)}
+	scanner := bufio.NewScanner(in)
+	for {
+		err = b.draw()
+		if err != nil {
+			return err
+		}
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+		args, err := splitArgs(scanner.Text())
+		if err != nil {
+			b.message = err.Error()
+			continue
+		}
+		if len(args) > 0 && strings.ToLower(args[0]) == "q" {
+			return nil
+		}
+		b.message = ""
+		err = b.run(args)
+		if err != nil {
+			b.message = "error: " + err.Error()
+		}
+	}
+}
+
+// run executes the command of the line, an empty line shows the next page
+func (b *browser) run(args []string) error {
+	rows := b.file.Header().RecordsCount()
+	command := "n"
+	if len(args) > 0 {
+		command = strings.ToLower(args[0])
+	}
+	switch command {
+	case "n":
+		if b.top+b.height < rows {
+			b.top += b.height
+		}
+	case "p":
+		if b.top < b.height {
+			b.top = 0
+		} else {
+			b.top -= b.height
+		}
+	case "g":
+		recno, err := parseRecno(args, rows)
+		if err != nil {
+			return err
+		}
+		b.top = recno - 1
+	case "/":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: / <text>")
+		}
+		return b.search(strings.Join(args[1:], " "))
+	case "h":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: h <column>...")
+		}
+		names := upper(args[1:])
+		err := b.file.HideColumns(names...)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			b.hidden[name] = true
+		}
+	case "s":
+		names := upper(args[1:])
+		err := b.file.ShowColumns(names...)
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			b.hidden = make(map[string]bool)
+		}
+		for _, name := range names {
+			delete(b.hidden, name)
+		}
+	case "m":
+		if len(args) != 3 {
+			return fmt.Errorf("usage: m <recno> <column>")
+		}
+		recno, err := parseRecno(args[:2], rows)
+		if err != nil {
+			return err
+		}
+		return b.memo(recno, args[2])
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+	return nil
+}
+
+// draw shows the rows of the page with the visible columns
+func (b *browser) draw() error {
+	if b.clear {
+		fmt.Fprint(b.out, "\033[H\033[2J")
+	}
+	rows := b.file.Header().RecordsCount()
+	columns := make([]*dbase.Column, 0)
+	for _, column := range b.file.Columns() {
+		if !b.hidden[column.Name()] {
+			columns = append(columns, column)
+		}
+	}
+	last := b.top + b.height
+	if last > rows {
+		last = rows
+	}
+	first := b.top + 1
+	if rows == 0 {
+		first = 0
+	}
+	fmt.Fprintf(b.out, "%s - records %d to %d of %d\n", b.name, first, last, rows)
+	w := tabwriter.NewWriter(b.out, 0, 0, 2, ' ', 0)
+	writeHeader(w, columns)
+	if rows > 0 {
+		err := b.file.GoTo(b.top)
+		if err != nil {
+			return err
+		}
+	}
+	for i := b.top; i < last; i++ {
+		row, err := b.file.Next()
+		if err != nil {
+			return err
+		}
+		err = writeRow(w, row, columns)
+		if err != nil {
+			return err
+		}
+	}
+	err := w.Flush()
+	if err != nil {
+		return err
+	}
+	if len(b.message) > 0 {
+		fmt.Fprintln(b.out, b.message)
+	}
+	fmt.Fprintf(b.out, "%s\n> ", browseHelp)
+	return nil
+}
+
+// search moves the page to the first row below the first row of the page with a visible value containing the text, ignoring case.
+// Searching again finds the next row.
+func (b *browser) search(text string) error {
+	rows := b.file.Header().RecordsCount()
+	if b.top+1 >= rows {
+		b.message = fmt.Sprintf("%q not found", text)
+		return nil
+	}
+	err := b.file.GoTo(b.top + 1)
+	if err != nil {
+		return err
+	}
+	text = strings.ToLower(text)
+	for !b.file.EOF() {
+		row, err := b.file.Next()
+		if err != nil {
+			return err
+		}
+		values, err := row.ToMap()
+		if err != nil {
+			return err
+		}
+		for _, value := range values {
+			if strings.Contains(strings.ToLower(formatValue(value, 0)), text) {
+				b.top = row.Position
+				b.message = fmt.Sprintf("Found %q in record %d", text, row.Position+1)
+				return nil
+			}
+		}
+	}
+	b.message = fmt.Sprintf("%q not found", text)
+	return nil
+}
+
+// memo shows the complete value of the column in the record, binary values as hex dump
+func (b *browser) memo(recno uint32, name string) error {
+	err := b.file.GoTo(recno - 1)
+	if err != nil {
+		return err
+	}
+	row, err := b.file.Row()
+	if err != nil {
+		return err
+	}
+	value, err := row.ValueByName(strings.ToUpper(name))
+	if err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case []byte:
+		dump := v
+		if len(dump) > memoDumpLimit {
+			dump = dump[:memoDumpLimit]
+		}
+		b.message = fmt.Sprintf("%s of record %d, %d bytes:\n%s", strings.ToUpper(name), recno, len(v), hex.Dump(dump))
+	case string:
+		b.message = fmt.Sprintf("%s of record %d:\n%s", strings.ToUpper(name), recno, strings.TrimRight(v, " "))
+	default:
+		b.message = fmt.Sprintf("%s of record %d: %s", strings.ToUpper(name), recno, formatValue(v, 0))
+	}
+	return nil
+}
+
+// parseRecno parses the record number of the second argument
+func parseRecno(args []string, rows uint32) (uint32, error) {
+	if len(args) != 2 {
+		return 0, fmt.Errorf("usage: %s <recno>", args[0])
+	}
+	recno, err := strconv.ParseUint(args[1], 10, 32)
+	if err != nil || recno < 1 || uint32(recno) > rows {
+		return 0, fmt.Errorf("record %s is not between 1 and %d", args[1], rows)
+	}
+	return uint32(recno), nil
+}
+
+// upper returns the column names in upper case, as they are stored in the table
+func upper(names []string) []string {
+	result := make([]string, len(names))
+	for i, name := range names {
+		result[i] = strings.ToUpper(name)
+	}
+	return result
+}
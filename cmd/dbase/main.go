@@ -0,0 +1,197 @@
+// Command dbase inspects dBase tables from the command line.
+//
+// Usage:
+//
+//	dbase repl <dir>       Interactive prompt for the tables in the directory
+//	dbase browse <file>    Page through the table in the terminal
+//	dbase info <file>      Print the file type, code page, rows and columns of the table
+//	dbase run <pipeline>   Run the pipeline defined in the JSON or YAML file
+//	dbase report <file> <template> [group]...
+//	                       Write the rows of the table through the text template, grouped by the columns
+//	dbase proto <file> [package]
+//	                       Print a protobuf message definition of the table for the protobuf export
+//	dbase ddl <file> <bigquery|snowflake> [table]
+//	                       Print the CREATE TABLE statement for the load files of the warehouse
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/Valentin-Kaiser/go-dbase/dbase"
+)
+
+const usage = `Usage:
+  dbase repl <dir>       Interactive prompt for the tables in the directory
+  dbase browse <file>    Page through the table in the terminal
+  dbase info <file>      Print the file type, code page, rows and columns of the table
+  dbase run <pipeline>   Run the pipeline defined in the JSON or YAML file
+  dbase report <file> <template> [group]...
+                         Write the rows of the table through the text template, grouped by the columns
+  dbase proto <file> [package]
+                         Print a protobuf message definition of the table for the protobuf export
+  dbase ddl <file> <bigquery|snowflake> [table]
+                         Print the CREATE TABLE statement for the load files of the warehouse
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var err error
+	switch os.Args[1] {
+	case "repl":
+		if len(os.Args) != 3 {
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+		err = runREPL(os.Args[2], os.Stdin, os.Stdout)
+	case "browse":
+		if len(os.Args) != 3 {
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+		err = runBrowse(os.Args[2], os.Stdin, os.Stdout, isTerminal(os.Stdout))
+	case "info":
+		if len(os.Args) != 3 {
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+		err = runInfo(os.Args[2], os.Stdout)
+	case "run":
+		if len(os.Args) != 3 {
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+		err = runPipeline(os.Args[2], os.Stdout)
+	case "report":
+		if len(os.Args) < 4 {
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+		err = runReport(os.Args[2], os.Args[3], os.Args[4:], os.Stdout)
+	case "proto":
+		if len(os.Args) < 3 || len(os.Args) > 4 {
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+		err = runProto(os.Args[2], strings.Join(os.Args[3:], ""), os.Stdout)
+	case "ddl":
+		if len(os.Args) < 4 || len(os.Args) > 5 {
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+		err = runDDL(os.Args[2], os.Args[3], strings.Join(os.Args[4:], ""), os.Stdout)
+	case "help", "-h", "--help":
+		fmt.Fprint(os.Stdout, usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// isTerminal returns true if the file is a terminal and not redirected
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// runInfo prints the summary of the table, see dbase.File.Describe
+func runInfo(path string, out io.Writer) error {
+	file, err := dbase.OpenTable(&dbase.Config{
+		Filename:        path,
+		ReadOnly:        true,
+		SkipMissingMemo: true,
+	})
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = fmt.Fprint(out, file.Describe())
+	return err
+}
+
+// runPipeline runs the pipeline of the definition file and reports the exported rows
+func runPipeline(path string, out io.Writer) error {
+	pipeline, err := dbase.LoadPipeline(path)
+	if err != nil {
+		return err
+	}
+	result, err := dbase.RunPipeline(pipeline)
+	if err != nil {
+		return err
+	}
+	for _, table := range pipeline.Tables {
+		fmt.Fprintf(out, "%s: %d rows\n", table, result.Rows[table])
+	}
+	for _, file := range result.Files {
+		fmt.Fprintf(out, "Wrote %s\n", file)
+	}
+	return nil
+}
+
+// runReport writes the rows of the table through the template file, the groups are column names
+func runReport(path string, templatePath string, groups []string, out io.Writer) error {
+	tmpl, err := template.New(filepath.Base(templatePath)).Funcs(dbase.ReportFuncs()).ParseFiles(templatePath)
+	if err != nil {
+		return err
+	}
+	file, err := dbase.OpenTable(&dbase.Config{
+		Filename:        path,
+		ReadOnly:        true,
+		TrimSpaces:      true,
+		SkipMissingMemo: true,
+	})
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	for i, group := range groups {
+		groups[i] = strings.ToUpper(group)
+	}
+	return file.Report(tmpl, out, groups...)
+}
+
+// runProto prints the protobuf message definition of the table, the package is left out if empty
+func runProto(path string, pkg string, out io.Writer) error {
+	file, err := dbase.OpenTable(&dbase.Config{
+		Filename:        path,
+		ReadOnly:        true,
+		SkipMissingMemo: true,
+	})
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = fmt.Fprint(out, file.ProtoSchema(pkg))
+	return err
+}
+
+// runDDL prints the CREATE TABLE statement of the warehouse for the table, the table name defaults to the file name
+func runDDL(path string, warehouse string, table string, out io.Writer) error {
+	target, err := dbase.ParseWarehouse(warehouse)
+	if err != nil {
+		return err
+	}
+	file, err := dbase.OpenTable(&dbase.Config{
+		Filename:        path,
+		ReadOnly:        true,
+		SkipMissingMemo: true,
+	})
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = fmt.Fprint(out, file.WarehouseDDL(target, table))
+	return err
+}
@@ -0,0 +1,588 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode"
+
+	"github.com/Valentin-Kaiser/go-dbase/dbase"
+)
+
+const replHelp = `Commands:
+  list                        List the tables of the directory
+  use <table>                 Open a table read-only, the extension is optional
+  browse [count]              Show the next rows from the current row (default: 20)
+  goto <recno>                Move to the record number, the first row is 1
+  locate <column=value>...    Show the first row with the values
+  continue                    Show the next row of the last locate
+  filter [column=value]...    Only browse, locate and export rows with the values, without values the filter is cleared
+  export <format> <file>      Export the rows of the filter to a new file
+  stats                       Show the read counters of the table
+  help                        Show this help
+  quit                        Leave the prompt
+Values containing spaces are quoted, e.g. locate lastname="van Dyke". Dates are written as 2006-01-02.
+`
+
+// browseWidth is the maximum width of a value shown by browse
+const browseWidth = 30
+
+// session is the state of the interactive prompt
+type session struct {
+	dir     string
+	out     io.Writer
+	table   *dbase.File // Table opened with use
+	name    string      // Name of the table without extension
+	filter  []condition // Conditions of filter
+	located []condition // Conditions of the last locate, used by continue
+}
+
+// condition is a column value given as column=value
+type condition struct {
+	column *dbase.Column
+	value  interface{}
+}
+
+// runREPL reads commands from in until it ends or quit is entered.
+// Tables are opened read-only, so the prompt can be used on files in use by other applications.
+func runREPL(dir string, in io.Reader, out io.Writer) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	s := &session{dir: dir, out: out}
+	defer s.close()
+	fmt.Fprintf(out, "Tables in %s, type help for the commands\n", dir)
+	err = s.list()
+	if err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, s.prompt())
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+		args, err := splitArgs(scanner.Text())
+		if err != nil {
+			fmt.Fprintln(out, "error:", err)
+			continue
+		}
+		if len(args) == 0 {
+			continue
+		}
+		command := strings.ToLower(args[0])
+		if command == "quit" || command == "exit" {
+			return nil
+		}
+		err = s.run(command, args[1:])
+		if err != nil {
+			fmt.Fprintln(out, "error:", err)
+		}
+	}
+}
+
+// prompt returns the prompt showing the table in use
+func (s *session) prompt() string {
+	if s.table == nil {
+		return "dbase> "
+	}
+	return fmt.Sprintf("dbase:%s> ", s.name)
+}
+
+// run executes the command with its arguments
+func (s *session) run(command string, args []string) error {
+	switch command {
+	case "help":
+		fmt.Fprint(s.out, replHelp)
+		return nil
+	case "list":
+		return s.list()
+	case "use":
+		return s.use(args)
+	case "browse":
+		return s.browse(args)
+	case "goto":
+		return s.goTo(args)
+	case "locate":
+		return s.locate(args)
+	case "continue":
+		return s.continueLocate()
+	case "filter":
+		return s.setFilter(args)
+	case "export":
+		return s.export(args)
+	case "stats":
+		return s.stats()
+	}
+	return fmt.Errorf("unknown command %q, type help for the commands", command)
+}
+
+// stats shows the counters of the table in use
+func (s *session) stats() error {
+	err := s.requireTable()
+	if err != nil {
+		return err
+	}
+	stats := s.table.Stats()
+	w := tabwriter.NewWriter(s.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Rows read\t%d\n", stats.RowsRead)
+	fmt.Fprintf(w, "Memos read\t%d\n", stats.MemosRead)
+	fmt.Fprintf(w, "Bytes read\t%d\n", stats.BytesRead)
+	fmt.Fprintf(w, "Decode errors\t%d\n", stats.DecodeErrors)
+	fmt.Fprintf(w, "I/O time\t%v\n", stats.IOTime)
+	fmt.Fprintf(w, "Decode time\t%v\n", stats.DecodeTime)
+	return w.Flush()
+}
+
+// close closes the table in use
+func (s *session) close() {
+	if s.table != nil {
+		s.table.Close()
+		s.table = nil
+	}
+}
+
+// tables returns the file names of the tables in the directory
+func (s *session) tables() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0)
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.EqualFold(filepath.Ext(entry.Name()), string(dbase.DBF)) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// list shows the tables of the directory with their rows and columns count.
+// Only the headers are read, the memo and index files are not opened.
+func (s *session) list() error {
+	names, err := s.tables()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(s.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TABLE\tROWS\tCOLUMNS")
+	for _, name := range names {
+		file, err := dbase.OpenTable(&dbase.Config{
+			Filename:     filepath.Join(s.dir, name),
+			ReadOnly:     true,
+			WithoutMemo:  true,
+			WithoutIndex: true,
+		})
+		if err != nil {
+			fmt.Fprintf(w, "%s\t%v\t\n", name, err)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\n", name, file.Header().RecordsCount(), file.ColumnsCount())
+		file.Close()
+	}
+	return w.Flush()
+}
+
+// use opens the table read-only and resets the filter
+func (s *session) use(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: use <table>")
+	}
+	names, err := s.tables()
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		stem := strings.TrimSuffix(name, filepath.Ext(name))
+		if !strings.EqualFold(name, args[0]) && !strings.EqualFold(stem, args[0]) {
+			continue
+		}
+		file, err := dbase.OpenTable(&dbase.Config{
+			Filename:        filepath.Join(s.dir, name),
+			ReadOnly:        true,
+			TrimSpaces:      true,
+			SkipMissingMemo: true,
+		})
+		if err != nil {
+			return err
+		}
+		s.close()
+		s.table, s.name, s.filter, s.located = file, stem, nil, nil
+		fmt.Fprintf(s.out, "%d rows, columns: %s\n", file.Header().RecordsCount(), strings.Join(file.ColumnNames(), ", "))
+		return nil
+	}
+	return fmt.Errorf("table %s not found in %s", args[0], s.dir)
+}
+
+// requireTable returns an error if no table is in use
+func (s *session) requireTable() error {
+	if s.table == nil {
+		return fmt.Errorf("no table in use, open one with use <table>")
+	}
+	return nil
+}
+
+// browse shows the next rows of the filter from the current row
+func (s *session) browse(args []string) error {
+	err := s.requireTable()
+	if err != nil {
+		return err
+	}
+	count := 20
+	if len(args) > 0 {
+		count, err = strconv.Atoi(args[0])
+		if err != nil || count < 1 {
+			return fmt.Errorf("usage: browse [count]")
+		}
+	}
+	if s.table.EOF() {
+		fmt.Fprintln(s.out, "End of table, use goto 1 to start over")
+		return nil
+	}
+	columns := s.table.Columns()
+	w := tabwriter.NewWriter(s.out, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+	writeHeader(w, columns)
+	for shown := 0; shown < count && !s.table.EOF(); {
+		row, err := s.table.Next()
+		if err != nil {
+			return err
+		}
+		if !matches(row, s.filter) {
+			continue
+		}
+		err = writeRow(w, row, columns)
+		if err != nil {
+			return err
+		}
+		shown++
+	}
+	return nil
+}
+
+// writeHeader writes the tab separated names of the columns after the record number
+func writeHeader(w io.Writer, columns []*dbase.Column) {
+	fmt.Fprint(w, "RECNO")
+	for _, column := range columns {
+		fmt.Fprintf(w, "\t%s", column.Name())
+	}
+	fmt.Fprintln(w)
+}
+
+// writeRow writes the record number and the tab separated values of the columns, deleted rows are marked with *
+func writeRow(w io.Writer, row *dbase.Row, columns []*dbase.Column) error {
+	values, err := row.ToMap()
+	if err != nil {
+		return err
+	}
+	recno := strconv.Itoa(int(row.Position) + 1)
+	if row.Deleted {
+		recno += "*"
+	}
+	fmt.Fprint(w, recno)
+	for _, column := range columns {
+		fmt.Fprintf(w, "\t%s", formatValue(values[column.Name()], browseWidth))
+	}
+	fmt.Fprintln(w)
+	return nil
+}
+
+// goTo moves to the record number
+func (s *session) goTo(args []string) error {
+	err := s.requireTable()
+	if err != nil {
+		return err
+	}
+	recno := 0
+	if len(args) == 1 {
+		recno, err = strconv.Atoi(args[0])
+	}
+	if len(args) != 1 || err != nil || recno < 1 {
+		return fmt.Errorf("usage: goto <recno>")
+	}
+	if uint32(recno) > s.table.Header().RecordsCount() {
+		return fmt.Errorf("record %d is beyond the %d rows of the table", recno, s.table.Header().RecordsCount())
+	}
+	return s.table.GoTo(uint32(recno - 1))
+}
+
+// locate shows the first row of the filter with the values
+func (s *session) locate(args []string) error {
+	err := s.requireTable()
+	if err != nil {
+		return err
+	}
+	conditions, err := s.conditions(args)
+	if err != nil {
+		return err
+	}
+	if len(conditions) == 0 {
+		return fmt.Errorf("usage: locate <column=value>...")
+	}
+	s.located = conditions
+	err = s.table.GoTo(0)
+	if err != nil {
+		return err
+	}
+	return s.continueLocate()
+}
+
+// continueLocate shows the next row of the last locate after the current row
+func (s *session) continueLocate() error {
+	err := s.requireTable()
+	if err != nil {
+		return err
+	}
+	if s.located == nil {
+		return fmt.Errorf("no locate to continue")
+	}
+	for !s.table.EOF() {
+		row, err := s.table.Next()
+		if err != nil {
+			return err
+		}
+		if !matches(row, s.filter) || !matches(row, s.located) {
+			continue
+		}
+		values, err := row.ToMap()
+		if err != nil {
+			return err
+		}
+		deleted := ""
+		if row.Deleted {
+			deleted = " (deleted)"
+		}
+		fmt.Fprintf(s.out, "Record %d%s\n", row.Position+1, deleted)
+		w := tabwriter.NewWriter(s.out, 0, 0, 2, ' ', 0)
+		for _, column := range s.table.Columns() {
+			fmt.Fprintf(w, "  %s\t%s\n", column.Name(), formatValue(values[column.Name()], 0))
+		}
+		return w.Flush()
+	}
+	fmt.Fprintln(s.out, "End of locate scope")
+	return nil
+}
+
+// setFilter sets the conditions rows have to match, without arguments the filter is cleared
+func (s *session) setFilter(args []string) error {
+	err := s.requireTable()
+	if err != nil {
+		return err
+	}
+	conditions, err := s.conditions(args)
+	if err != nil {
+		return err
+	}
+	s.filter = conditions
+	if len(conditions) == 0 {
+		fmt.Fprintln(s.out, "Filter cleared")
+		return nil
+	}
+	fmt.Fprintf(s.out, "Filter set on %d columns\n", len(conditions))
+	return nil
+}
+
+// export writes the rows of the filter to a new file, existing files are not overwritten.
+// The rows are written to a temporary file that is renamed when the export succeeded, so a failed export leaves no partial file.
+func (s *session) export(args []string) error {
+	err := s.requireTable()
+	if err != nil {
+		return err
+	}
+	if len(args) != 2 {
+		return fmt.Errorf("usage: export <%s> <file>", strings.Join(dbase.Exporters(), "|"))
+	}
+	if _, err := os.Stat(args[1]); err == nil {
+		return fmt.Errorf("file %s already exists", args[1])
+	}
+	tmp := args[1] + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	exporter, err := dbase.NewExporter(args[0], f)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	filtered := &filterExporter{Exporter: exporter, conditions: s.filter}
+	err = s.table.Export(filtered, false)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	err = os.Rename(tmp, args[1])
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "Exported %d rows to %s\n", filtered.rows, args[1])
+	return nil
+}
+
+// filterExporter passes the rows matching the conditions to the exporter
+type filterExporter struct {
+	dbase.Exporter
+	conditions []condition
+	rows       int
+}
+
+func (e *filterExporter) WriteRow(row *dbase.Row) error {
+	if !matches(row, e.conditions) {
+		return nil
+	}
+	e.rows++
+	return e.Exporter.WriteRow(row)
+}
+
+// conditions parses the column=value arguments with the types of the columns
+func (s *session) conditions(args []string) ([]condition, error) {
+	conditions := make([]condition, 0, len(args))
+	for _, arg := range args {
+		name, raw, ok := strings.Cut(arg, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected column=value instead of %q", arg)
+		}
+		var column *dbase.Column
+		for _, c := range s.table.Columns() {
+			if strings.EqualFold(c.Name(), name) {
+				column = c
+				break
+			}
+		}
+		if column == nil {
+			return nil, fmt.Errorf("column %s not found", name)
+		}
+		value, err := parseValue(column, raw)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition{column: column, value: value})
+	}
+	return conditions, nil
+}
+
+// parseValue converts the text to a value of the column type, compared with dbase.Equal
+func parseValue(column *dbase.Column, raw string) (interface{}, error) {
+	switch column.Kind() {
+	case dbase.Numeric, dbase.Float, dbase.Double, dbase.Currency, dbase.Integer:
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("column %s expects a number instead of %q", column.Name(), raw)
+		}
+		return value, nil
+	case dbase.Logical:
+		switch strings.ToUpper(strings.Trim(raw, ".")) {
+		case "T", "Y", "TRUE", "YES":
+			return true, nil
+		case "F", "N", "FALSE", "NO":
+			return false, nil
+		}
+		return nil, fmt.Errorf("column %s expects T or F instead of %q", column.Name(), raw)
+	case dbase.Date, dbase.DateTime:
+		if len(raw) == 0 {
+			return nil, nil
+		}
+		for _, layout := range []string{"2006-01-02", "2006-01-02 15:04:05", time.RFC3339} {
+			value, err := time.Parse(layout, raw)
+			if err == nil {
+				return value, nil
+			}
+		}
+		return nil, fmt.Errorf("column %s expects a date like 2006-01-02 instead of %q", column.Name(), raw)
+	}
+	return raw, nil
+}
+
+// matches returns true if the row contains the values of all conditions
+func matches(row *dbase.Row, conditions []condition) bool {
+	for _, c := range conditions {
+		value, err := row.ValueByName(c.column.Name())
+		if err != nil || !dbase.Equal(value, c.value, c.column) {
+			return false
+		}
+	}
+	return true
+}
+
+// formatValue returns the value as a single line, cut to width runes if width is not 0
+func formatValue(value interface{}, width int) string {
+	var text string
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 {
+			return v.Format("2006-01-02")
+		}
+		return v.Format("2006-01-02 15:04:05")
+	case []byte:
+		return fmt.Sprintf("<%d bytes>", len(v))
+	case string:
+		text = strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return ' '
+			}
+			return r
+		}, v)
+	default:
+		text = fmt.Sprint(v)
+	}
+	runes := []rune(text)
+	if width > 0 && len(runes) > width {
+		return string(runes[:width-3]) + "..."
+	}
+	return text
+}
+
+// splitArgs splits the line at spaces, double quotes group text containing spaces
+func splitArgs(line string) ([]string, error) {
+	args := make([]string, 0)
+	var current strings.Builder
+	quoted, started := false, false
+	for _, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			started = true
+		case unicode.IsSpace(r) && !quoted:
+			if started {
+				args = append(args, current.String())
+				current.Reset()
+				started = false
+			}
+		default:
+			current.WriteRune(r)
+			started = true
+		}
+	}
+	if quoted {
+		return nil, errors.New("unterminated quote")
+	}
+	if started {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
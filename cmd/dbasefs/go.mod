@@ -0,0 +1,20 @@
+module github.com/Valentin-Kaiser/go-dbase/cmd/dbasefs
+
+go 1.19
+
+require (
+	github.com/Valentin-Kaiser/go-dbase/v2 v2.0.0
+	github.com/hanwen/go-fuse/v2 v2.9.0
+)
+
+require (
+	github.com/Valentin-Kaiser/go-dbase v0.0.0-00010101000000-000000000000 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+	golang.org/x/text v0.11.0 // indirect
+)
+
+// The modules of this repository are built from the same revision, also without go.work
+replace (
+	github.com/Valentin-Kaiser/go-dbase => ../../
+	github.com/Valentin-Kaiser/go-dbase/v2 => ../../v2
+)
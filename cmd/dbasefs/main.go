@@ -0,0 +1,280 @@
+//go:build linux || darwin || freebsd
+
+// Command dbasefs mounts a directory of dBase tables read-only as FUSE file system.
+// Each table of the directory is exposed as CSV and newline delimited JSON file,
+// e.g. EMPLOYEES.DBF as EMPLOYEES.csv and EMPLOYEES.jsonl, so any tool can read the rows without a conversion step.
+// The files are written by the streaming exporters while they are read, deleted rows are left out.
+// As their length is only known at the end of the export, the files report a size of 0 and are read with direct I/O
+// like the files of /proc. Reading at another offset than the end of the previous read starts the export again.
+//
+// Usage:
+//
+//	dbasefs [-untested] [-direct] [-debug] <dir> <mountpoint>
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/Valentin-Kaiser/go-dbase/v2/dbf"
+	"github.com/hanwen/go-fuse/v2/fs"
+	"github.com/hanwen/go-fuse/v2/fuse"
+)
+
+// formats maps the extensions of the exposed files to the exporters
+var formats = map[string]func(file *dbf.File, w io.Writer) error{
+	".csv": func(file *dbf.File, w io.Writer) error {
+		return file.ExportCSV(w, true)
+	},
+	".jsonl": func(file *dbf.File, w io.Writer) error {
+		return file.ExportJSONL(w, true)
+	},
+}
+
+func main() {
+	untested := flag.Bool("untested", false, "Open tables of versions that are not tested")
+	direct := flag.Bool("direct", false, "Mount with the mount system call instead of fusermount, requires root")
+	debug := flag.Bool("debug", false, "Log the FUSE requests")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: dbasefs [-untested] [-direct] [-debug] <dir> <mountpoint>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dir, err := filepath.Abs(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	server, err := fs.Mount(flag.Arg(1), &root{dir: dir, untested: *untested}, &fs.Options{
+		MountOptions: fuse.MountOptions{
+			FsName:      dir,
+			Name:        "dbasefs",
+			Options:     []string{"ro"},
+			DirectMount: *direct,
+			Debug:       *debug,
+		},
+	})
+	if err != nil {
+		log.Fatalf("mounting %s failed with error: %v", flag.Arg(1), err)
+	}
+	// Unmount on interrupt, so the mountpoint is not left behind
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		err := server.Unmount()
+		if err != nil {
+			log.Printf("unmounting failed with error: %v", err)
+		}
+	}()
+	server.Wait()
+}
+
+// root is the directory of the mount, listing the views of the tables in the directory
+type root struct {
+	fs.Inode
+	dir      string
+	untested bool
+}
+
+var _ = (fs.NodeReaddirer)((*root)(nil))
+var _ = (fs.NodeLookuper)((*root)(nil))
+
+// Readdir lists the views of the tables, the directory is read again on every call
+func (r *root) Readdir(ctx context.Context) (fs.DirStream, syscall.Errno) {
+	tables, err := r.tables()
+	if err != nil {
+		log.Printf("reading %s failed with error: %v", r.dir, err)
+		return nil, fs.ToErrno(err)
+	}
+	entries := make([]fuse.DirEntry, 0, len(tables)*len(formats))
+	for _, name := range viewNames(tables) {
+		entries = append(entries, fuse.DirEntry{Name: name, Mode: fuse.S_IFREG})
+	}
+	return fs.NewListDirStream(entries), 0
+}
+
+// Lookup returns the view of the name, ENOENT if no table matches
+func (r *root) Lookup(ctx context.Context, name string, out *fuse.EntryOut) (*fs.Inode, syscall.Errno) {
+	export, ok := formats[filepath.Ext(name)]
+	if !ok {
+		return nil, syscall.ENOENT
+	}
+	tables, err := r.tables()
+	if err != nil {
+		return nil, fs.ToErrno(err)
+	}
+	table, ok := tables[strings.TrimSuffix(name, filepath.Ext(name))]
+	if !ok {
+		return nil, syscall.ENOENT
+	}
+	v := &view{path: filepath.Join(r.dir, table), export: export, untested: r.untested}
+	errno := v.attr(&out.Attr)
+	if errno != 0 {
+		return nil, errno
+	}
+	return r.NewInode(ctx, v, fs.StableAttr{Mode: fuse.S_IFREG}), 0
+}
+
+// tables returns the table files of the directory by the name of their views
+func (r *root) tables() (map[string]string, error) {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return nil, err
+	}
+	tables := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), string(dbf.DBF)) {
+			continue
+		}
+		tables[strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))] = entry.Name()
+	}
+	return tables, nil
+}
+
+// viewNames returns the sorted file names of the views of the tables
+func viewNames(tables map[string]string) []string {
+	names := make([]string, 0, len(tables)*len(formats))
+	for stem := range tables {
+		for ext := range formats {
+			names = append(names, stem+ext)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// view is a CSV or JSONL file of a table
+type view struct {
+	fs.Inode
+	path     string
+	export   func(file *dbf.File, w io.Writer) error
+	untested bool
+}
+
+var _ = (fs.NodeGetattrer)((*view)(nil))
+var _ = (fs.NodeOpener)((*view)(nil))
+
+// Getattr reports the view as read-only file with the modification time of the table
+func (v *view) Getattr(ctx context.Context, f fs.FileHandle, out *fuse.AttrOut) syscall.Errno {
+	return v.attr(&out.Attr)
+}
+
+// attr sets the attributes of the view, the size is unknown before the export and reported as 0
+func (v *view) attr(out *fuse.Attr) syscall.Errno {
+	info, err := os.Stat(v.path)
+	if err != nil {
+		return fs.ToErrno(err)
+	}
+	out.Mode = fuse.S_IFREG | 0444
+	out.Size = 0
+	modified := info.ModTime()
+	out.SetTimes(nil, &modified, &modified)
+	return 0
+}
+
+// Open starts a stream of the view, writing is rejected
+func (v *view) Open(ctx context.Context, flags uint32) (fs.FileHandle, uint32, syscall.Errno) {
+	if flags&(syscall.O_WRONLY|syscall.O_RDWR) != 0 {
+		return nil, 0, syscall.EROFS
+	}
+	return &stream{view: v}, fuse.FOPEN_DIRECT_IO, 0
+}
+
+// stream is an open view, the rows are exported into a pipe that is read sequentially
+type stream struct {
+	mutex  sync.Mutex
+	view   *view
+	reader *io.PipeReader
+	offset int64 // Offset of the next byte of the reader
+}
+
+var _ = (fs.FileReader)((*stream)(nil))
+var _ = (fs.FileReleaser)((*stream)(nil))
+
+// Read returns the bytes at the offset, the export is started again if the offset is before the bytes read so far
+func (s *stream) Read(ctx context.Context, dest []byte, off int64) (fuse.ReadResult, syscall.Errno) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.reader == nil || off < s.offset {
+		s.start()
+	}
+	if off > s.offset {
+		skipped, err := io.CopyN(io.Discard, s.reader, off-s.offset)
+		s.offset += skipped
+		if errors.Is(err, io.EOF) {
+			return fuse.ReadResultData(nil), 0
+		}
+		if err != nil {
+			log.Printf("exporting %s failed with error: %v", s.view.path, err)
+			return nil, syscall.EIO
+		}
+	}
+	n, err := io.ReadFull(s.reader, dest)
+	s.offset += int64(n)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		log.Printf("exporting %s failed with error: %v", s.view.path, err)
+		return nil, syscall.EIO
+	}
+	return fuse.ReadResultData(dest[:n]), 0
+}
+
+// Release stops the export
+func (s *stream) Release(ctx context.Context) syscall.Errno {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.reader != nil {
+		s.reader.Close()
+	}
+	return 0
+}
+
+// start stops a running export and exports the table from the start.
+// Each export opens the table, so concurrent readers do not share the row pointer.
+func (s *stream) start() {
+	if s.reader != nil {
+		s.reader.Close()
+	}
+	reader, writer := io.Pipe()
+	s.reader = reader
+	s.offset = 0
+	go func() {
+		writer.CloseWithError(s.view.write(writer))
+	}()
+}
+
+// write exports the table to the writer
+func (v *view) write(w io.Writer) error {
+	file, err := dbf.OpenTable(&dbf.Config{
+		Filename:        v.path,
+		ReadOnly:        true,
+		TrimSpaces:      true,
+		SkipMissingMemo: true,
+		Untested:        v.untested,
+	})
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	buffered := bufio.NewWriterSize(w, 64*1024)
+	err = v.export(file, buffered)
+	if err != nil {
+		return err
+	}
+	return buffered.Flush()
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !darwin && !freebsd
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+// FUSE is only available on Linux, macOS and FreeBSD, the command exits with an error on other platforms
+func main() {
+	fmt.Fprintf(os.Stderr, "dbasefs is not supported on %s, FUSE requires Linux, macOS or FreeBSD\n", runtime.GOOS)
+	os.Exit(1)
+}
@@ -0,0 +1,211 @@
+package dbase
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Typed accessors for the values of a row by column name.
+// The Get variants return an error if the column does not exist or the value has another type,
+// the Must variants panic instead and the Default variants return the given default value.
+// Nil values are returned as the zero value of the type.
+// Values are cast to the type declared in Config.Schema first, missing reference columns return their default (see SchemaDrift).
+
+// Returns the value of the column as string, trimmed according to the trim settings of the table and the column
+func (row *Row) GetString(name string) (string, error) {
+	val, err := row.castValueByName(name)
+	if err != nil {
+		return "", newError("dbase-accessors-getstring-1", err)
+	}
+	var mod *Modification
+	if pos := row.handle.ColumnPosByName(name); pos >= 0 {
+		mod = row.handle.table.mods[pos]
+	}
+	switch v := val.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return row.handle.trim(v, mod), nil
+	case []byte:
+		return row.handle.trim(string(v), mod), nil
+	default:
+		return "", newError("dbase-accessors-getstring-2", fmt.Errorf("invalid data type %T, expected string at column field: %v", v, name))
+	}
+}
+
+// Returns the value of the column as int64, numbers with a fractional part are rejected
+func (row *Row) GetInt(name string) (int64, error) {
+	val, err := row.castValueByName(name)
+	if err != nil {
+		return 0, newError("dbase-accessors-getint-1", err)
+	}
+	f, ok := toFloat(val)
+	if !ok {
+		return 0, newError("dbase-accessors-getint-2", fmt.Errorf("invalid data type %T, expected integer at column field: %v", val, name))
+	}
+	if i, ok := val.(int64); ok {
+		return i, nil
+	}
+	if f != math.Trunc(f) {
+		return 0, newError("dbase-accessors-getint-3", fmt.Errorf("value %v is not an integer at column field: %v", val, name))
+	}
+	return int64(f), nil
+}
+
+// Returns the value of the column as float64
+func (row *Row) GetFloat(name string) (float64, error) {
+	val, err := row.castValueByName(name)
+	if err != nil {
+		return 0, newError("dbase-accessors-getfloat-1", err)
+	}
+	f, ok := toFloat(val)
+	if !ok {
+		return 0, newError("dbase-accessors-getfloat-2", fmt.Errorf("invalid data type %T, expected number at column field: %v", val, name))
+	}
+	return f, nil
+}
+
+// Returns the value of the column as bool
+func (row *Row) GetBool(name string) (bool, error) {
+	val, err := row.castValueByName(name)
+	if err != nil {
+		return false, newError("dbase-accessors-getbool-1", err)
+	}
+	if val == nil {
+		return false, nil
+	}
+	b, ok := val.(bool)
+	if !ok {
+		return false, newError("dbase-accessors-getbool-2", fmt.Errorf("invalid data type %T, expected bool at column field: %v", val, name))
+	}
+	return b, nil
+}
+
+// Returns the value of the column as time.Time
+func (row *Row) GetTime(name string) (time.Time, error) {
+	val, err := row.castValueByName(name)
+	if err != nil {
+		return time.Time{}, newError("dbase-accessors-gettime-1", err)
+	}
+	if val == nil {
+		return time.Time{}, nil
+	}
+	t, ok := val.(time.Time)
+	if !ok {
+		return time.Time{}, newError("dbase-accessors-gettime-2", fmt.Errorf("invalid data type %T, expected time.Time at column field: %v", val, name))
+	}
+	return t, nil
+}
+
+// Returns the value of the column as string and panics on error
+func (row *Row) MustString(name string) string {
+	val, err := row.GetString(name)
+	if err != nil {
+		panic(GetErrorTrace(err))
+	}
+	return val
+}
+
+// Returns the value of the column as int64 and panics on error
+func (row *Row) MustInt(name string) int64 {
+	val, err := row.GetInt(name)
+	if err != nil {
+		panic(GetErrorTrace(err))
+	}
+	return val
+}
+
+// Returns the value of the column as float64 and panics on error
+func (row *Row) MustFloat(name string) float64 {
+	val, err := row.GetFloat(name)
+	if err != nil {
+		panic(GetErrorTrace(err))
+	}
+	return val
+}
+
+// Returns the value of the column as bool and panics on error
+func (row *Row) MustBool(name string) bool {
+	val, err := row.GetBool(name)
+	if err != nil {
+		panic(GetErrorTrace(err))
+	}
+	return val
+}
+
+// Returns the value of the column as time.Time and panics on error
+func (row *Row) MustTime(name string) time.Time {
+	val, err := row.GetTime(name)
+	if err != nil {
+		panic(GetErrorTrace(err))
+	}
+	return val
+}
+
+// Returns the value of the column as string or the default if the column does not exist, the value is nil or has another type
+func (row *Row) StringDefault(name string, def string) string {
+	val, err := row.GetString(name)
+	if err != nil || row.isNil(name) {
+		return def
+	}
+	return val
+}
+
+// Returns the value of the column as int64 or the default if the column does not exist, the value is nil or no integer
+func (row *Row) IntDefault(name string, def int64) int64 {
+	val, err := row.GetInt(name)
+	if err != nil || row.isNil(name) {
+		return def
+	}
+	return val
+}
+
+// Returns the value of the column as float64 or the default if the column does not exist, the value is nil or no number
+func (row *Row) FloatDefault(name string, def float64) float64 {
+	val, err := row.GetFloat(name)
+	if err != nil || row.isNil(name) {
+		return def
+	}
+	return val
+}
+
+// Returns the value of the column as bool or the default if the column does not exist, the value is nil or has another type
+func (row *Row) BoolDefault(name string, def bool) bool {
+	val, err := row.GetBool(name)
+	if err != nil || row.isNil(name) {
+		return def
+	}
+	return val
+}
+
+// Returns the value of the column as time.Time or the default if the column does not exist, the value is nil or has another type
+func (row *Row) TimeDefault(name string, def time.Time) time.Time {
+	val, err := row.GetTime(name)
+	if err != nil || row.isNil(name) {
+		return def
+	}
+	return val
+}
+
+// castValueByName returns the value of the column cast to the type declared in the schema
+func (row *Row) castValueByName(name string) (interface{}, error) {
+	pos := row.handle.ColumnPosByName(name)
+	if pos < 0 {
+		if reference := row.handle.missingReference(name); reference != nil {
+			return row.handle.castValue(reference, row.handle.referenceDefault(reference))
+		}
+		return nil, newError("dbase-accessors-castvaluebyname-1", fmt.Errorf("column %v not found", name))
+	}
+	val, err := row.handle.castValue(row.handle.table.columns[pos], row.Value(pos))
+	if err != nil {
+		return nil, newError("dbase-accessors-castvaluebyname-2", err)
+	}
+	return val, nil
+}
+
+// isNil returns true if the column does not exist or its value is nil
+func (row *Row) isNil(name string) bool {
+	val, err := row.castValueByName(name)
+	return err != nil || val == nil
+}
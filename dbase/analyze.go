@@ -0,0 +1,273 @@
+package dbase
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ColumnProfile contains the observed values of a column and the suggested target type for a schema migration
+type ColumnProfile struct {
+	Column     *Column     // Analyzed column
+	Rows       uint32      // Number of analyzed rows
+	Empty      uint32      // Number of nil or blank values
+	MinLength  int         // Minimum length of the non-empty text values (trailing spaces trimmed)
+	MaxLength  int         // Maximum length of the text values (trailing spaces trimmed)
+	Min        float64     // Minimum numeric value
+	Max        float64     // Maximum numeric value
+	Decimals   int         // Maximum number of observed decimal places of numeric values
+	Suggestion string      // Suggested SQL type, e.g. "INTEGER", "VARCHAR(11)" or "DATE"
+	Reason     string      // Explanation of the suggestion
+	Constant   bool        // True if all rows contain the same value, empty values are equal to each other
+	Value      interface{} // The value of the first row, the value of all rows if Constant is set
+
+	numeric  bool // Numeric values were observed
+	integer  bool // All numeric values are integers
+	dates    bool // All non-empty text values are dates
+	numbers  bool // All non-empty text values are numbers
+	bools    bool // All non-empty text values are logical values
+	midnight bool // All time values are at midnight
+	text     int  // Number of non-empty text values
+}
+
+// Nullable returns true if empty values were observed
+func (p *ColumnProfile) Nullable() bool {
+	return p.Empty > 0
+}
+
+// Unused returns true if the column is empty or contains the same value in all analyzed rows
+func (p *ColumnProfile) Unused() bool {
+	return p.Empty == p.Rows || p.Constant
+}
+
+// UnusedColumns analyzes the table and returns the profiles of the columns that are empty or constant across all rows.
+// If the profiles of Analyze are already available, filter them with ColumnProfile.Unused instead to avoid another scan.
+func (file *File) UnusedColumns(skipDeleted bool) ([]*ColumnProfile, error) {
+	profiles, err := file.Analyze(skipDeleted)
+	if err != nil {
+		return nil, newError("dbase-analyze-unusedcolumns-1", err)
+	}
+	unused := make([]*ColumnProfile, 0)
+	for _, profile := range profiles {
+		if profile.Unused() {
+			unused = append(unused, profile)
+		}
+	}
+	return unused, nil
+}
+
+// textDateLayouts are the layouts used to detect dates stored in text columns
+var textDateLayouts = []string{"2006-01-02", "20060102", "02.01.2006", "01/02/2006", "2006/01/02", time.RFC3339}
+
+// Analyze scans all rows of the table and suggests the tightest SQL type per column that fits all observed values.
+// The internal row pointer is restored after the scan.
+func (file *File) Analyze(skipDeleted bool) ([]*ColumnProfile, error) {
+	profiles := make([]*ColumnProfile, len(file.table.columns))
+	for i, column := range file.table.columns {
+		profiles[i] = &ColumnProfile{
+			Column:   column,
+			Min:      math.Inf(1),
+			Max:      math.Inf(-1),
+			integer:  true,
+			dates:    true,
+			numbers:  true,
+			bools:    true,
+			midnight: true,
+		}
+	}
+	err := file.forEachRow(skipDeleted, func(row *Row) error {
+		for i, field := range row.fields {
+			profiles[i].observe(field.value)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, newError("dbase-analyze-analyze-1", err)
+	}
+	for _, profile := range profiles {
+		profile.suggest()
+		if !profile.numeric {
+			profile.Min, profile.Max = 0, 0
+		}
+	}
+	return profiles, nil
+}
+
+// observe adds a value to the profile
+func (p *ColumnProfile) observe(val interface{}) {
+	p.Rows++
+	if p.Rows == 1 {
+		p.Value, p.Constant = val, true
+	} else if p.Constant && !Equal(p.Value, val, p.Column) {
+		p.Constant = false
+	}
+	switch v := val.(type) {
+	case nil:
+		p.Empty++
+	case string:
+		p.observeText(strings.TrimRight(v, " \x00"))
+	case []byte:
+		if len(v) == 0 {
+			p.Empty++
+		}
+		if len(v) > p.MaxLength {
+			p.MaxLength = len(v)
+		}
+	case time.Time:
+		if v.IsZero() {
+			p.Empty++
+			return
+		}
+		if v.Hour() != 0 || v.Minute() != 0 || v.Second() != 0 || v.Nanosecond() != 0 {
+			p.midnight = false
+		}
+	case bool:
+	default:
+		if f, ok := toFloat(v); ok {
+			p.observeNumber(f, strconv.FormatFloat(f, 'f', -1, 64))
+		}
+	}
+}
+
+// observeText adds a text value with trailing spaces removed to the profile
+func (p *ColumnProfile) observeText(s string) {
+	length := len([]rune(s))
+	if length > p.MaxLength {
+		p.MaxLength = length
+	}
+	trimmed := strings.TrimSpace(s)
+	if len(trimmed) == 0 {
+		p.Empty++
+		return
+	}
+	if p.text == 0 || length < p.MinLength {
+		p.MinLength = length
+	}
+	p.text++
+	if p.dates && !isTextDate(trimmed) {
+		p.dates = false
+	}
+	if p.bools {
+		switch strings.ToUpper(trimmed) {
+		case "T", "F", "Y", "N", "TRUE", "FALSE", "YES", "NO":
+		default:
+			p.bools = false
+		}
+	}
+	if p.numbers {
+		f, err := strconv.ParseFloat(trimmed, 64)
+		if err != nil {
+			p.numbers = false
+			return
+		}
+		p.observeNumber(f, trimmed)
+	}
+}
+
+// observeNumber adds a number and its text representation to the profile
+func (p *ColumnProfile) observeNumber(f float64, s string) {
+	p.numeric = true
+	if f < p.Min {
+		p.Min = f
+	}
+	if f > p.Max {
+		p.Max = f
+	}
+	if f != math.Trunc(f) {
+		p.integer = false
+	}
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		decimals := len(strings.TrimRight(s[i+1:], "0"))
+		if decimals > p.Decimals {
+			p.Decimals = decimals
+		}
+	}
+}
+
+// suggest determines the suggested type from the observed values
+func (p *ColumnProfile) suggest() {
+	definition := p.definition()
+	switch DataType(p.Column.DataType) {
+	case Character, Varchar:
+		p.suggestText(definition)
+	case Memo:
+		p.Suggestion, p.Reason = "TEXT", fmt.Sprintf("%s max observed length %d", definition, p.MaxLength)
+	case Numeric, Float, Double, Currency, Integer:
+		p.suggestNumber(definition)
+	case Date:
+		p.Suggestion, p.Reason = "DATE", definition
+	case DateTime:
+		if p.midnight && p.Rows > p.Empty {
+			p.Suggestion, p.Reason = "DATE", fmt.Sprintf("%s contains only dates without time", definition)
+			return
+		}
+		p.Suggestion, p.Reason = "TIMESTAMP", definition
+	case Logical:
+		p.Suggestion, p.Reason = "BOOLEAN", definition
+	default:
+		p.Suggestion, p.Reason = "BLOB", fmt.Sprintf("%s max observed length %d", definition, p.MaxLength)
+	}
+}
+
+// suggestText suggests a type for C and V columns
+func (p *ColumnProfile) suggestText(definition string) {
+	switch {
+	case p.text == 0:
+		p.Suggestion, p.Reason = fmt.Sprintf("VARCHAR(%d)", p.Column.Length), fmt.Sprintf("%s contains no values", definition)
+	case p.dates:
+		p.Suggestion, p.Reason = "DATE", fmt.Sprintf("%s column contains only dates", definition)
+	case p.numbers && p.numeric:
+		p.suggestNumber(definition + " column contains only numbers,")
+	case p.bools:
+		p.Suggestion, p.Reason = "BOOLEAN", fmt.Sprintf("%s column contains only logical values", definition)
+	case p.MinLength == p.MaxLength && p.MaxLength == int(p.Column.Length):
+		p.Suggestion, p.Reason = fmt.Sprintf("CHAR(%d)", p.MaxLength), fmt.Sprintf("%s all values use the full length", definition)
+	default:
+		p.Suggestion, p.Reason = fmt.Sprintf("VARCHAR(%d)", p.MaxLength), fmt.Sprintf("%s max observed length %d", definition, p.MaxLength)
+	}
+}
+
+// suggestNumber suggests the smallest numeric type fitting the observed range
+func (p *ColumnProfile) suggestNumber(definition string) {
+	if !p.numeric {
+		p.Suggestion, p.Reason = "INTEGER", fmt.Sprintf("%s contains no values", definition)
+		return
+	}
+	if p.integer {
+		switch {
+		case p.Min >= math.MinInt16 && p.Max <= math.MaxInt16:
+			p.Suggestion, p.Reason = "SMALLINT", fmt.Sprintf("%s always fits int16", definition)
+		case p.Min >= math.MinInt32 && p.Max <= math.MaxInt32:
+			p.Suggestion, p.Reason = "INTEGER", fmt.Sprintf("%s always fits int32", definition)
+		default:
+			p.Suggestion, p.Reason = "BIGINT", fmt.Sprintf("%s always fits int64", definition)
+		}
+		return
+	}
+	digits := len(strconv.FormatFloat(math.Trunc(math.Max(math.Abs(p.Min), math.Abs(p.Max))), 'f', 0, 64))
+	p.Suggestion = fmt.Sprintf("DECIMAL(%d,%d)", digits+p.Decimals, p.Decimals)
+	p.Reason = fmt.Sprintf("%s max observed %d integer digits and %d decimals", definition, digits, p.Decimals)
+}
+
+// definition returns the column definition in dBase notation, e.g. N(10,2)
+func (p *ColumnProfile) definition() string {
+	switch DataType(p.Column.DataType) {
+	case Numeric, Float:
+		return fmt.Sprintf("%s(%d,%d)", p.Column.Type(), p.Column.Length, p.Column.Decimals)
+	case Character, Varchar:
+		return fmt.Sprintf("%s(%d)", p.Column.Type(), p.Column.Length)
+	}
+	return p.Column.Type()
+}
+
+// isTextDate returns true if the text is a date in one of the known layouts
+func isTextDate(s string) bool {
+	for _, layout := range textDateLayouts {
+		if _, err := time.Parse(layout, s); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,447 @@
+package dbase
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DBZ is the file extension of the compressed archive format
+const DBZ FileExtension = ".DBZ"
+
+// archiveMagic identifies the archive format and version
+var archiveMagic = [4]byte{'D', 'B', 'Z', '1'}
+
+// maxDeflateRatio is the highest ratio of decompressed to compressed bytes of a deflate stream,
+// sizes in the header of an archive beyond this ratio of the archive size are invalid
+const maxDeflateRatio = 1032
+
+// DefaultMaxArchiveSize is the maximum size in bytes of the table and memo data of an archive if Config.MaxArchiveSize is not set.
+// Restore uses it as well, as the data is restored in memory before it is written.
+const DefaultMaxArchiveSize = 1 << 30
+
+// archiveHeader is the fixed size header of an archive
+type archiveHeader struct {
+	Magic      [4]byte // Format identifier
+	TableSize  uint64  // Size of the original DBF file
+	TableCRC   uint32  // CRC32 checksum of the original DBF file
+	MemoSize   uint64  // Size of the original memo file
+	MemoCRC    uint32  // CRC32 checksum of the original memo file
+	FirstRow   uint16  // Position of the first row in the DBF file
+	RowLength  uint16  // Length of one row
+	RowsCount  uint32  // Number of rows stored in the archive
+	Boundaries uint16  // Number of column boundaries
+}
+
+// Archive writes the table (and memo file) in the compressed archive format to out.
+// The header and column descriptors are stored as is, the rows are split into their columns and
+// each column is compressed on its own, which compresses much better than row oriented data.
+// The archive can be read with OpenArchive and restored to the original files with Restore.
+func Archive(file *File, out io.Writer) error {
+	tableSize, err := file.Size(false)
+	if err != nil {
+		return newError("dbase-archive-archive-1", err)
+	}
+	table, err := file.ReadRaw(false, 0, int(tableSize))
+	if err != nil {
+		return newError("dbase-archive-archive-2", err)
+	}
+	memo := make([]byte, 0)
+	if file.memoHeader != nil {
+		memoSize, err := file.Size(true)
+		if err != nil {
+			return newError("dbase-archive-archive-3", err)
+		}
+		memo, err = file.ReadRaw(true, 0, int(memoSize))
+		if err != nil {
+			return newError("dbase-archive-archive-4", err)
+		}
+	}
+	header := archiveHeader{
+		Magic:     archiveMagic,
+		TableSize: uint64(len(table)),
+		TableCRC:  crc32.ChecksumIEEE(table),
+		MemoSize:  uint64(len(memo)),
+		MemoCRC:   crc32.ChecksumIEEE(memo),
+		FirstRow:  file.header.FirstRow,
+		RowLength: file.header.RowLength,
+	}
+	first := int(header.FirstRow)
+	if first > len(table) {
+		return newError("dbase-archive-archive-5", fmt.Errorf("first row %d is behind the end of the file (%d bytes)", first, len(table)))
+	}
+	// Only complete rows are split into columns, everything after them is stored as trailer
+	rows := 0
+	if header.RowLength > 0 {
+		rows = (len(table) - first) / int(header.RowLength)
+		if uint32(rows) > file.header.RowsCount {
+			rows = int(file.header.RowsCount)
+		}
+	}
+	header.RowsCount = uint32(rows)
+	boundaries := file.columnBoundaries()
+	header.Boundaries = uint16(len(boundaries))
+	debugf("Archiving table with %d rows in %d column segments", rows, len(boundaries)-1)
+	writer := bufio.NewWriter(out)
+	err = binary.Write(writer, binary.LittleEndian, header)
+	if err != nil {
+		return newError("dbase-archive-archive-6", err)
+	}
+	err = binary.Write(writer, binary.LittleEndian, boundaries)
+	if err != nil {
+		return newError("dbase-archive-archive-7", err)
+	}
+	// Header and column descriptors
+	err = writeArchiveSection(writer, table[:first])
+	if err != nil {
+		return newError("dbase-archive-archive-8", err)
+	}
+	// One section per column segment
+	data := table[first : first+rows*int(header.RowLength)]
+	for i := 0; i < len(boundaries)-1; i++ {
+		start, end := int(boundaries[i]), int(boundaries[i+1])
+		segment := make([]byte, 0, rows*(end-start))
+		for row := 0; row < rows; row++ {
+			offset := row * int(header.RowLength)
+			segment = append(segment, data[offset+start:offset+end]...)
+		}
+		err = writeArchiveSection(writer, segment)
+		if err != nil {
+			return newError("dbase-archive-archive-9", err)
+		}
+	}
+	// Trailer (end of file marker and anything else after the rows)
+	err = writeArchiveSection(writer, table[first+rows*int(header.RowLength):])
+	if err != nil {
+		return newError("dbase-archive-archive-10", err)
+	}
+	err = writeArchiveSection(writer, memo)
+	if err != nil {
+		return newError("dbase-archive-archive-11", err)
+	}
+	err = writer.Flush()
+	if err != nil {
+		return newError("dbase-archive-archive-12", err)
+	}
+	return nil
+}
+
+// OpenArchive opens a compressed archive in memory and returns it as read-only table.
+// The rows can be read with the same API as regular tables. The config is copied, Filename, ReadOnly and IO are set for the archive.
+// Archives whose table and memo data exceed Config.MaxArchiveSize fail with ErrMemoryLimit before the data is decompressed.
+func OpenArchive(path string, config *Config) (*File, error) {
+	archiveConfig := Config{}
+	if config != nil {
+		archiveConfig = *config
+	}
+	maxSize := archiveConfig.MaxArchiveSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxArchiveSize
+	}
+	handle, err := os.Open(path)
+	if err != nil {
+		return nil, newError("dbase-archive-openarchive-1", err)
+	}
+	defer handle.Close()
+	info, err := handle.Stat()
+	if err != nil {
+		return nil, newError("dbase-archive-openarchive-4", err)
+	}
+	table, memo, err := readArchive(handle, info.Size(), maxSize)
+	if err != nil {
+		return nil, newError("dbase-archive-openarchive-2", err)
+	}
+	archiveConfig.Filename = path
+	archiveConfig.ReadOnly = true
+	generic := GenericIO{Handle: &memoryFile{data: table}}
+	if len(memo) > 0 {
+		generic.RelatedHandle = &memoryFile{data: memo}
+	}
+	archiveConfig.IO = generic
+	debugf("Opening archive: %s", path)
+	file, err := OpenTable(&archiveConfig)
+	if err != nil {
+		return nil, newError("dbase-archive-openarchive-3", err)
+	}
+	return file, nil
+}
+
+// Restore regenerates the original DBF file (and memo file) from an archive.
+// The memo file is written next to the DBF file using the FPT (or DCT for databases) extension.
+// Existing files are not overwritten. The files are written to temporary files next to the targets, read back
+// and verified against the checksums of the original files stored in the archive. Only if all files match they are
+// renamed to the targets, the DBF file last, so a failed restore leaves no partial files behind.
+// Archives whose table and memo data exceed DefaultMaxArchiveSize fail with ErrMemoryLimit.
+func Restore(archivePath string, outDBF string) error {
+	handle, err := os.Open(archivePath)
+	if err != nil {
+		return newError("dbase-archive-restore-1", err)
+	}
+	defer handle.Close()
+	info, err := handle.Stat()
+	if err != nil {
+		return newError("dbase-archive-restore-7", err)
+	}
+	table, memo, err := readArchive(handle, info.Size(), DefaultMaxArchiveSize)
+	if err != nil {
+		return newError("dbase-archive-restore-2", err)
+	}
+	targets := []string{outDBF}
+	contents := [][]byte{table}
+	if len(memo) > 0 {
+		ext := FPT
+		if FileExtension(strings.ToUpper(filepath.Ext(outDBF))) == DBC {
+			ext = DCT
+		}
+		targets = append(targets, strings.TrimSuffix(outDBF, filepath.Ext(outDBF))+string(ext))
+		contents = append(contents, memo)
+	}
+	for _, target := range targets {
+		if _, err := os.Stat(target); err == nil {
+			return newError("dbase-archive-restore-3", fmt.Errorf("file %v already exists", target))
+		}
+	}
+	temps := make([]string, 0, len(targets))
+	defer func() {
+		for _, tmp := range temps {
+			os.Remove(tmp)
+		}
+	}()
+	for i, target := range targets {
+		debugf("Restoring %d bytes to %v", len(contents[i]), target)
+		tmp, _, _, err := copyToTemp(target, bytes.NewReader(contents[i]))
+		if err != nil {
+			return newError("dbase-archive-restore-4", err)
+		}
+		temps = append(temps, tmp)
+		written, err := os.ReadFile(tmp)
+		if err != nil {
+			return newError("dbase-archive-restore-5", err)
+		}
+		if crc32.ChecksumIEEE(written) != crc32.ChecksumIEEE(contents[i]) {
+			return newError("dbase-archive-restore-6", fmt.Errorf("verification of restored file %v failed", target))
+		}
+	}
+	for i := len(targets) - 1; i >= 0; i-- {
+		err = os.Rename(temps[i], targets[i])
+		if err != nil {
+			return newError("dbase-archive-restore-8", err)
+		}
+	}
+	return nil
+}
+
+// readArchive decompresses an archive of the size in bytes and returns the original table and memo file data.
+// The sizes of the header are checked against the size of the archive and maxSize before buffers are allocated.
+func readArchive(r io.Reader, size int64, maxSize int64) ([]byte, []byte, error) {
+	reader := bufio.NewReader(r)
+	header := archiveHeader{}
+	err := binary.Read(reader, binary.LittleEndian, &header)
+	if err != nil {
+		return nil, nil, newError("dbase-archive-readarchive-1", err)
+	}
+	if header.Magic != archiveMagic {
+		return nil, nil, newError("dbase-archive-readarchive-2", fmt.Errorf("invalid archive format %q", header.Magic[:]))
+	}
+	limit := uint64(size) * maxDeflateRatio
+	if limit > uint64(^uint(0)>>1) {
+		limit = uint64(^uint(0) >> 1)
+	}
+	if header.TableSize > limit || header.MemoSize > limit {
+		return nil, nil, newError("dbase-archive-readarchive-13", fmt.Errorf("sizes of %d and %d bytes can not be stored in an archive of %d bytes", header.TableSize, header.MemoSize, size))
+	}
+	if header.TableSize+header.MemoSize > uint64(maxSize) {
+		return nil, nil, newError("dbase-archive-readarchive-15", fmt.Errorf("%w, the archive contains %d bytes of table and memo data, more than the maximum of %d bytes", ErrMemoryLimit, header.TableSize+header.MemoSize, maxSize))
+	}
+	if uint64(header.FirstRow)+uint64(header.RowsCount)*uint64(header.RowLength) > header.TableSize {
+		return nil, nil, newError("dbase-archive-readarchive-14", fmt.Errorf("%d rows of %d bytes exceed the table size of %d bytes", header.RowsCount, header.RowLength, header.TableSize))
+	}
+	boundaries := make([]uint16, header.Boundaries)
+	err = binary.Read(reader, binary.LittleEndian, boundaries)
+	if err != nil {
+		return nil, nil, newError("dbase-archive-readarchive-3", err)
+	}
+	if len(boundaries) < 2 || boundaries[0] != 0 || boundaries[len(boundaries)-1] != header.RowLength {
+		return nil, nil, newError("dbase-archive-readarchive-4", fmt.Errorf("invalid column boundaries"))
+	}
+	head, err := readArchiveSection(reader, int64(header.FirstRow))
+	if err != nil {
+		return nil, nil, newError("dbase-archive-readarchive-5", err)
+	}
+	rows := int(header.RowsCount)
+	table := make([]byte, 0, header.TableSize)
+	table = append(table, head...)
+	data := make([]byte, rows*int(header.RowLength))
+	for i := 0; i < len(boundaries)-1; i++ {
+		start, end := int(boundaries[i]), int(boundaries[i+1])
+		if end < start {
+			return nil, nil, newError("dbase-archive-readarchive-6", fmt.Errorf("invalid column boundaries"))
+		}
+		segment, err := readArchiveSection(reader, int64(rows*(end-start)))
+		if err != nil {
+			return nil, nil, newError("dbase-archive-readarchive-7", err)
+		}
+		if len(segment) != rows*(end-start) {
+			return nil, nil, newError("dbase-archive-readarchive-8", fmt.Errorf("%w, column segment %d", ErrIncomplete, i))
+		}
+		for row := 0; row < rows; row++ {
+			copy(data[row*int(header.RowLength)+start:row*int(header.RowLength)+end], segment[row*(end-start):(row+1)*(end-start)])
+		}
+	}
+	table = append(table, data...)
+	trailer, err := readArchiveSection(reader, int64(header.TableSize)-int64(len(table)))
+	if err != nil {
+		return nil, nil, newError("dbase-archive-readarchive-9", err)
+	}
+	table = append(table, trailer...)
+	memo, err := readArchiveSection(reader, int64(header.MemoSize))
+	if err != nil {
+		return nil, nil, newError("dbase-archive-readarchive-10", err)
+	}
+	if uint64(len(table)) != header.TableSize || crc32.ChecksumIEEE(table) != header.TableCRC {
+		return nil, nil, newError("dbase-archive-readarchive-11", fmt.Errorf("checksum mismatch of the restored table data"))
+	}
+	if uint64(len(memo)) != header.MemoSize || crc32.ChecksumIEEE(memo) != header.MemoCRC {
+		return nil, nil, newError("dbase-archive-readarchive-12", fmt.Errorf("checksum mismatch of the restored memo data"))
+	}
+	return table, memo, nil
+}
+
+// columnBoundaries returns the sorted byte offsets within a row where a column starts or ends.
+// Bytes that do not belong to any column (like the deleted flag) end up in their own segment.
+func (file *File) columnBoundaries() []uint16 {
+	length := file.header.RowLength
+	set := map[uint16]bool{0: true, length: true}
+	if length > 0 {
+		set[1] = true
+	}
+	offsets := file.offsets()
+	for i, column := range file.rowColumns() {
+		start := offsets[i]
+		end := start + uint32(column.Length)
+		if end > uint32(length) {
+			continue
+		}
+		set[uint16(start)] = true
+		set[uint16(end)] = true
+	}
+	boundaries := make([]uint16, 0, len(set))
+	for boundary := range set {
+		boundaries = append(boundaries, boundary)
+	}
+	sort.Slice(boundaries, func(i, j int) bool { return boundaries[i] < boundaries[j] })
+	return boundaries
+}
+
+// writeArchiveSection writes the length of the compressed data followed by the compressed data
+func writeArchiveSection(w io.Writer, data []byte) error {
+	buf := new(bytes.Buffer)
+	compressor, err := flate.NewWriter(buf, flate.BestCompression)
+	if err != nil {
+		return newError("dbase-archive-writearchivesection-1", err)
+	}
+	_, err = compressor.Write(data)
+	if err != nil {
+		return newError("dbase-archive-writearchivesection-2", err)
+	}
+	err = compressor.Close()
+	if err != nil {
+		return newError("dbase-archive-writearchivesection-3", err)
+	}
+	err = binary.Write(w, binary.LittleEndian, uint64(buf.Len()))
+	if err != nil {
+		return newError("dbase-archive-writearchivesection-4", err)
+	}
+	_, err = w.Write(buf.Bytes())
+	if err != nil {
+		return newError("dbase-archive-writearchivesection-5", err)
+	}
+	return nil
+}
+
+// readArchiveSection reads and decompresses one section, limiting the decompressed size to limit bytes
+func readArchiveSection(r io.Reader, limit int64) ([]byte, error) {
+	var length uint64
+	err := binary.Read(r, binary.LittleEndian, &length)
+	if err != nil {
+		return nil, newError("dbase-archive-readarchivesection-1", err)
+	}
+	if limit < 0 {
+		return nil, newError("dbase-archive-readarchivesection-2", fmt.Errorf("invalid section size"))
+	}
+	decompressor := flate.NewReader(io.LimitReader(r, int64(length)))
+	defer decompressor.Close()
+	data, err := io.ReadAll(io.LimitReader(decompressor, limit+1))
+	if err != nil {
+		return nil, newError("dbase-archive-readarchivesection-3", err)
+	}
+	if int64(len(data)) > limit {
+		return nil, newError("dbase-archive-readarchivesection-4", fmt.Errorf("section exceeds the expected size of %d bytes", limit))
+	}
+	return data, nil
+}
+
+// memoryFile is an in memory io.ReadWriteSeeker used to open archives with the GenericIO
+type memoryFile struct {
+	data   []byte
+	offset int64
+}
+
+// Read reads from the current offset
+func (m *memoryFile) Read(p []byte) (int, error) {
+	if m.offset >= int64(len(m.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, m.data[m.offset:])
+	m.offset += int64(n)
+	return n, nil
+}
+
+// Write writes at the current offset and grows the data if needed
+func (m *memoryFile) Write(p []byte) (int, error) {
+	end := m.offset + int64(len(p))
+	if end > int64(len(m.data)) {
+		m.data = append(m.data, make([]byte, end-int64(len(m.data)))...)
+	}
+	n := copy(m.data[m.offset:], p)
+	m.offset += int64(n)
+	return n, nil
+}
+
+// Seek sets the offset for the next read or write
+func (m *memoryFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += m.offset
+	case io.SeekEnd:
+		offset += int64(len(m.data))
+	default:
+		return 0, fmt.Errorf("invalid whence %d", whence)
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("%w, negative offset %d", ErrInvalidPosition, offset)
+	}
+	m.offset = offset
+	return offset, nil
+}
+
+func (m *memoryFile) Truncate(size int64) error {
+	if size < 0 {
+		return fmt.Errorf("%w, negative size %d", ErrInvalidPosition, size)
+	}
+	if size > int64(len(m.data)) {
+		m.data = append(m.data, make([]byte, size-int64(len(m.data)))...)
+		return nil
+	}
+	m.data = m.data[:size]
+	return nil
+}
@@ -0,0 +1,47 @@
+package dbase
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestOpenArchive opens an archive without changing the configuration of the caller and limits the size of the archived data
+func TestOpenArchive(t *testing.T) {
+	path := copyCorpus(t, "TEST.DBF", "TEST.FPT")
+	file, err := OpenTable(&Config{Filename: path, ReadOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	archivePath := filepath.Join(t.TempDir(), "TEST.DBZ")
+	out, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = Archive(file, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.Close()
+	rows := file.RowsCount()
+	file.Close()
+
+	config := &Config{Filename: path, TrimSpaces: true}
+	archive, err := OpenArchive(archivePath, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archive.Close()
+	if archive.RowsCount() != rows {
+		t.Errorf("archive contains %d rows, expected %d", archive.RowsCount(), rows)
+	}
+	if config.Filename != path || config.ReadOnly || config.IO != nil {
+		t.Errorf("configuration of the caller was changed to %+v", config)
+	}
+
+	_, err = OpenArchive(archivePath, &Config{MaxArchiveSize: 512})
+	if !errors.Is(err, ErrMemoryLimit) {
+		t.Errorf("expected ErrMemoryLimit for an archive beyond the maximum size, got %v", err)
+	}
+}
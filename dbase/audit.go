@@ -0,0 +1,121 @@
+package dbase
+
+import (
+	"sort"
+	"sync"
+)
+
+// LengthAudit tracks the maximum length of the values written to C, N, F, V and Q columns,
+// so columns that are about to overflow can be found before values are cut off.
+// Values longer than the column are counted as overflows, as they are truncated when written.
+type LengthAudit struct {
+	mutex   *sync.Mutex               // Mutex to protect the audit
+	columns map[*Column]*ColumnLength // Lengths by column
+}
+
+// ColumnLength contains the written lengths of a column
+type ColumnLength struct {
+	Column    *Column // Audited column
+	Writes    uint32  // Number of written non-nil values
+	Max       int     // Maximum length in bytes of the written values, after encoding and without padding
+	Overflows uint32  // Number of written values longer than the column
+}
+
+// Usage returns the maximum written length relative to the column length, values above 1 overflowed
+func (c *ColumnLength) Usage() float64 {
+	if c.Column.Length == 0 {
+		return 0
+	}
+	return float64(c.Max) / float64(c.Column.Length)
+}
+
+// EnableLengthAudit starts tracking the lengths of the values written through this handle.
+// Returns the audit to create reports. The lengths are computed a second time for every written field while the audit is enabled.
+func (file *File) EnableLengthAudit() *LengthAudit {
+	debugf("Enabling length audit")
+	file.lengthAudit = &LengthAudit{
+		mutex:   &sync.Mutex{},
+		columns: make(map[*Column]*ColumnLength),
+	}
+	return file.lengthAudit
+}
+
+// DisableLengthAudit stops tracking the lengths of written values
+func (file *File) DisableLengthAudit() {
+	debugf("Disabling length audit")
+	file.lengthAudit = nil
+}
+
+// LengthAudit returns the length audit or nil if it is disabled
+func (file *File) LengthAudit() *LengthAudit {
+	return file.lengthAudit
+}
+
+// Report returns the audited columns with a usage of at least threshold (e.g. 0.9 for 90 percent of the column length),
+// sorted by descending usage. A threshold of 0 returns all columns written since the audit was enabled.
+func (a *LengthAudit) Report(threshold float64) []*ColumnLength {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	report := make([]*ColumnLength, 0, len(a.columns))
+	for _, length := range a.columns {
+		if length.Usage() >= threshold {
+			copied := *length
+			report = append(report, &copied)
+		}
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Usage() != report[j].Usage() {
+			return report[i].Usage() > report[j].Usage()
+		}
+		return report[i].Column.Name() < report[j].Column.Name()
+	})
+	return report
+}
+
+// Reset clears the tracked lengths
+func (a *LengthAudit) Reset() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.columns = make(map[*Column]*ColumnLength)
+}
+
+// observe records the length of the value of the field, values that can not be encoded are left to the write
+func (a *LengthAudit) observe(file *File, field *Field) {
+	if field.value == nil {
+		return
+	}
+	var raw []byte
+	var err error
+	switch DataType(field.column.DataType) {
+	case Character:
+		raw, err = file.getCharacterRepresentation(field, true)
+	case Numeric:
+		raw, err = file.getNumericRepresentation(field, true)
+	case Float:
+		raw, err = file.getFloatRepresentation(field, true)
+	case Varchar:
+		raw, err = file.getVarcharRepresentation(field)
+	case Varbinary:
+		raw, err = file.getVarbinaryRepresentation(field)
+	default:
+		return
+	}
+	if err != nil {
+		return
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	length, ok := a.columns[field.column]
+	if !ok {
+		length = &ColumnLength{Column: field.column}
+		a.columns[field.column] = length
+	}
+	length.Writes++
+	if len(raw) > length.Max {
+		length.Max = len(raw)
+	}
+	if len(raw) > int(field.column.Length) {
+		length.Overflows++
+		debugf("Value of %d bytes overflows column %s with %d bytes", len(raw), field.Name(), field.column.Length)
+	}
+}
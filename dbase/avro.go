@@ -0,0 +1,340 @@
+package dbase
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// avroMagic starts an Avro object container file
+var avroMagic = []byte{'O', 'b', 'j', 1}
+
+// Limits of a data block of the Avro container, a block is written when one of them is reached
+const (
+	avroBlockRows  = 4096
+	avroBlockBytes = 1 << 20
+)
+
+// avroSchema is the record schema of the exported columns
+type avroSchema struct {
+	Type   string       `json:"type"`
+	Name   string       `json:"name"`
+	Doc    string       `json:"doc,omitempty"`
+	Fields []*avroField `json:"fields"`
+}
+
+// avroField is a field of the record schema. The type is a union of null and the type of the column.
+type avroField struct {
+	Name    string          `json:"name"`
+	Type    []interface{}   `json:"type"`
+	Doc     string          `json:"doc,omitempty"`
+	Default json.RawMessage `json:"default"`
+	column  *Column
+}
+
+// avroLogicalType is a type annotated with a logical type
+type avroLogicalType struct {
+	Type        string `json:"type"`
+	LogicalType string `json:"logicalType"`
+	Precision   int    `json:"precision,omitempty"`
+	Scale       int    `json:"scale,omitempty"`
+}
+
+// newAvroSchema returns the record schema of the exported columns.
+// All fields are nullable, as empty fields can be decoded to nil (see NilPolicy) and dates can be empty.
+func newAvroSchema(schema *ExportSchema) *avroSchema {
+	record := &avroSchema{
+		Type:   "record",
+		Name:   schemaTypeName(schema.Name),
+		Doc:    fmt.Sprintf("Rows of the table %s", schema.Name),
+		Fields: make([]*avroField, 0, len(schema.Columns)),
+	}
+	names := schemaFieldNames(schema.Keys)
+	for i, column := range schema.Columns {
+		record.Fields = append(record.Fields, &avroField{
+			Name:    names[i],
+			Type:    []interface{}{"null", avroType(column)},
+			Doc:     column.String(),
+			Default: json.RawMessage("null"),
+			column:  column,
+		})
+	}
+	return record
+}
+
+// avroType returns the Avro type of the column. Dates use the date and date times the timestamp-millis logical type,
+// numeric columns with decimals and currency columns the decimal logical type with the digits of the column.
+func avroType(column *Column) interface{} {
+	switch DataType(column.DataType) {
+	case Integer:
+		return "int"
+	case Numeric:
+		if column.Decimals == 0 {
+			return "long"
+		}
+		// The length includes the decimal point
+		precision := int(column.Length) - 1
+		if precision < int(column.Decimals) {
+			precision = int(column.Decimals)
+		}
+		return &avroLogicalType{Type: "bytes", LogicalType: "decimal", Precision: precision, Scale: int(column.Decimals)}
+	case Currency:
+		// Currency values are stored as 64 bit integers with 4 decimals
+		return &avroLogicalType{Type: "bytes", LogicalType: "decimal", Precision: 19, Scale: 4}
+	case Float, Double:
+		return "double"
+	case Logical:
+		return "boolean"
+	case Date:
+		return &avroLogicalType{Type: "int", LogicalType: "date"}
+	case DateTime:
+		return &avroLogicalType{Type: "long", LogicalType: "timestamp-millis"}
+	case Varbinary, Blob, General, Picture:
+		return "bytes"
+	}
+	return "string"
+}
+
+// AvroSchema returns the Avro record schema of the exported columns of the table as JSON,
+// as written to the header of the container files of the "avro" exporter.
+func (file *File) AvroSchema() (string, error) {
+	data, err := json.Marshal(newAvroSchema(file.exportSchema()))
+	if err != nil {
+		return "", newError("dbase-avro-avroschema-1", err)
+	}
+	return string(data), nil
+}
+
+// avroExporter writes the rows as Avro object container file without compression.
+// The sync marker is derived from the schema, so exports of unchanged tables are byte-identical.
+type avroExporter struct {
+	writer *bufio.Writer
+	schema *avroSchema
+	fields map[string]*avroField
+	sync   []byte
+	block  []byte
+	rows   int64
+}
+
+func newAvroExporter(w io.Writer) *avroExporter {
+	return &avroExporter{writer: bufio.NewWriter(w)}
+}
+
+func (e *avroExporter) Begin(schema *ExportSchema) error {
+	e.schema = newAvroSchema(schema)
+	e.fields = make(map[string]*avroField, len(e.schema.Fields))
+	for i, field := range e.schema.Fields {
+		e.fields[schema.Keys[i]] = field
+	}
+	data, err := json.Marshal(e.schema)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	e.sync = sum[:16]
+	header := append([]byte{}, avroMagic...)
+	// The metadata is a map of bytes with a single block of entries
+	header = binary.AppendVarint(header, 2)
+	header = appendAvroBytes(header, []byte("avro.schema"))
+	header = appendAvroBytes(header, data)
+	header = appendAvroBytes(header, []byte("avro.codec"))
+	header = appendAvroBytes(header, []byte("null"))
+	header = binary.AppendVarint(header, 0)
+	header = append(header, e.sync...)
+	_, err = e.writer.Write(header)
+	return err
+}
+
+func (e *avroExporter) WriteRow(row *Row) error {
+	values, err := row.ExportValues()
+	if err != nil {
+		return err
+	}
+	for _, value := range values {
+		field, ok := e.fields[value.Key]
+		if !ok {
+			return fmt.Errorf("key %s is not part of the record %s", value.Key, e.schema.Name)
+		}
+		e.block, err = field.append(e.block, value.Value)
+		if err != nil {
+			return fmt.Errorf("encoding %s failed with error: %w", value.Key, err)
+		}
+	}
+	e.rows++
+	if e.rows >= avroBlockRows || len(e.block) >= avroBlockBytes {
+		return e.flush()
+	}
+	return nil
+}
+
+func (e *avroExporter) End() error {
+	err := e.flush()
+	if err != nil {
+		return err
+	}
+	return e.writer.Flush()
+}
+
+// flush writes the rows encoded so far as data block followed by the sync marker
+func (e *avroExporter) flush() error {
+	if e.rows == 0 {
+		return nil
+	}
+	header := binary.AppendVarint(nil, e.rows)
+	header = binary.AppendVarint(header, int64(len(e.block)))
+	_, err := e.writer.Write(header)
+	if err != nil {
+		return err
+	}
+	_, err = e.writer.Write(e.block)
+	if err != nil {
+		return err
+	}
+	_, err = e.writer.Write(e.sync)
+	if err != nil {
+		return err
+	}
+	e.block = e.block[:0]
+	e.rows = 0
+	return nil
+}
+
+// append appends the value encoded as union of null and the type of the field, zero times are written as null.
+// Values changed by modifications are converted to the type of the field if possible.
+func (field *avroField) append(buf []byte, val interface{}) ([]byte, error) {
+	if t, ok := val.(time.Time); val == nil || (ok && t.IsZero()) {
+		return binary.AppendVarint(buf, 0), nil
+	}
+	buf = binary.AppendVarint(buf, 1)
+	column := field.column
+	switch DataType(column.DataType) {
+	case Integer:
+		i, err := exportInt(val)
+		if err != nil {
+			return nil, err
+		}
+		if i < math.MinInt32 || i > math.MaxInt32 {
+			return nil, fmt.Errorf("value %d overflows int", i)
+		}
+		return binary.AppendVarint(buf, i), nil
+	case Numeric, Currency:
+		if column.Decimals == 0 && DataType(column.DataType) == Numeric {
+			i, err := exportInt(val)
+			if err != nil {
+				return nil, err
+			}
+			return binary.AppendVarint(buf, i), nil
+		}
+		scale := int(column.Decimals)
+		if DataType(column.DataType) == Currency {
+			scale = 4
+		}
+		unscaled, err := avroDecimal(val, scale)
+		if err != nil {
+			return nil, err
+		}
+		return appendAvroBytes(buf, unscaled), nil
+	case Float, Double:
+		f, err := exportFloat(val)
+		if err != nil {
+			return nil, err
+		}
+		return binary.LittleEndian.AppendUint64(buf, math.Float64bits(f)), nil
+	case Logical:
+		b, ok := val.(bool)
+		if !ok {
+			return nil, fmt.Errorf("invalid type %T for boolean", val)
+		}
+		if b {
+			return append(buf, 1), nil
+		}
+		return append(buf, 0), nil
+	case Date:
+		t, ok := val.(time.Time)
+		if !ok {
+			return nil, fmt.Errorf("invalid type %T for date", val)
+		}
+		// Dates have no time zone, the day is taken as it is and counted from the Unix epoch
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return binary.AppendVarint(buf, day.Unix()/86400), nil
+	case DateTime:
+		t, ok := val.(time.Time)
+		if !ok {
+			return nil, fmt.Errorf("invalid type %T for timestamp", val)
+		}
+		return binary.AppendVarint(buf, t.UnixMilli()), nil
+	case Varbinary, Blob, General, Picture:
+		switch v := val.(type) {
+		case []byte:
+			return appendAvroBytes(buf, v), nil
+		case string:
+			return appendAvroBytes(buf, []byte(v)), nil
+		}
+		return appendAvroBytes(buf, []byte(formatValue(val))), nil
+	}
+	switch v := val.(type) {
+	case string:
+		return appendAvroBytes(buf, []byte(v)), nil
+	case []byte:
+		return appendAvroBytes(buf, v), nil
+	}
+	return appendAvroBytes(buf, []byte(formatValue(val))), nil
+}
+
+// appendAvroBytes appends the length and the data as Avro bytes or string
+func appendAvroBytes(buf []byte, data []byte) []byte {
+	buf = binary.AppendVarint(buf, int64(len(data)))
+	return append(buf, data...)
+}
+
+// avroDecimal returns the unscaled value of the decimal as big-endian two's complement
+func avroDecimal(val interface{}, scale int) ([]byte, error) {
+	var text string
+	switch v := val.(type) {
+	case float64:
+		text = strconv.FormatFloat(v, 'f', scale, 64)
+	case int32:
+		text = strconv.FormatFloat(float64(v), 'f', scale, 64)
+	case int64:
+		text = strconv.FormatInt(v, 10) + "." + strings.Repeat("0", scale)
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return nil, err
+		}
+		text = strconv.FormatFloat(f, 'f', scale, 64)
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, err
+		}
+		text = strconv.FormatFloat(f, 'f', scale, 64)
+	default:
+		return nil, fmt.Errorf("invalid type %T for decimal", val)
+	}
+	unscaled, ok := new(big.Int).SetString(strings.Replace(text, ".", "", 1), 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid decimal %s", text)
+	}
+	if unscaled.Sign() >= 0 {
+		data := unscaled.Bytes()
+		if len(data) == 0 || data[0]&0x80 != 0 {
+			data = append([]byte{0}, data...)
+		}
+		return data, nil
+	}
+	// Two's complement of the smallest length n with -2^(8n-1) <= value, it has the sign bit set
+	length := len(unscaled.Bytes())
+	if new(big.Int).Neg(unscaled).Cmp(new(big.Int).Lsh(big.NewInt(1), uint(length*8-1))) > 0 {
+		length++
+	}
+	return new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), uint(length*8)), unscaled).Bytes(), nil
+}
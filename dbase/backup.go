@@ -0,0 +1,282 @@
+package dbase
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// BackupManifestName is the name of the manifest file, Backup writes it after all other files of the backup
+const BackupManifestName = "backup.json"
+
+// BackupManifest describes a backup written by Backup
+type BackupManifest struct {
+	Created   time.Time     `json:"created"`    // Time the backup was taken
+	Table     string        `json:"table"`      // Filename of the backed up table
+	RowsCount uint32        `json:"rows_count"` // Rows count of the backed up table
+	Files     []*BackupFile `json:"files"`      // Table, memo and index file, the table file first
+}
+
+// BackupFile is a file of a backup
+type BackupFile struct {
+	Name      string        `json:"name"`      // Name of the file in the backup directory
+	Extension FileExtension `json:"extension"` // Extension of the file, restored files get the name of the target with this extension
+	Size      int64         `json:"size"`      // Size of the file in bytes
+	CRC32     uint32        `json:"crc32"`     // IEEE checksum of the file
+}
+
+// backupExtensions are the extensions of the files of a backup, the table file first
+var backupExtensions = map[FileExtension]bool{DBF: true, DBC: true, FPT: true, CDX: true, DCT: true, DCX: true}
+
+// Backup copies the table, memo and index file into the directory and writes a manifest with their checksums.
+// Writes through this handle wait until the copy is complete, with Config.SharedWrite the headers of the table and
+// memo file are locked as well, so other applications do not write meanwhile. Like Snapshot the table is copied up to
+// the rows count of the header and the memo file after the table, so rows appended by other applications meanwhile
+// can not reference missing memo blocks. The files are streamed, so tables of any size can be backed up.
+// The index file is copied as is, as it is not maintained by this package, and skipped if the table was opened with Config.WithoutIndex.
+// The directory is created if needed, must not contain another backup and must not be the directory of the table,
+// where the copies would overwrite the table files.
+func (file *File) Backup(dir string) (*BackupManifest, error) {
+	manifestPath := filepath.Join(dir, BackupManifestName)
+	if _, err := os.Stat(manifestPath); err == nil {
+		return nil, newError("dbase-backup-backup-1", fmt.Errorf("directory %v already contains a backup", dir))
+	}
+	target, err := os.Stat(filepath.Clean(dir))
+	if err == nil {
+		source, err := os.Stat(filepath.Dir(file.config.Filename))
+		if err == nil && os.SameFile(source, target) {
+			return nil, newError("dbase-backup-backup-13", fmt.Errorf("directory %v is the directory of the table", dir))
+		}
+	}
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, newError("dbase-backup-backup-2", err)
+	}
+	file.writeMutex.Lock()
+	defer file.writeMutex.Unlock()
+	unlock, err := file.lockBackup()
+	if err != nil {
+		return nil, newError("dbase-backup-backup-3", err)
+	}
+	defer unlock()
+	header, err := file.currentHeader()
+	if err != nil {
+		return nil, newError("dbase-backup-backup-4", err)
+	}
+	base := filepath.Base(file.config.Filename)
+	extension := FileExtension(strings.ToUpper(filepath.Ext(base)))
+	stem := strings.TrimSuffix(base, filepath.Ext(base))
+	memoExtension, indexExtension := FPT, CDX
+	if extension != DBC {
+		extension = DBF
+	} else {
+		memoExtension, indexExtension = DCT, DCX
+	}
+	manifest := &BackupManifest{
+		Created:   time.Now(),
+		Table:     file.config.Filename,
+		RowsCount: header.RowsCount,
+		Files:     make([]*BackupFile, 0, 3),
+	}
+	end := int64(header.FirstRow) + int64(header.RowsCount)*int64(header.RowLength)
+	table := io.MultiReader(&rawReader{file: file, end: end}, bytes.NewReader([]byte{byte(EOFMarker)}))
+	err = manifest.add(dir, stem, extension, table)
+	if err != nil {
+		return nil, newError("dbase-backup-backup-5", err)
+	}
+	if file.relatedHandle != nil {
+		size, err := file.Size(true)
+		if err != nil {
+			return nil, newError("dbase-backup-backup-6", err)
+		}
+		err = manifest.add(dir, stem, memoExtension, &rawReader{file: file, related: true, end: size})
+		if err != nil {
+			return nil, newError("dbase-backup-backup-7", err)
+		}
+	}
+	if len(file.path) > 0 && !file.config.WithoutIndex {
+		// Index files written by CreateIndex have no structural flag
+		path, err := _findFile(strings.TrimSuffix(file.path, filepath.Ext(file.path)) + string(indexExtension))
+		if err != nil {
+			return nil, newError("dbase-backup-backup-8", err)
+		}
+		index, err := os.Open(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist) && !StructuralFlag.Defined(header.TableFlags):
+		case err != nil:
+			return nil, newError("dbase-backup-backup-9", err)
+		default:
+			err = manifest.add(dir, stem, indexExtension, index)
+			index.Close()
+			if err != nil {
+				return nil, newError("dbase-backup-backup-10", err)
+			}
+		}
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, newError("dbase-backup-backup-11", err)
+	}
+	err = writeFileAtomic(manifestPath, data)
+	if err != nil {
+		return nil, newError("dbase-backup-backup-12", err)
+	}
+	return manifest, nil
+}
+
+// lockBackup locks the headers of the table and memo file with Config.SharedWrite, like appending rows and writing memos
+func (file *File) lockBackup() (func(), error) {
+	if !file.config.SharedWrite {
+		return func() {}, nil
+	}
+	unlockTable, err := file.lockInternal(sharedLock{offset: sharedLockOffset})
+	if err != nil {
+		return nil, err
+	}
+	if file.relatedHandle == nil {
+		return func() { unlockTable() }, nil
+	}
+	unlockMemo, err := file.lockInternal(sharedLock{related: true, offset: sharedLockOffset})
+	if err != nil {
+		unlockTable()
+		return nil, err
+	}
+	return func() {
+		unlockMemo()
+		unlockTable()
+	}, nil
+}
+
+// add copies the file into the backup directory and adds it to the manifest
+func (manifest *BackupManifest) add(dir string, stem string, ext FileExtension, src io.Reader) error {
+	name := stem + string(ext)
+	tmp, size, checksum, err := copyToTemp(filepath.Join(dir, name), src)
+	if err != nil {
+		return err
+	}
+	debugf("Backed up %d bytes to %v", size, filepath.Join(dir, name))
+	err = os.Rename(tmp, filepath.Join(dir, name))
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	manifest.Files = append(manifest.Files, &BackupFile{Name: name, Extension: ext, Size: size, CRC32: checksum})
+	return nil
+}
+
+// RestoreBackup restores the backup in the directory to the target table file.
+// The memo and index file are written next to the target with the extensions of the backup, existing files are not overwritten.
+// The files are copied to temporary files next to the targets and checked against the checksums of the manifest,
+// they are only renamed to the targets if all files match. The table file is renamed last,
+// so an interrupted restore does not leave a table without its memo file.
+func RestoreBackup(dir string, target string) error {
+	raw, err := os.ReadFile(filepath.Join(dir, BackupManifestName))
+	if err != nil {
+		return newError("dbase-backup-restorebackup-1", fmt.Errorf("reading the manifest of the backup failed with error: %w", err))
+	}
+	manifest := &BackupManifest{}
+	err = json.Unmarshal(raw, manifest)
+	if err != nil {
+		return newError("dbase-backup-restorebackup-2", err)
+	}
+	if len(manifest.Files) == 0 {
+		return newError("dbase-backup-restorebackup-3", fmt.Errorf("the backup in %v contains no files", dir))
+	}
+	stem := strings.TrimSuffix(target, filepath.Ext(target))
+	targets := make([]string, len(manifest.Files))
+	seen := make(map[FileExtension]bool, len(manifest.Files))
+	for i, backup := range manifest.Files {
+		table := backup.Extension == DBF || backup.Extension == DBC
+		if !backupExtensions[backup.Extension] || table != (i == 0) || seen[backup.Extension] {
+			return newError("dbase-backup-restorebackup-4", fmt.Errorf("backup file %v has the invalid extension %q", backup.Name, backup.Extension))
+		}
+		seen[backup.Extension] = true
+		targets[i] = stem + string(backup.Extension)
+		if i == 0 {
+			targets[i] = target
+		}
+		if _, err := os.Stat(targets[i]); err == nil {
+			return newError("dbase-backup-restorebackup-5", fmt.Errorf("file %v already exists", targets[i]))
+		}
+	}
+	temps := make([]string, 0, len(targets))
+	defer func() {
+		for _, tmp := range temps {
+			os.Remove(tmp)
+		}
+	}()
+	for i, backup := range manifest.Files {
+		src, err := os.Open(filepath.Join(dir, filepath.Base(backup.Name)))
+		if err != nil {
+			return newError("dbase-backup-restorebackup-6", err)
+		}
+		tmp, size, checksum, err := copyToTemp(targets[i], src)
+		src.Close()
+		if err != nil {
+			return newError("dbase-backup-restorebackup-7", err)
+		}
+		temps = append(temps, tmp)
+		if size != backup.Size || checksum != backup.CRC32 {
+			return newError("dbase-backup-restorebackup-8", fmt.Errorf("backup file %v does not match the checksum of the manifest", backup.Name))
+		}
+	}
+	for i := len(targets) - 1; i >= 0; i-- {
+		debugf("Restoring %v", targets[i])
+		err = os.Rename(temps[i], targets[i])
+		if err != nil {
+			return newError("dbase-backup-restorebackup-9", err)
+		}
+	}
+	return nil
+}
+
+// copyToTemp copies the reader to a temporary file next to the path and returns its name, size and IEEE checksum.
+// The file is synced, the caller renames or removes it.
+func copyToTemp(path string, src io.Reader) (string, int64, uint32, error) {
+	tmp := path + ".tmp"
+	handle, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	checksum := crc32.NewIEEE()
+	size, err := io.Copy(io.MultiWriter(handle, checksum), src)
+	if err == nil {
+		err = handle.Sync()
+	}
+	if closeErr := handle.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return "", 0, 0, err
+	}
+	return tmp, size, checksum.Sum32(), nil
+}
+
+// rawReader reads the bytes of the table or memo file up to end through ReadRaw
+type rawReader struct {
+	file    *File
+	related bool
+	offset  int64
+	end     int64
+}
+
+func (r *rawReader) Read(p []byte) (int, error) {
+	if r.offset >= r.end {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > r.end-r.offset {
+		p = p[:r.end-r.offset]
+	}
+	data, err := r.file.ReadRaw(r.related, r.offset, len(p))
+	n := copy(p, data)
+	r.offset += int64(n)
+	return n, err
+}
@@ -0,0 +1,44 @@
+package dbase
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestBackupDirectory rejects backups into the directory of the table, also if the path is not the same string
+func TestBackupDirectory(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "BACKUP.DBF")
+	file := createSortTable(t, path, []string{"first", "second"})
+	defer file.Close()
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Mkdir(filepath.Join(dir, "sub"), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, target := range []string{dir, filepath.Join(dir, "sub", "..")} {
+		_, err = file.Backup(target)
+		if err == nil {
+			t.Errorf("backup into %v succeeded, expected an error for the directory of the table", target)
+		}
+	}
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(before) != string(after) {
+		t.Errorf("table file changed by the rejected backups")
+	}
+
+	manifest, err := file.Backup(filepath.Join(dir, "sub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.RowsCount != 2 || len(manifest.Files) != 1 {
+		t.Errorf("backup of %d rows in %d files, expected 2 rows in 1 file", manifest.RowsCount, len(manifest.Files))
+	}
+}
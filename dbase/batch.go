@@ -0,0 +1,132 @@
+package dbase
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BatchConfig configures the projection and filtering of StreamBatches
+type BatchConfig struct {
+	Columns     []string            // Columns to include in the given order, all columns if empty
+	Filter      func(row *Row) bool // Only rows for which the filter returns true are included, all rows if nil
+	Size        int                 // Number of rows per batch (default: 1000)
+	SkipDeleted bool                // If true deleted rows are skipped
+}
+
+// BatchCondition matches the value of a column in its textual representation used by ExportCSV
+type BatchCondition struct {
+	Column string // Name of the column
+	Value  string // Value as written by ExportCSV
+	Exact  bool   // If false the value only has to be contained in the field
+}
+
+// RowBatch is a batch of projected rows
+type RowBatch struct {
+	Columns   []string        // Names of the projected columns
+	Positions []uint32        // Positions of the rows in the table
+	Deleted   []bool          // Deleted flags of the rows
+	Values    [][]interface{} // Values of the projected columns per row
+}
+
+// StreamBatches reads all rows, applies the filter and projection and passes them in batches to fn.
+// The values are converted like in the exports: strings are trimmed and modifications are applied, hidden columns are nil.
+// This is the server side of streaming services, like the gRPC service defined in the proto directory.
+// The internal row pointer is restored afterwards.
+func (file *File) StreamBatches(config *BatchConfig, fn func(batch *RowBatch) error) error {
+	if config == nil {
+		config = &BatchConfig{}
+	}
+	size := config.Size
+	if size <= 0 {
+		size = 1000
+	}
+	columns := config.Columns
+	if len(columns) == 0 {
+		columns = file.ColumnNames()
+	}
+	positions := make([]int, len(columns))
+	for i, name := range columns {
+		positions[i] = file.ColumnPosByName(name)
+		if positions[i] < 0 {
+			return newError("dbase-batch-streambatches-1", fmt.Errorf("column '%s' not found", name))
+		}
+	}
+	newBatch := func() *RowBatch {
+		return &RowBatch{
+			Columns:   columns,
+			Positions: make([]uint32, 0, size),
+			Deleted:   make([]bool, 0, size),
+			Values:    make([][]interface{}, 0, size),
+		}
+	}
+	batch := newBatch()
+	err := file.forEachRow(config.SkipDeleted, func(row *Row) error {
+		if config.Filter != nil && !config.Filter(row) {
+			return nil
+		}
+		values := make([]interface{}, len(positions))
+		for i, pos := range positions {
+			value, err := row.exportValue(pos)
+			if err != nil {
+				return err
+			}
+			values[i] = value
+		}
+		batch.Positions = append(batch.Positions, row.Position)
+		batch.Deleted = append(batch.Deleted, row.Deleted)
+		batch.Values = append(batch.Values, values)
+		if len(batch.Values) < size {
+			return nil
+		}
+		err := fn(batch)
+		batch = newBatch()
+		return err
+	})
+	if err != nil {
+		return newError("dbase-batch-streambatches-2", err)
+	}
+	if len(batch.Values) > 0 {
+		err = fn(batch)
+		if err != nil {
+			return newError("dbase-batch-streambatches-3", err)
+		}
+	}
+	return nil
+}
+
+// ConditionFilter returns a filter for BatchConfig.Filter matching rows that satisfy all conditions,
+// so filters received as text, e.g. by the gRPC service defined in the proto directory, are applied on the server side
+func (file *File) ConditionFilter(conditions []BatchCondition) (func(row *Row) bool, error) {
+	positions := make([]int, len(conditions))
+	for i, condition := range conditions {
+		positions[i] = file.ColumnPosByName(condition.Column)
+		if positions[i] < 0 {
+			return nil, newError("dbase-batch-conditionfilter-1", fmt.Errorf("column '%s' not found", condition.Column))
+		}
+	}
+	return func(row *Row) bool {
+		for i, condition := range conditions {
+			val, err := row.exportValue(positions[i])
+			if err != nil {
+				return false
+			}
+			value := formatValue(row.handle.stableValue(row.fields[positions[i]].column, val))
+			if condition.Exact && value != condition.Value {
+				return false
+			}
+			if !condition.Exact && !strings.Contains(value, condition.Value) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// exportValue returns the value of the field at the position as converted by the exporters, nil if the column is hidden
+func (row *Row) exportValue(pos int) (interface{}, error) {
+	var value interface{}
+	err := row.modifiedValue(pos, func(field *Field, key string, val interface{}) {
+		value = val
+	})
+	return value, err
+}
@@ -0,0 +1,167 @@
+package dbase
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// bookmarkLength is the length of the decoded bookmark token
+const bookmarkLength = 17
+
+// Bookmark is an opaque token for a row position.
+// Pack records which rows it removed, so bookmarks created before a Pack of the same handle are moved to the new position of the row.
+// The string representation can be persisted to resume at the same row after a restart,
+// bookmarks of another handle are resolved by their position as the packs of that handle are unknown.
+type Bookmark struct {
+	position uint32 // Position of the row when the bookmark was created
+	eof      bool   // The bookmark points behind the last row
+	handle   uint64 // Identifier of the handle the bookmark was created with
+	packs    uint32 // Number of packs of the handle when the bookmark was created
+}
+
+// packHistory contains the mappings of the packs of a handle, used to resolve bookmarks created before a pack
+type packHistory struct {
+	mutex  sync.Mutex
+	handle uint64        // Random identifier of the handle, set by the first bookmark
+	packs  []packMapping // Mappings of the packs in the order they were made
+}
+
+// packMapping maps the row positions before a pack to the positions after it.
+// The kept rows keep their order, so a row moves forward by the number of removed rows before it.
+type packMapping struct {
+	removed    []uint32 // Positions of the removed rows in ascending order
+	incomplete bool     // The pack was interrupted or resumed from a checkpoint, the positions of the moved rows are unknown
+}
+
+// Bookmark returns a bookmark for the row at the internal row pointer
+func (file *File) Bookmark() (Bookmark, error) {
+	handle, packs, err := file.packs.current()
+	if err != nil {
+		return Bookmark{}, newError("dbase-bookmark-bookmark-1", err)
+	}
+	if file.EOF() {
+		return Bookmark{position: file.header.RowsCount, eof: true, handle: handle, packs: packs}, nil
+	}
+	return Bookmark{position: file.table.rowPointer, handle: handle, packs: packs}, nil
+}
+
+// GoToBookmark moves the internal row pointer to the bookmarked row.
+// Bookmarks created before a Pack of this handle are moved to the new position of the row,
+// if the row was removed by the pack or the pack was interrupted ErrInvalidPosition is returned.
+func (file *File) GoToBookmark(bookmark Bookmark) error {
+	if bookmark.eof {
+		debugf("Going to bookmarked end of file")
+		return file.GoTo(file.header.RowsCount)
+	}
+	position, err := file.packs.resolve(bookmark)
+	if err != nil {
+		return newError("dbase-bookmark-gotobookmark-1", err)
+	}
+	if position >= file.header.RowsCount {
+		return newError("dbase-bookmark-gotobookmark-2", fmt.Errorf("%w, bookmarked row %d is behind the last row %d", ErrInvalidPosition, position, file.header.RowsCount))
+	}
+	if position != bookmark.position {
+		debugf("Bookmarked row %d was moved to %d by a pack", bookmark.position, position)
+	}
+	return file.GoTo(position)
+}
+
+// current returns the identifier of the handle and the number of its packs
+func (history *packHistory) current() (uint64, uint32, error) {
+	history.mutex.Lock()
+	defer history.mutex.Unlock()
+	if history.handle == 0 {
+		buf := make([]byte, 8)
+		_, err := rand.Read(buf)
+		if err != nil {
+			return 0, 0, err
+		}
+		// 0 marks a handle without bookmarks
+		history.handle = binary.LittleEndian.Uint64(buf) | 1
+	}
+	return history.handle, uint32(len(history.packs)), nil
+}
+
+// record adds the mapping of a pack
+func (history *packHistory) record(mapping packMapping) {
+	history.mutex.Lock()
+	defer history.mutex.Unlock()
+	history.packs = append(history.packs, mapping)
+}
+
+// resolve maps the position of the bookmark through the packs made after it was created
+func (history *packHistory) resolve(bookmark Bookmark) (uint32, error) {
+	history.mutex.Lock()
+	defer history.mutex.Unlock()
+	if bookmark.handle != history.handle || int(bookmark.packs) > len(history.packs) {
+		return bookmark.position, nil
+	}
+	position := bookmark.position
+	for _, mapping := range history.packs[bookmark.packs:] {
+		if mapping.incomplete {
+			return 0, fmt.Errorf("%w, bookmarked row %d can not be found after an interrupted pack", ErrInvalidPosition, bookmark.position)
+		}
+		removed := sort.Search(len(mapping.removed), func(i int) bool { return mapping.removed[i] >= position })
+		if removed < len(mapping.removed) && mapping.removed[removed] == position {
+			return 0, fmt.Errorf("%w, bookmarked row %d was deleted and removed by a pack", ErrInvalidPosition, bookmark.position)
+		}
+		position -= uint32(removed)
+	}
+	return position, nil
+}
+
+// Position returns the row position the bookmark was created at
+func (b Bookmark) Position() uint32 {
+	return b.position
+}
+
+// String returns the bookmark as a token that can be persisted
+func (b Bookmark) String() string {
+	buf := make([]byte, bookmarkLength)
+	binary.LittleEndian.PutUint32(buf[:4], b.position)
+	if b.eof {
+		buf[4] = 1
+	}
+	binary.LittleEndian.PutUint64(buf[5:13], b.handle)
+	binary.LittleEndian.PutUint32(buf[13:], b.packs)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface
+func (b Bookmark) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface
+func (b *Bookmark) UnmarshalText(text []byte) error {
+	bookmark, err := ParseBookmark(string(text))
+	if err != nil {
+		return newError("dbase-bookmark-unmarshaltext-1", err)
+	}
+	*b = bookmark
+	return nil
+}
+
+// ParseBookmark parses a bookmark token as returned by Bookmark.String
+func ParseBookmark(token string) (Bookmark, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Bookmark{}, newError("dbase-bookmark-parsebookmark-1", err)
+	}
+	if len(buf) != bookmarkLength {
+		return Bookmark{}, newError("dbase-bookmark-parsebookmark-2", fmt.Errorf("invalid bookmark length %d", len(buf)))
+	}
+	if buf[4] > 1 {
+		return Bookmark{}, newError("dbase-bookmark-parsebookmark-3", fmt.Errorf("invalid bookmark flags 0x%02X", buf[4]))
+	}
+	return Bookmark{
+		position: binary.LittleEndian.Uint32(buf[:4]),
+		eof:      buf[4] == 1,
+		handle:   binary.LittleEndian.Uint64(buf[5:13]),
+		packs:    binary.LittleEndian.Uint32(buf[13:]),
+	}, nil
+}
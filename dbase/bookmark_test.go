@@ -0,0 +1,97 @@
+package dbase
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// TestBookmarkPack resolves bookmarks created before a pack, also for rows with the same content and edited rows
+func TestBookmarkPack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "BOOKMARK.DBF")
+	file := createSortTable(t, path, []string{"deleted", "same", "same", "edited", "last"})
+	defer file.Close()
+
+	bookmarks := make([]Bookmark, 0, 6)
+	for position := uint32(0); position <= file.RowsCount(); position++ {
+		err := file.GoTo(position)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bookmark, err := file.Bookmark()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Persisted bookmarks are resolved like the original ones
+		bookmark, err = ParseBookmark(bookmark.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		bookmarks = append(bookmarks, bookmark)
+	}
+	for _, position := range []uint32{0, 2} {
+		err := file.GoTo(position)
+		if err != nil {
+			t.Fatal(err)
+		}
+		row, err := file.Row()
+		if err != nil {
+			t.Fatal(err)
+		}
+		row.Deleted = true
+		err = row.Write()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := file.UpdateField(3, "NAME", "changed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = file.Pack(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		bookmark Bookmark
+		position uint32
+		removed  bool
+	}{
+		{"removed row", bookmarks[0], 0, true},
+		{"first of the same rows", bookmarks[1], 0, false},
+		{"removed second of the same rows", bookmarks[2], 0, true},
+		{"edited row", bookmarks[3], 1, false},
+		{"last row", bookmarks[4], 2, false},
+		{"end of file", bookmarks[5], 3, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := file.GoToBookmark(tt.bookmark)
+			if tt.removed {
+				if !errors.Is(err, ErrInvalidPosition) {
+					t.Fatalf("expected ErrInvalidPosition for a removed row, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if file.Pointer() != tt.position {
+				t.Errorf("bookmark of row %d resolved to %d, expected %d", tt.bookmark.Position(), file.Pointer(), tt.position)
+			}
+		})
+	}
+
+	// Another handle does not know the packs, the bookmarks are resolved by their position
+	other, err := OpenTable(&Config{Filename: path, ReadOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	err = other.GoToBookmark(bookmarks[1])
+	if err != nil || other.Pointer() != 1 {
+		t.Errorf("bookmark of another handle resolved to %d with error %v, expected its position 1", other.Pointer(), err)
+	}
+}
@@ -0,0 +1,54 @@
+package dbase
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// memoBlockHeader is the length of the header of a memo block (signature and data length)
+const memoBlockHeader = 8
+
+// rowOffset returns the offset of the row at the position in the table file
+func (file *File) rowOffset(position uint64) (int64, error) {
+	if position > math.MaxUint32 {
+		return 0, fmt.Errorf("%w, row %d exceeds the maximum number of rows", ErrInvalidPosition, position)
+	}
+	return int64(file.header.FirstRow) + int64(position)*int64(file.header.RowLength), nil
+}
+
+// binaryLength returns the number of bytes decoded from columns of binary types, 0 for types without a fixed length
+func binaryLength(dataType DataType) int {
+	switch dataType {
+	case Integer:
+		return 4
+	case Double, Currency:
+		return 8
+	}
+	return 0
+}
+
+// memoBlock returns the block number and the offset in the memo file of the address stored in a memo column.
+// Block 0 means the column has no memo.
+func (file *File) memoBlock(address []byte) (uint32, int64, error) {
+	if len(address) < 4 {
+		return 0, 0, fmt.Errorf("%w, memo address of %d bytes is too short", ErrInvalidMemo, len(address))
+	}
+	block := binary.LittleEndian.Uint32(address)
+	if block == 0 {
+		return 0, 0, nil
+	}
+	if file.memoHeader == nil || file.memoHeader.BlockSize == 0 {
+		return 0, 0, fmt.Errorf("%w, memo block %d can not be located without a block size", ErrInvalidMemo, block)
+	}
+	return block, int64(block) * int64(file.memoHeader.BlockSize), nil
+}
+
+// checkMemoRange returns ErrInvalidMemo if length bytes from the offset of the memo block exceed the memo file size.
+// Called before reading the block header and before allocating the data of the length given in the block header.
+func checkMemoRange(block uint32, offset int64, length int64, size int64) error {
+	if offset+length > size {
+		return fmt.Errorf("%w, memo block %d at offset %d with %d bytes exceeds the memo file size of %d bytes", ErrInvalidMemo, block, offset, length, size)
+	}
+	return nil
+}
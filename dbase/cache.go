@@ -0,0 +1,222 @@
+package dbase
+
+import (
+	"container/list"
+	"sync"
+)
+
+// RowCache is a least recently used cache of decoded rows keyed by the row position.
+// The cache is limited by the number of rows and by the estimated size of the cached rows in bytes.
+// Cached rows are invalidated when a row is written through the same file handle.
+// A cache created with NewRowCache and set as Config.RowCache is shared by the tables opened with the configuration,
+// the limits then apply to the rows of all tables together.
+type RowCache struct {
+	store   *rowStore             // Rows and limits, shared by the caches of the tables opened with the same Config.RowCache
+	OnEvict func(position uint32) // Called when a row of this table is evicted or invalidated
+}
+
+// rowStore holds the cached rows of one or more tables
+type rowStore struct {
+	mutex    *sync.Mutex                            // Mutex to protect the store
+	maxRows  int                                    // Maximum number of cached rows (0 = unlimited)
+	maxBytes int64                                  // Maximum estimated size of cached rows in bytes (0 = unlimited)
+	bytes    int64                                  // Current estimated size of cached rows in bytes
+	entries  map[*RowCache]map[uint32]*list.Element // Cached rows by table cache and position
+	order    *list.List                             // Cached rows of all tables ordered by last access, most recent first
+	hits     uint64                                 // Number of cache hits
+	misses   uint64                                 // Number of cache misses
+}
+
+// RowCacheStats contains the counters of the row cache, of all tables sharing the cache
+type RowCacheStats struct {
+	Rows   int    // Number of cached rows
+	Bytes  int64  // Estimated size of cached rows in bytes
+	Hits   uint64 // Number of cache hits
+	Misses uint64 // Number of cache misses
+}
+
+// cacheEntry is a cached row with its estimated size and the cache of its table
+type cacheEntry struct {
+	cache *RowCache
+	row   *Row
+	size  int64
+}
+
+// NewRowCache creates a row cache limited by maxRows and maxBytes (0 = unlimited) to be shared by tables with Config.RowCache.
+// Each table gets a cache of its own backed by the shared rows, see File.RowCache.
+func NewRowCache(maxRows int, maxBytes int64) *RowCache {
+	return &RowCache{
+		store: &rowStore{
+			mutex:    &sync.Mutex{},
+			maxRows:  maxRows,
+			maxBytes: maxBytes,
+			entries:  make(map[*RowCache]map[uint32]*list.Element),
+			order:    list.New(),
+		},
+	}
+}
+
+// share returns a new cache for a table backed by the rows and limits of the cache
+func (c *RowCache) share() *RowCache {
+	return &RowCache{store: c.store}
+}
+
+// EnableRowCache enables caching of decoded rows, limited by maxRows and maxBytes (0 = unlimited).
+// Returns the cache to allow inspection and registering an eviction hook.
+func (file *File) EnableRowCache(maxRows int, maxBytes int64) *RowCache {
+	debugf("Enabling row cache - max rows: %d - max bytes: %d", maxRows, maxBytes)
+	file.rowCache.Invalidate()
+	file.rowCache = NewRowCache(maxRows, maxBytes)
+	return file.rowCache
+}
+
+// DisableRowCache disables and clears the row cache
+func (file *File) DisableRowCache() {
+	debugf("Disabling row cache")
+	file.rowCache.Invalidate()
+	file.rowCache = nil
+}
+
+// RowCache returns the row cache or nil if caching is disabled
+func (file *File) RowCache() *RowCache {
+	return file.rowCache
+}
+
+// Invalidate removes the rows at the given positions from the cache.
+// If no position is given all rows of the table are removed, rows of other tables sharing the cache are kept.
+func (c *RowCache) Invalidate(positions ...uint32) {
+	if c == nil {
+		return
+	}
+	c.store.mutex.Lock()
+	entries := c.store.entries[c]
+	evicted := make([]*cacheEntry, 0, len(entries))
+	if len(positions) == 0 {
+		for _, element := range entries {
+			evicted = append(evicted, c.store.remove(element))
+		}
+	}
+	for _, position := range positions {
+		if element, ok := entries[position]; ok {
+			evicted = append(evicted, c.store.remove(element))
+		}
+	}
+	c.store.mutex.Unlock()
+	notifyEvicted(evicted)
+}
+
+// Stats returns the current counters of the cache, of all tables sharing the cache
+func (c *RowCache) Stats() RowCacheStats {
+	if c == nil {
+		return RowCacheStats{}
+	}
+	c.store.mutex.Lock()
+	defer c.store.mutex.Unlock()
+	return RowCacheStats{
+		Rows:   c.store.order.Len(),
+		Bytes:  c.store.bytes,
+		Hits:   c.store.hits,
+		Misses: c.store.misses,
+	}
+}
+
+// get returns a copy of the cached row at the given position
+func (c *RowCache) get(position uint32) (*Row, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.store.mutex.Lock()
+	defer c.store.mutex.Unlock()
+	element, ok := c.store.entries[c][position]
+	if !ok {
+		c.store.misses++
+		return nil, false
+	}
+	c.store.hits++
+	c.store.order.MoveToFront(element)
+	debugf("Row cache hit for row %d", position)
+	entry, _ := element.Value.(*cacheEntry)
+	return entry.row.copy(), true
+}
+
+// put stores a copy of the row in the cache and evicts the least recently used rows if a limit is exceeded
+func (c *RowCache) put(row *Row) {
+	if c == nil || row == nil {
+		return
+	}
+	s := c.store
+	s.mutex.Lock()
+	entries, ok := s.entries[c]
+	if !ok {
+		entries = make(map[uint32]*list.Element)
+		s.entries[c] = entries
+	}
+	var evicted []*cacheEntry
+	if element, ok := entries[row.Position]; ok {
+		evicted = append(evicted, s.remove(element))
+	}
+	entry := &cacheEntry{cache: c, row: row.copy(), size: row.estimateSize()}
+	entries[row.Position] = s.order.PushFront(entry)
+	s.bytes += entry.size
+	for s.order.Len() > 1 && ((s.maxRows > 0 && s.order.Len() > s.maxRows) || (s.maxBytes > 0 && s.bytes > s.maxBytes)) {
+		evicted = append(evicted, s.remove(s.order.Back()))
+	}
+	s.mutex.Unlock()
+	notifyEvicted(evicted)
+}
+
+// remove deletes the element from the store and returns its entry, the mutex must be held by the caller.
+// The OnEvict hooks are called by notifyEvicted after the mutex is released, so they can use the cache.
+func (s *rowStore) remove(element *list.Element) *cacheEntry {
+	entry, _ := element.Value.(*cacheEntry)
+	s.order.Remove(element)
+	entries := s.entries[entry.cache]
+	delete(entries, entry.row.Position)
+	if len(entries) == 0 {
+		delete(s.entries, entry.cache)
+	}
+	s.bytes -= entry.size
+	return entry
+}
+
+// notifyEvicted calls the OnEvict hooks of the tables of the evicted entries
+func notifyEvicted(evicted []*cacheEntry) {
+	for _, entry := range evicted {
+		if entry.cache.OnEvict != nil {
+			entry.cache.OnEvict(entry.row.Position)
+		}
+	}
+}
+
+// copy returns a copy of the row with copied fields, so changes to the copy do not affect the original
+func (row *Row) copy() *Row {
+	c := *row
+	c.fields = make([]*Field, len(row.fields))
+	for i, field := range row.fields {
+		if field != nil {
+			f := *field
+			c.fields[i] = &f
+		}
+	}
+	return &c
+}
+
+// estimateSize returns the estimated memory size of the row in bytes
+func (row *Row) estimateSize() int64 {
+	size := int64(64)
+	for _, field := range row.fields {
+		if field == nil {
+			continue
+		}
+		size += 32
+		switch v := field.value.(type) {
+		case string:
+			size += int64(len(v))
+		case []byte:
+			size += int64(len(v))
+		default:
+			size += 8
+		}
+	}
+	return size
+}
@@ -0,0 +1,38 @@
+package dbase
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestRowCacheEvict calls the eviction hook for evicted and invalidated rows, the hook can use the cache
+func TestRowCacheEvict(t *testing.T) {
+	file := createSortTable(t, filepath.Join(t.TempDir(), "CACHE.DBF"), []string{"first", "second", "third"})
+	defer file.Close()
+	cache := file.EnableRowCache(2, 0)
+	evicted := make([]uint32, 0, 3)
+	cache.OnEvict = func(position uint32) {
+		if stats := cache.Stats(); stats.Rows > 2 {
+			t.Errorf("%d rows cached while evicting row %d, expected at most 2", stats.Rows, position)
+		}
+		evicted = append(evicted, position)
+	}
+	for position := uint32(0); position < file.RowsCount(); position++ {
+		err := file.GoTo(position)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = file.Row()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	cache.Invalidate(2)
+	if !reflect.DeepEqual(evicted, []uint32{0, 2}) {
+		t.Errorf("evicted rows %v, expected [0 2]", evicted)
+	}
+	if stats := cache.Stats(); stats.Rows != 1 {
+		t.Errorf("%d rows cached, expected 1", stats.Rows)
+	}
+}
@@ -0,0 +1,220 @@
+package dbase
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// castType returns the Go type declared for the column in the schema of the config
+func (file *File) castType(column *Column) CastType {
+	if len(file.config.Schema) == 0 {
+		return CastNone
+	}
+	name := column.Name()
+	if cast, ok := file.config.Schema[name]; ok {
+		return cast
+	}
+	if file.config.CaseInsensitiveNames {
+		for key, cast := range file.config.Schema {
+			if strings.EqualFold(key, name) {
+				return cast
+			}
+		}
+	}
+	return CastNone
+}
+
+// castValue casts the value of the column to the type declared in the schema,
+// or to the type of the reference column if the column differs from the reference schema.
+// Nil values stay nil, see NilPolicy to decode empty fields to nil.
+func (file *File) castValue(column *Column, val interface{}) (interface{}, error) {
+	cast := file.castType(column)
+	if cast == CastNone && file.drift != nil {
+		cast = file.drift.casts[column]
+	}
+	if cast == CastNone || val == nil {
+		return val, nil
+	}
+	var out interface{}
+	var err error
+	switch cast {
+	case CastString:
+		out, err = file.castString(column, val)
+	case CastInt:
+		out, err = file.castInt(val)
+	case CastFloat:
+		out, err = file.castFloat(val)
+	case CastBool:
+		out, err = castBool(val)
+	case CastTime:
+		out, err = file.castTime(column, val)
+	case CastBytes:
+		out, err = castBytes(val)
+	default:
+		err = fmt.Errorf("unknown cast type %d", cast)
+	}
+	if err != nil {
+		return nil, newError("dbase-cast-castvalue-1", fmt.Errorf("casting %T failed at column field: %v failed with error: %w", val, column.Name(), err))
+	}
+	return out, nil
+}
+
+// castString formats numbers with the decimals of the column and times as RFC3339
+func (file *File) castString(column *Column, val interface{}) (string, error) {
+	switch v := val.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		switch DataType(column.DataType) {
+		case Numeric, Float:
+			return strconv.FormatFloat(v, 'f', int(column.Decimals), 64), nil
+		case Currency:
+			return strconv.FormatFloat(v, 'f', 4, 64), nil
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case time.Time:
+		if DataType(column.DataType) == Date {
+			return v.Format("2006-01-02"), nil
+		}
+		return v.Format(time.RFC3339), nil
+	}
+	return "", fmt.Errorf("unsupported type %T", val)
+}
+
+// castInt converts numbers and numeric strings, fractions are handled according to the cast policy
+func (file *File) castInt(val interface{}) (int64, error) {
+	switch v := val.(type) {
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		s := strings.TrimSpace(v)
+		if len(s) == 0 {
+			return 0, nil
+		}
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i, nil
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, err
+		}
+		return file.castFraction(f)
+	case float64:
+		return file.castFraction(v)
+	}
+	return 0, fmt.Errorf("unsupported type %T", val)
+}
+
+// castFraction converts the float to int64 according to the cast policy
+func (file *File) castFraction(f float64) (int64, error) {
+	switch file.config.CastPolicy {
+	case CastTruncate:
+		f = math.Trunc(f)
+	case CastRound:
+		f = math.Round(f)
+	}
+	if f != math.Trunc(f) {
+		return 0, fmt.Errorf("value %v has a fraction", f)
+	}
+	// 2^63 is exactly representable, larger values and NaN overflow int64
+	if !(f >= math.MinInt64 && f < math.MaxInt64) {
+		return 0, fmt.Errorf("value %v overflows int64", f)
+	}
+	return int64(f), nil
+}
+
+// castFloat converts numbers and numeric strings.
+// Integers beyond 2^53 are not exactly representable and fail with CastStrict.
+func (file *File) castFloat(val interface{}) (float64, error) {
+	switch v := val.(type) {
+	case int32:
+		return float64(v), nil
+	case int64:
+		if file.config.CastPolicy == CastStrict && (v > 1<<53 || v < -1<<53) {
+			return 0, fmt.Errorf("value %v is not exactly representable as float64", v)
+		}
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case string:
+		s := strings.TrimSpace(v)
+		if len(s) == 0 {
+			return 0, nil
+		}
+		return strconv.ParseFloat(s, 64)
+	}
+	return 0, fmt.Errorf("unsupported type %T", val)
+}
+
+// castBool converts logical strings like "T" or "yes" and numbers, 0 is false
+func castBool(val interface{}) (bool, error) {
+	switch v := val.(type) {
+	case bool:
+		return v, nil
+	case string:
+		s := strings.TrimSpace(v)
+		if len(s) == 0 || s == "?" {
+			return false, nil
+		}
+		if b, ok := parseLogical(s); ok {
+			return b, nil
+		}
+		return false, fmt.Errorf("invalid logical value %q", s)
+	case int32:
+		return v != 0, nil
+	case int64:
+		return v != 0, nil
+	case float64:
+		return v != 0, nil
+	}
+	return false, fmt.Errorf("unsupported type %T", val)
+}
+
+// castTime parses strings with the date layouts of the config
+func (file *File) castTime(column *Column, val interface{}) (time.Time, error) {
+	switch val.(type) {
+	case time.Time, string:
+		return file.timeValue(&Field{column: column, value: val})
+	}
+	return time.Time{}, fmt.Errorf("unsupported type %T", val)
+}
+
+// castBytes returns strings and byte slices as byte slice
+func castBytes(val interface{}) ([]byte, error) {
+	switch v := val.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	}
+	return nil, fmt.Errorf("unsupported type %T", val)
+}
+
+// parseLogical parses the logical values of dBase and common text representations
+func parseLogical(s string) (bool, bool) {
+	switch strings.ToUpper(s) {
+	case "T", "Y", "TRUE", "YES", "1":
+		return true, true
+	case "F", "N", "FALSE", "NO", "0":
+		return false, true
+	}
+	return false, false
+}
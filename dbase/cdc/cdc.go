@@ -0,0 +1,219 @@
+// Package cdc publishes row changes of a dBase table as JSON encoded change events.
+// The events are handed to a user provided publisher, which can forward them to a message bus like Kafka or NATS.
+// Attach produces the events with the write hooks of the dbase package for the writes through one handle,
+// Watch polls the table and also captures the writes of other handles and processes.
+package cdc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"sync"
+	"time"
+
+	"github.com/Valentin-Kaiser/go-dbase/dbase"
+)
+
+// Operation is the kind of change that produced an event
+type Operation string
+
+const (
+	Insert Operation = "insert" // A row was appended to the table
+	Update Operation = "update" // An existing row was changed
+)
+
+// Publisher is implemented by the transport the change events are sent to
+type Publisher interface {
+	Publish(topic string, payload []byte) error
+}
+
+// PublisherFunc allows to use an ordinary function as publisher
+type PublisherFunc func(topic string, payload []byte) error
+
+// Publish calls f(topic, payload)
+func (f PublisherFunc) Publish(topic string, payload []byte) error {
+	return f(topic, payload)
+}
+
+// Event is a single row change
+type Event struct {
+	Table     string                 `json:"table"`     // Name of the table
+	Operation Operation              `json:"operation"` // Kind of change
+	Position  uint32                 `json:"position"`  // Zero based position of the row in the table
+	Deleted   bool                   `json:"deleted"`   // Deleted flag of the row
+	Timestamp time.Time              `json:"timestamp"` // Time the change was observed
+	Data      map[string]interface{} `json:"data"`      // Row values as returned by ToMap
+}
+
+// Config configures a change stream
+type Config struct {
+	Table     string        // Name of the table used in the events
+	Topic     string        // Topic passed to the publisher (default: table name)
+	Publisher Publisher     // Publisher the events are sent to, mandatory
+	OnError   func(error)   // Called if an event could not be encoded or published
+	Interval  time.Duration // Time between two polls of Watch (default: 1 second)
+}
+
+// Stream publishes the changes of one table
+type Stream struct {
+	config *Config
+	file   *dbase.File
+	mutex  *sync.Mutex
+	closed bool
+}
+
+// Attach registers the write hooks on the file and starts publishing change events.
+// The config is copied, so changing it afterwards does not affect the stream.
+func Attach(file *dbase.File, config *Config) (*Stream, error) {
+	stream, err := newStream(file, config)
+	if err != nil {
+		return nil, err
+	}
+	file.OnRowWritten(stream.publish)
+	return stream, nil
+}
+
+// Watch polls the table every Config.Interval and publishes the rows appended or changed since the previous poll until the context is done.
+// Unlike Attach it also captures the writes of other handles and processes, like FoxPro applications, and returns the error of the context.
+// The file should be a read-only handle used only by Watch, as the header is read again on every poll.
+// Rows are compared by a checksum of their data, so every poll reads all rows and a memo rewritten in its previous block is not detected.
+// If rows were removed, e.g. by a pack, the rows read by the poll become the new state without publishing events.
+func Watch(ctx context.Context, file *dbase.File, config *Config) error {
+	stream, err := newStream(file, config)
+	if err != nil {
+		return err
+	}
+	interval := stream.config.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	checksums, err := stream.poll(nil, false)
+	if err != nil {
+		return err
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		checksums, err = stream.poll(checksums, true)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// newStream checks the arguments and returns a stream with a copy of the config
+func newStream(file *dbase.File, config *Config) (*Stream, error) {
+	if file == nil {
+		return nil, fmt.Errorf("missing file")
+	}
+	if config == nil || config.Publisher == nil {
+		return nil, fmt.Errorf("missing publisher")
+	}
+	c := *config
+	if len(c.Topic) == 0 {
+		c.Topic = c.Table
+	}
+	return &Stream{
+		config: &c,
+		file:   file,
+		mutex:  &sync.Mutex{},
+	}, nil
+}
+
+// Close stops publishing events, the hook stays registered but is inactive
+func (s *Stream) Close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.closed = true
+}
+
+// poll reads the header and all rows of the table and returns the checksums of the rows.
+// If publish is set, the rows behind the previous rows are published as inserts and the rows with another checksum as updates.
+func (s *Stream) poll(previous []uint32, publish bool) ([]uint32, error) {
+	err := s.file.ReadHeader()
+	if err != nil {
+		return nil, fmt.Errorf("reading the header failed: %w", err)
+	}
+	count := s.file.RowsCount()
+	if count < uint32(len(previous)) {
+		publish = false
+	}
+	checksums := make([]uint32, count)
+	for position := uint32(0); position < count; position++ {
+		err = s.file.GoTo(position)
+		if err != nil {
+			return nil, fmt.Errorf("moving to row %d failed: %w", position, err)
+		}
+		data, err := s.file.ReadRow(position)
+		if err != nil {
+			return nil, fmt.Errorf("reading row %d failed: %w", position, err)
+		}
+		checksums[position] = crc32.ChecksumIEEE(data)
+		if !publish || (position < uint32(len(previous)) && previous[position] == checksums[position]) {
+			continue
+		}
+		row, err := s.file.BytesToRow(data)
+		if err != nil {
+			s.fail(err)
+			continue
+		}
+		operation := Update
+		if position >= uint32(len(previous)) {
+			operation = Insert
+		}
+		s.send(row, position, operation)
+	}
+	return checksums, nil
+}
+
+// publish converts the written row to an event and hands it to the publisher
+func (s *Stream) publish(written *dbase.WrittenRow) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.closed {
+		return
+	}
+	operation := Update
+	if written.Appended {
+		operation = Insert
+	}
+	s.send(written.Row, written.Position, operation)
+}
+
+// send encodes the row as event and hands it to the publisher
+func (s *Stream) send(row *dbase.Row, position uint32, operation Operation) {
+	data, err := row.ToMap()
+	if err != nil {
+		s.fail(err)
+		return
+	}
+	payload, err := json.Marshal(&Event{
+		Table:     s.config.Table,
+		Operation: operation,
+		Position:  position,
+		Deleted:   row.Deleted,
+		Timestamp: time.Now(),
+		Data:      data,
+	})
+	if err != nil {
+		s.fail(err)
+		return
+	}
+	err = s.config.Publisher.Publish(s.config.Topic, payload)
+	if err != nil {
+		s.fail(err)
+	}
+}
+
+// fail passes the error to the error handler if defined
+func (s *Stream) fail(err error) {
+	if s.config.OnError != nil {
+		s.config.OnError(dbase.GetErrorTrace(err))
+	}
+}
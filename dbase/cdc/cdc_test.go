@@ -0,0 +1,189 @@
+package cdc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Valentin-Kaiser/go-dbase/dbase"
+)
+
+func TestStreamPositions(t *testing.T) {
+	column, err := dbase.NewColumn("NAME", dbase.Character, 10, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := dbase.CreateTable(&dbase.Config{Filename: filepath.Join(t.TempDir(), "CHANGES.DBF")}, column)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	events := make([]*Event, 0)
+	config := &Config{
+		Table: "changes",
+		Publisher: PublisherFunc(func(topic string, payload []byte) error {
+			if topic != "changes" {
+				t.Errorf("published to topic %q, expected the table name", topic)
+			}
+			event := &Event{}
+			err := json.Unmarshal(payload, event)
+			if err != nil {
+				return err
+			}
+			events = append(events, event)
+			return nil
+		}),
+		OnError: func(err error) {
+			t.Error(err)
+		},
+	}
+	stream, err := Attach(file, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+	if config.Topic != "" {
+		t.Errorf("Attach changed the topic of the config to %q", config.Topic)
+	}
+
+	add := func(name string) {
+		row := file.NewRow()
+		err := row.FieldByName("NAME").SetValue(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = row.Add()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	add("first")
+	add("second")
+	err = file.UpdateField(0, "NAME", "updated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = file.GoTo(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	row, err := file.Row()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = row.FieldByName("NAME").SetValue("rewritten")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = row.Write()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		operation Operation
+		position  uint32
+	}{
+		{Insert, 0},
+		{Insert, 1},
+		{Update, 0},
+		{Update, 1},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("published %d events, expected %d", len(events), len(expected))
+	}
+	for i, e := range expected {
+		if events[i].Operation != e.operation || events[i].Position != e.position {
+			t.Errorf("event %d is %s at %d, expected %s at %d", i, events[i].Operation, events[i].Position, e.operation, e.position)
+		}
+	}
+}
+
+// TestWatch publishes the rows written through another handle
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "WATCHED.DBF")
+	column, err := dbase.NewColumn("NAME", dbase.Character, 10, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer, err := dbase.CreateTable(&dbase.Config{Filename: path}, column)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer writer.Close()
+	add := func(name string) {
+		row := writer.NewRow()
+		err := row.FieldByName("NAME").SetValue(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = row.Add()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	add("existing")
+
+	watched, err := dbase.OpenTable(&dbase.Config{Filename: path, ReadOnly: true, TrimSpaces: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watched.Close()
+	events := make(chan *Event, 10)
+	config := &Config{
+		Table:    "watched",
+		Interval: 10 * time.Millisecond,
+		Publisher: PublisherFunc(func(topic string, payload []byte) error {
+			event := &Event{}
+			err := json.Unmarshal(payload, event)
+			if err != nil {
+				return err
+			}
+			events <- event
+			return nil
+		}),
+		OnError: func(err error) {
+			t.Error(err)
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- Watch(ctx, watched, config)
+	}()
+	// Give Watch the time to read the existing rows, which are not published
+	time.Sleep(50 * time.Millisecond)
+	add("appended")
+	err = writer.UpdateField(0, "NAME", "updated")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first event of a row has the operation, a row can be published again if a poll read it while it was written
+	operations := make(map[uint32]Operation)
+	names := make(map[uint32]interface{})
+	timeout := time.After(5 * time.Second)
+	for names[0] != "updated" || names[1] != "appended" {
+		select {
+		case event := <-events:
+			if _, ok := operations[event.Position]; !ok {
+				operations[event.Position] = event.Operation
+			}
+			names[event.Position] = event.Data["NAME"]
+		case <-timeout:
+			t.Fatalf("received the names %v, expected the updated and the appended row", names)
+		}
+	}
+	cancel()
+	err = <-done
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the error of the context, got %v", err)
+	}
+	if operations[0] != Update || operations[1] != Insert {
+		t.Errorf("published the operations %v, expected an update of row 0 and an insert of row 1", operations)
+	}
+}
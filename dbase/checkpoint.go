@@ -0,0 +1,165 @@
+package dbase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Checkpoint records the progress of a long running export, so it can be continued after a failure
+type Checkpoint struct {
+	Position   uint32 `json:"position"`    // Next row position to export
+	RowLength  uint16 `json:"row_length"`  // Row length of the table, used to detect schema changes
+	MemoOffset uint32 `json:"memo_offset"` // Next free memo block at the time of the checkpoint
+	Output     int64  `json:"output"`      // Durable offset of the output as returned by the flush function
+}
+
+// CheckpointConfig configures a resumable export
+type CheckpointConfig struct {
+	Path        string                             // Path of the checkpoint file
+	Interval    uint32                             // Number of rows between two checkpoints (default: 1000)
+	SkipDeleted bool                               // If true deleted rows are not exported
+	Export      func(row *Row) error               // Exports a single row, mandatory
+	Flush       func() (int64, error)              // Makes the exported rows durable and returns the output offset, mandatory
+	Resume      func(checkpoint *Checkpoint) error // Called before continuing from a checkpoint, e.g. to truncate the output
+}
+
+// LoadCheckpoint reads the checkpoint file at path. Returns nil if no checkpoint exists.
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, newError("dbase-checkpoint-loadcheckpoint-1", err)
+	}
+	checkpoint := &Checkpoint{}
+	err = json.Unmarshal(data, checkpoint)
+	if err != nil {
+		return nil, newError("dbase-checkpoint-loadcheckpoint-2", err)
+	}
+	return checkpoint, nil
+}
+
+// Save writes the checkpoint atomically to path by writing a temporary file and renaming it
+func (c *Checkpoint) Save(path string) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return newError("dbase-checkpoint-save-1", err)
+	}
+	err = writeFileAtomic(path, data)
+	if err != nil {
+		return newError("dbase-checkpoint-save-2", err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes the data to a temporary file, syncs it and renames it to path
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	handle, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = handle.Write(data)
+	if err == nil {
+		err = handle.Sync()
+	}
+	if closeErr := handle.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// ExportWithCheckpoint exports all rows through the export function and periodically records the last durable
+// row position in the checkpoint file. If a checkpoint exists the export continues from the recorded position.
+// The checkpoint file is removed after the export completed successfully.
+func (file *File) ExportWithCheckpoint(config *CheckpointConfig) error {
+	if config == nil || config.Export == nil || config.Flush == nil {
+		return newError("dbase-checkpoint-exportwithcheckpoint-1", fmt.Errorf("missing export or flush function"))
+	}
+	if len(config.Path) == 0 {
+		return newError("dbase-checkpoint-exportwithcheckpoint-2", fmt.Errorf("missing checkpoint path"))
+	}
+	interval := config.Interval
+	if interval == 0 {
+		interval = 1000
+	}
+	checkpoint, err := LoadCheckpoint(config.Path)
+	if err != nil {
+		return newError("dbase-checkpoint-exportwithcheckpoint-3", err)
+	}
+	start := uint32(0)
+	if checkpoint != nil {
+		if checkpoint.RowLength != file.header.RowLength {
+			return newError("dbase-checkpoint-exportwithcheckpoint-4", fmt.Errorf("checkpoint row length %d does not match table row length %d", checkpoint.RowLength, file.header.RowLength))
+		}
+		if checkpoint.Position > file.header.RowsCount {
+			return newError("dbase-checkpoint-exportwithcheckpoint-5", fmt.Errorf("%w, checkpoint position %d > %d", ErrInvalidPosition, checkpoint.Position, file.header.RowsCount))
+		}
+		debugf("Resuming export from checkpoint at row %d", checkpoint.Position)
+		if config.Resume != nil {
+			err = config.Resume(checkpoint)
+			if err != nil {
+				return newError("dbase-checkpoint-exportwithcheckpoint-6", err)
+			}
+		}
+		start = checkpoint.Position
+	}
+	err = file.GoTo(start)
+	if err != nil {
+		return newError("dbase-checkpoint-exportwithcheckpoint-7", err)
+	}
+	// The interval counts the processed rows, positions of hidden deleted rows are jumped over
+	processed := uint32(0)
+	for file.skipHidden(); !file.EOF(); file.skipHidden() {
+		row, err := file.Next()
+		if err != nil {
+			return newError("dbase-checkpoint-exportwithcheckpoint-8", err)
+		}
+		processed++
+		if !(row.Deleted && config.SkipDeleted) {
+			err = config.Export(row)
+			if err != nil {
+				return newError("dbase-checkpoint-exportwithcheckpoint-9", err)
+			}
+		}
+		if processed%interval == 0 {
+			err = file.saveCheckpoint(config)
+			if err != nil {
+				return newError("dbase-checkpoint-exportwithcheckpoint-10", err)
+			}
+		}
+	}
+	_, err = config.Flush()
+	if err != nil {
+		return newError("dbase-checkpoint-exportwithcheckpoint-11", err)
+	}
+	err = os.Remove(config.Path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return newError("dbase-checkpoint-exportwithcheckpoint-12", err)
+	}
+	return nil
+}
+
+// saveCheckpoint flushes the output and records the current row pointer as checkpoint
+func (file *File) saveCheckpoint(config *CheckpointConfig) error {
+	output, err := config.Flush()
+	if err != nil {
+		return newError("dbase-checkpoint-savecheckpoint-1", err)
+	}
+	checkpoint := &Checkpoint{
+		Position:  file.table.rowPointer,
+		RowLength: file.header.RowLength,
+		Output:    output,
+	}
+	if file.memoHeader != nil {
+		checkpoint.MemoOffset = file.memoHeader.NextFree
+	}
+	debugf("Saving export checkpoint at row %d", checkpoint.Position)
+	return checkpoint.Save(config.Path)
+}
@@ -0,0 +1,140 @@
+package dbase
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/unicode/norm"
+)
+
+// foldExceptions are letters without canonical decomposition that are folded to their base letter
+var foldExceptions = map[rune]rune{
+	'ø': 'o', 'Ø': 'O',
+	'đ': 'd', 'Đ': 'D',
+	'ł': 'l', 'Ł': 'L',
+	'ı': 'i',
+}
+
+// CaseMapper converts the case of decoded text like FoxPro does for the code page of a table.
+// Letters are only mapped if the result is a character of the code page, e.g. 'ÿ' is not converted to 'Ÿ' in code page 437,
+// so normalized search values and index keys match the values produced by the legacy application.
+// Runes outside of the code page are left unchanged. For Unicode converters the simple Unicode case mapping is used.
+type CaseMapper struct {
+	upper map[rune]rune
+	lower map[rune]rune
+	fold  map[rune]rune
+}
+
+// NewCaseMapper returns a case mapper for the characters of the converter's code page
+func NewCaseMapper(converter EncodingConverter) *CaseMapper {
+	c, ok := converter.(DefaultConverter)
+	if !ok || c.encoding == nil {
+		return &CaseMapper{}
+	}
+	return newCharmapCaseMapper(c.encoding)
+}
+
+// CaseMapper returns a case mapper for the code page of the table converter.
+// Columns with a differing encoding require a case mapper created with NewCaseMapper.
+func (file *File) CaseMapper() *CaseMapper {
+	return NewCaseMapper(file.config.Converter)
+}
+
+// newCharmapCaseMapper builds the mapping tables from the 256 characters of the charmap
+func newCharmapCaseMapper(cm *charmap.Charmap) *CaseMapper {
+	mapper := &CaseMapper{
+		upper: make(map[rune]rune),
+		lower: make(map[rune]rune),
+		fold:  make(map[rune]rune),
+	}
+	encodable := func(r rune) bool {
+		_, ok := cm.EncodeRune(r)
+		return ok
+	}
+	for b := 0; b < 256; b++ {
+		r := cm.DecodeByte(byte(b))
+		if r == unicode.ReplacementChar {
+			continue
+		}
+		if u := unicode.ToUpper(r); u != r && encodable(u) {
+			mapper.upper[r] = u
+		}
+		if l := unicode.ToLower(r); l != r && encodable(l) {
+			mapper.lower[r] = l
+		}
+		if base, ok := foldRune(r); ok && encodable(base) {
+			mapper.fold[r] = base
+		}
+	}
+	return mapper
+}
+
+// Upper returns the text with all letters of the code page converted to upper case
+func (m *CaseMapper) Upper(s string) string {
+	if m.upper == nil {
+		return strings.Map(unicode.ToUpper, s)
+	}
+	return mapRunes(m.upper, s)
+}
+
+// Lower returns the text with all letters of the code page converted to lower case
+func (m *CaseMapper) Lower(s string) string {
+	if m.lower == nil {
+		return strings.Map(unicode.ToLower, s)
+	}
+	return mapRunes(m.lower, s)
+}
+
+// Fold returns the text with accented letters replaced by their base letter, e.g. "Crème brûlée" becomes "Creme brulee"
+func (m *CaseMapper) Fold(s string) string {
+	if m.fold == nil {
+		return strings.Map(func(r rune) rune {
+			if base, ok := foldRune(r); ok {
+				return base
+			}
+			return r
+		}, s)
+	}
+	return mapRunes(m.fold, s)
+}
+
+// Key returns the normalized text for searching and index keys: accents folded, upper case and trailing spaces removed
+func (m *CaseMapper) Key(s string) string {
+	return m.Upper(m.Fold(strings.TrimRight(s, " ")))
+}
+
+// EqualFold returns true if both texts have the same normalized key
+func (m *CaseMapper) EqualFold(a, b string) bool {
+	return m.Key(a) == m.Key(b)
+}
+
+// mapRunes replaces the runes found in the mapping
+func mapRunes(mapping map[rune]rune, s string) string {
+	return strings.Map(func(r rune) rune {
+		if mapped, ok := mapping[r]; ok {
+			return mapped
+		}
+		return r
+	}, s)
+}
+
+// foldRune returns the base letter of an accented letter
+func foldRune(r rune) (rune, bool) {
+	if base, ok := foldExceptions[r]; ok {
+		return base, true
+	}
+	if r < 0x80 || !unicode.IsLetter(r) {
+		return r, false
+	}
+	decomposed := []rune(norm.NFD.String(string(r)))
+	if len(decomposed) < 2 || !unicode.IsLetter(decomposed[0]) {
+		return r, false
+	}
+	for _, mark := range decomposed[1:] {
+		if !unicode.Is(unicode.Mn, mark) {
+			return r, false
+		}
+	}
+	return decomposed[0], true
+}
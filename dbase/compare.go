@@ -0,0 +1,197 @@
+package dbase
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Equal compares two field values using the comparison semantics of dBase for the column type:
+//   - C, V and M text values are equal if they only differ in trailing spaces or null bytes
+//   - D values are compared by date, T values by millisecond, nil and empty dates are equal
+//   - N, F, B and Y values are equal if they are equal when rounded to the decimals of the column
+//   - nil is equal to the empty value of the column type
+//
+// If column is nil the comparison is derived from the types of the values.
+func Equal(a, b interface{}, column *Column) bool {
+	dataType := DataType(0)
+	decimals := -1
+	if column != nil {
+		dataType = DataType(column.DataType)
+		decimals = int(column.Decimals)
+	}
+	switch dataType {
+	case Character, Varchar, Memo:
+		if isBinary(a) && isBinary(b) {
+			return bytes.Equal(toBytes(a), toBytes(b))
+		}
+		return trimText(a) == trimText(b)
+	case Date:
+		return equalTime(a, b, func(t time.Time) time.Time {
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		})
+	case DateTime:
+		return equalTime(a, b, func(t time.Time) time.Time { return t.Truncate(time.Millisecond) })
+	case Currency:
+		return equalNumber(a, b, 4)
+	case Numeric, Float, Double, Integer:
+		return equalNumber(a, b, decimals)
+	case Logical:
+		return toBool(a) == toBool(b)
+	case Blob, Varbinary, General, Picture:
+		return bytes.Equal(toBytes(a), toBytes(b))
+	}
+	// Without a column the types of the values decide
+	switch {
+	case a == nil && b == nil:
+		return true
+	case isText(a) || isText(b):
+		return trimText(a) == trimText(b)
+	case isTime(a) || isTime(b):
+		return equalTime(a, b, func(t time.Time) time.Time { return t })
+	case isNumber(a) || isNumber(b):
+		return equalNumber(a, b, -1)
+	case isBinary(a) || isBinary(b):
+		return bytes.Equal(toBytes(a), toBytes(b))
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// compareValues orders two field values of the column with the semantics of Equal, returns -1, 0 or 1.
+// Returns false if the values can not be ordered, like logical and binary values.
+func compareValues(a, b interface{}, column *Column) (int, bool) {
+	dataType := DataType(0)
+	if column != nil {
+		dataType = DataType(column.DataType)
+	}
+	switch {
+	case dataType == Character || dataType == Varchar || dataType == Memo || (dataType == 0 && (isText(a) || isText(b))):
+		return strings.Compare(trimText(a), trimText(b)), true
+	case dataType == Date || dataType == DateTime || (dataType == 0 && (isTime(a) || isTime(b))):
+		ta, oka := a.(time.Time)
+		tb, okb := b.(time.Time)
+		if (a != nil && !oka) || (b != nil && !okb) {
+			return 0, false
+		}
+		if dataType == Date {
+			ta = time.Date(ta.Year(), ta.Month(), ta.Day(), 0, 0, 0, 0, time.UTC)
+			tb = time.Date(tb.Year(), tb.Month(), tb.Day(), 0, 0, 0, 0, time.UTC)
+		}
+		switch {
+		case ta.Before(tb):
+			return -1, true
+		case ta.After(tb):
+			return 1, true
+		}
+		return 0, true
+	case dataType == Currency || dataType == Numeric || dataType == Float || dataType == Double || dataType == Integer || (dataType == 0 && (isNumber(a) || isNumber(b))):
+		if Equal(a, b, column) {
+			return 0, true
+		}
+		fa, oka := toFloat(a)
+		fb, okb := toFloat(b)
+		if !oka || !okb {
+			return 0, false
+		}
+		if fa < fb {
+			return -1, true
+		}
+		return 1, true
+	}
+	return 0, false
+}
+
+// trimText returns the value as string without trailing spaces and null bytes
+func trimText(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return strings.TrimRight(t, " \x00")
+	case []byte:
+		return strings.TrimRight(string(t), " \x00")
+	case nil:
+		return ""
+	}
+	return strings.TrimRight(formatValue(v), " \x00")
+}
+
+// equalTime compares two time values after normalizing them, nil and the zero time are equal
+func equalTime(a, b interface{}, normalize func(time.Time) time.Time) bool {
+	ta, oka := a.(time.Time)
+	tb, okb := b.(time.Time)
+	if (a != nil && !oka) || (b != nil && !okb) {
+		return reflect.DeepEqual(a, b)
+	}
+	if ta.IsZero() || tb.IsZero() {
+		return ta.IsZero() && tb.IsZero()
+	}
+	return normalize(ta).Equal(normalize(tb))
+}
+
+// equalNumber compares two numbers rounded to the decimals, a negative number of decimals compares exactly
+func equalNumber(a, b interface{}, decimals int) bool {
+	fa, oka := toFloat(a)
+	fb, okb := toFloat(b)
+	if !oka || !okb {
+		return reflect.DeepEqual(a, b)
+	}
+	if decimals < 0 || decimals >= len(pow10) {
+		return fa == fb
+	}
+	return math.Round(fa*pow10[decimals]) == math.Round(fb*pow10[decimals])
+}
+
+// toFloat converts a numeric value to float64, nil is zero
+func toFloat(v interface{}) (float64, bool) {
+	if v == nil {
+		return 0, true
+	}
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	}
+	return 0, false
+}
+
+// toBool converts a logical value to bool, nil is false
+func toBool(v interface{}) bool {
+	b, ok := v.(bool)
+	return ok && b
+}
+
+// toBytes returns the value as byte slice, nil is empty
+func toBytes(v interface{}) []byte {
+	switch t := v.(type) {
+	case []byte:
+		return t
+	case string:
+		return []byte(t)
+	}
+	return nil
+}
+
+func isText(v interface{}) bool {
+	_, ok := v.(string)
+	return ok
+}
+
+func isBinary(v interface{}) bool {
+	_, ok := v.([]byte)
+	return ok
+}
+
+func isTime(v interface{}) bool {
+	_, ok := v.(time.Time)
+	return ok
+}
+
+func isNumber(v interface{}) bool {
+	_, ok := toFloat(v)
+	return ok && v != nil
+}
@@ -0,0 +1,277 @@
+package dbase
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReferenceReader reads the rows of a table through a reference implementation, e.g. the Visual FoxPro ODBC driver
+// used by the tests of this package on Windows. Deleted rows must be left out, like SET DELETED ON in FoxPro.
+type ReferenceReader interface {
+	Columns() []string            // Column names in the order of the values
+	Next() ([]interface{}, error) // Values of the next row, nil for NULL, io.EOF after the last row
+	Close() error
+}
+
+// ConformanceReport is the result of CheckConformance
+type ConformanceReport struct {
+	Rows           int           // Rows compared
+	ReferenceRows  int           // Rows returned by the reference
+	Columns        []string      // Columns compared
+	MissingColumns []string      // Columns of the table that the reference did not return
+	Divergences    []*Divergence // Values that differ from the reference, the first maxDivergences are kept
+	Total          int           // Number of divergent values, including those not kept
+}
+
+// Divergence is a value decoded differently than by the reference
+type Divergence struct {
+	Position  uint32      // Position of the row in the table
+	Column    string      // Name of the column
+	Value     interface{} // Value decoded by this package
+	Reference interface{} // Value returned by the reference
+}
+
+// maxDivergences is the number of divergences kept in a report
+const maxDivergences = 1000
+
+// String describes the divergence, e.g. "row 12, column PRICE: 12.3455 != 12.3456"
+func (d *Divergence) String() string {
+	return fmt.Sprintf("row %d, column %s: %s != %s", d.Position+1, d.Column, describeValue(d.Value), describeValue(d.Reference))
+}
+
+// Valid returns true if all rows and columns match the reference
+func (r *ConformanceReport) Valid() bool {
+	return r.Total == 0 && len(r.MissingColumns) == 0 && r.Rows == r.ReferenceRows
+}
+
+// CheckConformance reads the rows of the table that are not deleted and compares them row by row with the rows
+// of the reference, to verify the decoding of this package against another implementation.
+// Columns are matched by name. The comparison follows the representation of the Visual FoxPro drivers:
+// trailing spaces of text are ignored, numbers are compared with the decimals of the column, currency with 4 decimals,
+// date times to the second, and empty dates match NULL. The internal row pointer is restored afterwards.
+func (file *File) CheckConformance(ref ReferenceReader) (*ConformanceReport, error) {
+	report := &ConformanceReport{
+		Columns:        make([]string, 0),
+		MissingColumns: make([]string, 0),
+		Divergences:    make([]*Divergence, 0),
+	}
+	positions := make(map[string]int)
+	for i, name := range ref.Columns() {
+		positions[strings.ToUpper(strings.TrimSpace(name))] = i
+	}
+	columns := make(map[*Column]int)
+	for _, column := range file.table.columns {
+		if column.System() {
+			continue
+		}
+		pos, ok := positions[strings.ToUpper(column.Name())]
+		if !ok {
+			report.MissingColumns = append(report.MissingColumns, column.Name())
+			continue
+		}
+		columns[column] = pos
+		report.Columns = append(report.Columns, column.Name())
+	}
+	done := false
+	err := file.forEachRow(true, func(row *Row) error {
+		report.Rows++
+		if done {
+			return nil
+		}
+		values, err := ref.Next()
+		if errors.Is(err, io.EOF) {
+			done = true
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		report.ReferenceRows++
+		for _, field := range row.fields {
+			pos, ok := columns[field.column]
+			if !ok || pos >= len(values) {
+				continue
+			}
+			if conforms(field.column, field.value, values[pos]) {
+				continue
+			}
+			report.Total++
+			if len(report.Divergences) < maxDivergences {
+				report.Divergences = append(report.Divergences, &Divergence{
+					Position:  row.Position,
+					Column:    field.column.Name(),
+					Value:     field.value,
+					Reference: values[pos],
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, newError("dbase-conformance-checkconformance-1", err)
+	}
+	// Rows the reference returned beyond the rows of the table
+	for !done {
+		_, err := ref.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, newError("dbase-conformance-checkconformance-2", err)
+		}
+		report.ReferenceRows++
+	}
+	return report, nil
+}
+
+// conforms returns true if the value of the column matches the value of the reference
+func conforms(column *Column, value interface{}, reference interface{}) bool {
+	empty := value == nil
+	if t, ok := value.(time.Time); ok && t.IsZero() {
+		empty = true
+	}
+	switch {
+	case reference == nil:
+		// Empty dates are NULL in the drivers
+		return empty && (value == nil || DataType(column.DataType) == Date || DataType(column.DataType) == DateTime)
+	case value == nil:
+		return false
+	}
+	switch DataType(column.DataType) {
+	case Character, Varchar, Memo:
+		text, ok := referenceText(reference)
+		if !ok {
+			return false
+		}
+		var own string
+		switch v := value.(type) {
+		case string:
+			own = v
+		case []byte:
+			own = string(v)
+		default:
+			return false
+		}
+		return strings.TrimRight(own, " \x00") == strings.TrimRight(text, " \x00")
+	case Varbinary, Blob, General, Picture:
+		own, ok := value.([]byte)
+		if !ok {
+			return false
+		}
+		switch r := reference.(type) {
+		case []byte:
+			return string(own) == string(r)
+		case string:
+			return strings.EqualFold(hex.EncodeToString(own), strings.TrimPrefix(r, "0x"))
+		}
+		return false
+	case Integer, Numeric, Float, Double, Currency:
+		text, ok := referenceText(reference)
+		if !ok {
+			return false
+		}
+		expected, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil {
+			return false
+		}
+		own, err := exportFloat(value)
+		if err != nil {
+			return false
+		}
+		switch DataType(column.DataType) {
+		case Currency:
+			return strconv.FormatFloat(own, 'f', 4, 64) == strconv.FormatFloat(expected, 'f', 4, 64)
+		case Double:
+			return own == expected || math.Abs(own-expected) <= 1e-12*math.Max(math.Abs(own), math.Abs(expected))
+		}
+		return strconv.FormatFloat(own, 'f', int(column.Decimals), 64) == strconv.FormatFloat(expected, 'f', int(column.Decimals), 64)
+	case Logical:
+		own, ok := value.(bool)
+		if !ok {
+			return false
+		}
+		text, ok := referenceText(reference)
+		if !ok {
+			return false
+		}
+		switch strings.ToUpper(strings.TrimSpace(text)) {
+		case "1", "T", "Y", "TRUE", ".T.":
+			return own
+		case "0", "F", "N", "FALSE", ".F.":
+			return !own
+		}
+		return false
+	case Date, DateTime:
+		own, ok := value.(time.Time)
+		if !ok {
+			return false
+		}
+		expected, ok := referenceTime(reference)
+		if !ok {
+			return empty && strings.TrimSpace(fmt.Sprint(reference)) == ""
+		}
+		if DataType(column.DataType) == Date {
+			return own.Format("2006-01-02") == expected.Format("2006-01-02")
+		}
+		return own.Round(time.Second).Equal(expected.Round(time.Second))
+	}
+	text, ok := referenceText(reference)
+	return ok && fmt.Sprint(value) == text
+}
+
+// referenceText returns the value of the reference as text
+func referenceText(reference interface{}) (string, bool) {
+	switch r := reference.(type) {
+	case string:
+		return r, true
+	case []byte:
+		return string(r), true
+	case bool:
+		if r {
+			return "1", true
+		}
+		return "0", true
+	case int, int32, int64, float64:
+		return fmt.Sprint(r), true
+	}
+	return "", false
+}
+
+// referenceTime returns the date or date time of the reference, given as time or in the ODBC format
+func referenceTime(reference interface{}) (time.Time, bool) {
+	if t, ok := reference.(time.Time); ok {
+		return t, !t.IsZero()
+	}
+	text, ok := referenceText(reference)
+	if !ok {
+		return time.Time{}, false
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05.999999999", "2006-01-02", time.RFC3339Nano} {
+		t, err := time.ParseInLocation(layout, strings.TrimSpace(text), time.UTC)
+		if err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// describeValue formats a value of a divergence
+func describeValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return strconv.Quote(v)
+	case []byte:
+		return "0x" + hex.EncodeToString(v)
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05.000")
+	}
+	return fmt.Sprint(value)
+}
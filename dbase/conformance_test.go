@@ -0,0 +1,63 @@
+package dbase
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+)
+
+// sliceReference returns fixed rows as reference for CheckConformance
+type sliceReference struct {
+	columns []string
+	rows    [][]interface{}
+}
+
+func (r *sliceReference) Columns() []string {
+	return r.columns
+}
+
+func (r *sliceReference) Next() ([]interface{}, error) {
+	if len(r.rows) == 0 {
+		return nil, io.EOF
+	}
+	row := r.rows[0]
+	r.rows = r.rows[1:]
+	return row, nil
+}
+
+func (r *sliceReference) Close() error {
+	return nil
+}
+
+func TestCheckConformance(t *testing.T) {
+	file, err := OpenTable(&Config{Filename: filepath.Join(vfpCorpus, "employees.dbf"), ReadOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	// The reference returns text without the padding like the drivers, a divergent first name and not all columns
+	ref := &sliceReference{
+		columns: []string{"employeeid", "lastname", "firstname"},
+		rows: [][]interface{}{
+			{"1", "Davolio", "Nancy"},
+			{"2", "Leverling", "Jane"},
+			{"3", "Buchanan", "Steven "},
+		},
+	}
+	report, err := file.CheckConformance(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Rows != 3 || report.ReferenceRows != 3 {
+		t.Fatalf("expected 3 rows compared, got %d of %d", report.Rows, report.ReferenceRows)
+	}
+	if len(report.Columns) != 3 || len(report.MissingColumns) != len(file.Columns())-3 {
+		t.Errorf("expected 3 columns compared, got %v, missing %v", report.Columns, report.MissingColumns)
+	}
+	if report.Total != 1 || report.Divergences[0].Column != "FIRSTNAME" || report.Divergences[0].Position != 1 {
+		t.Fatalf("expected the first name of the second row to diverge, got %v", report.Divergences)
+	}
+	if report.Valid() {
+		t.Error("report with divergences is valid")
+	}
+}
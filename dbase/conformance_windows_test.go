@@ -0,0 +1,313 @@
+//go:build windows
+// +build windows
+
+package dbase
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// TestConformanceODBC compares the decoding of the corpus with the Visual FoxPro ODBC driver.
+// The test is skipped if the driver is not installed, it is 32 bit only and requires GOARCH=386.
+func TestConformanceODBC(t *testing.T) {
+	dir, err := filepath.Abs(vfpCorpus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		file       string
+		connection string
+		table      string
+	}{
+		{file: "TEST.DBF", connection: vfpConnectionString("DBF", dir), table: "TEST"},
+		{file: "employees.dbf", connection: vfpConnectionString("DBC", filepath.Join(dir, "EXPENSES.DBC")), table: "employees"},
+		{file: "expense reports.dbf", connection: vfpConnectionString("DBC", filepath.Join(dir, "EXPENSES.DBC")), table: "expense reports"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			ref, err := openODBCReference(tt.connection, fmt.Sprintf("SELECT * FROM \"%s\"", tt.table))
+			if errors.Is(err, errODBCUnavailable) {
+				t.Skip(err)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ref.Close()
+			file, err := OpenTable(&Config{Filename: filepath.Join(dir, tt.file), ReadOnly: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+			report, err := file.CheckConformance(ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(report.MissingColumns) > 0 {
+				t.Errorf("columns not returned by the driver: %v", report.MissingColumns)
+			}
+			if report.Rows != report.ReferenceRows {
+				t.Errorf("read %d rows, the driver returned %d", report.Rows, report.ReferenceRows)
+			}
+			for _, divergence := range report.Divergences {
+				t.Error(divergence)
+			}
+		})
+	}
+}
+
+// errODBCUnavailable is returned if the ODBC driver manager or the driver is not installed
+var errODBCUnavailable = errors.New("the Visual FoxPro ODBC driver is not available")
+
+// vfpConnectionString returns the connection string of the Visual FoxPro ODBC driver for the free tables of a directory
+// (DBF) or the tables of a database container (DBC). Deleted rows are hidden and NULL values are returned as NULL,
+// as expected by CheckConformance.
+func vfpConnectionString(sourceType string, source string) string {
+	return fmt.Sprintf("Driver={Microsoft Visual FoxPro Driver};SourceType=%s;SourceDB=%s;Exclusive=No;Collate=Machine;Null=Yes;Deleted=Yes;BackgroundFetch=No;", sourceType, source)
+}
+
+// ODBC functions of the driver manager
+var (
+	odbc                 = windows.NewLazySystemDLL("odbc32.dll")
+	procSQLAllocHandle   = odbc.NewProc("SQLAllocHandle")
+	procSQLSetEnvAttr    = odbc.NewProc("SQLSetEnvAttr")
+	procSQLDriverConnect = odbc.NewProc("SQLDriverConnectW")
+	procSQLExecDirect    = odbc.NewProc("SQLExecDirectW")
+	procSQLNumResultCols = odbc.NewProc("SQLNumResultCols")
+	procSQLDescribeCol   = odbc.NewProc("SQLDescribeColW")
+	procSQLFetch         = odbc.NewProc("SQLFetch")
+	procSQLGetData       = odbc.NewProc("SQLGetData")
+	procSQLGetDiagRec    = odbc.NewProc("SQLGetDiagRecW")
+	procSQLDisconnect    = odbc.NewProc("SQLDisconnect")
+	procSQLFreeHandle    = odbc.NewProc("SQLFreeHandle")
+)
+
+// ODBC constants used by the reference reader
+const (
+	sqlHandleEnv        = 1
+	sqlHandleDbc        = 2
+	sqlHandleStmt       = 3
+	sqlAttrODBCVersion  = 200
+	sqlODBC3            = 3
+	sqlDriverNoPrompt   = 0
+	sqlNTS              = -3
+	sqlSuccess          = 0
+	sqlSuccessWithInfo  = 1
+	sqlNoData           = 100
+	sqlNullData         = -1
+	sqlNoTotal          = -4
+	sqlCWChar           = -8
+	sqlCBinary          = -2
+	sqlBinary           = -2
+	sqlVarbinary        = -3
+	sqlLongVarbinary    = -4
+	odbcChunkSize       = 8192
+	odbcColumnNameChars = 256
+)
+
+// odbcReference reads the rows of a query through an ODBC driver
+type odbcReference struct {
+	env     uintptr
+	dbc     uintptr
+	stmt    uintptr
+	columns []string
+	binary  []bool
+}
+
+// openODBCReference runs the query through the ODBC driver of the connection string
+// and returns its rows for CheckConformance. Text is returned as string and binary columns as []byte.
+func openODBCReference(connection string, query string) (ReferenceReader, error) {
+	r := &odbcReference{}
+	err := r.open(connection, query)
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *odbcReference) open(connection string, query string) error {
+	if odbc.Load() != nil || procSQLAllocHandle.Find() != nil {
+		return fmt.Errorf("%w: odbc32.dll can not be loaded", errODBCUnavailable)
+	}
+	ret, _, _ := procSQLAllocHandle.Call(sqlHandleEnv, 0, uintptr(unsafe.Pointer(&r.env)))
+	if !odbcSucceeded(ret) {
+		return fmt.Errorf("%w: allocating the ODBC environment failed", errODBCUnavailable)
+	}
+	ret, _, _ = procSQLSetEnvAttr.Call(r.env, sqlAttrODBCVersion, sqlODBC3, 0)
+	if !odbcSucceeded(ret) {
+		return odbcError(sqlHandleEnv, r.env, "setting the ODBC version")
+	}
+	ret, _, _ = procSQLAllocHandle.Call(sqlHandleDbc, r.env, uintptr(unsafe.Pointer(&r.dbc)))
+	if !odbcSucceeded(ret) {
+		return odbcError(sqlHandleEnv, r.env, "allocating the connection")
+	}
+	text, err := windows.UTF16PtrFromString(connection)
+	if err != nil {
+		return err
+	}
+	nts := int16(sqlNTS)
+	ret, _, _ = procSQLDriverConnect.Call(r.dbc, 0, uintptr(unsafe.Pointer(text)), uintptr(nts), 0, 0, 0, sqlDriverNoPrompt)
+	if !odbcSucceeded(ret) {
+		// IM002: data source or driver not found, IM014: the driver does not match the architecture of the process
+		err := odbcError(sqlHandleDbc, r.dbc, "connecting")
+		state := odbcState(sqlHandleDbc, r.dbc)
+		if state == "IM002" || state == "IM014" {
+			return fmt.Errorf("%w: %v", errODBCUnavailable, err)
+		}
+		return err
+	}
+	ret, _, _ = procSQLAllocHandle.Call(sqlHandleStmt, r.dbc, uintptr(unsafe.Pointer(&r.stmt)))
+	if !odbcSucceeded(ret) {
+		return odbcError(sqlHandleDbc, r.dbc, "allocating the statement")
+	}
+	text, err = windows.UTF16PtrFromString(query)
+	if err != nil {
+		return err
+	}
+	ntsLong := int32(sqlNTS)
+	ret, _, _ = procSQLExecDirect.Call(r.stmt, uintptr(unsafe.Pointer(text)), uintptr(ntsLong))
+	if !odbcSucceeded(ret) {
+		return odbcError(sqlHandleStmt, r.stmt, "executing the query")
+	}
+	var count int16
+	ret, _, _ = procSQLNumResultCols.Call(r.stmt, uintptr(unsafe.Pointer(&count)))
+	if !odbcSucceeded(ret) {
+		return odbcError(sqlHandleStmt, r.stmt, "reading the result columns")
+	}
+	r.columns = make([]string, count)
+	r.binary = make([]bool, count)
+	for i := 0; i < int(count); i++ {
+		name := make([]uint16, odbcColumnNameChars)
+		var length, dataType, decimals, nullable int16
+		var size uintptr
+		ret, _, _ = procSQLDescribeCol.Call(r.stmt, uintptr(i+1), uintptr(unsafe.Pointer(&name[0])), odbcColumnNameChars,
+			uintptr(unsafe.Pointer(&length)), uintptr(unsafe.Pointer(&dataType)), uintptr(unsafe.Pointer(&size)),
+			uintptr(unsafe.Pointer(&decimals)), uintptr(unsafe.Pointer(&nullable)))
+		if !odbcSucceeded(ret) {
+			return odbcError(sqlHandleStmt, r.stmt, "describing the result columns")
+		}
+		r.columns[i] = windows.UTF16ToString(name)
+		r.binary[i] = dataType == sqlBinary || dataType == sqlVarbinary || dataType == sqlLongVarbinary
+	}
+	return nil
+}
+
+func (r *odbcReference) Columns() []string {
+	return r.columns
+}
+
+func (r *odbcReference) Next() ([]interface{}, error) {
+	ret, _, _ := procSQLFetch.Call(r.stmt)
+	if int16(ret) == sqlNoData {
+		return nil, io.EOF
+	}
+	if !odbcSucceeded(ret) {
+		return nil, odbcError(sqlHandleStmt, r.stmt, "fetching the row")
+	}
+	values := make([]interface{}, len(r.columns))
+	for i := range r.columns {
+		value, err := r.data(i+1, r.binary[i])
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// data reads the value of the column in chunks, as string or as []byte for binary columns
+func (r *odbcReference) data(column int, binary bool) (interface{}, error) {
+	target := int16(sqlCWChar)
+	terminator := 2
+	if binary {
+		target = sqlCBinary
+		terminator = 0
+	}
+	chunk := make([]byte, odbcChunkSize)
+	data := make([]byte, 0)
+	for {
+		var indicator int
+		ret, _, _ := procSQLGetData.Call(r.stmt, uintptr(column), uintptr(target), uintptr(unsafe.Pointer(&chunk[0])), odbcChunkSize, uintptr(unsafe.Pointer(&indicator)))
+		if int16(ret) == sqlNoData {
+			break
+		}
+		if !odbcSucceeded(ret) {
+			return nil, odbcError(sqlHandleStmt, r.stmt, fmt.Sprintf("reading column %d", column))
+		}
+		if indicator == sqlNullData {
+			return nil, nil
+		}
+		// The chunk is full if the length is unknown or larger than the chunk
+		n := len(chunk) - terminator
+		if indicator != sqlNoTotal && indicator < n {
+			n = indicator
+		}
+		data = append(data, chunk[:n]...)
+		if int16(ret) == sqlSuccess {
+			break
+		}
+	}
+	if binary {
+		return data, nil
+	}
+	text := make([]uint16, len(data)/2)
+	for i := range text {
+		text[i] = uint16(data[2*i]) | uint16(data[2*i+1])<<8
+	}
+	return string(utf16.Decode(text)), nil
+}
+
+func (r *odbcReference) Close() error {
+	if r.stmt != 0 {
+		procSQLFreeHandle.Call(sqlHandleStmt, r.stmt)
+		r.stmt = 0
+	}
+	if r.dbc != 0 {
+		procSQLDisconnect.Call(r.dbc)
+		procSQLFreeHandle.Call(sqlHandleDbc, r.dbc)
+		r.dbc = 0
+	}
+	if r.env != 0 {
+		procSQLFreeHandle.Call(sqlHandleEnv, r.env)
+		r.env = 0
+	}
+	return nil
+}
+
+// odbcSucceeded returns true for SQL_SUCCESS and SQL_SUCCESS_WITH_INFO
+func odbcSucceeded(ret uintptr) bool {
+	return int16(ret) == sqlSuccess || int16(ret) == sqlSuccessWithInfo
+}
+
+// odbcState returns the SQLSTATE of the first diagnostic record of the handle
+func odbcState(handleType int, handle uintptr) string {
+	state := make([]uint16, 6)
+	message := make([]uint16, 1)
+	var native int32
+	var length int16
+	procSQLGetDiagRec.Call(uintptr(handleType), handle, 1, uintptr(unsafe.Pointer(&state[0])), uintptr(unsafe.Pointer(&native)),
+		uintptr(unsafe.Pointer(&message[0])), uintptr(len(message)), uintptr(unsafe.Pointer(&length)))
+	return windows.UTF16ToString(state)
+}
+
+// odbcError returns the first diagnostic record of the handle as error
+func odbcError(handleType int, handle uintptr, action string) error {
+	state := make([]uint16, 6)
+	message := make([]uint16, 1024)
+	var native int32
+	var length int16
+	ret, _, _ := procSQLGetDiagRec.Call(uintptr(handleType), handle, 1, uintptr(unsafe.Pointer(&state[0])), uintptr(unsafe.Pointer(&native)),
+		uintptr(unsafe.Pointer(&message[0])), uintptr(len(message)), uintptr(unsafe.Pointer(&length)))
+	if !odbcSucceeded(ret) {
+		return fmt.Errorf("%s failed", action)
+	}
+	return fmt.Errorf("%s failed with ODBC error %s (%d): %s", action, windows.UTF16ToString(state), native, windows.UTF16ToString(message))
+}
@@ -0,0 +1,209 @@
+package dbase
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// memoHeaderSize is the size of the header of FoxPro memo files, blocks in this range can not contain memos
+const memoHeaderSize = 512
+
+// defaultMemoBlockSize is the block size of memo files created by Visual FoxPro, SET BLOCKSIZE TO 64
+const defaultMemoBlockSize = 64
+
+// CompanionReport contains the result of the consistency check of the memo and index file against the table,
+// e.g. to find a table that was restored without its memo file
+type CompanionReport struct {
+	NextFree     uint32   `json:"next_free"`      // Next free block of the memo file header
+	MaxMemoBlock uint32   `json:"max_memo_block"` // Highest memo block referenced by a row
+	InvalidMemos []uint32 `json:"invalid_memos"`  // Positions of rows referencing memo blocks outside of the used part of the memo file
+	IndexTags    int      `json:"index_tags"`     // Number of tags of the index file
+	Issues       []string `json:"issues"`         // Description of all problems found
+}
+
+// Valid returns true if no problems were found
+func (r *CompanionReport) Valid() bool {
+	return len(r.Issues) == 0
+}
+
+// CompanionReport returns the result of the consistency check done when the table was opened with Config.CheckCompanions,
+// nil if the check was not done
+func (file *File) CompanionReport() *CompanionReport {
+	return file.companions
+}
+
+// CheckCompanions checks the memo and index file against the table.
+// The memo addresses of all rows are compared with the block size and next free block of the memo file,
+// and the record numbers and key counts of the index tags are compared with the rows count.
+// Problems are reported like in Validate, an error is only returned if the files can not be read.
+func (file *File) CheckCompanions() (*CompanionReport, error) {
+	report := &CompanionReport{InvalidMemos: make([]uint32, 0), Issues: make([]string, 0)}
+	err := file.checkMemoFile(report)
+	if err != nil {
+		return nil, newError("dbase-consistency-checkcompanions-1", err)
+	}
+	err = file.checkIndexFile(report)
+	if err != nil {
+		return nil, newError("dbase-consistency-checkcompanions-2", err)
+	}
+	return report, nil
+}
+
+// checkMemoFile compares the memo addresses of all rows with the memo file header
+func (file *File) checkMemoFile(report *CompanionReport) error {
+	columns := make([]*Column, 0)
+	for _, column := range file.table.columns {
+		switch DataType(column.DataType) {
+		case Memo, General, Picture, Blob:
+			// dBase III tables store the address as 10 digits, only binary addresses are checked
+			if column.Length == 4 {
+				columns = append(columns, column)
+			}
+		}
+	}
+	if len(columns) == 0 || file.config.WithoutMemo {
+		return nil
+	}
+	if file.memoHeader == nil {
+		report.Issues = append(report.Issues, fmt.Sprintf("table has %d memo columns but no memo file", len(columns)))
+		return nil
+	}
+	report.NextFree = file.memoHeader.NextFree
+	blockSize := int64(file.memoHeader.BlockSize)
+	if blockSize == 0 {
+		report.Issues = append(report.Issues, "memo file has a block size of 0")
+		return nil
+	}
+	size, err := file.Size(true)
+	if err != nil {
+		return err
+	}
+	if size < int64(report.NextFree)*blockSize {
+		report.Issues = append(report.Issues, fmt.Sprintf("memo file of %d bytes is shorter than its next free block %d with %d bytes per block", size, report.NextFree, blockSize))
+	}
+	firstBlock := uint32((memoHeaderSize + blockSize - 1) / blockSize)
+	invalid := make(map[*Column]uint32)
+	first := make(map[*Column]uint32)
+	length := int64(file.header.RowLength)
+	chunk := uint32(1024)
+	throttle := file.throttle()
+	for start := uint32(0); start < file.header.RowsCount; start += chunk {
+		count := chunk
+		if file.header.RowsCount-start < count {
+			count = file.header.RowsCount - start
+		}
+		data, err := file.ReadRaw(false, int64(file.header.FirstRow)+int64(start)*length, int(int64(count)*length))
+		if err != nil && !errors.Is(err, ErrIncomplete) {
+			return err
+		}
+		throttle.wait(uint64(count), uint64(len(data)))
+		for i := int64(0); (i+1)*length <= int64(len(data)); i++ {
+			row := data[i*length : (i+1)*length]
+			position := start + uint32(i)
+			valid := true
+			for _, column := range columns {
+				offset := int(file.columnOffset(column))
+				block := binary.LittleEndian.Uint32(row[offset : offset+4])
+				if block == 0 {
+					continue
+				}
+				if block > report.MaxMemoBlock {
+					report.MaxMemoBlock = block
+				}
+				if block < firstBlock || block >= report.NextFree {
+					if invalid[column] == 0 {
+						first[column] = position
+					}
+					invalid[column]++
+					valid = false
+				}
+			}
+			if !valid {
+				report.InvalidMemos = append(report.InvalidMemos, position)
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	for _, column := range columns {
+		if invalid[column] > 0 {
+			report.Issues = append(report.Issues, fmt.Sprintf("%d rows of column %s reference memo blocks in the memo header or from the next free block %d on, the first is row %d", invalid[column], column.Name(), report.NextFree, first[column]))
+		}
+	}
+	return nil
+}
+
+// checkIndexFile compares the tags of the index file with the rows of the table
+func (file *File) checkIndexFile(report *CompanionReport) error {
+	if len(file.path) == 0 || file.config.WithoutIndex {
+		return nil
+	}
+	path, err := _findFile(strings.TrimSuffix(file.path, filepath.Ext(file.path)) + string(CDX))
+	if err != nil {
+		return err
+	}
+	_, err = os.Stat(path)
+	exists := err == nil
+	structural := StructuralFlag.Defined(file.header.TableFlags)
+	if !exists {
+		if structural {
+			report.Issues = append(report.Issues, "table has a structural index but the index file is missing")
+		}
+		return nil
+	}
+	// Index files written by CreateIndex are not structural, their tags are compared the same way
+	handle, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer handle.Close()
+	reader := &indexReader{handle: handle, file: file}
+	directory, root, err := reader.header(0)
+	if err != nil {
+		report.Issues = append(report.Issues, fmt.Sprintf("index file can not be read: %v", err))
+		return nil
+	}
+	tags, err := reader.keys(root, directory.KeyLength)
+	if err != nil {
+		report.Issues = append(report.Issues, fmt.Sprintf("tags of the index file can not be read: %v", err))
+		return nil
+	}
+	report.IndexTags = len(tags)
+	for _, entry := range tags {
+		tag, root, err := reader.header(int64(entry.offset))
+		if err != nil {
+			report.Issues = append(report.Issues, fmt.Sprintf("index tag %s can not be read: %v", entry.key, err))
+			continue
+		}
+		// Expressions with functions not supported by CreateIndex or with long column names of a database container can not be compared
+		term, err := file.compileIndexExpression(tag.Expression)
+		if err == nil && term.length != tag.KeyLength {
+			report.Issues = append(report.Issues, fmt.Sprintf("index tag %s has keys of %d bytes but its expression %q produces %d bytes", entry.key, tag.KeyLength, tag.Expression, term.length))
+		}
+		reader.pages = 0
+		keys, err := reader.keys(root, tag.KeyLength)
+		if err != nil {
+			report.Issues = append(report.Issues, fmt.Sprintf("keys of index tag %s can not be read: %v", entry.key, err))
+			continue
+		}
+		highest := uint32(0)
+		for _, key := range keys {
+			if key.offset > highest {
+				highest = key.offset
+			}
+		}
+		if highest > file.header.RowsCount {
+			report.Issues = append(report.Issues, fmt.Sprintf("index tag %s references row %d of %d rows", entry.key, highest, file.header.RowsCount))
+		}
+		// Tags with a filter or unique keys contain fewer keys than rows
+		if !tag.Unique && len(tag.Filter) == 0 && uint32(len(keys)) != file.header.RowsCount {
+			report.Issues = append(report.Issues, fmt.Sprintf("index tag %s has %d keys for %d rows", entry.key, len(keys), file.header.RowsCount))
+		}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package dbase
 
 import (
+	"fmt"
 	"reflect"
 	"time"
 )
@@ -27,8 +28,61 @@ const (
 	DBaseMemo       FileVersion = 0x8B
 	DBaseSQLMemo    FileVersion = 0xCB
 	FoxPro2Memo     FileVersion = 0xF5
+	DBase7          FileVersion = 0x04
+	DBase5          FileVersion = 0x05
+	DBaseSQLSystem  FileVersion = 0x63
+	DBase7Memo      FileVersion = 0x8C
+	HiPerSixMemo    FileVersion = 0xE5
 )
 
+// Product names of the file versions
+const (
+	VisualFoxPro = FoxPro      // Visual FoxPro table
+	FoxPro2      = FoxPro2Memo // FoxPro 2.x table with memo, FoxPro 2.x tables without memo use FoxBasePlus
+	DBase3       = FoxBasePlus // dBase III table without memo
+	DBase3Memo   = FoxBasePlusMemo
+	DBase4Memo   = DBaseMemo
+)
+
+// Returns the name of the file version
+func (v FileVersion) String() string {
+	switch v {
+	case FoxPro:
+		return "Visual FoxPro"
+	case FoxProAutoincrement:
+		return "Visual FoxPro with autoincrement"
+	case FoxProVar:
+		return "Visual FoxPro with varchar or varbinary"
+	case FoxBase:
+		return "FoxBASE"
+	case FoxBase2:
+		return "FoxBASE 2"
+	case FoxBasePlus:
+		return "FoxBASE+/dBase III"
+	case DBaseSQLTable:
+		return "dBase IV SQL table"
+	case FoxBasePlusMemo:
+		return "FoxBASE+/dBase III with memo"
+	case DBaseMemo:
+		return "dBase IV with memo"
+	case DBaseSQLMemo:
+		return "dBase IV SQL table with memo"
+	case FoxPro2Memo:
+		return "FoxPro 2.x with memo"
+	case DBase7:
+		return "dBase 7"
+	case DBase5:
+		return "dBase V"
+	case DBaseSQLSystem:
+		return "dBase IV SQL system"
+	case DBase7Memo:
+		return "dBase 7 with memo"
+	case HiPerSixMemo:
+		return "HiPer-Six with memo"
+	}
+	return fmt.Sprintf("unknown (0x%02X)", byte(v))
+}
+
 // Table file extenstions
 type FileExtension string
 
@@ -37,6 +91,8 @@ const (
 	DCT FileExtension = ".DCT" // Database container file extension
 	DBF FileExtension = ".DBF" // Table file extension
 	FPT FileExtension = ".FPT" // Memo file extension
+	CDX FileExtension = ".CDX" // Structural compound index file extension
+	DCX FileExtension = ".DCX" // Database container index file extension
 	SCX FileExtension = ".SCX" // Form file extension
 	LBX FileExtension = ".LBX" // Label file extension
 	MNX FileExtension = ".MNX" // Menu file extension
@@ -57,6 +113,121 @@ const (
 	EOFMarker Marker = 0x1A
 )
 
+// LineEnding defines the line ending of memo text stored in the memo file
+type LineEnding byte
+
+const (
+	LineEndingKeep LineEnding = iota // Line endings are not converted
+	LineEndingLF                     // CRLF and CR are converted to LF on read, text is written with LF
+	LineEndingCRLF                   // CRLF and CR are converted to LF on read, LF is converted to CRLF on write
+)
+
+// TrimMode defines which side of string values is trimmed in ToMap, ToJSON, ToStruct and the exports
+type TrimMode byte
+
+const (
+	TrimDefault TrimMode = iota // Inherit the mode, TrimSpaces is used if no mode is set
+	TrimNone                    // Values are not trimmed, e.g. to preserve space padded codes
+	TrimLeft                    // Leading characters are trimmed
+	TrimRight                   // Trailing characters are trimmed
+	TrimBoth                    // Leading and trailing characters are trimmed
+)
+
+// NilPolicy defines which empty fields are decoded to nil instead of the zero value of the column type.
+// The policies can be combined, e.g. NilEmptyText | NilEmptyDate.
+type NilPolicy byte
+
+const (
+	NilNone        NilPolicy = 0                                            // Empty fields are decoded to "", 0 and the zero time
+	NilEmptyText   NilPolicy = 1 << 0                                       // Blank C fields and empty V and M fields are decoded to nil
+	NilEmptyNumber NilPolicy = 1 << 1                                       // Blank N and F fields are decoded to nil
+	NilEmptyDate   NilPolicy = 1 << 2                                       // Blank D and zero T fields are decoded to nil
+	NilEmpty       NilPolicy = NilEmptyText | NilEmptyNumber | NilEmptyDate // All empty fields are decoded to nil
+)
+
+// CastType is the Go type the values of a column are cast to on read, see Config.Schema
+type CastType byte
+
+const (
+	CastNone   CastType = iota // Values keep the type of the column
+	CastString                 // Values are cast to string
+	CastInt                    // Values are cast to int64
+	CastFloat                  // Values are cast to float64
+	CastBool                   // Values are cast to bool
+	CastTime                   // Values are cast to time.Time
+	CastBytes                  // Values are cast to []byte
+)
+
+// CastPolicy defines how casts that lose information are handled, e.g. the fraction of a number cast to int64
+type CastPolicy byte
+
+const (
+	CastStrict   CastPolicy = iota // Casts that lose information fail
+	CastTruncate                   // Fractions are truncated toward zero
+	CastRound                      // Fractions are rounded half away from zero
+)
+
+// KeyCase defines the casing of column names used as JSON keys
+type KeyCase byte
+
+const (
+	KeyCaseKeep  KeyCase = iota // Column names are used as stored, usually upper case
+	KeyCaseLower                // Column names are converted to lower case
+	KeyCaseUpper                // Column names are converted to upper case
+	KeyCaseCamel                // Column names are converted to camel case, underscores separate the words
+)
+
+// DuplicatePolicy defines the keys of columns whose name is already used by a previous column in ToMap, ToJSON and the exports
+type DuplicatePolicy byte
+
+const (
+	DuplicateOverwrite DuplicatePolicy = iota // In ToMap the value of the last column wins, ordered results and exports use the keys of DuplicateSuffix
+	DuplicateError                            // Converting rows of tables with duplicate column names fails with ErrDuplicateColumn
+	DuplicateSuffix                           // Duplicates get the number of the occurrence as suffix, e.g. NAME_2 and NAME_3
+	DuplicateKeepFirst                        // Duplicates are left out, the value of the first column is kept
+)
+
+// Warehouse defines the data warehouse of the load-file exporters and WarehouseDDL
+type Warehouse byte
+
+const (
+	WarehouseBigQuery  Warehouse = iota // Google BigQuery, exported as "bigquery-csv" and "bigquery-jsonl"
+	WarehouseSnowflake                  // Snowflake, exported as "snowflake-csv" and "snowflake-jsonl"
+)
+
+// SupportLevel defines how far a feature is supported for the files of a product, see SupportMatrix
+type SupportLevel byte
+
+const (
+	SupportNone     SupportLevel = iota // The feature is not implemented for the format
+	SupportUntested                     // The format is implemented but not verified with files of the product
+	SupportTested                       // Verified with files created by the product
+)
+
+// nullFlagsName is the name of the system column containing the null flags of nullable and variable length columns
+const nullFlagsName = "_NullFlags"
+
+// Limits of the 16 bit length fields of the header
+const (
+	MaxRowLength    = 0xFFFF // Maximum length of a row in bytes, including the deletion flag
+	MaxHeaderLength = 0xFFFF // Maximum length of the header in bytes, the position of the first row
+)
+
+// RedactedValue replaces the values of redacted columns in the output, see File.RedactColumns
+const RedactedValue = "***"
+
+// EpochSeconds can be added to Config.DateLayouts to accept numbers and numeric strings as seconds since the Unix epoch for D and T columns
+const EpochSeconds = "epoch"
+
+// DefaultDateLayouts are the layouts accepted for string values of D and T columns if Config.DateLayouts is empty
+var DefaultDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"20060102",
+}
+
 // Table flags inidicate the type of the table
 // https://learn.microsoft.com/en-us/previous-versions/visualstudio/foxpro/st4a0s68(v=vs.71)
 type TableFlag byte
@@ -103,10 +274,49 @@ const (
 )
 
 // Returns the type of the column as string
+// Returns the type character, e.g. "C"
 func (t DataType) String() string {
 	return string(t)
 }
 
+// Returns the name of the type, e.g. "Character"
+func (t DataType) Name() string {
+	switch t {
+	case Character:
+		return "Character"
+	case Currency:
+		return "Currency"
+	case Double:
+		return "Double"
+	case Date:
+		return "Date"
+	case DateTime:
+		return "DateTime"
+	case Float:
+		return "Float"
+	case Integer:
+		return "Integer"
+	case Logical:
+		return "Logical"
+	case Memo:
+		return "Memo"
+	case Numeric:
+		return "Numeric"
+	case Blob:
+		return "Blob"
+	case General:
+		return "General"
+	case Picture:
+		return "Picture"
+	case Varbinary:
+		return "Varbinary"
+	case Varchar:
+		return "Varchar"
+	}
+	return fmt.Sprintf("unknown (%s)", string(t))
+}
+
+// Returns the Go type of the values of the type
 func (t DataType) Reflect() reflect.Type {
 	switch t {
 	case Character:
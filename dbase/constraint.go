@@ -0,0 +1,208 @@
+package dbase
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// UniqueKey is a key expression whose keys must be unique in the table.
+// Rows written through the handle are rejected with a ConstraintError if another row has the same key.
+type UniqueKey struct {
+	Name       string     // Name of the key, the tag name for keys of the index
+	Expression string     // Key expression, supports the same expressions as CreateIndex
+	term       *indexTerm // Compiled expression
+}
+
+// ConstraintError is returned when a written row has the same key as another row of the table
+type ConstraintError struct {
+	Key        string // Name of the violated key
+	Expression string // Key expression of the violated key
+	Position   uint32 // Position of the written row
+	Conflict   uint32 // Position of the row with the same key
+}
+
+// Error returns the key and the positions of both rows
+func (e *ConstraintError) Error() string {
+	return fmt.Sprintf("%v, key %s (%s) of row %d already exists in row %d", ErrDuplicateKey, e.Key, e.Expression, e.Position, e.Conflict)
+}
+
+// Unwrap returns ErrDuplicateKey, so errors.Is can be used
+func (e *ConstraintError) Unwrap() error {
+	return ErrDuplicateKey
+}
+
+// AddUniqueKey declares the expression as unique key, a key with the same name is replaced.
+// Every row written with WriteRow, Add or UpdateField is compared with all other rows of the table,
+// so each write of a column used by a key scans the table once.
+// Deleted rows are compared as well, unless they are hidden, see SetDeletedHidden.
+func (file *File) AddUniqueKey(name string, expression string) error {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if len(name) == 0 {
+		return newError("dbase-constraint-adduniquekey-1", fmt.Errorf("the unique key needs a name"))
+	}
+	term, err := file.compileIndexExpression(expression)
+	if err != nil {
+		return newError("dbase-constraint-adduniquekey-2", err)
+	}
+	file.writeMutex.Lock()
+	defer file.writeMutex.Unlock()
+	keys := make([]*UniqueKey, 0, len(file.uniqueKeys)+1)
+	for _, key := range file.uniqueKeys {
+		if key.Name != name {
+			keys = append(keys, key)
+		}
+	}
+	file.uniqueKeys = append(keys, &UniqueKey{Name: name, Expression: expression, term: term})
+	debugf("Added unique key %s on %s", name, expression)
+	return nil
+}
+
+// EnforceIndexKeys declares the unique and candidate tags of the structural index as unique keys.
+// Returns the tags that can not be enforced, because they have a FOR filter or an expression that is not supported.
+func (file *File) EnforceIndexKeys() ([]*IndexTag, error) {
+	info, err := file.IndexInfo()
+	if err != nil {
+		return nil, newError("dbase-constraint-enforceindexkeys-1", err)
+	}
+	if info == nil {
+		return nil, nil
+	}
+	skipped := make([]*IndexTag, 0)
+	for _, tag := range info.Tags {
+		if !tag.Unique && !tag.Candidate {
+			continue
+		}
+		if len(tag.Filter) > 0 {
+			debugf("Skipping unique tag %s with FOR filter %s", tag.Name, tag.Filter)
+			skipped = append(skipped, tag)
+			continue
+		}
+		err = file.AddUniqueKey(tag.Name, tag.Expression)
+		if err != nil {
+			debugf("Skipping unique tag %s: %v", tag.Name, err)
+			skipped = append(skipped, tag)
+		}
+	}
+	return skipped, nil
+}
+
+// UniqueKeys returns the declared unique keys
+func (file *File) UniqueKeys() []*UniqueKey {
+	file.writeMutex.Lock()
+	defer file.writeMutex.Unlock()
+	return append([]*UniqueKey(nil), file.uniqueKeys...)
+}
+
+// ClearUniqueKeys removes all declared unique keys
+func (file *File) ClearUniqueKeys() {
+	file.writeMutex.Lock()
+	defer file.writeMutex.Unlock()
+	file.uniqueKeys = nil
+}
+
+// uses returns true if the key is computed from the column
+func (key *UniqueKey) uses(column *Column) bool {
+	for _, c := range key.term.columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRow compares the keys of the row with all other rows, called with the write lock held
+func (file *File) checkRow(row *Row, appending bool) error {
+	if len(file.uniqueKeys) == 0 {
+		return nil
+	}
+	// Only the columns of the keys are encoded, memo columns can not be part of a key
+	data := make([]byte, file.header.RowLength)
+	data[0] = byte(Active)
+	if row.Deleted {
+		data[0] = byte(Deleted)
+	}
+	for _, key := range file.uniqueKeys {
+		for _, column := range key.term.columns {
+			field := row.Field(file.ColumnPos(column))
+			if field == nil {
+				continue
+			}
+			val, err := file.GetRepresentation(field, false)
+			if err != nil {
+				return err
+			}
+			offset := int(file.columnOffset(column))
+			copy(data[offset:offset+int(column.Length)], val)
+		}
+	}
+	position := row.Position
+	if appending || position >= file.header.RowsCount {
+		position = file.header.RowsCount
+	}
+	return file.checkUniqueKeys(file.uniqueKeys, position, data)
+}
+
+// checkField compares the keys of the row at the position with the updated field with all other rows,
+// called with the write lock held
+func (file *File) checkField(position uint32, field *Field) error {
+	keys := make([]*UniqueKey, 0, len(file.uniqueKeys))
+	for _, key := range file.uniqueKeys {
+		if key.uses(field.column) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	data, err := file.ReadRow(position)
+	if err != nil {
+		return err
+	}
+	val, err := file.GetRepresentation(field, false)
+	if err != nil {
+		return err
+	}
+	offset := int(file.columnOffset(field.column))
+	copy(data[offset:offset+int(field.column.Length)], val)
+	return file.checkUniqueKeys(keys, position, data)
+}
+
+// checkUniqueKeys returns a ConstraintError if another row than the one at the position has one of the keys of the data.
+// If deleted rows are hidden they neither violate nor are violated by a key.
+func (file *File) checkUniqueKeys(keys []*UniqueKey, position uint32, data []byte) error {
+	ignoreDeleted := file.deletedHidden
+	if ignoreDeleted && Marker(data[0]) == Deleted {
+		return nil
+	}
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		value, err := key.term.eval(data)
+		if err != nil {
+			return fmt.Errorf("evaluating unique key %s failed with error: %w", key.Name, err)
+		}
+		values[i] = value
+	}
+	for other := uint32(0); other < file.header.RowsCount; other++ {
+		if other == position {
+			continue
+		}
+		raw, err := file.ReadRow(other)
+		if err != nil {
+			return err
+		}
+		if ignoreDeleted && Marker(raw[0]) == Deleted {
+			continue
+		}
+		for i, key := range keys {
+			value, err := key.term.eval(raw)
+			if err != nil {
+				return fmt.Errorf("evaluating unique key %s of row %d failed with error: %w", key.Name, other, err)
+			}
+			if bytes.Equal(value, values[i]) {
+				return &ConstraintError{Key: key.Name, Expression: key.Expression, Position: position, Conflict: other}
+			}
+		}
+	}
+	return nil
+}
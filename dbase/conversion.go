@@ -34,6 +34,9 @@ func jd2ymd(date int) (int, int, int) {
 
 // parseDate parses a date string from a byte slice and returns a time.Time
 func parseDate(raw []byte) (time.Time, error) {
+	if t, ok := parseDateFast(raw); ok {
+		return t, nil
+	}
 	raw = sanitizeString(raw)
 	if len(raw) == 0 {
 		return time.Time{}, nil
@@ -66,6 +69,9 @@ func parseDateTime(raw []byte) time.Time {
 
 // parseNumericInt parses a string as byte array to int64
 func parseNumericInt(raw []byte) (int64, error) {
+	if i, ok := parseNumericIntFast(raw); ok {
+		return i, nil
+	}
 	trimmed := string(sanitizeString(raw))
 	if len(trimmed) == 0 {
 		return int64(0), nil
@@ -79,6 +85,9 @@ func parseNumericInt(raw []byte) (int64, error) {
 
 // parseFloat parses a string as byte array to float64
 func parseFloat(raw []byte) (float64, error) {
+	if f, ok := parseFloatFast(raw); ok {
+		return f, nil
+	}
 	trimmed := strings.TrimSpace(string(sanitizeString(raw)))
 	if len(trimmed) == 0 {
 		return float64(0), nil
@@ -90,6 +99,150 @@ func parseFloat(raw []byte) (float64, error) {
 	return f, nil
 }
 
+// pow10 contains the powers of ten that are exactly representable as float64
+var pow10 = [...]float64{1e0, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9, 1e10, 1e11, 1e12, 1e13, 1e14, 1e15, 1e16, 1e17, 1e18, 1e19, 1e20, 1e21, 1e22}
+
+// trimPadding returns the start and end index of the byte slice without leading and trailing spaces or null bytes
+func trimPadding(raw []byte) (int, int) {
+	start, end := 0, len(raw)
+	for start < end && (raw[start] == ' ' || raw[start] == 0x00) {
+		start++
+	}
+	for end > start && (raw[end-1] == ' ' || raw[end-1] == 0x00) {
+		end--
+	}
+	return start, end
+}
+
+// parseDigits parses an optional sign followed by digits without allocating.
+// The second return value is the number of digits read, the third is false if the input is not a plain integer.
+func parseDigits(raw []byte) (int64, int, bool) {
+	if len(raw) == 0 {
+		return 0, 0, true
+	}
+	negative := false
+	switch raw[0] {
+	case '-':
+		negative = true
+		raw = raw[1:]
+	case '+':
+		raw = raw[1:]
+	}
+	if len(raw) == 0 {
+		return 0, 0, false
+	}
+	n, ok := parseUnsignedDigits(raw)
+	if !ok {
+		return 0, 0, false
+	}
+	if negative {
+		n = -n
+	}
+	return n, len(raw), true
+}
+
+// parseUnsignedDigits parses digits without a sign, a sign after the leading position is not a plain integer
+func parseUnsignedDigits(raw []byte) (int64, bool) {
+	// More than 18 digits could overflow, let strconv handle these cases
+	if len(raw) > 18 {
+		return 0, false
+	}
+	n := int64(0)
+	for _, b := range raw {
+		if b < '0' || b > '9' {
+			return 0, false
+		}
+		n = n*10 + int64(b-'0')
+	}
+	return n, true
+}
+
+// parseNumericIntFast parses a space padded fixed width integer directly from the byte slice.
+// Returns false if the value can not be handled by the fast path.
+func parseNumericIntFast(raw []byte) (int64, bool) {
+	start, end := trimPadding(raw)
+	if start == end {
+		return 0, true
+	}
+	i, _, ok := parseDigits(raw[start:end])
+	return i, ok
+}
+
+// parseFloatFast parses a space padded fixed width decimal number directly from the byte slice.
+// Only values with at most 15 significant digits are handled, as they can be converted exactly.
+// Returns false if the value can not be handled by the fast path.
+func parseFloatFast(raw []byte) (float64, bool) {
+	start, end := trimPadding(raw)
+	if start == end {
+		return 0, true
+	}
+	raw = raw[start:end]
+	dot := bytes.IndexByte(raw, '.')
+	if dot < 0 {
+		i, digits, ok := parseDigits(raw)
+		if !ok || digits > 15 {
+			return 0, false
+		}
+		return float64(i), true
+	}
+	integer, fraction := raw[:dot], raw[dot+1:]
+	if len(fraction) == 0 {
+		return 0, false
+	}
+	negative := len(integer) > 0 && integer[0] == '-'
+	if len(integer) > 0 && (integer[0] == '-' || integer[0] == '+') {
+		integer = integer[1:]
+	}
+	if len(integer)+len(fraction) > 15 {
+		return 0, false
+	}
+	// Only the leading sign is handled, other signs are left to strconv
+	i, ok := parseUnsignedDigits(integer)
+	if !ok {
+		return 0, false
+	}
+	f, ok := parseUnsignedDigits(fraction)
+	if !ok {
+		return 0, false
+	}
+	value := (float64(i)*pow10[len(fraction)] + float64(f)) / pow10[len(fraction)]
+	if negative {
+		value = -value
+	}
+	return value, true
+}
+
+// parseDateFast parses a date in the format YYYYMMDD directly from the byte slice.
+// Returns false if the value can not be handled by the fast path.
+func parseDateFast(raw []byte) (time.Time, bool) {
+	start, end := trimPadding(raw)
+	if start == end {
+		return time.Time{}, true
+	}
+	raw = raw[start:end]
+	if len(raw) != 8 {
+		return time.Time{}, false
+	}
+	ymd := [3]int{}
+	for i, r := range [3][2]int{{0, 4}, {4, 6}, {6, 8}} {
+		for _, b := range raw[r[0]:r[1]] {
+			if b < '0' || b > '9' {
+				return time.Time{}, false
+			}
+			ymd[i] = ymd[i]*10 + int(b-'0')
+		}
+	}
+	year, month, day := ymd[0], ymd[1], ymd[2]
+	if month < 1 || month > 12 || day < 1 {
+		return time.Time{}, false
+	}
+	// Let time.Date normalize the last day of the month and compare to detect invalid days
+	if day > time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day() {
+		return time.Time{}, false
+	}
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC), true
+}
+
 // toUTF8String converts a byte slice to a UTF8 string using the converter
 func toUTF8String(raw []byte, converter EncodingConverter) (string, error) {
 	utf8, err := converter.Decode(raw)
@@ -150,7 +303,7 @@ func sanitizeString(raw []byte) []byte {
 
 // nthBit returns the nth bit of a byte slice
 func getNthBit(bytes []byte, n int) bool {
-	if n > len(bytes)*8 {
+	if n < 0 || n >= len(bytes)*8 {
 		return false
 	}
 	byteIndex := n / 8 // byte index
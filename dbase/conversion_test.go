@@ -0,0 +1,84 @@
+package dbase
+
+import (
+	"testing"
+)
+
+// TestParseNumeric parses valid and malformed numerics, the fast paths must agree with strconv
+func TestParseNumeric(t *testing.T) {
+	floats := []struct {
+		raw   string
+		value float64
+		fails bool
+	}{
+		{"      5.30", 5.3, false},
+		{"     -5.30", -5.3, false},
+		{"     +5.30", 5.3, false},
+		{"      -.50", -0.5, false},
+		{"          ", 0, false},
+		{"      12  ", 12, false},
+		{"    +-5.3 ", 0, true},
+		{"    -+5.3 ", 0, true},
+		{"    --5.3 ", 0, true},
+		{"     5.-3 ", 0, true},
+		{"     5.+3 ", 0, true},
+		{"     5-.3 ", 0, true},
+		{"      5.3-", 0, true},
+		{"      +-5 ", 0, true},
+		{"       5. ", 5, false},
+		{"     5.3.1", 0, true},
+		{"       1a ", 0, true},
+		{"         -", 0, true},
+	}
+	for _, tt := range floats {
+		t.Run("float "+tt.raw, func(t *testing.T) {
+			value, err := parseFloat([]byte(tt.raw))
+			if tt.fails {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if value != tt.value {
+				t.Errorf("parsed %v, expected %v", value, tt.value)
+			}
+		})
+	}
+
+	integers := []struct {
+		raw   string
+		value int64
+		fails bool
+	}{
+		{"   42", 42, false},
+		{"  -42", -42, false},
+		{"  +42", 42, false},
+		{"     ", 0, false},
+		{" +-42", 0, true},
+		{" -+42", 0, true},
+		{"  4-2", 0, true},
+		{"  42-", 0, true},
+		{"    -", 0, true},
+		{"  4.2", 0, true},
+	}
+	for _, tt := range integers {
+		t.Run("integer "+tt.raw, func(t *testing.T) {
+			value, err := parseNumericInt([]byte(tt.raw))
+			if tt.fails {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if value != tt.value {
+				t.Errorf("parsed %v, expected %v", value, tt.value)
+			}
+		})
+	}
+}
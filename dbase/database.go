@@ -65,6 +65,31 @@ func OpenDatabase(config *Config) (*Database, error) {
 			Exclusive:                         config.Exclusive,
 			Untested:                          config.Untested,
 			TrimSpaces:                        config.TrimSpaces,
+			TrimMode:                          config.TrimMode,
+			TrimCutset:                        config.TrimCutset,
+			NilPolicy:                         config.NilPolicy,
+			JSON:                              config.JSON,
+			CaseInsensitiveNames:              config.CaseInsensitiveNames,
+			FastRead:                          config.FastRead,
+			Throttle:                          config.Throttle,
+			AutoReopen:                        config.AutoReopen,
+			ReopenInterval:                    config.ReopenInterval,
+			Reproducible:                      config.Reproducible,
+			Schema:                            config.Schema,
+			CastPolicy:                        config.CastPolicy,
+			IgnoreDeleted:                     config.IgnoreDeleted,
+			VerifyReads:                       config.VerifyReads,
+			MemoryLimit:                       config.MemoryLimit,
+			TempDir:                           config.TempDir,
+			CheckCompanions:                   config.CheckCompanions,
+			SkipMissingMemo:                   config.SkipMissingMemo,
+			WithoutMemo:                       config.WithoutMemo,
+			WithoutIndex:                      config.WithoutIndex,
+			MemoHistory:                       config.MemoHistory,
+			SharedWrite:                       config.SharedWrite,
+			LockRetry:                         config.LockRetry,
+			SchemaCache:                       config.SchemaCache,
+			RowCache:                          config.RowCache,
 			DisableConvertFilenameUnderscores: config.DisableConvertFilenameUnderscores,
 			ReadOnly:                          config.ReadOnly,
 			WriteLock:                         config.WriteLock,
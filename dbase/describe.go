@@ -0,0 +1,64 @@
+package dbase
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+)
+
+// String returns the column as name, type, length and decimals followed by its flags, e.g. "PRICE N(10,2) nullable"
+func (c *Column) String() string {
+	s := fmt.Sprintf("%s %s(%d,%d)", c.Name(), c.Type(), c.Length, c.Decimals)
+	if flags := c.flags(); len(flags) > 0 {
+		s += " " + strings.Join(flags, " ")
+	}
+	return s
+}
+
+// flags returns the names of the flags set for the column
+func (c *Column) flags() []string {
+	flags := make([]string, 0)
+	if c.Flag&byte(HiddenFlag) != 0 {
+		flags = append(flags, "hidden")
+	}
+	if c.Flag&byte(NullableFlag) != 0 {
+		flags = append(flags, "nullable")
+	}
+	// The autoincrement flag includes the binary flag
+	if c.Flag&byte(AutoincrementFlag) == byte(AutoincrementFlag) {
+		flags = append(flags, "autoincrement")
+	} else if c.Flag&byte(BinaryFlag) != 0 {
+		flags = append(flags, "binary")
+	}
+	return flags
+}
+
+// String returns a one line summary of the table for logging, e.g. "TEST.DBF: Visual FoxPro, 3 rows, 16 columns"
+func (file *File) String() string {
+	return fmt.Sprintf("%s: %s, %d rows, %d columns", filepath.Base(file.config.Filename), file.header.Version(), file.header.RowsCount, len(file.table.columns))
+}
+
+// Describe returns an aligned summary of the table: file type, code page, rows, memo file and all columns with their types, lengths and flags
+func (file *File) Describe() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Table:\t%s\n", file.config.Filename)
+	fmt.Fprintf(w, "File type:\t%s (0x%02X)\n", file.header.Version(), file.header.FileType)
+	fmt.Fprintf(w, "Code page:\t%s (0x%02X)\n", CodePageName(file.header.CodePage), file.header.CodePage)
+	fmt.Fprintf(w, "Modified:\t%s\n", file.header.Modified(0).Format("2006-01-02"))
+	fmt.Fprintf(w, "Rows:\t%d\n", file.header.RowsCount)
+	fmt.Fprintf(w, "Row length:\t%d bytes, first row at %d\n", file.header.RowLength, file.header.FirstRow)
+	if file.memoHeader != nil {
+		fmt.Fprintf(w, "Memo:\tblock size %d, next free block %d\n", file.memoHeader.BlockSize, file.memoHeader.NextFree)
+	}
+	fmt.Fprintf(w, "Columns:\t%d\n", len(file.table.columns))
+	w.Flush()
+	// Column names have at most 10 characters and type names at most 9
+	fmt.Fprintf(&b, "%4s  %-10s  %-11s  %6s  %8s  %8s  %s\n", "#", "Name", "Type", "Length", "Decimals", "Position", "Flags")
+	for i, column := range file.table.columns {
+		line := fmt.Sprintf("%4d  %-10s  %-11s  %6d  %8d  %8d  %s", i+1, column.Name(), column.Type()+" "+column.Kind().Name(), column.Length, column.Decimals, column.Position, strings.Join(column.flags(), ", "))
+		b.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,208 @@
+package dbase
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Family is the product family that created a table file
+type Family string
+
+const (
+	FamilyUnknown       Family = "unknown"
+	FamilyDBase3        Family = "dBase III"
+	FamilyDBase4        Family = "dBase IV"
+	FamilyDBase5        Family = "dBase V"
+	FamilyDBase7        Family = "dBase 7"
+	FamilyFoxBase       Family = "FoxBASE"
+	FamilyFoxPro2       Family = "FoxPro 2.x"
+	FamilyVisualFoxPro  Family = "Visual FoxPro"
+	FamilyClipper       Family = "Clipper"
+	FamilyHiPerSix      Family = "HiPer-Six"
+	FamilyDBaseSQLTable Family = "dBase IV SQL"
+)
+
+// MemoType is the format of the memo file belonging to a table
+type MemoType string
+
+const (
+	MemoNone MemoType = ""
+	MemoDBT  MemoType = "DBT" // dBase and Clipper memo file
+	MemoFPT  MemoType = "FPT" // FoxPro memo file
+	MemoSMT  MemoType = "SMT" // HiPer-Six memo file
+)
+
+// IndexType is the format of an index file belonging to a table
+type IndexType string
+
+const (
+	IndexCDX IndexType = "CDX" // FoxPro compound index
+	IndexIDX IndexType = "IDX" // FoxPro single index
+	IndexMDX IndexType = "MDX" // dBase IV multiple index
+	IndexNDX IndexType = "NDX" // dBase III index
+	IndexNTX IndexType = "NTX" // Clipper index
+)
+
+// Capabilities lists which features of this package are available for a detected table
+type Capabilities struct {
+	Read   bool // Rows can be read
+	Write  bool // Rows can be written
+	Memo   bool // Memo fields can be read and written
+	Index  bool // Index files are used and maintained
+	Tested bool // The file version is tested, otherwise Config.Untested is required to open the table
+}
+
+// Detection is the result of Detect
+type Detection struct {
+	Version      FileVersion  // File type byte of the header
+	Family       Family       // Detected product family
+	Memo         MemoType     // Expected memo file format, empty if the table has no memo file
+	MemoFile     string       // Path of the memo file, empty if it was not found
+	Indexes      []IndexType  // Formats of the index files found next to the table
+	IndexFiles   []string     // Paths of the index files found next to the table
+	Header       *Header      // Header of the table
+	Capabilities Capabilities // Features of this package available for the table
+	Notes        []string     // Explanations for unsupported features
+}
+
+// Detect reads the header of the table at path and looks for related memo and index files.
+// It returns the product family, memo and index formats and which features of this package are supported for the table.
+func Detect(path string) (*Detection, error) {
+	header, err := ReadHeader(path)
+	if err != nil {
+		return nil, newError("dbase-detect-detect-1", err)
+	}
+	detection := &Detection{
+		Version: FileVersion(header.FileType),
+		Header:  header,
+	}
+	detection.Indexes, detection.IndexFiles, err = findIndexes(path)
+	if err != nil {
+		return nil, newError("dbase-detect-detect-2", err)
+	}
+	detection.Family, detection.Memo = detectFamily(header, detection.Indexes)
+	if detection.Memo != MemoNone {
+		ext := "." + string(detection.Memo)
+		// Database containers store their memos in a DCT file
+		if strings.EqualFold(filepath.Ext(path), string(DBC)) {
+			ext = string(DCT)
+		}
+		memoPath, err := _findFile(strings.TrimSuffix(path, filepath.Ext(path)) + ext)
+		if err != nil {
+			return nil, newError("dbase-detect-detect-3", err)
+		}
+		if _, err := os.Stat(memoPath); err == nil {
+			detection.MemoFile = memoPath
+		} else {
+			detection.Notes = append(detection.Notes, fmt.Sprintf("memo file %s not found", filepath.Base(memoPath)))
+		}
+	}
+	detection.capabilities()
+	return detection, nil
+}
+
+// detectFamily determines the product family and memo format from the file type byte.
+// Tables without memo share the file type 0x03, the index files are used to tell them apart.
+func detectFamily(header *Header, indexes []IndexType) (Family, MemoType) {
+	has := func(index IndexType) bool {
+		for _, i := range indexes {
+			if i == index {
+				return true
+			}
+		}
+		return false
+	}
+	switch header.FileType {
+	case byte(FoxBase):
+		return FamilyFoxBase, MemoNone
+	case byte(FoxBasePlus):
+		switch {
+		case has(IndexNTX):
+			return FamilyClipper, MemoNone
+		case has(IndexCDX) || has(IndexIDX):
+			return FamilyFoxPro2, MemoNone
+		case has(IndexMDX):
+			return FamilyDBase4, MemoNone
+		}
+		return FamilyDBase3, MemoNone
+	case byte(DBase7):
+		return FamilyDBase7, MemoNone
+	case byte(DBase5):
+		return FamilyDBase5, MemoNone
+	case byte(FoxPro), byte(FoxProAutoincrement), byte(FoxProVar):
+		if MemoFlag.Defined(header.TableFlags) {
+			return FamilyVisualFoxPro, MemoFPT
+		}
+		return FamilyVisualFoxPro, MemoNone
+	case byte(DBaseSQLTable), byte(DBaseSQLSystem):
+		return FamilyDBaseSQLTable, MemoNone
+	case byte(DBaseSQLMemo):
+		return FamilyDBaseSQLTable, MemoDBT
+	case byte(FoxBasePlusMemo):
+		if has(IndexNTX) {
+			return FamilyClipper, MemoDBT
+		}
+		return FamilyDBase3, MemoDBT
+	case byte(DBaseMemo):
+		return FamilyDBase4, MemoDBT
+	case byte(DBase7Memo):
+		return FamilyDBase7, MemoDBT
+	case byte(HiPerSixMemo):
+		return FamilyHiPerSix, MemoSMT
+	case byte(FoxPro2Memo):
+		return FamilyFoxPro2, MemoFPT
+	case byte(FoxBase2):
+		return FamilyFoxBase, MemoNone
+	}
+	return FamilyUnknown, MemoNone
+}
+
+// capabilities fills the capabilities and notes according to the detected formats
+func (d *Detection) capabilities() {
+	d.Capabilities.Tested = ValidateFileVersion(byte(d.Version), false) == nil
+	if d.Family == FamilyUnknown {
+		d.Notes = append(d.Notes, fmt.Sprintf("unknown file type 0x%02x", byte(d.Version)))
+		return
+	}
+	d.Capabilities.Read = true
+	d.Capabilities.Write = true
+	if !d.Capabilities.Tested {
+		d.Notes = append(d.Notes, fmt.Sprintf("file type 0x%02x (%s) is not tested, Config.Untested is required", byte(d.Version), d.Family))
+	}
+	switch d.Memo {
+	case MemoFPT:
+		d.Capabilities.Memo = len(d.MemoFile) > 0
+	case MemoNone:
+		d.Capabilities.Memo = true
+	default:
+		d.Notes = append(d.Notes, fmt.Sprintf("%s memo files are not supported, memo fields can not be read", d.Memo))
+	}
+	if len(d.IndexFiles) > 0 {
+		d.Notes = append(d.Notes, "index files are neither used nor maintained, they have to be rebuilt after writing")
+	}
+}
+
+// findIndexes returns the index files next to the table with the same base name
+func findIndexes(path string) ([]IndexType, []string, error) {
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return nil, nil, newError("dbase-detect-findindexes-1", err)
+	}
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	types := make([]IndexType, 0)
+	files := make([]string, 0)
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || !strings.EqualFold(strings.TrimSuffix(entry.Name(), ext), base) {
+			continue
+		}
+		switch index := IndexType(strings.ToUpper(strings.TrimPrefix(ext, "."))); index {
+		case IndexCDX, IndexIDX, IndexMDX, IndexNDX, IndexNTX:
+			types = append(types, index)
+			files = append(files, filepath.Join(filepath.Dir(path), entry.Name()))
+		}
+	}
+	return types, files, nil
+}
@@ -0,0 +1,183 @@
+package dbase
+
+import (
+	"container/heap"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+)
+
+// DefaultDictionaryLimit is the default maximum number of distinct values counted exactly by ColumnDictionary
+const DefaultDictionaryLimit = 100000
+
+// countMinDepth is the number of hash functions of the count-min sketch
+const countMinDepth = 4
+
+// ColumnDictionary contains the distinct values of a column and how often they occur, e.g. to build lookup tables
+type ColumnDictionary struct {
+	Column  *Column            // Counted column
+	Rows    uint32             // Number of counted rows
+	Exact   bool               // False if the column has more distinct values than the limit, the counts are estimates then
+	Entries []*DictionaryEntry // Distinct values by descending count, only the most frequent values if not exact
+}
+
+// DictionaryEntry is a distinct value of a column and its count
+type DictionaryEntry struct {
+	Value interface{} // Value of the column, trailing spaces of strings are removed
+	Count uint32      // Number of rows containing the value, an upper bound if the dictionary is not exact
+
+	key   string
+	index int
+}
+
+// ColumnDictionary counts the distinct values of the column. Only the bytes of the column are interpreted.
+// Up to limit distinct values are counted exactly (DefaultDictionaryLimit if limit <= 0).
+// If the column contains more distinct values, the counting continues with a count-min sketch with bounded memory:
+// the dictionary then contains the limit most frequent values with estimated counts that may be too high, but never too low.
+// Values are compared like in dBase, trailing spaces of strings are ignored.
+func (file *File) ColumnDictionary(name string, skipDeleted bool, limit int) (*ColumnDictionary, error) {
+	pos := file.ColumnPosByName(name)
+	if pos < 0 {
+		return nil, newError("dbase-dictionary-columndictionary-1", fmt.Errorf("column '%s' not found", name))
+	}
+	if limit <= 0 {
+		limit = DefaultDictionaryLimit
+	}
+	dictionary := &ColumnDictionary{Column: file.table.columns[pos], Exact: true}
+	entries := make(map[string]*DictionaryEntry)
+	var sketch *countMinSketch
+	var frequent *dictionaryHeap
+	err := file.scanKeys([]string{name}, func(position uint32, deleted bool, key string, values []interface{}) {
+		if deleted && (skipDeleted || file.deletedHidden) {
+			return
+		}
+		dictionary.Rows++
+		if sketch == nil {
+			if entry, ok := entries[key]; ok {
+				entry.Count++
+				return
+			}
+			if len(entries) < limit {
+				entries[key] = &DictionaryEntry{Value: dictionaryValue(values[0]), Count: 1, key: key}
+				return
+			}
+			// Too many distinct values, continue with the estimation
+			debugf("Column %s has more than %d distinct values, estimating counts", name, limit)
+			dictionary.Exact = false
+			sketch = newCountMinSketch(4 * limit)
+			frequent = &dictionaryHeap{}
+			for _, entry := range entries {
+				sketch.add(entry.key, entry.Count)
+				heap.Push(frequent, entry)
+			}
+		}
+		count := sketch.add(key, 1)
+		if entry, ok := entries[key]; ok {
+			entry.Count = count
+			heap.Fix(frequent, entry.index)
+			return
+		}
+		if min := (*frequent)[0]; count > min.Count {
+			delete(entries, min.key)
+			entry := &DictionaryEntry{Value: dictionaryValue(values[0]), Count: count, key: key}
+			entries[key] = entry
+			(*frequent)[0] = entry
+			entry.index = 0
+			heap.Fix(frequent, 0)
+		}
+	})
+	if err != nil {
+		return nil, newError("dbase-dictionary-columndictionary-2", err)
+	}
+	dictionary.Entries = make([]*DictionaryEntry, 0, len(entries))
+	for _, entry := range entries {
+		dictionary.Entries = append(dictionary.Entries, entry)
+	}
+	sort.Slice(dictionary.Entries, func(i, j int) bool {
+		a, b := dictionary.Entries[i], dictionary.Entries[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.key < b.key
+	})
+	return dictionary, nil
+}
+
+// Count returns the count of the value, 0 if the value is not part of the dictionary
+func (d *ColumnDictionary) Count(value interface{}) uint32 {
+	key := relationKey([]interface{}{value})
+	for _, entry := range d.Entries {
+		if entry.key == key {
+			return entry.Count
+		}
+	}
+	return 0
+}
+
+// dictionaryValue removes the trailing spaces of strings, as they are ignored when comparing values
+func dictionaryValue(val interface{}) interface{} {
+	if s, ok := val.(string); ok {
+		return strings.TrimRight(s, " ")
+	}
+	return val
+}
+
+// countMinSketch estimates the counts of keys with a fixed amount of memory
+type countMinSketch struct {
+	width  uint64
+	counts [countMinDepth][]uint32
+}
+
+// newCountMinSketch returns a sketch with width counters per hash function
+func newCountMinSketch(width int) *countMinSketch {
+	sketch := &countMinSketch{width: uint64(width)}
+	for i := range sketch.counts {
+		sketch.counts[i] = make([]uint32, width)
+	}
+	return sketch
+}
+
+// add adds n to the count of the key and returns the new estimated count
+func (s *countMinSketch) add(key string, n uint32) uint32 {
+	hash := fnv.New64a()
+	hash.Write([]byte(key))
+	h1 := hash.Sum64()
+	// The second hash is derived by rotating the first one, so every row uses a different counter
+	h2 := h1>>32 | h1<<32 | 1
+	estimate := ^uint32(0)
+	for i := range s.counts {
+		counter := &s.counts[i][(h1+uint64(i)*h2)%s.width]
+		*counter += n
+		if *counter < estimate {
+			estimate = *counter
+		}
+	}
+	return estimate
+}
+
+// dictionaryHeap is a min heap of dictionary entries by count
+type dictionaryHeap []*DictionaryEntry
+
+func (h dictionaryHeap) Len() int { return len(h) }
+
+func (h dictionaryHeap) Less(i, j int) bool { return h[i].Count < h[j].Count }
+
+func (h dictionaryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *dictionaryHeap) Push(x interface{}) {
+	entry := x.(*DictionaryEntry)
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *dictionaryHeap) Pop() interface{} {
+	old := *h
+	entry := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return entry
+}
@@ -0,0 +1,5 @@
+// Package dbase implements reading and writing of Visual FoxPro and other dBase tables, their memo and index files.
+//
+// It is the implementation behind the version 2 API in github.com/Valentin-Kaiser/go-dbase/v2/dbase.
+// The dbf package of the version 2 module re-exports this package, its types are aliases of the types of this package.
+package dbase
@@ -0,0 +1,201 @@
+package dbase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SchemaDrift describes how the columns of a table differ from the reference schema of the config.
+// If Config.Reference is set, the output of rows (ToMap, ToJSON, ToStruct, the exports and views) contains the reference columns in reference order:
+// columns are mapped by name, missing columns are filled with defaults and extra columns are ignored.
+// Values of changed columns are cast to the Go type of the reference column, unless Config.Schema declares a type.
+type SchemaDrift struct {
+	Missing []*Column       // Reference columns missing in the table, filled with the default set by SetDefault or the zero value of the type
+	Extra   []*Column       // Columns of the table that are not part of the reference, ignored
+	Changed []*ColumnChange // Columns with another type, length or decimals than in the reference
+
+	positions []int                // Position of each reference column in the table, -1 if missing
+	casts     map[*Column]CastType // Go type of the reference column by changed column of the table
+}
+
+// ColumnChange is a column of the table that differs from the reference column of the same name
+type ColumnChange struct {
+	Reference *Column // Column of the reference schema
+	Column    *Column // Column of the table
+}
+
+// Empty returns true if the table matches the reference schema
+func (d *SchemaDrift) Empty() bool {
+	return len(d.Missing) == 0 && len(d.Extra) == 0 && len(d.Changed) == 0
+}
+
+// String returns a summary of the drift, e.g. for logging
+func (d *SchemaDrift) String() string {
+	if d.Empty() {
+		return "no drift"
+	}
+	names := func(columns []*Column) string {
+		list := make([]string, len(columns))
+		for i, column := range columns {
+			list[i] = column.Name()
+		}
+		return strings.Join(list, ", ")
+	}
+	parts := make([]string, 0, 3)
+	if len(d.Missing) > 0 {
+		parts = append(parts, "missing: "+names(d.Missing))
+	}
+	if len(d.Extra) > 0 {
+		parts = append(parts, "extra: "+names(d.Extra))
+	}
+	if len(d.Changed) > 0 {
+		list := make([]string, len(d.Changed))
+		for i, change := range d.Changed {
+			list[i] = fmt.Sprintf("%s %s(%d,%d) instead of %s(%d,%d)", change.Column.Name(), change.Column.Type(), change.Column.Length, change.Column.Decimals, change.Reference.Type(), change.Reference.Length, change.Reference.Decimals)
+		}
+		parts = append(parts, "changed: "+strings.Join(list, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// SchemaDrift returns the differences of the table to Config.Reference, nil if no reference schema is set
+func (file *File) SchemaDrift() *SchemaDrift {
+	return file.drift
+}
+
+// compareReference maps the reference columns by name to the columns of the table
+func (file *File) compareReference() *SchemaDrift {
+	drift := &SchemaDrift{positions: make([]int, len(file.config.Reference))}
+	mapped := make(map[int]bool, len(file.table.columns))
+	for i, reference := range file.config.Reference {
+		pos := file.ColumnPosByName(reference.Name())
+		drift.positions[i] = pos
+		if pos < 0 {
+			drift.Missing = append(drift.Missing, reference)
+			continue
+		}
+		mapped[pos] = true
+		column := file.table.columns[pos]
+		if column.DataType == reference.DataType && column.Length == reference.Length && column.Decimals == reference.Decimals {
+			continue
+		}
+		drift.Changed = append(drift.Changed, &ColumnChange{Reference: reference, Column: column})
+		if cast := referenceCast(reference); cast != referenceCast(column) {
+			if drift.casts == nil {
+				drift.casts = make(map[*Column]CastType)
+			}
+			drift.casts[column] = cast
+		}
+	}
+	for pos, column := range file.table.columns {
+		if !mapped[pos] {
+			drift.Extra = append(drift.Extra, column)
+		}
+	}
+	return drift
+}
+
+// referenceCast returns the Go type the values of the column are decoded to
+func referenceCast(column *Column) CastType {
+	switch DataType(column.DataType) {
+	case Character, Varchar, Memo:
+		return CastString
+	case Numeric:
+		if column.Decimals == 0 {
+			return CastInt
+		}
+		return CastFloat
+	case Integer:
+		return CastInt
+	case Float, Double, Currency:
+		return CastFloat
+	case Logical:
+		return CastBool
+	case Date, DateTime:
+		return CastTime
+	}
+	return CastBytes
+}
+
+// referenceValues calls fn with the values of the reference columns, see modifiedValues
+func (row *Row) referenceValues(fn func(field *Field, key string, val interface{})) error {
+	file := row.handle
+	for i, reference := range file.config.Reference {
+		pos := file.drift.positions[i]
+		if pos >= 0 {
+			err := row.modifiedValue(pos, fn)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		val, err := file.castValue(reference, file.referenceDefault(reference))
+		if err != nil {
+			return newError("dbase-drift-referencevalues-1", err)
+		}
+		fn(&Field{column: reference, value: val}, reference.Name(), val)
+	}
+	return nil
+}
+
+// referenceDefault returns the value of a missing reference column:
+// the default set by SetDefault, nil if the nil policy applies to empty values of the column, otherwise the zero value of the type
+func (file *File) referenceDefault(column *Column) interface{} {
+	if def, ok := file.table.defaults[column]; ok {
+		return def()
+	}
+	policy := file.config.NilPolicy
+	switch DataType(column.DataType) {
+	case Character, Varchar, Memo:
+		if policy&NilEmptyText != 0 {
+			return nil
+		}
+		return ""
+	case Numeric, Float:
+		if policy&NilEmptyNumber != 0 {
+			return nil
+		}
+		if DataType(column.DataType) == Numeric && column.Decimals == 0 {
+			return int64(0)
+		}
+		return float64(0)
+	case Integer:
+		return int32(0)
+	case Double, Currency:
+		return float64(0)
+	case Logical:
+		return false
+	case Date, DateTime:
+		if policy&NilEmptyDate != 0 {
+			return nil
+		}
+		return time.Time{}
+	}
+	return nil
+}
+
+// missingReference returns the missing reference column with the name, nil if there is none
+func (file *File) missingReference(name string) *Column {
+	if file.drift == nil {
+		return nil
+	}
+	for _, column := range file.drift.Missing {
+		if name == column.Name() || (file.config.CaseInsensitiveNames && strings.EqualFold(name, column.Name())) {
+			return column
+		}
+	}
+	return nil
+}
+
+// outputColumns returns the columns of the output in order and their position in the table, -1 for missing reference columns
+func (file *File) outputColumns() ([]*Column, []int) {
+	if file.drift != nil {
+		return file.config.Reference, file.drift.positions
+	}
+	positions := make([]int, len(file.table.columns))
+	for i := range positions {
+		positions[i] = i
+	}
+	return file.table.columns, positions
+}
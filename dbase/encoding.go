@@ -6,10 +6,15 @@ import (
 
 	"io"
 
+	"golang.org/x/text/encoding"
 	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
 	"golang.org/x/text/transform"
 )
 
+// Byte order mark of UTF-8 encoded text
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
 // EncodingConverter is the interface as passed to Open
 type EncodingConverter interface {
 	Decode(in []byte) ([]byte, error)
@@ -118,3 +123,87 @@ func ConverterFromCodePage(codePageMark byte) DefaultConverter {
 		return NewDefaultConverter(charmap.Windows1250)
 	}
 }
+
+// CodePageName returns the name of the code page of a code page mark, e.g. "Windows ANSI"
+func CodePageName(codePageMark byte) string {
+	switch codePageMark {
+	case 0x00:
+		return "none"
+	case 0x01:
+		return "U.S. MS-DOS"
+	case 0x02:
+		return "International MS-DOS"
+	case 0x64:
+		return "Eastern European MS-DOS"
+	case 0x66:
+		return "Nordic MS-DOS"
+	case 0x65:
+		return "Russian MS-DOS"
+	case 0x7C:
+		return "Thai Windows"
+	case 0xC8:
+		return "Central European Windows"
+	case 0xC9:
+		return "Russian Windows"
+	case 0x03:
+		return "Windows ANSI"
+	case 0xCB:
+		return "Greek Windows"
+	case 0xCA:
+		return "Turkish Windows"
+	case 0x7D:
+		return "Hebrew Windows"
+	case 0x7E:
+		return "Arabic Windows"
+	}
+	return "unknown"
+}
+
+// UnicodeConverter converts UTF-8 or UTF-16 text, which some applications store regardless of the table code page
+type UnicodeConverter struct {
+	encoding encoding.Encoding // UTF-16 encoding, nil for UTF-8
+}
+
+// NewUTF8Converter returns a converter for UTF-8 text, a leading byte order mark is removed on decode
+func NewUTF8Converter() UnicodeConverter {
+	return UnicodeConverter{}
+}
+
+// NewUTF16Converter returns a converter for UTF-16 text.
+// A byte order mark overrides the endianness on decode and is written on encode.
+func NewUTF16Converter(bigEndian bool) UnicodeConverter {
+	endianness := unicode.LittleEndian
+	if bigEndian {
+		endianness = unicode.BigEndian
+	}
+	return UnicodeConverter{encoding: unicode.UTF16(endianness, unicode.UseBOM)}
+}
+
+// Decode decodes UTF-8 or UTF-16 text to a UTF-8 byte slice
+func (c UnicodeConverter) Decode(in []byte) ([]byte, error) {
+	if c.encoding == nil {
+		return bytes.TrimPrefix(in, utf8BOM), nil
+	}
+	out, _, err := transform.Bytes(c.encoding.NewDecoder(), in)
+	if err != nil {
+		return nil, newError("dbase-encoding-decode-2", err)
+	}
+	return out, nil
+}
+
+// Encode encodes a UTF-8 byte slice to UTF-8 or UTF-16 text
+func (c UnicodeConverter) Encode(in []byte) ([]byte, error) {
+	if c.encoding == nil {
+		return in, nil
+	}
+	out, _, err := transform.Bytes(c.encoding.NewEncoder(), in)
+	if err != nil {
+		return nil, newError("dbase-encoding-encode-2", err)
+	}
+	return out, nil
+}
+
+// CodePage returns 0x00, as there is no code page mark for Unicode tables
+func (c UnicodeConverter) CodePage() byte {
+	return 0x00
+}
@@ -15,9 +15,41 @@ var (
 	// Returned when a file operation is attempted on a non existent file
 	ErrNoFPT = errors.New("FPT_FILE_NOT_FOUND")
 	ErrNoDBF = errors.New("DBF_FILE_NOT_FOUND")
+	// Returned on open if the table has memo columns but its memo file is missing, see Config.SkipMissingMemo
+	ErrMissingMemoFile = errors.New("MEMO_FILE_NOT_FOUND")
 	// Returned when an invalid column position is used (x<1 or x>number of columns)
 	ErrInvalidPosition = errors.New("INVALID_POSITION")
 	ErrInvalidEncoding = errors.New("INVALID_ENCODING")
+	// Returned in strict mode when a map or JSON row contains unknown keys or lacks required columns
+	ErrSchemaMismatch = errors.New("SCHEMA_MISMATCH")
+	// Returned by Ping if the table or memo file can not be read anymore
+	ErrFileUnavailable = errors.New("FILE_UNAVAILABLE")
+	// Returned by Ping if the file at the table path was removed or replaced by another file
+	ErrFileReplaced = errors.New("FILE_REPLACED")
+	// Returned by Ping if the header does not match the structure read when the table was opened
+	ErrHeaderChanged = errors.New("HEADER_CHANGED")
+	// Returned when a system column like _NullFlags is written through the field API, system columns are maintained by the table
+	ErrSystemColumn = errors.New("SYSTEM_COLUMN")
+	// Returned when the header can not describe the columns, e.g. the columns do not fit into the row length of a corrupt or crafted file
+	ErrInvalidHeader = errors.New("INVALID_HEADER")
+	// Returned when a memo address or the length of a memo block points outside of the memo file
+	ErrInvalidMemo = errors.New("INVALID_MEMO")
+	// Returned when an index file can not be read because its nodes or tag headers are corrupt
+	ErrInvalidIndex = errors.New("INVALID_INDEX")
+	// Returned by CreateIndex and Pack if the table has a structural index, which is maintained by FoxPro and not by this package
+	ErrStructuralIndex = errors.New("STRUCTURAL_INDEX")
+	// Returned by CreateIndex if rows of a candidate tag have the same key and wrapped by ConstraintError if a written row violates a unique key
+	ErrDuplicateKey = errors.New("DUPLICATE_KEY")
+	// Returned if a row that can not be decoded returns different data when it is read again, so the read and not the file is corrupt
+	ErrIntegrity = errors.New("IO_INTEGRITY")
+	// Returned by Rows and RowsWithErrors if the collected rows exceed Config.MemoryLimit and by OpenArchive and Restore if the archived data exceeds Config.MaxArchiveSize
+	ErrMemoryLimit = errors.New("MEMORY_LIMIT")
+	// Wrapped by LockError if a row, the header or the memo file stays locked by another process, see Config.LockRetry
+	ErrLocked = errors.New("LOCKED")
+	// Returned when rows of a table with duplicate column names are converted with DuplicateError, see Config.DuplicateNames
+	ErrDuplicateColumn = errors.New("DUPLICATE_COLUMN")
+	// Returned if the IO implementation of the table does not support an operation, see RawIO and OpenAll
+	ErrUnsupported = errors.New("UNSUPPORTED")
 )
 
 // Error is a wrapper for errors that occur in the dbase package
@@ -52,6 +84,11 @@ func (e Error) Error() string {
 	return e.err.Error()
 }
 
+// Unwrap returns the wrapped error, so errors.Is can be used with the sentinel errors
+func (e Error) Unwrap() error {
+	return e.err
+}
+
 // Context returns the context of the error in the dbase package
 func (e Error) Context() []string {
 	return e.context
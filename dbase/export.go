@@ -0,0 +1,297 @@
+package dbase
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ExportCSV streams all rows as CSV to the writer.
+// The first line contains the column names (or external keys) in column order.
+// The internal row pointer is restored after the export.
+//
+// The exports contain no timestamps and are written in column order with LF line endings.
+// If Reproducible is set in the config, the output of unchanged tables is byte-identical across runs and platforms:
+// line endings within text are normalized to LF, numbers of N, F and Y columns are formatted with the decimals
+// of the column and times are converted to UTC. This applies to ExportCSV, ExportJSONL and ToJSON.
+func (file *File) ExportCSV(w io.Writer, skipDeleted bool) error {
+	err := file.Export(newCSVExporter(w), skipDeleted)
+	if err != nil {
+		return newError("dbase-export-exportcsv-1", err)
+	}
+	return nil
+}
+
+// ExportJSONL streams all rows as newline delimited JSON objects to the writer.
+// The keys of each object are written in column order.
+// The internal row pointer is restored after the export.
+func (file *File) ExportJSONL(w io.Writer, skipDeleted bool) error {
+	err := file.Export(newJSONLExporter(w), skipDeleted)
+	if err != nil {
+		return newError("dbase-export-exportjsonl-1", err)
+	}
+	return nil
+}
+
+// ExportValues returns the values of the row as written by the exporters in column order:
+// modifications are applied, hidden columns are left out and the values are normalized if Reproducible is set.
+func (row *Row) ExportValues() ([]KeyValue, error) {
+	out := make([]KeyValue, 0, len(row.fields))
+	err := row.modifiedValues(func(field *Field, key string, val interface{}) {
+		out = append(out, KeyValue{Key: key, Value: row.handle.stableValue(field.column, val)})
+	})
+	if err != nil {
+		return nil, newError("dbase-export-exportvalues-1", err)
+	}
+	return out, nil
+}
+
+// documentValues returns the values of the row for the YAML and TOML exporters in column order,
+// keys and values are converted with the JSON options like in ExportJSONL
+func (row *Row) documentValues() ([]KeyValue, error) {
+	out := make([]KeyValue, 0, len(row.fields))
+	err := row.jsonValues(func(field *Field, key string, val interface{}) {
+		out = append(out, KeyValue{Key: key, Value: row.handle.stableValue(field.column, val)})
+	})
+	if err != nil {
+		return nil, newError("dbase-export-documentvalues-1", err)
+	}
+	return out, nil
+}
+
+// formatDocumentFloat formats a float for YAML and TOML, which need a fraction or an exponent to read the value as float
+func formatDocumentFloat(v float64, inf string, nan string) string {
+	switch {
+	case math.IsNaN(v):
+		return nan
+	case math.IsInf(v, 1):
+		return inf
+	case math.IsInf(v, -1):
+		return "-" + inf
+	}
+	text := strconv.FormatFloat(v, 'f', -1, 64)
+	if !strings.Contains(text, ".") {
+		text += ".0"
+	}
+	return text
+}
+
+// schemaTypeName returns the table name as type name of generated schemas in CamelCase, e.g. ExpenseReports
+func schemaTypeName(table string) string {
+	var builder strings.Builder
+	upper := true
+	for _, r := range table {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			upper = true
+			continue
+		}
+		if builder.Len() == 0 && unicode.IsDigit(r) {
+			builder.WriteString("Table")
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		builder.WriteRune(r)
+	}
+	if builder.Len() == 0 {
+		return "Table"
+	}
+	return builder.String()
+}
+
+// schemaFieldName returns the key as field name of generated schemas, characters that are not allowed are replaced by underscores
+func schemaFieldName(key string) string {
+	var builder strings.Builder
+	for _, r := range key {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_') {
+			r = '_'
+		}
+		if builder.Len() == 0 && unicode.IsDigit(r) {
+			builder.WriteByte('_')
+		}
+		builder.WriteRune(r)
+	}
+	if builder.Len() == 0 {
+		return "_"
+	}
+	return builder.String()
+}
+
+// schemaFieldNames returns the field names of the keys, keys differing only in characters that are not allowed get a suffix
+func schemaFieldNames(keys []string) []string {
+	names := make([]string, 0, len(keys))
+	used := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		name := schemaFieldName(key)
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s_%d", schemaFieldName(key), n)
+		}
+		used[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// exportInt converts the value of an integer field of the binary exporters
+func exportInt(val interface{}) (int64, error) {
+	switch v := val.(type) {
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case float64:
+		return int64(v), nil
+	case json.Number:
+		return v.Int64()
+	case string:
+		return strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+	}
+	return 0, fmt.Errorf("invalid type %T for integer", val)
+}
+
+// exportFloat converts the value of a floating point field of the binary exporters, values of Reproducible exports are json.Number
+func exportFloat(val interface{}) (float64, error) {
+	switch v := val.(type) {
+	case float64:
+		return v, nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case json.Number:
+		return v.Float64()
+	case string:
+		return strconv.ParseFloat(strings.TrimSpace(v), 64)
+	}
+	return 0, fmt.Errorf("invalid type %T for double", val)
+}
+
+// forEachRow calls fn for every row of the table and restores the internal row pointer afterwards
+func (file *File) forEachRow(skipDeleted bool, fn func(row *Row) error) error {
+	err := file.autoReopen()
+	if err != nil {
+		return newError("dbase-export-foreachrow-3", err)
+	}
+	pointer := file.table.rowPointer
+	file.scans++
+	defer func() {
+		file.table.rowPointer = pointer
+		file.scans--
+	}()
+	file.table.rowPointer = 0
+	throttle := file.throttle()
+	// Hidden deleted rows are skipped before the end is checked, so trailing deleted rows do not cause a read past the end
+	for file.skipHidden(); !file.EOF(); file.skipHidden() {
+		row, err := file.Next()
+		if err != nil {
+			return newError("dbase-export-foreachrow-1", err)
+		}
+		throttle.wait(1, uint64(file.header.RowLength))
+		if row.Deleted && skipDeleted {
+			continue
+		}
+		err = fn(row)
+		if err != nil {
+			return newError("dbase-export-foreachrow-2", err)
+		}
+	}
+	return nil
+}
+
+// orderedJSON encodes the row as JSON object with the keys in column order
+func (row *Row) orderedJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	err := row.writeJSON(buf)
+	if err != nil {
+		return nil, newError("dbase-export-orderedjson-1", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeJSON writes the row as JSON object with the keys in column order into the buffer, see writeJSONValue
+func (row *Row) writeJSON(buf *bytes.Buffer) error {
+	buf.WriteByte('{')
+	first := true
+	var encodeErr error
+	err := row.jsonValues(func(field *Field, key string, val interface{}) {
+		if encodeErr != nil {
+			return
+		}
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		writeJSONString(buf, key)
+		buf.WriteByte(':')
+		encodeErr = writeJSONValue(buf, row.handle.stableValue(field.column, val))
+	})
+	if err != nil {
+		return err
+	}
+	if encodeErr != nil {
+		return encodeErr
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// stableValue normalizes the value for reproducible exports if Reproducible is set
+func (file *File) stableValue(column *Column, val interface{}) interface{} {
+	if !file.config.Reproducible {
+		return val
+	}
+	switch v := val.(type) {
+	case string:
+		v = strings.ReplaceAll(v, "\r\n", "\n")
+		return strings.ReplaceAll(v, "\r", "\n")
+	case float64:
+		switch DataType(column.DataType) {
+		case Numeric, Float:
+			return json.Number(strconv.FormatFloat(v, 'f', int(column.Decimals), 64))
+		case Currency:
+			return json.Number(strconv.FormatFloat(v, 'f', 4, 64))
+		}
+	case time.Time:
+		return v.UTC()
+	}
+	return val
+}
+
+// formatValue converts a field value to its textual representation used by the exporters
+func formatValue(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+}
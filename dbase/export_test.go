@@ -0,0 +1,203 @@
+package dbase
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestScansWithHiddenRows scans tables with deleted rows at the start, in between and at the end while deleted rows are hidden
+func TestScansWithHiddenRows(t *testing.T) {
+	tables := []struct {
+		name    string
+		deleted []bool
+		active  int
+	}{
+		{"mixed", []bool{true, false, true, false, true}, 2},
+		{"all deleted", []bool{true, true, true}, 0},
+	}
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			dir := t.TempDir()
+			file := createHiddenTable(t, filepath.Join(dir, "HIDDEN.DBF"), table.deleted)
+			defer file.Close()
+			file.SetDeletedHidden(true)
+
+			scans := []struct {
+				name string
+				scan func() (int, error)
+			}{
+				{"Rows", func() (int, error) {
+					err := file.GoTo(0)
+					if err != nil {
+						return 0, err
+					}
+					rows, err := file.Rows(false, false)
+					return len(rows), err
+				}},
+				{"ExportCSV", func() (int, error) {
+					var buf bytes.Buffer
+					err := file.ExportCSV(&buf, false)
+					// One line of column names and one line per row
+					return bytes.Count(buf.Bytes(), []byte("\n")) - 1, err
+				}},
+				{"ExportWithCheckpoint", func() (int, error) {
+					exported := 0
+					flushed := 0
+					err := file.ExportWithCheckpoint(&CheckpointConfig{
+						Path:     filepath.Join(dir, "export.checkpoint"),
+						Interval: 2,
+						Export: func(row *Row) error {
+							exported++
+							return nil
+						},
+						Flush: func() (int64, error) {
+							flushed++
+							return int64(exported), nil
+						},
+					})
+					// One checkpoint after every second row and the final flush
+					if err == nil && flushed != 1+exported/2 {
+						t.Errorf("flushed %d times for %d rows, expected %d", flushed, exported, 1+exported/2)
+					}
+					return exported, err
+				}},
+			}
+			for _, scan := range scans {
+				count, err := scan.scan()
+				if err != nil {
+					t.Fatalf("%s: %v", scan.name, err)
+				}
+				if count != table.active {
+					t.Errorf("%s scanned %d rows, expected the %d rows that are not deleted", scan.name, count, table.active)
+				}
+			}
+		})
+	}
+}
+
+// createHiddenTable creates a table with one row per deleted flag
+func createHiddenTable(t *testing.T, path string, deleted []bool) *File {
+	t.Helper()
+	column, err := NewColumn("NAME", Character, 10, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := CreateTable(&Config{Filename: path}, column)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, d := range deleted {
+		row := file.NewRow()
+		err = row.FieldByName("NAME").SetValue(string(rune('a' + i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		row.Deleted = d
+		err = row.Add()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return file
+}
+
+// TestWriteJSONValue encodes values like json.Marshal
+func TestWriteJSONValue(t *testing.T) {
+	values := []interface{}{
+		nil, true, false, "", "plain", "quote \" backslash \\ slash /", "<a href=\"x\">&amp;</a>",
+		"\x00\x01\b\f\n\r\t\x1f\x7f", "invalid \xff\xfe utf-8", "line\u2028separator\u2029", "ümlaut €",
+		int(-1), int8(-8), int16(16), int32(-32), int64(1 << 62), uint8(8), uint16(16), uint32(32), uint64(1 << 63),
+		float64(0), math.Copysign(0, -1), 1.5, -123.456, 1e-7, 1e-6, 1e20, 1e21, 1.234e-300, math.MaxFloat64, float32(3.14), float32(1e-7),
+		time.Date(2024, 2, 29, 13, 14, 15, 123456789, time.UTC), time.Date(1999, 12, 31, 0, 0, 0, 0, time.FixedZone("", 3600)), time.Time{},
+		[]byte(nil), []byte{}, []byte("a"), bytes.Repeat([]byte{0xFF, 0x00, 0x7F}, 50), json.Number("12.50"),
+		map[string]int{"b": 2, "a": 1},
+	}
+	for _, value := range values {
+		expected, err := json.Marshal(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		err = writeJSONValue(&buf, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != string(expected) {
+			t.Errorf("%#v: got %s, expected %s", value, buf.String(), expected)
+		}
+	}
+	for _, value := range []interface{}{math.NaN(), math.Inf(1), time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)} {
+		var buf bytes.Buffer
+		if writeJSONValue(&buf, value) == nil {
+			t.Errorf("%v: expected an error like json.Marshal", value)
+		}
+	}
+}
+
+// TestToJSONIntoAllocations reuses the buffer of ToJSONInto without allocations
+func TestToJSONIntoAllocations(t *testing.T) {
+	columns := make([]*Column, 0, 5)
+	for _, c := range []struct {
+		name     string
+		dataType DataType
+		length   uint8
+		decimals uint8
+	}{
+		{"NAME", Character, 20, 0},
+		{"COUNT", Integer, 4, 0},
+		{"PRICE", Double, 8, 2},
+		{"ACTIVE", Logical, 1, 0},
+		{"BORN", Date, 8, 0},
+	} {
+		column, err := NewColumn(c.name, c.dataType, c.length, c.decimals, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		columns = append(columns, column)
+	}
+	file, err := CreateTable(&Config{Filename: filepath.Join(t.TempDir(), "ALLOCS.DBF")}, columns...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	row := file.NewRow()
+	for name, value := range map[string]interface{}{"NAME": "<Müller & Söhne>", "COUNT": int32(1000), "PRICE": 12.5, "ACTIVE": true, "BORN": time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)} {
+		err = row.FieldByName(name).SetValue(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = row.Add()
+	if err != nil {
+		t.Fatal(err)
+	}
+	row, err = file.Row()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := row.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	err = row.ToJSONInto(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), expected) {
+		t.Errorf("ToJSONInto wrote %s, ToJSON returned %s", buf.Bytes(), expected)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		err = row.ToJSONInto(&buf)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if allocs != 0 {
+		t.Errorf("ToJSONInto made %v allocations per row, expected none", allocs)
+	}
+}
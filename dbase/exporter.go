@@ -0,0 +1,206 @@
+package dbase
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Exporter writes the rows of a table in an output format.
+// Additional formats (e.g. Avro, ORC or protobuf) can be added by implementing Exporter and registering it with RegisterExporter.
+type Exporter interface {
+	Begin(schema *ExportSchema) error // Called once before the first row, e.g. to write a header
+	WriteRow(row *Row) error          // Called for every exported row, the values are available from Row.ExportValues
+	End() error                       // Called once after the last row, e.g. to flush the output
+}
+
+// ExportSchema describes the exported columns
+type ExportSchema struct {
+	Name    string    // Table name, the file name without extension
+	Columns []*Column // Exported columns in column order, hidden columns are left out. The reference columns if Config.Reference is set
+	Keys    []string  // Column names or external keys of the exported columns
+}
+
+// ExporterFactory returns a new exporter writing to w
+type ExporterFactory func(w io.Writer) Exporter
+
+// exporters contains the registered exporters by format name
+var exporters = struct {
+	sync.RWMutex
+	factories map[string]ExporterFactory
+}{
+	factories: map[string]ExporterFactory{
+		"avro":            func(w io.Writer) Exporter { return newAvroExporter(w) },
+		"bigquery-csv":    func(w io.Writer) Exporter { return newWarehouseExporter(WarehouseBigQuery, true, w) },
+		"bigquery-jsonl":  func(w io.Writer) Exporter { return newWarehouseExporter(WarehouseBigQuery, false, w) },
+		"csv":             func(w io.Writer) Exporter { return newCSVExporter(w) },
+		"jsonl":           func(w io.Writer) Exporter { return newJSONLExporter(w) },
+		"postgres":        func(w io.Writer) Exporter { return newPostgresCopyExporter(w, false) },
+		"postgres-binary": func(w io.Writer) Exporter { return newPostgresCopyExporter(w, true) },
+		"protobuf":        func(w io.Writer) Exporter { return newProtobufExporter(w) },
+		"snowflake-csv":   func(w io.Writer) Exporter { return newWarehouseExporter(WarehouseSnowflake, true, w) },
+		"snowflake-jsonl": func(w io.Writer) Exporter { return newWarehouseExporter(WarehouseSnowflake, false, w) },
+		"toml":            func(w io.Writer) Exporter { return newTOMLExporter(w) },
+		"yaml":            func(w io.Writer) Exporter { return newYAMLExporter(w) },
+	},
+}
+
+// RegisterExporter registers the exporter for the format name, e.g. "avro". Names are case-insensitive.
+// The built-in formats are "avro", "csv", "jsonl", "postgres", "postgres-binary", "protobuf", "toml" and "yaml",
+// and the load files "bigquery-csv", "bigquery-jsonl", "snowflake-csv" and "snowflake-jsonl".
+func RegisterExporter(name string, factory ExporterFactory) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if len(name) == 0 || factory == nil {
+		return newError("dbase-exporter-registerexporter-1", fmt.Errorf("missing format name or exporter factory"))
+	}
+	exporters.Lock()
+	defer exporters.Unlock()
+	if _, ok := exporters.factories[name]; ok {
+		return newError("dbase-exporter-registerexporter-2", fmt.Errorf("exporter for format '%s' already registered", name))
+	}
+	exporters.factories[name] = factory
+	return nil
+}
+
+// Exporters returns the names of all registered formats in alphabetical order
+func Exporters() []string {
+	exporters.RLock()
+	defer exporters.RUnlock()
+	names := make([]string, 0, len(exporters.factories))
+	for name := range exporters.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewExporter returns a new exporter of the registered format writing to w
+func NewExporter(name string, w io.Writer) (Exporter, error) {
+	exporters.RLock()
+	factory, ok := exporters.factories[strings.ToLower(strings.TrimSpace(name))]
+	exporters.RUnlock()
+	if !ok {
+		return nil, newError("dbase-exporter-newexporter-1", fmt.Errorf("no exporter registered for format '%s'", name))
+	}
+	return factory(w), nil
+}
+
+// Export streams all rows through the exporter. The internal row pointer is restored after the export.
+func (file *File) Export(exporter Exporter, skipDeleted bool) error {
+	err := exporter.Begin(file.exportSchema())
+	if err != nil {
+		return newError("dbase-exporter-export-1", err)
+	}
+	err = file.forEachRow(skipDeleted, exporter.WriteRow)
+	if err != nil {
+		return newError("dbase-exporter-export-2", err)
+	}
+	err = exporter.End()
+	if err != nil {
+		return newError("dbase-exporter-export-3", err)
+	}
+	return nil
+}
+
+// exportSchema returns the schema of the exported columns
+func (file *File) exportSchema() *ExportSchema {
+	output, positions := file.outputColumns()
+	columns := make([]*Column, 0, len(output))
+	keys := make([]string, 0, len(output))
+	for i, column := range output {
+		pos := positions[i]
+		if pos < 0 {
+			columns = append(columns, column)
+			keys = append(keys, column.Name())
+			continue
+		}
+		own := file.table.columns[pos]
+		if redact, hidden := file.table.hidden[own]; hidden && !redact {
+			continue
+		}
+		if mod := file.table.mods[pos]; mod != nil && len(mod.ExternalKey) != 0 {
+			columns = append(columns, column)
+			keys = append(keys, mod.ExternalKey)
+			continue
+		}
+		// Errors of DuplicateError are returned by the rows
+		key, ok, _ := file.duplicateKey(own, own.Name())
+		if !ok {
+			continue
+		}
+		columns = append(columns, column)
+		keys = append(keys, key)
+	}
+	base := filepath.Base(file.config.Filename)
+	return &ExportSchema{
+		Name:    strings.TrimSuffix(base, filepath.Ext(base)),
+		Columns: columns,
+		Keys:    keys,
+	}
+}
+
+// csvExporter writes a header line with the keys and one line per row
+type csvExporter struct {
+	writer *csv.Writer
+	record []string
+}
+
+func newCSVExporter(w io.Writer) *csvExporter {
+	return &csvExporter{writer: csv.NewWriter(w)}
+}
+
+func (e *csvExporter) Begin(schema *ExportSchema) error {
+	e.record = make([]string, 0, len(schema.Keys))
+	return e.writer.Write(schema.Keys)
+}
+
+func (e *csvExporter) WriteRow(row *Row) error {
+	values, err := row.ExportValues()
+	if err != nil {
+		return err
+	}
+	e.record = e.record[:0]
+	for _, value := range values {
+		e.record = append(e.record, formatValue(value.Value))
+	}
+	return e.writer.Write(e.record)
+}
+
+func (e *csvExporter) End() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// jsonlExporter writes one JSON object per line
+type jsonlExporter struct {
+	writer *bufio.Writer
+}
+
+func newJSONLExporter(w io.Writer) *jsonlExporter {
+	return &jsonlExporter{writer: bufio.NewWriter(w)}
+}
+
+func (e *jsonlExporter) Begin(schema *ExportSchema) error {
+	return nil
+}
+
+func (e *jsonlExporter) WriteRow(row *Row) error {
+	data, err := row.orderedJSON()
+	if err != nil {
+		return err
+	}
+	_, err = e.writer.Write(data)
+	if err != nil {
+		return err
+	}
+	return e.writer.WriteByte('\n')
+}
+
+func (e *jsonlExporter) End() error {
+	return e.writer.Flush()
+}
@@ -0,0 +1,67 @@
+package dbase
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fuzzRows limits the rows read of a fuzzed table, its header may claim billions of rows
+const fuzzRows = 64
+
+// FuzzOpenTable opens malformed tables and memo files and reads their rows and memos.
+// Errors are expected, panics, negative seeks and huge allocations are not.
+// The seeds are the tables of the Visual FoxPro corpus, run with: go test -fuzz FuzzOpenTable ./dbase
+func FuzzOpenTable(f *testing.F) {
+	seeds := []struct {
+		table string
+		memo  string
+	}{
+		{"TEST.DBF", "TEST.FPT"},
+		{"employees.dbf", "employees.FPT"},
+		{"expense reports.dbf", "expense reports.FPT"},
+		{"EXPENSES.DBC", "EXPENSES.DCT"},
+	}
+	for _, seed := range seeds {
+		table, err := os.ReadFile(filepath.Join(vfpCorpus, seed.table))
+		if err != nil {
+			f.Fatal(err)
+		}
+		memo, err := os.ReadFile(filepath.Join(vfpCorpus, seed.memo))
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(table, memo)
+		// Truncated within the rows and without memo file
+		f.Add(table[:len(table)-len(table)/4], memo[:len(memo)/2])
+		f.Add(table, []byte{})
+	}
+	f.Fuzz(func(t *testing.T, table []byte, memo []byte) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "FUZZ.DBF")
+		err := os.WriteFile(path, table, 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(filepath.Join(dir, "FUZZ.FPT"), memo, 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		file, err := OpenTable(&Config{Filename: path, ReadOnly: true, Untested: true, WithoutIndex: true})
+		if err != nil {
+			return
+		}
+		defer file.Close()
+		for position := uint32(0); position < file.RowsCount() && position < fuzzRows; position++ {
+			err = file.GoTo(position)
+			if err != nil {
+				return
+			}
+			row, err := file.Row()
+			if err != nil {
+				continue
+			}
+			_, _ = row.ToMap()
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package dbase
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+)
+
+// ReadHeader reads only the 32 byte header of the table file at path.
+// The columns and the memo file are not read, so this is the cheapest way to get the rows count,
+// the last modification date or the file type of many files.
+func ReadHeader(path string) (*Header, error) {
+	handle, err := os.Open(path)
+	if err != nil {
+		return nil, newError("dbase-header-readheader-1", err)
+	}
+	defer handle.Close()
+	buf := make([]byte, 32)
+	n, err := io.ReadFull(handle, buf)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, newError("dbase-header-readheader-2", err)
+	}
+	header := &Header{}
+	if n < binary.Size(header) {
+		return nil, newError("dbase-header-readheader-3", ErrIncomplete)
+	}
+	// LittleEndian - Integers in table files are stored with the least significant byte first.
+	err = binary.Read(bytes.NewReader(buf[:n]), binary.LittleEndian, header)
+	if err != nil {
+		return nil, newError("dbase-header-readheader-4", err)
+	}
+	return header, nil
+}
@@ -0,0 +1,204 @@
+package dbase
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// identify records the path and the identity of the opened table file, used by Ping to detect replaced files.
+// For custom IO implementations the identity is only known if the handle is an *os.File.
+func (file *File) identify() {
+	if handle, ok := file.handle.(*os.File); ok {
+		info, err := handle.Stat()
+		if err == nil {
+			file.path, file.stat = handle.Name(), info
+		}
+		return
+	}
+	if _, generic := file.io.(GenericIO); generic || len(file.config.Filename) == 0 {
+		return
+	}
+	path, err := _findFile(filepath.Clean(file.config.Filename))
+	if err != nil {
+		return
+	}
+	info, err := os.Stat(path)
+	if err == nil {
+		file.path, file.stat = path, info
+	}
+}
+
+// Ping checks that the table is still usable by a long-lived handle:
+// the table and memo file can be read, the file at the table path is still the opened file
+// and the header still matches the structure read when the table was opened.
+// Returns an error wrapping ErrFileUnavailable, ErrFileReplaced or ErrHeaderChanged.
+func (file *File) Ping() error {
+	size, err := file.Size(false)
+	if err != nil {
+		return newError("dbase-health-ping-1", fmt.Errorf("%w, reading table size failed with error: %v", ErrFileUnavailable, err))
+	}
+	if file.relatedHandle != nil {
+		if _, err := file.Size(true); err != nil {
+			return newError("dbase-health-ping-2", fmt.Errorf("%w, reading memo size failed with error: %v", ErrFileUnavailable, err))
+		}
+	}
+	err = file.checkReplaced(size)
+	if err != nil {
+		return newError("dbase-health-ping-3", err)
+	}
+	err = file.checkHeader(size)
+	if err != nil {
+		return newError("dbase-health-ping-4", err)
+	}
+	return nil
+}
+
+// checkReplaced compares the file at the table path with the opened file
+func (file *File) checkReplaced(size int64) error {
+	if file.stat == nil {
+		return nil
+	}
+	current, err := os.Stat(file.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%w, file %s was removed", ErrFileReplaced, file.path)
+		}
+		return fmt.Errorf("%w, reading file info of %s failed with error: %v", ErrFileUnavailable, file.path, err)
+	}
+	if !os.SameFile(file.stat, current) {
+		return fmt.Errorf("%w, file %s is not the opened file anymore", ErrFileReplaced, file.path)
+	}
+	// The identity may be unreliable on network shares, the opened file has to match the size and modification time as well
+	if current.Size() != size {
+		return fmt.Errorf("%w, file %s has %d bytes but the opened file %d bytes", ErrFileReplaced, file.path, current.Size(), size)
+	}
+	if handle, ok := file.handle.(*os.File); ok {
+		opened, err := handle.Stat()
+		if err != nil {
+			return fmt.Errorf("%w, reading file info of the opened file failed with error: %v", ErrFileUnavailable, err)
+		}
+		if !opened.ModTime().Equal(current.ModTime()) {
+			return fmt.Errorf("%w, file %s was modified at %v but the opened file at %v", ErrFileReplaced, file.path, current.ModTime(), opened.ModTime())
+		}
+	}
+	return nil
+}
+
+// checkHeader reads the header again and compares it with the header read when the table was opened
+func (file *File) checkHeader(size int64) error {
+	header, err := file.currentHeader()
+	if err != nil {
+		return fmt.Errorf("%w, reading header failed with error: %v", ErrFileUnavailable, err)
+	}
+	if header.FileType != file.header.FileType || header.FirstRow != file.header.FirstRow || header.RowLength != file.header.RowLength {
+		return fmt.Errorf("%w, file type 0x%02x, first row %d and row length %d differ from 0x%02x, %d and %d when opened", ErrHeaderChanged, header.FileType, header.FirstRow, header.RowLength, file.header.FileType, file.header.FirstRow, file.header.RowLength)
+	}
+	end := int64(header.FirstRow) + int64(header.RowsCount)*int64(header.RowLength)
+	if end > size {
+		return fmt.Errorf("%w, %d rows of %d bytes exceed the file size of %d bytes", ErrHeaderChanged, header.RowsCount, header.RowLength, size)
+	}
+	return nil
+}
+
+// currentHeader reads the header from the file without replacing the header of the table
+func (file *File) currentHeader() (*Header, error) {
+	raw, err := file.ReadRaw(false, 0, 30)
+	if err != nil {
+		return nil, newError("dbase-health-currentheader-1", err)
+	}
+	header := &Header{}
+	err = binary.Read(bytes.NewReader(raw), binary.LittleEndian, header)
+	if err != nil {
+		return nil, newError("dbase-health-currentheader-2", err)
+	}
+	return header, nil
+}
+
+// Reopen opens the file at the table path again and replaces the handles and headers of the table.
+// Modifications, column settings, hooks and the configuration are preserved, the row pointer is kept if still valid.
+// Returns an error wrapping ErrSchemaMismatch if the columns of the new file differ.
+// Index files are not affected, as they are not opened by this package.
+// Tables holding locks of Config.SharedWrite are not reopened, as closing the handles would release the locks.
+func (file *File) Reopen() error {
+	if file.holdsLocks() {
+		return newError("dbase-health-reopen-4", fmt.Errorf("the table %s holds row or header locks and can not be reopened", file.config.Filename))
+	}
+	opened, err := OpenTable(file.config)
+	if err != nil {
+		return newError("dbase-health-reopen-1", err)
+	}
+	err = file.sameColumns(opened)
+	if err != nil {
+		opened.Close()
+		return newError("dbase-health-reopen-2", err)
+	}
+	debugf("Reopening table %s", file.config.Filename)
+	previous := &File{config: file.config, io: file.io, handle: file.handle, relatedHandle: file.relatedHandle}
+	file.dbaseMutex.Lock()
+	file.memoMutex.Lock()
+	file.handle, file.relatedHandle = opened.handle, opened.relatedHandle
+	file.header, file.memoHeader = opened.header, opened.memoHeader
+	file.confirmedRows = opened.confirmedRows
+	file.path, file.stat = opened.path, opened.stat
+	file.memoMutex.Unlock()
+	file.dbaseMutex.Unlock()
+	if file.table.rowPointer > file.header.RowsCount {
+		file.table.rowPointer = file.header.RowsCount
+	}
+	file.rowCache.Invalidate()
+	if file.relations != nil {
+		file.relations.Refresh()
+	}
+	err = previous.io.Close(previous)
+	if err != nil {
+		return newError("dbase-health-reopen-3", err)
+	}
+	return nil
+}
+
+// sameColumns returns an error if the columns of the opened file differ from the columns of the table
+func (file *File) sameColumns(opened *File) error {
+	if len(opened.table.columns) != len(file.table.columns) {
+		return fmt.Errorf("%w, %d columns instead of %d", ErrSchemaMismatch, len(opened.table.columns), len(file.table.columns))
+	}
+	for i, column := range file.table.columns {
+		other := opened.table.columns[i]
+		if other.FieldName != column.FieldName || other.DataType != column.DataType || other.Length != column.Length || other.Decimals != column.Decimals || other.Position != column.Position {
+			return fmt.Errorf("%w, column %s %s(%d,%d) changed to %s %s(%d,%d)", ErrSchemaMismatch, column.Name(), column.Type(), column.Length, column.Decimals, other.Name(), other.Type(), other.Length, other.Decimals)
+		}
+	}
+	return nil
+}
+
+// autoReopen reopens the table if AutoReopen is set and Ping reports a replaced file or changed header.
+// The check is done at most once per ReopenInterval and never while the table is scanned.
+func (file *File) autoReopen() error {
+	// Tables are not reopened during a scan or while they hold locks, see Reopen
+	if !file.config.AutoReopen || file.scans > 0 || file.holdsLocks() {
+		return nil
+	}
+	interval := file.config.ReopenInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	if time.Since(file.checked) < interval {
+		return nil
+	}
+	file.checked = time.Now()
+	err := file.Ping()
+	if err == nil || !(errors.Is(err, ErrFileReplaced) || errors.Is(err, ErrHeaderChanged)) {
+		// Unavailable files are reported by the following read
+		return nil
+	}
+	debugf("Table %s has to be reopened: %v", file.config.Filename, err)
+	err = file.Reopen()
+	if err != nil {
+		return newError("dbase-health-autoreopen-1", err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package dbase
+
+// hooks contains the callbacks registered on a file handle
+type hooks struct {
+	rowRead     []func(row *Row)            // Called after a row has been read
+	beforeWrite []func(row *Row) error      // Called before a row is written
+	afterWrite  []func(row *Row)            // Called after a row has been written
+	written     []func(written *WrittenRow) // Called after a row has been written, with the position and operation
+}
+
+// WrittenRow describes a row written to the table
+type WrittenRow struct {
+	Row      *Row   // Written row, for UpdateField the row as read back from the file
+	Position uint32 // Zero based position the row was written to
+	Appended bool   // The row was appended after the last row
+}
+
+// OnRowRead registers a hook that is called for every row read through Row, Next or Rows.
+func (file *File) OnRowRead(hook func(row *Row)) {
+	file.registerHook(func(h *hooks) { h.rowRead = append(h.rowRead, hook) })
+}
+
+// BeforeRowWrite registers a hook that is called before a row is written.
+// The hook may change the row (e.g. to compute derived fields) or abort the write by returning an error.
+func (file *File) BeforeRowWrite(hook func(row *Row) error) {
+	file.registerHook(func(h *hooks) { h.beforeWrite = append(h.beforeWrite, hook) })
+}
+
+// AfterRowWrite registers a hook that is called after a row has been written successfully.
+// For UpdateField the hook is called with the updated row as read back from the file.
+func (file *File) AfterRowWrite(hook func(row *Row)) {
+	file.registerHook(func(h *hooks) { h.afterWrite = append(h.afterWrite, hook) })
+}
+
+// OnRowWritten registers a hook that is called after a row has been written successfully.
+// Unlike the row passed to AfterRowWrite, which keeps the position it was created with, the hook gets the zero based position
+// the row was written to and whether it was appended, both taken while the write was locked.
+func (file *File) OnRowWritten(hook func(written *WrittenRow)) {
+	file.registerHook(func(h *hooks) { h.written = append(h.written, hook) })
+}
+
+// ClearHooks removes all registered hooks
+func (file *File) ClearHooks() {
+	file.writeMutex.Lock()
+	defer file.writeMutex.Unlock()
+	file.hooks.Store((*hooks)(nil))
+}
+
+// registerHook adds a hook to a copy of the registered hooks under the write lock and replaces them,
+// so hooks can be registered while other goroutines read and write rows and run the registered hooks
+func (file *File) registerHook(add func(h *hooks)) {
+	file.writeMutex.Lock()
+	defer file.writeMutex.Unlock()
+	h := &hooks{}
+	if current := file.loadHooks(); current != nil {
+		h.rowRead = append(h.rowRead, current.rowRead...)
+		h.beforeWrite = append(h.beforeWrite, current.beforeWrite...)
+		h.afterWrite = append(h.afterWrite, current.afterWrite...)
+		h.written = append(h.written, current.written...)
+	}
+	add(h)
+	file.hooks.Store(h)
+}
+
+// loadHooks returns the registered hooks or nil if no hook is registered
+func (file *File) loadHooks() *hooks {
+	h, _ := file.hooks.Load().(*hooks)
+	return h
+}
+
+// runRowRead calls all registered read hooks
+func (file *File) runRowRead(row *Row) {
+	h := file.loadHooks()
+	if h == nil {
+		return
+	}
+	for _, hook := range h.rowRead {
+		hook(row)
+	}
+}
+
+// runBeforeWrite calls all registered before write hooks and stops at the first error
+func (file *File) runBeforeWrite(row *Row) error {
+	h := file.loadHooks()
+	if h == nil {
+		return nil
+	}
+	for _, hook := range h.beforeWrite {
+		err := hook(row)
+		if err != nil {
+			return newError("dbase-hooks-runbeforewrite-1", err)
+		}
+	}
+	return nil
+}
+
+// runAfterWrite calls all registered after write hooks
+func (file *File) runAfterWrite(row *Row, position uint32, appended bool) {
+	h := file.loadHooks()
+	if h == nil {
+		return
+	}
+	for _, hook := range h.afterWrite {
+		hook(row)
+	}
+	if len(h.written) == 0 {
+		return
+	}
+	written := &WrittenRow{Row: row, Position: position, Appended: appended}
+	for _, hook := range h.written {
+		hook(written)
+	}
+}
+
+// afterWriteHooked returns true if a hook is registered that is called after a row has been written
+func (file *File) afterWriteHooked() bool {
+	h := file.loadHooks()
+	return h != nil && (len(h.afterWrite) > 0 || len(h.written) > 0)
+}
@@ -0,0 +1,309 @@
+package dbase
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Importer reads the records of a source format to append them to a table with an Appender.
+// Additional sources (e.g. Excel or SQL) can be added by implementing Importer and registering it with RegisterImporter.
+type Importer interface {
+	Begin(schema *ExportSchema) error // Called once before the first record with the schema of the target table
+	Next() (*ImportRecord, error)     // Returns the next record or io.EOF after the last record
+	End() error                       // Called once after the last record
+}
+
+// ImportRecord is a record read by an importer
+type ImportRecord struct {
+	Position uint32                 // Number of the record in the source, starting at 1
+	Values   map[string]interface{} // Values by column name or external key, strings are converted to the column type
+	Raw      []byte                 // Source data of the record, written to the quarantine file if the record is rejected
+	Err      error                  // Error reading the record from the source, e.g. a malformed line, the record is rejected
+}
+
+// ImporterFactory returns a new importer reading from r
+type ImporterFactory func(r io.Reader) Importer
+
+// importers contains the registered importers by format name
+var importers = struct {
+	sync.RWMutex
+	factories map[string]ImporterFactory
+}{
+	factories: map[string]ImporterFactory{
+		"csv":   func(r io.Reader) Importer { return newCSVImporter(r) },
+		"jsonl": func(r io.Reader) Importer { return newJSONLImporter(r) },
+	},
+}
+
+// RegisterImporter registers the importer for the format name, e.g. "xlsx". Names are case-insensitive.
+// The built-in formats are "csv" and "jsonl", they read the output of the exporters of the same name.
+func RegisterImporter(name string, factory ImporterFactory) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if len(name) == 0 || factory == nil {
+		return newError("dbase-importer-registerimporter-1", fmt.Errorf("missing format name or importer factory"))
+	}
+	importers.Lock()
+	defer importers.Unlock()
+	if _, ok := importers.factories[name]; ok {
+		return newError("dbase-importer-registerimporter-2", fmt.Errorf("importer for format '%s' already registered", name))
+	}
+	importers.factories[name] = factory
+	return nil
+}
+
+// Importers returns the names of all registered formats in alphabetical order
+func Importers() []string {
+	importers.RLock()
+	defer importers.RUnlock()
+	names := make([]string, 0, len(importers.factories))
+	for name := range importers.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewImporter returns a new importer of the registered format reading from r
+func NewImporter(name string, r io.Reader) (Importer, error) {
+	importers.RLock()
+	factory, ok := importers.factories[strings.ToLower(strings.TrimSpace(name))]
+	importers.RUnlock()
+	if !ok {
+		return nil, newError("dbase-importer-newimporter-1", fmt.Errorf("no importer registered for format '%s'", name))
+	}
+	return factory(r), nil
+}
+
+// ImportProgress contains the number of processed records of an import
+type ImportProgress struct {
+	Records  uint32 // Number of read records
+	Appended uint32 // Number of records appended to the table
+	Rejected uint32 // Number of invalid records that were skipped
+}
+
+// Appender appends records to a table. The values are converted to the column types and mapped like RowFromMap,
+// so defaults and StrictMapping apply. Rejected records are written to the quarantine file of the table, if set.
+type Appender struct {
+	File        *File                          // Target table
+	SkipInvalid bool                           // If true invalid records are rejected and the import continues, otherwise it stops
+	Progress    func(progress *ImportProgress) // Called after every record, optional
+
+	progress ImportProgress
+}
+
+// NewAppender returns an appender for the table
+func NewAppender(file *File) *Appender {
+	return &Appender{File: file}
+}
+
+// Append converts the record and appends it as new row.
+// If the record is invalid and SkipInvalid is set, it is rejected and nil is returned.
+func (a *Appender) Append(record *ImportRecord) error {
+	a.progress.Records++
+	err := a.append(record)
+	if err != nil {
+		if !a.SkipInvalid {
+			return newError("dbase-importer-append-1", &RowError{Position: record.Position, Err: err})
+		}
+		a.progress.Rejected++
+		if a.File.quarantine != nil {
+			err = a.File.quarantine.Write(record.Position, record.Raw, err)
+			if err != nil {
+				return newError("dbase-importer-append-2", err)
+			}
+		}
+	} else {
+		a.progress.Appended++
+	}
+	if a.Progress != nil {
+		a.Progress(&a.progress)
+	}
+	return nil
+}
+
+// Import appends all records of the importer and returns the progress of this appender
+func (a *Appender) Import(importer Importer) (*ImportProgress, error) {
+	err := importer.Begin(a.File.exportSchema())
+	if err != nil {
+		return nil, newError("dbase-importer-import-1", err)
+	}
+	for {
+		record, err := importer.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, newError("dbase-importer-import-2", err)
+		}
+		err = a.Append(record)
+		if err != nil {
+			return nil, newError("dbase-importer-import-3", err)
+		}
+	}
+	err = importer.End()
+	if err != nil {
+		return nil, newError("dbase-importer-import-4", err)
+	}
+	progress := a.progress
+	return &progress, nil
+}
+
+// append converts the values of the record and writes the row
+func (a *Appender) append(record *ImportRecord) error {
+	if record.Err != nil {
+		return record.Err
+	}
+	file := a.File
+	values := make(map[string]interface{}, len(record.Values))
+	for key, val := range record.Values {
+		values[key] = val
+	}
+	for i, column := range file.table.columns {
+		key := column.Name()
+		if mod := file.table.mods[i]; mod != nil && len(mod.ExternalKey) != 0 {
+			key = mod.ExternalKey
+		}
+		val, ok := values[key]
+		if !ok {
+			continue
+		}
+		converted, err := file.importValue(column, val)
+		if err != nil {
+			return err
+		}
+		values[key] = converted
+	}
+	row, err := file.RowFromMap(values)
+	if err != nil {
+		return err
+	}
+	return row.Add()
+}
+
+// importValue converts text values of the sources to the type of the column, other values like jsonValue
+func (file *File) importValue(column *Column, val interface{}) (interface{}, error) {
+	s, ok := val.(string)
+	if !ok {
+		return file.jsonValue(column, val)
+	}
+	switch DataType(column.DataType) {
+	case Character, Varchar, Memo:
+		return s, nil
+	}
+	s = strings.TrimSpace(s)
+	if len(s) == 0 {
+		return nil, nil
+	}
+	switch DataType(column.DataType) {
+	case Integer:
+		i, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return nil, newError("dbase-importer-importvalue-1", fmt.Errorf("parsing integer failed at column field: %v failed with error: %w", column.Name(), err))
+		}
+		return int32(i), nil
+	case Double:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, newError("dbase-importer-importvalue-2", fmt.Errorf("parsing double failed at column field: %v failed with error: %w", column.Name(), err))
+		}
+		return f, nil
+	case Logical:
+		if b, ok := parseLogical(s); ok {
+			return b, nil
+		}
+		return nil, newError("dbase-importer-importvalue-3", fmt.Errorf("invalid logical value %q at column field: %v", s, column.Name()))
+	}
+	return file.jsonValue(column, s)
+}
+
+// csvImporter reads a header line with the keys and one record per line
+type csvImporter struct {
+	reader   *csv.Reader
+	keys     []string
+	position uint32
+}
+
+func newCSVImporter(r io.Reader) *csvImporter {
+	return &csvImporter{reader: csv.NewReader(r)}
+}
+
+func (i *csvImporter) Begin(schema *ExportSchema) error {
+	keys, err := i.reader.Read()
+	if err != nil {
+		return err
+	}
+	i.keys = keys
+	i.reader.FieldsPerRecord = len(keys)
+	return nil
+}
+
+func (i *csvImporter) Next() (*ImportRecord, error) {
+	fields, err := i.reader.Read()
+	var parseErr *csv.ParseError
+	if err != nil && !errors.As(err, &parseErr) {
+		return nil, err
+	}
+	i.position++
+	raw := &bytes.Buffer{}
+	writer := csv.NewWriter(raw)
+	writer.Write(fields)
+	writer.Flush()
+	record := &ImportRecord{Position: i.position, Values: make(map[string]interface{}, len(fields)), Raw: raw.Bytes(), Err: err}
+	if err != nil {
+		return record, nil
+	}
+	for j, field := range fields {
+		record.Values[i.keys[j]] = field
+	}
+	return record, nil
+}
+
+func (i *csvImporter) End() error {
+	return nil
+}
+
+// jsonlImporter reads one JSON object per line
+type jsonlImporter struct {
+	scanner  *bufio.Scanner
+	position uint32
+}
+
+func newJSONLImporter(r io.Reader) *jsonlImporter {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	return &jsonlImporter{scanner: scanner}
+}
+
+func (i *jsonlImporter) Begin(schema *ExportSchema) error {
+	return nil
+}
+
+func (i *jsonlImporter) Next() (*ImportRecord, error) {
+	for i.scanner.Scan() {
+		i.position++
+		line := bytes.TrimSpace(i.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		record := &ImportRecord{Position: i.position, Values: make(map[string]interface{}), Raw: append([]byte(nil), line...)}
+		record.Err = json.Unmarshal(line, &record.Values)
+		return record, nil
+	}
+	if err := i.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+func (i *jsonlImporter) End() error {
+	return nil
+}
@@ -0,0 +1,260 @@
+package dbase
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Layout of compact (CDX) index files
+const (
+	indexPageSize        = 512  // Size of an index node
+	indexHeaderSize      = 1024 // Size of a tag header including the expression pool
+	indexMaxPages        = 1 << 16
+	indexOptionUnique    = 0x01 // Only the first row of each key is indexed
+	indexOptionCandidate = 0x04 // Primary or candidate key, duplicate keys are rejected
+	indexOptionFor       = 0x08 // The tag has a FOR filter
+)
+
+// IndexInfo describes the compound index (CDX) of a table.
+// Only the tag metadata is read, the index is not used or maintained by this package.
+// Tags of index snapshots, which are not structural, can be written with CreateIndex.
+type IndexInfo struct {
+	Path string      // Path of the index file
+	Tags []*IndexTag // Tags in the order they were created
+}
+
+// IndexTag describes one tag of a compound index
+type IndexTag struct {
+	Name       string // Name of the tag
+	Expression string // Key expression, e.g. "UPPER(LASTNAME)"
+	Filter     string // FOR filter expression, empty if the tag indexes all rows
+	Descending bool   // Keys are sorted in descending order
+	Unique     bool   // Only the first row of each key is indexed (INDEX ... UNIQUE)
+	Candidate  bool   // Primary or candidate key, rows with duplicate keys are rejected
+	KeyLength  int    // Length of the keys in bytes
+	Options    byte   // Raw index options of the tag header
+}
+
+// IndexInfo reads the tag metadata of the compound index (CDX) next to the table,
+// so the indexes can be recreated in another database.
+// The index is read if the table has a structural index or an index snapshot written by CreateIndex.
+// Returns nil if the table has no index file or was opened with Config.WithoutIndex.
+func (file *File) IndexInfo() (*IndexInfo, error) {
+	if file.header == nil || file.config.WithoutIndex {
+		return nil, nil
+	}
+	structural := StructuralFlag.Defined(file.header.TableFlags)
+	if len(file.path) == 0 {
+		if !structural {
+			return nil, nil
+		}
+		return nil, newError("dbase-index-indexinfo-1", fmt.Errorf("the index file of a table without a path can not be located"))
+	}
+	path, err := _findFile(strings.TrimSuffix(file.path, filepath.Ext(file.path)) + string(CDX))
+	if err != nil {
+		return nil, newError("dbase-index-indexinfo-2", err)
+	}
+	handle, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) && !structural {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, newError("dbase-index-indexinfo-3", fmt.Errorf("opening index file failed with error: %w", err))
+	}
+	defer handle.Close()
+	info := &IndexInfo{Path: path}
+	reader := &indexReader{handle: handle, file: file}
+	directory, root, err := reader.header(0)
+	if err != nil {
+		return nil, newError("dbase-index-indexinfo-4", err)
+	}
+	entries, err := reader.keys(root, directory.KeyLength)
+	if err != nil {
+		return nil, newError("dbase-index-indexinfo-5", err)
+	}
+	// The directory is sorted by name, tags created later have a higher offset
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].offset < entries[j].offset })
+	for _, entry := range entries {
+		tag, _, err := reader.header(int64(entry.offset))
+		if err != nil {
+			return nil, newError("dbase-index-indexinfo-6", fmt.Errorf("reading tag %s failed with error: %w", entry.key, err))
+		}
+		tag.Name = entry.key
+		info.Tags = append(info.Tags, tag)
+	}
+	debugf("Read %d index tags from %s", len(info.Tags), path)
+	return info, nil
+}
+
+// indexReader reads pages of a compact index file
+type indexReader struct {
+	handle *os.File
+	file   *File
+	pages  int // Number of nodes read, limits the reads of corrupt files with cyclic pointers
+}
+
+// indexEntry is a key of a leaf node with its record number, the offset of the tag header in the tag directory
+type indexEntry struct {
+	key    string
+	offset uint32
+}
+
+// read reads length bytes at the offset
+func (r *indexReader) read(offset int64, length int) ([]byte, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("%w, negative offset %d", ErrInvalidIndex, offset)
+	}
+	buf := make([]byte, length)
+	n, err := r.handle.ReadAt(buf, offset)
+	if n != length {
+		return nil, fmt.Errorf("%w, read %d of %d bytes at offset %d: %v", ErrInvalidIndex, n, length, offset, err)
+	}
+	return buf, nil
+}
+
+// header reads the tag header at the offset, returns the tag and the offset of its root node
+func (r *indexReader) header(offset int64) (*IndexTag, int64, error) {
+	raw, err := r.read(offset, indexHeaderSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	tag := &IndexTag{
+		KeyLength:  int(binary.LittleEndian.Uint16(raw[12:14])),
+		Options:    raw[14],
+		Descending: binary.LittleEndian.Uint16(raw[0x1F6:0x1F8]) != 0,
+	}
+	tag.Unique = tag.Options&indexOptionUnique != 0
+	tag.Candidate = tag.Options&indexOptionCandidate != 0
+	pool := raw[indexPageSize:]
+	tag.Expression, err = r.expression(pool, raw[0x1FC:0x1FE], raw[0x1FE:0x200])
+	if err != nil {
+		return nil, 0, fmt.Errorf("reading key expression failed with error: %w", err)
+	}
+	if tag.Options&indexOptionFor != 0 {
+		tag.Filter, err = r.expression(pool, raw[0x1F8:0x1FA], raw[0x1FA:0x1FC])
+		if err != nil {
+			return nil, 0, fmt.Errorf("reading FOR expression failed with error: %w", err)
+		}
+	}
+	return tag, int64(binary.LittleEndian.Uint32(raw[0:4])), nil
+}
+
+// expression returns the NUL terminated expression at the position in the expression pool
+func (r *indexReader) expression(pool []byte, position []byte, length []byte) (string, error) {
+	start := int(binary.LittleEndian.Uint16(position))
+	end := start + int(binary.LittleEndian.Uint16(length))
+	if end > len(pool) {
+		return "", fmt.Errorf("%w, expression at %d with %d bytes exceeds the expression pool", ErrInvalidIndex, start, end-start)
+	}
+	raw := pool[start:end]
+	if i := bytes.IndexByte(raw, 0); i >= 0 {
+		raw = raw[:i]
+	}
+	return r.decode(raw)
+}
+
+// decode converts names and expressions with the converter of the table
+func (r *indexReader) decode(raw []byte) (string, error) {
+	raw = bytes.TrimRight(raw, " \x00")
+	if r.file.config == nil || r.file.config.Converter == nil {
+		return string(raw), nil
+	}
+	decoded, err := r.file.config.Converter.Decode(raw)
+	if err != nil {
+		return "", fmt.Errorf("%w, %v", ErrInvalidEncoding, err)
+	}
+	return string(decoded), nil
+}
+
+// keys returns the keys of the leaf nodes below the root node in index order
+func (r *indexReader) keys(offset int64, keyLength int) ([]*indexEntry, error) {
+	// Descend to the leftmost leaf
+	for {
+		node, err := r.node(offset)
+		if err != nil {
+			return nil, err
+		}
+		if node[0]&0x02 != 0 {
+			break
+		}
+		if binary.LittleEndian.Uint16(node[2:4]) == 0 {
+			return nil, nil
+		}
+		// Interior entries are the key followed by the record number and the child pointer, both big endian
+		child := 12 + keyLength + 4
+		if child+4 > indexPageSize {
+			return nil, fmt.Errorf("%w, key length %d exceeds the node size", ErrInvalidIndex, keyLength)
+		}
+		offset = int64(binary.BigEndian.Uint32(node[child : child+4]))
+	}
+	entries := make([]*indexEntry, 0)
+	for offset != -1 {
+		node, err := r.node(offset)
+		if err != nil {
+			return nil, err
+		}
+		leaf, err := r.leaf(node, keyLength)
+		if err != nil {
+			return nil, fmt.Errorf("reading leaf node at offset %d failed with error: %w", offset, err)
+		}
+		entries = append(entries, leaf...)
+		offset = int64(int32(binary.LittleEndian.Uint32(node[8:12])))
+	}
+	return entries, nil
+}
+
+// node reads the index node at the offset
+func (r *indexReader) node(offset int64) ([]byte, error) {
+	r.pages++
+	if r.pages > indexMaxPages {
+		return nil, fmt.Errorf("%w, more than %d nodes read, the node pointers may be cyclic", ErrInvalidIndex, indexMaxPages)
+	}
+	return r.read(offset, indexPageSize)
+}
+
+// leaf decodes the compressed keys of a compact leaf node.
+// Each key is stored without the bytes it shares with the previous key (duplicates) and without trailing blanks,
+// the remaining bytes are stored from the end of the node towards the entry list.
+func (r *indexReader) leaf(node []byte, keyLength int) ([]*indexEntry, error) {
+	count := int(binary.LittleEndian.Uint16(node[2:4]))
+	recordMask := uint64(binary.LittleEndian.Uint32(node[14:18]))
+	duplicateMask := uint64(node[18])
+	trailingMask := uint64(node[19])
+	recordBits := uint(node[20])
+	duplicateBits := uint(node[21])
+	size := int(node[23])
+	if size == 0 || size > 8 || 24+count*size > indexPageSize {
+		return nil, fmt.Errorf("%w, %d keys with %d bytes of information do not fit into the node", ErrInvalidIndex, count, size)
+	}
+	entries := make([]*indexEntry, 0, count)
+	previous := make([]byte, 0, keyLength)
+	position := indexPageSize
+	for i := 0; i < count; i++ {
+		information := make([]byte, 8)
+		copy(information, node[24+i*size:24+(i+1)*size])
+		value := binary.LittleEndian.Uint64(information)
+		duplicates := int((value >> recordBits) & duplicateMask)
+		trailing := int((value >> (recordBits + duplicateBits)) & trailingMask)
+		length := keyLength - duplicates - trailing
+		if length < 0 || duplicates > len(previous) || position-length < 24+count*size {
+			return nil, fmt.Errorf("%w, key %d with %d duplicate and %d trailing bytes does not match the key length %d", ErrInvalidIndex, i, duplicates, trailing, keyLength)
+		}
+		position -= length
+		key := append(append(make([]byte, 0, keyLength), previous[:duplicates]...), node[position:position+length]...)
+		// The following key may share bytes of the trailing blanks, they are restored as spaces because only
+		// character keys are decoded, the keys of other types are read for their record numbers only
+		previous = append(key, bytes.Repeat([]byte{' '}, trailing)...)
+		name, err := r.decode(key)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, &indexEntry{key: name, offset: uint32(value & recordMask)})
+	}
+	return entries, nil
+}
@@ -0,0 +1,384 @@
+package dbase
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// indexTerm is a compiled part of an index expression, evaluated on the raw data of a row
+type indexTerm struct {
+	character bool                              // The term evaluates to a character key that can be concatenated
+	length    int                               // Length of the key in bytes
+	source    *Column                           // Column of a plain column term, nil for functions and concatenations
+	columns   []*Column                         // Columns used by the compiled expression
+	eval      func(data []byte) ([]byte, error) // Key of the term for the raw row data
+}
+
+// indexParser compiles index expressions for the columns of a table.
+// Supported are column names, the concatenation of character terms with +,
+// and the functions UPPER, LOWER, DTOS and STR.
+type indexParser struct {
+	file    *File
+	tokens  []string
+	pos     int
+	columns []*Column // Columns used by the expression
+}
+
+// compileIndexExpression compiles the expression to a term producing the keys of the index
+func (file *File) compileIndexExpression(expression string) (*indexTerm, error) {
+	tokens, err := tokenizeIndexExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+	parser := &indexParser{file: file, tokens: tokens}
+	term, err := parser.concatenation()
+	if err != nil {
+		return nil, err
+	}
+	if parser.pos < len(parser.tokens) {
+		return nil, fmt.Errorf("unexpected %q in index expression %q", parser.tokens[parser.pos], expression)
+	}
+	if term.length == 0 || term.length > MaxIndexKeyLength {
+		return nil, fmt.Errorf("key length %d of index expression %q is not between 1 and %d", term.length, expression, MaxIndexKeyLength)
+	}
+	term.columns = parser.columns
+	return term, nil
+}
+
+// tokenizeIndexExpression splits the expression into names, numbers and the characters ( ) , +
+func tokenizeIndexExpression(expression string) ([]string, error) {
+	tokens := make([]string, 0)
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case strings.ContainsRune("(),+", r):
+			tokens = append(tokens, string(r))
+			i++
+		case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		default:
+			return nil, fmt.Errorf("unsupported character %q in index expression %q", r, expression)
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty index expression")
+	}
+	return tokens, nil
+}
+
+// next returns the next token, empty at the end of the expression
+func (p *indexParser) next() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	p.pos++
+	return p.tokens[p.pos-1]
+}
+
+// peek returns the next token without consuming it
+func (p *indexParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+// expect consumes the token or returns an error
+func (p *indexParser) expect(token string) error {
+	if next := p.next(); next != token {
+		return fmt.Errorf("expected %q but found %q in index expression", token, next)
+	}
+	return nil
+}
+
+// concatenation parses terms joined with +, only character terms can be concatenated
+func (p *indexParser) concatenation() (*indexTerm, error) {
+	first, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	terms := []*indexTerm{first}
+	for p.peek() == "+" {
+		p.next()
+		term, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+	if len(terms) == 1 {
+		return first, nil
+	}
+	length := 0
+	for _, term := range terms {
+		if !term.character {
+			return nil, fmt.Errorf("only character terms can be concatenated, convert other types with STR or DTOS")
+		}
+		length += term.length
+	}
+	return &indexTerm{
+		character: true,
+		length:    length,
+		eval: func(data []byte) ([]byte, error) {
+			key := make([]byte, 0, length)
+			for _, term := range terms {
+				part, err := term.eval(data)
+				if err != nil {
+					return nil, err
+				}
+				key = append(key, part...)
+			}
+			return key, nil
+		},
+	}, nil
+}
+
+// term parses a column name or a function call
+func (p *indexParser) term() (*indexTerm, error) {
+	name := p.next()
+	if len(name) == 0 || strings.ContainsAny(name, "(),+") {
+		return nil, fmt.Errorf("expected a column or function but found %q in index expression", name)
+	}
+	if p.peek() != "(" {
+		return p.column(name)
+	}
+	p.next()
+	argument, err := p.concatenation()
+	if err != nil {
+		return nil, err
+	}
+	numbers := make([]int, 0, 2)
+	for p.peek() == "," {
+		p.next()
+		number, err := strconv.Atoi(p.next())
+		if err != nil {
+			return nil, fmt.Errorf("expected a number as argument of %s: %w", strings.ToUpper(name), err)
+		}
+		numbers = append(numbers, number)
+	}
+	err = p.expect(")")
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToUpper(name) {
+	case "UPPER":
+		return p.changeCase(argument, numbers, strings.ToUpper)
+	case "LOWER":
+		return p.changeCase(argument, numbers, strings.ToLower)
+	case "DTOS":
+		return p.dtos(argument, numbers)
+	case "STR":
+		return p.str(argument, numbers)
+	}
+	return nil, fmt.Errorf("unsupported function %s in index expression", strings.ToUpper(name))
+}
+
+// column returns the term of the column with the name, character columns are used as stored
+func (p *indexParser) column(name string) (*indexTerm, error) {
+	file := p.file
+	pos := file.ColumnPosByName(strings.ToUpper(name))
+	if pos < 0 {
+		return nil, fmt.Errorf("%w, column %s of the index expression not found", ErrInvalidPosition, name)
+	}
+	column := file.table.columns[pos]
+	offset := int(file.offsets()[pos])
+	raw := func(data []byte) []byte {
+		return data[offset : offset+int(column.Length)]
+	}
+	term := &indexTerm{length: int(column.Length)}
+	switch DataType(column.DataType) {
+	case Character:
+		term.character = true
+		term.eval = func(data []byte) ([]byte, error) {
+			return append([]byte(nil), raw(data)...), nil
+		}
+	case Integer:
+		term.length = 4
+		term.eval = func(data []byte) ([]byte, error) {
+			key := make([]byte, 4)
+			binary.BigEndian.PutUint32(key, binary.LittleEndian.Uint32(raw(data))^0x80000000)
+			return key, nil
+		}
+	case Numeric, Float, Double, Currency, Date, DateTime:
+		term.length = 8
+		term.eval = func(data []byte) ([]byte, error) {
+			value, err := indexNumber(raw(data), column)
+			if err != nil {
+				return nil, err
+			}
+			return indexDoubleKey(value), nil
+		}
+	case Logical:
+		term.length = 1
+		term.eval = func(data []byte) ([]byte, error) {
+			if value, _ := parseLogical(string(bytes.TrimSpace(raw(data)))); value {
+				return []byte("T"), nil
+			}
+			return []byte("F"), nil
+		}
+	default:
+		return nil, fmt.Errorf("columns of type %s can not be indexed", column.Type())
+	}
+	term.source = column
+	p.columns = append(p.columns, column)
+	return term, nil
+}
+
+// changeCase converts the case of a character term in the code page of the table
+func (p *indexParser) changeCase(argument *indexTerm, numbers []int, convert func(string) string) (*indexTerm, error) {
+	if !argument.character || len(numbers) > 0 {
+		return nil, fmt.Errorf("UPPER and LOWER expect one character argument")
+	}
+	converter := p.file.config.Converter
+	return &indexTerm{
+		character: true,
+		length:    argument.length,
+		eval: func(data []byte) ([]byte, error) {
+			key, err := argument.eval(data)
+			if err != nil {
+				return nil, err
+			}
+			text, err := toUTF8String(key, converter)
+			if err != nil {
+				return nil, err
+			}
+			key, err = fromUtf8String([]byte(convert(text)), converter)
+			if err != nil {
+				return nil, err
+			}
+			return fitIndexKey(key, argument.length), nil
+		},
+	}, nil
+}
+
+// dtos converts a date column to a character key in the format YYYYMMDD
+func (p *indexParser) dtos(argument *indexTerm, numbers []int) (*indexTerm, error) {
+	column := argument.source
+	if column == nil || len(numbers) > 0 || (DataType(column.DataType) != Date && DataType(column.DataType) != DateTime) {
+		return nil, fmt.Errorf("DTOS expects a date or datetime column")
+	}
+	offset := int(p.file.columnOffset(column))
+	return &indexTerm{
+		character: true,
+		length:    8,
+		eval: func(data []byte) ([]byte, error) {
+			raw := data[offset : offset+int(column.Length)]
+			if DataType(column.DataType) == Date {
+				return fitIndexKey(append([]byte(nil), raw...), 8), nil
+			}
+			date := parseDateTime(raw)
+			if date.IsZero() {
+				return fitIndexKey(nil, 8), nil
+			}
+			return []byte(date.Format("20060102")), nil
+		},
+	}, nil
+}
+
+// str converts a numeric column to a right aligned character key with the length (default 10) and decimals (default 0)
+func (p *indexParser) str(argument *indexTerm, numbers []int) (*indexTerm, error) {
+	column := argument.source
+	if column == nil || argument.character || DataType(column.DataType) == Logical || DataType(column.DataType) == Date || DataType(column.DataType) == DateTime {
+		return nil, fmt.Errorf("STR expects a numeric column")
+	}
+	length, decimals := 10, 0
+	if len(numbers) > 0 {
+		length = numbers[0]
+	}
+	if len(numbers) > 1 {
+		decimals = numbers[1]
+	}
+	if len(numbers) > 2 || length < 1 || decimals < 0 || decimals >= length {
+		return nil, fmt.Errorf("invalid length or decimals of STR")
+	}
+	offset := int(p.file.columnOffset(column))
+	return &indexTerm{
+		character: true,
+		length:    length,
+		eval: func(data []byte) ([]byte, error) {
+			var value float64
+			if DataType(column.DataType) == Integer {
+				value = float64(int32(binary.LittleEndian.Uint32(data[offset : offset+4])))
+			} else {
+				var err error
+				value, err = indexNumber(data[offset:offset+int(column.Length)], column)
+				if err != nil {
+					return nil, err
+				}
+			}
+			text := strconv.FormatFloat(value, 'f', decimals, 64)
+			if len(text) > length {
+				// Values that do not fit are shown as asterisks, like in FoxPro
+				return bytes.Repeat([]byte("*"), length), nil
+			}
+			return prependSpaces([]byte(text), length), nil
+		},
+	}, nil
+}
+
+// indexNumber returns the value of a numeric, date or datetime column as number, dates as julian day
+func indexNumber(raw []byte, column *Column) (float64, error) {
+	switch DataType(column.DataType) {
+	case Double:
+		return math.Float64frombits(binary.LittleEndian.Uint64(raw)), nil
+	case Currency:
+		return float64(int64(binary.LittleEndian.Uint64(raw))) / 10000, nil
+	case Date:
+		if len(bytes.TrimSpace(raw)) == 0 {
+			return 0, nil
+		}
+		date, err := parseDate(raw)
+		if err != nil {
+			return 0, fmt.Errorf("parsing date of column %s failed with error: %w", column.Name(), err)
+		}
+		return float64(date.Unix()/86400 + unixJulianDay), nil
+	case DateTime:
+		julian := binary.LittleEndian.Uint32(raw[:4])
+		milliseconds := binary.LittleEndian.Uint32(raw[4:8])
+		return float64(julian) + float64(milliseconds)/86400000, nil
+	}
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return 0, nil
+	}
+	value, err := parseFloat(raw)
+	if err != nil {
+		return 0, fmt.Errorf("parsing number of column %s failed with error: %w", column.Name(), err)
+	}
+	return value, nil
+}
+
+// unixJulianDay is the julian day number of 1970-01-01
+const unixJulianDay = 2440588
+
+// indexDoubleKey returns the 8 byte key of a number, the bytes of the keys sort like the numbers
+func indexDoubleKey(value float64) []byte {
+	bits := math.Float64bits(value + 0) // Adding 0 removes negative zero
+	if value >= 0 {
+		bits |= 1 << 63
+	} else {
+		bits = ^bits
+	}
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, bits)
+	return key
+}
+
+// fitIndexKey pads the key with spaces or cuts it to the length
+func fitIndexKey(key []byte, length int) []byte {
+	if len(key) >= length {
+		return key[:length]
+	}
+	return appendSpaces(key, length)
+}
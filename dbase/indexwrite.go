@@ -0,0 +1,532 @@
+package dbase
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// MaxIndexKeyLength is the maximum length of the keys of a compact index tag
+const MaxIndexKeyLength = 240
+
+// Options of compact index headers and nodes
+const (
+	indexOptionCompact   = 0x20 // Compact index format
+	indexOptionCompound  = 0x40 // Compound index with several tags
+	indexOptionStructure = 0x80 // Tag directory of a compound index
+	indexSignature       = 0x01
+	indexNodeRoot        = 0x01
+	indexNodeLeaf        = 0x02
+)
+
+// IndexOptions configures a tag created by CreateIndex
+type IndexOptions struct {
+	Descending bool // Keys are sorted in descending order
+	Unique     bool // Only the first row of each key is indexed (INDEX ... UNIQUE)
+	Candidate  bool // Primary or candidate key, creating the tag fails if rows have duplicate keys
+}
+
+// indexKey is the key of a row
+type indexKey struct {
+	key    []byte
+	record uint32
+}
+
+// CreateIndex creates or replaces the tag in an index snapshot, a compound index (CDX) file next to the table
+// that is not structural. FoxPro does not open it automatically and it is not maintained when rows are written,
+// applications use it after opening it explicitly (USE ... INDEX). Call CreateIndex again for each tag after modifying the table.
+// The keys of all rows (including deleted rows) are written with the expression, which may contain column names,
+// concatenations of character terms with + and the functions UPPER, LOWER, DTOS and STR.
+// Character keys use the machine collation, FOR filters are not supported.
+// Other tags of an existing snapshot are kept and the index file is rewritten, so replaced tags leave no unused pages.
+// Tables with a structural index fail with ErrStructuralIndex: FoxPro maintains its tags, this package does not,
+// so created or replaced tags would become stale with the next write.
+func (file *File) CreateIndex(tag string, expression string, opts *IndexOptions) error {
+	if opts == nil {
+		opts = &IndexOptions{}
+	}
+	tag = strings.ToUpper(strings.TrimSpace(tag))
+	if len(tag) == 0 || len(tag) > 10 || strings.ContainsAny(tag, " \x00") {
+		return newError("dbase-indexwrite-createindex-1", fmt.Errorf("tag name %q must have 1 to 10 characters without spaces", tag))
+	}
+	if file.config.ReadOnly {
+		return newError("dbase-indexwrite-createindex-2", fmt.Errorf("the index of a read-only table can not be created"))
+	}
+	if file.config.WithoutIndex {
+		return newError("dbase-indexwrite-createindex-9", fmt.Errorf("the index of a table opened without index can not be created"))
+	}
+	if StructuralFlag.Defined(file.header.TableFlags) {
+		return newError("dbase-indexwrite-createindex-10", fmt.Errorf("%w, the tags of %s are maintained by FoxPro and can not be created by this package", ErrStructuralIndex, file.config.Filename))
+	}
+	if len(file.path) == 0 {
+		return newError("dbase-indexwrite-createindex-3", fmt.Errorf("the index file of a table without a path can not be located"))
+	}
+	term, err := file.compileIndexExpression(expression)
+	if err != nil {
+		return newError("dbase-indexwrite-createindex-4", err)
+	}
+	keys, err := file.indexKeys(term, opts)
+	if err != nil {
+		return newError("dbase-indexwrite-createindex-5", err)
+	}
+	path, err := _findFile(strings.TrimSuffix(file.path, filepath.Ext(file.path)) + string(CDX))
+	if err != nil {
+		return newError("dbase-indexwrite-createindex-6", err)
+	}
+	blank := byte(0x00)
+	if term.character {
+		blank = ' '
+	}
+	err = file.writeIndexTag(path, tag, expression, term.length, blank, keys, opts)
+	if err != nil {
+		return newError("dbase-indexwrite-createindex-7", err)
+	}
+	debugf("Created index tag %s on %s with %d keys in %s", tag, expression, len(keys), path)
+	return nil
+}
+
+// indexKeys evaluates the expression for all rows and returns the keys sorted in index order
+func (file *File) indexKeys(term *indexTerm, opts *IndexOptions) ([]*indexKey, error) {
+	throttle := file.throttle()
+	keys := make([]*indexKey, 0, file.header.RowsCount)
+	for position := uint32(0); position < file.header.RowsCount; position++ {
+		data, err := file.ReadRow(position)
+		if err != nil {
+			return nil, err
+		}
+		throttle.wait(1, uint64(len(data)))
+		key, err := term.eval(data)
+		if err != nil {
+			return nil, fmt.Errorf("evaluating the key of row %d failed with error: %w", position, err)
+		}
+		keys = append(keys, &indexKey{key: key, record: position + 1})
+	}
+	// Keys are stored in ascending order, descending tags are read backwards
+	sort.SliceStable(keys, func(i, j int) bool { return bytes.Compare(keys[i].key, keys[j].key) < 0 })
+	if !opts.Unique && !opts.Candidate {
+		return keys, nil
+	}
+	unique := keys[:0]
+	for i, key := range keys {
+		if i > 0 && bytes.Equal(key.key, keys[i-1].key) {
+			if opts.Candidate {
+				return nil, fmt.Errorf("%w, rows %d and %d have the same key", ErrDuplicateKey, keys[i-1].record, key.record)
+			}
+			continue
+		}
+		unique = append(unique, key)
+	}
+	return unique, nil
+}
+
+// writeIndexTag writes the index file at the path with the tag and the other tags of the existing file.
+// The file is written anew into a temporary file that replaces it, so the pages of a replaced tag are not kept.
+func (file *File) writeIndexTag(path string, tag string, expression string, keyLength int, blank byte, keys []*indexKey, opts *IndexOptions) error {
+	tmp := path + ".tmp"
+	handle, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_RDWR, 0600)
+	if err != nil {
+		return fmt.Errorf("creating index file failed with error: %w", err)
+	}
+	err = file.writeIndexFile(handle, path, tag, expression, keyLength, blank, keys, opts)
+	if err == nil {
+		err = handle.Sync()
+	}
+	if closeErr := handle.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// writeIndexFile writes the tags of the index file at the path except the replaced tag and the new tag into the handle
+func (file *File) writeIndexFile(handle *os.File, path string, tag string, expression string, keyLength int, blank byte, keys []*indexKey, opts *IndexOptions) error {
+	// The directory header is written at the start of the file, the tags follow
+	writer := &indexWriter{handle: handle, end: indexHeaderSize}
+	directory, err := file.copyIndexTags(path, tag, writer)
+	if err != nil {
+		return err
+	}
+	tagOffset := writer.allocate(indexHeaderSize)
+	root, err := writer.tree(keys, keyLength, blank)
+	if err != nil {
+		return err
+	}
+	options := byte(indexOptionCompact | indexOptionCompound)
+	if opts.Unique {
+		options |= indexOptionUnique
+	}
+	if opts.Candidate {
+		options |= indexOptionCandidate
+	}
+	err = writer.header(tagOffset, root, keyLength, options, opts.Descending, expression)
+	if err != nil {
+		return err
+	}
+	directory = append(directory, &indexKey{key: fitIndexKey([]byte(tag), 10), record: uint32(tagOffset)})
+	sort.SliceStable(directory, func(i, j int) bool { return bytes.Compare(directory[i].key, directory[j].key) < 0 })
+	directoryRoot, err := writer.tree(directory, 10, ' ')
+	if err != nil {
+		return err
+	}
+	return writer.header(0, directoryRoot, 10, indexOptionCompact|indexOptionCompound|indexOptionStructure, false, "")
+}
+
+// copyIndexTags copies the tags of the index file at the path except the skipped tag and returns their directory entries.
+// Returns no entries if the file does not exist.
+func (file *File) copyIndexTags(path string, skip string, writer *indexWriter) ([]*indexKey, error) {
+	directory := make([]*indexKey, 0)
+	handle, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return directory, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening index file failed with error: %w", err)
+	}
+	defer handle.Close()
+	reader := &indexReader{handle: handle, file: file}
+	header, root, err := reader.header(0)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := reader.keys(root, header.KeyLength)
+	if err != nil {
+		return nil, err
+	}
+	// Tags are copied in the order they were created
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].offset < entries[j].offset })
+	for _, entry := range entries {
+		if entry.key == skip {
+			continue
+		}
+		reader.pages = 0
+		offset, err := writer.copyTag(reader, int64(entry.offset))
+		if err != nil {
+			return nil, fmt.Errorf("copying tag %s failed with error: %w", entry.key, err)
+		}
+		directory = append(directory, &indexKey{key: fitIndexKey([]byte(entry.key), 10), record: uint32(offset)})
+	}
+	return directory, nil
+}
+
+// indexWriter writes the pages of a compact index file
+type indexWriter struct {
+	handle *os.File
+	end    int64 // Offset of the next free page
+}
+
+// allocate reserves length bytes at the end of the file and returns their offset
+func (w *indexWriter) allocate(length int64) int64 {
+	offset := w.end
+	w.end += length
+	return offset
+}
+
+// copyTag copies the tag header at the offset and the nodes of its tree from the reader and returns the offset of the copy.
+// The nodes keep their contents, only the pointers to the root, sibling and child nodes are moved to the new offsets.
+func (w *indexWriter) copyTag(reader *indexReader, offset int64) (int64, error) {
+	raw, err := reader.read(offset, indexHeaderSize)
+	if err != nil {
+		return 0, err
+	}
+	keyLength := int(binary.LittleEndian.Uint16(raw[12:14]))
+	root := int64(binary.LittleEndian.Uint32(raw[0:4]))
+	tagOffset := w.allocate(indexHeaderSize)
+	offsets := make(map[int64]int64)
+	nodes := make([][]byte, 0)
+	sources := make([]int64, 0)
+	for queue := []int64{root}; len(queue) > 0; queue = queue[1:] {
+		if _, ok := offsets[queue[0]]; ok {
+			continue
+		}
+		node, err := reader.node(queue[0])
+		if err != nil {
+			return 0, err
+		}
+		offsets[queue[0]] = w.allocate(indexPageSize)
+		nodes = append(nodes, node)
+		sources = append(sources, queue[0])
+		if node[0]&indexNodeLeaf != 0 {
+			continue
+		}
+		count := int(binary.LittleEndian.Uint16(node[2:4]))
+		if 12+count*(keyLength+8) > indexPageSize {
+			return 0, fmt.Errorf("%w, %d entries with keys of %d bytes do not fit into the node", ErrInvalidIndex, count, keyLength)
+		}
+		for j := 0; j < count; j++ {
+			child := 12 + j*(keyLength+8) + keyLength + 4
+			queue = append(queue, int64(binary.BigEndian.Uint32(node[child:child+4])))
+		}
+	}
+	// relocate moves the little or big endian pointer in the node, -1 marks a missing sibling
+	relocate := func(pointer []byte, order binary.ByteOrder) error {
+		if int32(order.Uint32(pointer)) == -1 {
+			return nil
+		}
+		moved, ok := offsets[int64(order.Uint32(pointer))]
+		if !ok {
+			return fmt.Errorf("%w, node pointer %d is not part of the tree", ErrInvalidIndex, order.Uint32(pointer))
+		}
+		order.PutUint32(pointer, uint32(moved))
+		return nil
+	}
+	for i, node := range nodes {
+		for _, pointer := range [][]byte{node[4:8], node[8:12]} {
+			err = relocate(pointer, binary.LittleEndian)
+			if err != nil {
+				return 0, err
+			}
+		}
+		if node[0]&indexNodeLeaf == 0 {
+			for j := 0; j < int(binary.LittleEndian.Uint16(node[2:4])); j++ {
+				child := 12 + j*(keyLength+8) + keyLength + 4
+				err = relocate(node[child:child+4], binary.BigEndian)
+				if err != nil {
+					return 0, err
+				}
+			}
+		}
+		_, err = w.handle.WriteAt(node, offsets[sources[i]])
+		if err != nil {
+			return 0, err
+		}
+	}
+	binary.LittleEndian.PutUint32(raw[0:4], uint32(offsets[root]))
+	_, err = w.handle.WriteAt(raw, tagOffset)
+	if err != nil {
+		return 0, err
+	}
+	return tagOffset, nil
+}
+
+// header writes a tag header with the expression pool
+func (w *indexWriter) header(offset int64, root int64, keyLength int, options byte, descending bool, expression string) error {
+	if len(expression)+2 > indexHeaderSize-indexPageSize {
+		return fmt.Errorf("index expression of %d bytes exceeds the expression pool", len(expression))
+	}
+	raw := make([]byte, indexHeaderSize)
+	binary.LittleEndian.PutUint32(raw[0:4], uint32(root))
+	binary.LittleEndian.PutUint16(raw[12:14], uint16(keyLength))
+	raw[14] = options
+	raw[15] = indexSignature
+	if descending {
+		binary.LittleEndian.PutUint16(raw[0x1F6:0x1F8], 1)
+	}
+	// The pool holds the key expression and the empty FOR expression, both terminated with NUL
+	pool := len(expression) + 1
+	binary.LittleEndian.PutUint16(raw[0x1F8:0x1FA], uint16(pool))
+	binary.LittleEndian.PutUint16(raw[0x1FA:0x1FC], 1)
+	binary.LittleEndian.PutUint16(raw[0x1FC:0x1FE], 0)
+	binary.LittleEndian.PutUint16(raw[0x1FE:0x200], uint16(pool))
+	copy(raw[indexPageSize:], expression)
+	_, err := w.handle.WriteAt(raw, offset)
+	return err
+}
+
+// tree writes the keys as balanced tree of leaf and interior nodes and returns the offset of the root node
+func (w *indexWriter) tree(keys []*indexKey, keyLength int, blank byte) (int64, error) {
+	level, err := w.leaves(keys, keyLength, blank)
+	if err != nil {
+		return 0, err
+	}
+	// Interior entries hold the key, the record number and the pointer of the child node
+	perNode := (indexPageSize - 12) / (keyLength + 8)
+	for len(level) > 1 {
+		parents := make([]*indexNode, 0, len(level)/perNode+1)
+		for start := 0; start < len(level); start += perNode {
+			end := start + perNode
+			if end > len(level) {
+				end = len(level)
+			}
+			parents = append(parents, &indexNode{offset: w.allocate(indexPageSize), children: level[start:end]})
+		}
+		level = parents
+	}
+	root := level[0]
+	root.attributes |= indexNodeRoot
+	err = w.nodes(root, keyLength)
+	if err != nil {
+		return 0, err
+	}
+	return root.offset, nil
+}
+
+// indexNode is a node of the tree while it is written
+type indexNode struct {
+	offset     int64
+	attributes uint16
+	keys       []*indexKey  // Keys of a leaf node
+	children   []*indexNode // Children of an interior node
+	raw        []byte       // Encoded leaf node without the attributes and sibling pointers
+}
+
+// last returns the highest key below the node
+func (n *indexNode) last() *indexKey {
+	if len(n.children) > 0 {
+		return n.children[len(n.children)-1].last()
+	}
+	return n.keys[len(n.keys)-1]
+}
+
+// leaves packs the keys into compact leaf nodes
+func (w *indexWriter) leaves(keys []*indexKey, keyLength int, blank byte) ([]*indexNode, error) {
+	maxRecord := uint32(0)
+	for _, key := range keys {
+		if key.record > maxRecord {
+			maxRecord = key.record
+		}
+	}
+	layout := newLeafLayout(maxRecord, keyLength, blank)
+	leaves := make([]*indexNode, 0)
+	for start := 0; start < len(keys) || len(leaves) == 0; {
+		node := &indexNode{offset: w.allocate(indexPageSize), attributes: indexNodeLeaf}
+		count := layout.fit(keys[start:], keyLength)
+		if count == 0 && start < len(keys) {
+			return nil, fmt.Errorf("key of %d bytes does not fit into a leaf node", keyLength)
+		}
+		node.keys = keys[start : start+count]
+		node.raw = layout.encode(node.keys, keyLength)
+		leaves = append(leaves, node)
+		start += count
+	}
+	return leaves, nil
+}
+
+// nodes writes the node and its children, leaves are linked with their siblings
+func (w *indexWriter) nodes(root *indexNode, keyLength int) error {
+	level := []*indexNode{root}
+	for len(level) > 0 {
+		next := make([]*indexNode, 0)
+		for i, node := range level {
+			raw := make([]byte, indexPageSize)
+			binary.LittleEndian.PutUint16(raw[0:2], node.attributes)
+			left, right := int32(-1), int32(-1)
+			if i > 0 {
+				left = int32(level[i-1].offset)
+			}
+			if i < len(level)-1 {
+				right = int32(level[i+1].offset)
+			}
+			binary.LittleEndian.PutUint32(raw[4:8], uint32(left))
+			binary.LittleEndian.PutUint32(raw[8:12], uint32(right))
+			if node.children == nil {
+				binary.LittleEndian.PutUint16(raw[2:4], uint16(len(node.keys)))
+				copy(raw[12:], node.raw)
+			} else {
+				binary.LittleEndian.PutUint16(raw[2:4], uint16(len(node.children)))
+				for j, child := range node.children {
+					entry := raw[12+j*(keyLength+8):]
+					last := child.last()
+					copy(entry, last.key)
+					binary.BigEndian.PutUint32(entry[keyLength:], last.record)
+					binary.BigEndian.PutUint32(entry[keyLength+4:], uint32(child.offset))
+				}
+				next = append(next, node.children...)
+			}
+			_, err := w.handle.WriteAt(raw, node.offset)
+			if err != nil {
+				return err
+			}
+		}
+		level = next
+	}
+	return nil
+}
+
+// leafLayout is the bit layout of the key information of compact leaf nodes
+type leafLayout struct {
+	recordBits    uint
+	duplicateBits uint
+	trailingBits  uint
+	size          int  // Bytes of the information of one key
+	blank         byte // Trailing byte that is not stored, spaces for character keys and 0x00 for binary keys
+}
+
+// newLeafLayout returns the smallest layout holding the record numbers and the duplicate and trailing byte counts
+func newLeafLayout(maxRecord uint32, keyLength int, blank byte) *leafLayout {
+	countBits := uint(bits.Len(uint(keyLength)))
+	recordBits := uint(bits.Len32(maxRecord))
+	size := int((recordBits + 2*countBits + 7) / 8)
+	recordBits = uint(size*8) - 2*countBits
+	duplicateBits, trailingBits := countBits, countBits
+	// Record numbers have at most 32 bits, remaining bits widen the counts
+	for recordBits > 32 {
+		recordBits--
+		if duplicateBits <= trailingBits {
+			duplicateBits++
+		} else {
+			trailingBits++
+		}
+	}
+	return &leafLayout{
+		recordBits:    recordBits,
+		duplicateBits: duplicateBits,
+		trailingBits:  trailingBits,
+		size:          size,
+		blank:         blank,
+	}
+}
+
+// compress returns the number of bytes the key shares with the previous key and the number of trailing blanks
+func (l *leafLayout) compress(key []byte, previous []byte) (int, int) {
+	trailing := 0
+	for trailing < len(key) && key[len(key)-1-trailing] == l.blank {
+		trailing++
+	}
+	duplicates := 0
+	for duplicates < len(previous) && duplicates < len(key)-trailing && key[duplicates] == previous[duplicates] {
+		duplicates++
+	}
+	return duplicates, trailing
+}
+
+// fit returns how many of the keys fit into one leaf node
+func (l *leafLayout) fit(keys []*indexKey, keyLength int) int {
+	free := indexPageSize - 24
+	var previous []byte
+	for i, key := range keys {
+		duplicates, trailing := l.compress(key.key, previous)
+		free -= l.size + keyLength - duplicates - trailing
+		if free < 0 {
+			return i
+		}
+		previous = key.key
+	}
+	return len(keys)
+}
+
+// encode returns the leaf node from the free space on, behind the attributes and sibling pointers
+func (l *leafLayout) encode(keys []*indexKey, keyLength int) []byte {
+	raw := make([]byte, indexPageSize-12)
+	binary.LittleEndian.PutUint32(raw[2:6], uint32(1)<<l.recordBits-1)
+	raw[6] = byte(1<<l.duplicateBits - 1)
+	raw[7] = byte(1<<l.trailingBits - 1)
+	raw[8] = byte(l.recordBits)
+	raw[9] = byte(l.duplicateBits)
+	raw[10] = byte(l.trailingBits)
+	raw[11] = byte(l.size)
+	position := len(raw)
+	var previous []byte
+	for i, key := range keys {
+		duplicates, trailing := l.compress(key.key, previous)
+		value := uint64(key.record) | uint64(duplicates)<<l.recordBits | uint64(trailing)<<(l.recordBits+l.duplicateBits)
+		information := make([]byte, 8)
+		binary.LittleEndian.PutUint64(information, value)
+		copy(raw[12+i*l.size:], information[:l.size])
+		length := keyLength - duplicates - trailing
+		position -= length
+		copy(raw[position:], key.key[duplicates:duplicates+length])
+		previous = key.key
+	}
+	free := position - 12 - len(keys)*l.size
+	binary.LittleEndian.PutUint16(raw[0:2], uint16(free))
+	return raw
+}
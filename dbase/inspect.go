@@ -0,0 +1,150 @@
+package dbase
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// InspectionReport contains the structure, companion files and validation results of a table, see InspectJSON
+type InspectionReport struct {
+	Table        string              `json:"table"`          // File name of the table
+	FileType     byte                `json:"file_type"`      // File type flag of the header
+	FileTypeName string              `json:"file_type_name"` // Name of the file type, e.g. "Visual FoxPro"
+	CodePage     byte                `json:"code_page"`      // Code page mark of the header
+	CodePageName string              `json:"code_page_name"` // Name of the code page, e.g. "Windows ANSI"
+	Modified     string              `json:"modified"`       // Date of the last update stored in the header (YYYY-MM-DD)
+	Rows         uint32              `json:"rows"`           // Rows count of the header
+	RowLength    uint16              `json:"row_length"`     // Length of one row including the deletion flag
+	FirstRow     uint16              `json:"first_row"`      // Offset of the first row
+	Columns      []*ColumnInspection `json:"columns"`        // Columns in table order
+	Memo         *MemoInspection     `json:"memo,omitempty"` // Memo file header, if the table has a memo file
+	Companions   []*CompanionFile    `json:"companions"`     // Files next to the table with the same base name, e.g. memo and index files
+	Validation   *ValidationReport   `json:"validation"`     // Result of Validate
+	Valid        bool                `json:"valid"`          // True if the validation found no problems
+}
+
+// ColumnInspection describes a column of an inspected table
+type ColumnInspection struct {
+	Name     string   `json:"name"`      // Column name
+	Type     string   `json:"type"`      // Type character, e.g. "C"
+	TypeName string   `json:"type_name"` // Name of the type, e.g. "Character"
+	Length   uint8    `json:"length"`    // Length in bytes
+	Decimals uint8    `json:"decimals"`  // Number of decimal places
+	Position uint32   `json:"position"`  // Offset of the column within a row
+	Flags    []string `json:"flags"`     // Names of the column flags, e.g. "nullable"
+}
+
+// MemoInspection describes the memo file of an inspected table
+type MemoInspection struct {
+	BlockSize uint16 `json:"block_size"` // Size of a memo block in bytes
+	NextFree  uint32 `json:"next_free"`  // Next free block
+	Size      int64  `json:"size"`       // Size of the memo file in bytes
+}
+
+// CompanionFile is a file next to the table with the same base name
+type CompanionFile struct {
+	Name     string    `json:"name"`     // File name
+	Type     string    `json:"type"`     // Upper case extension without dot, e.g. "FPT" or "CDX"
+	Size     int64     `json:"size"`     // Size in bytes
+	Modified time.Time `json:"modified"` // Time of the last modification
+}
+
+// Inspect returns the structure, the companion files and the validation results of the table.
+// The validation reads all rows, see Validate.
+func (file *File) Inspect() (*InspectionReport, error) {
+	name := file.config.Filename
+	if len(file.path) > 0 {
+		name = file.path
+	}
+	report := &InspectionReport{
+		Table:        filepath.Base(name),
+		FileType:     file.header.FileType,
+		FileTypeName: file.header.Version().String(),
+		CodePage:     file.header.CodePage,
+		CodePageName: CodePageName(file.header.CodePage),
+		Modified:     file.header.Modified(0).Format("2006-01-02"),
+		Rows:         file.header.RowsCount,
+		RowLength:    file.header.RowLength,
+		FirstRow:     file.header.FirstRow,
+		Columns:      make([]*ColumnInspection, 0, len(file.table.columns)),
+		Companions:   make([]*CompanionFile, 0),
+	}
+	for _, column := range file.table.columns {
+		report.Columns = append(report.Columns, &ColumnInspection{
+			Name:     column.Name(),
+			Type:     column.Type(),
+			TypeName: column.Kind().Name(),
+			Length:   column.Length,
+			Decimals: column.Decimals,
+			Position: column.Position,
+			Flags:    column.flags(),
+		})
+	}
+	if file.memoHeader != nil {
+		size, err := file.Size(true)
+		if err != nil {
+			return nil, newError("dbase-inspect-inspect-1", err)
+		}
+		report.Memo = &MemoInspection{BlockSize: file.memoHeader.BlockSize, NextFree: file.memoHeader.NextFree, Size: size}
+	}
+	companions, err := file.companionFiles()
+	if err != nil {
+		return nil, newError("dbase-inspect-inspect-2", err)
+	}
+	report.Companions = append(report.Companions, companions...)
+	report.Validation, err = file.Validate()
+	if err != nil {
+		return nil, newError("dbase-inspect-inspect-3", err)
+	}
+	report.Valid = report.Validation.Valid()
+	return report, nil
+}
+
+// InspectJSON returns the result of Inspect as JSON, e.g. to catalog many tables
+func (file *File) InspectJSON() ([]byte, error) {
+	report, err := file.Inspect()
+	if err != nil {
+		return nil, newError("dbase-inspect-inspectjson-1", err)
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		return nil, newError("dbase-inspect-inspectjson-2", err)
+	}
+	return data, nil
+}
+
+// companionFiles lists the files in the directory of the table with the same base name, compared case-insensitive.
+// Tables without a path, e.g. opened with GenericIO, have no companion files.
+func (file *File) companionFiles() ([]*CompanionFile, error) {
+	path := file.path
+	if len(path) == 0 {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return nil, newError("dbase-inspect-companionfiles-1", err)
+	}
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	companions := make([]*CompanionFile, 0)
+	for _, entry := range entries {
+		name := entry.Name()
+		ext := filepath.Ext(name)
+		if entry.IsDir() || strings.EqualFold(name, filepath.Base(path)) || !strings.EqualFold(strings.TrimSuffix(name, ext), base) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, newError("dbase-inspect-companionfiles-2", err)
+		}
+		companions = append(companions, &CompanionFile{
+			Name:     name,
+			Type:     strings.ToUpper(strings.TrimPrefix(ext, ".")),
+			Size:     info.Size(),
+			Modified: info.ModTime(),
+		})
+	}
+	return companions, nil
+}
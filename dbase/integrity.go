@@ -0,0 +1,109 @@
+package dbase
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IntegrityReport contains the result of the referential integrity check of a relation set
+type IntegrityReport struct {
+	Relations []*RelationIntegrity // Result per declared relation
+}
+
+// RelationIntegrity contains the result of the referential integrity check of one relation
+type RelationIntegrity struct {
+	Relation       *Relation       // Checked relation
+	ParentRows     uint32          // Number of checked parent rows
+	ChildRows      uint32          // Number of checked child rows
+	EmptyKeys      uint32          // Number of child rows with an empty key, these are not related to any parent
+	OrphanedRows   []uint32        // Positions of the child rows without parent row
+	MissingParents [][]interface{} // Distinct key values referenced by child rows without parent row
+}
+
+// Valid returns true if no relation has orphaned child rows
+func (r *IntegrityReport) Valid() bool {
+	for _, relation := range r.Relations {
+		if len(relation.OrphanedRows) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns a summary of the orphaned rows and missing parents per relation
+func (r *IntegrityReport) String() string {
+	var b strings.Builder
+	for _, relation := range r.Relations {
+		fmt.Fprintf(&b, "%s %v -> %s %v: %d orphaned of %d rows, %d missing parents\n", relation.Relation.Parent, relation.Relation.ParentKey, relation.Relation.Child, relation.Relation.ChildKey, len(relation.OrphanedRows), relation.ChildRows, len(relation.MissingParents))
+	}
+	return b.String()
+}
+
+// CheckIntegrity streams the parent and child table of every declared relation and reports child rows without parent row.
+// Only the key columns are read. Deleted rows are skipped if SkipDeleted is set for the relation.
+// Child rows with an empty key (all key values blank or nil) are counted but not reported as orphaned.
+// Relations stored in a database container are not read, they refer to index tags that are not supported by this package.
+func (set *RelationSet) CheckIntegrity() (*IntegrityReport, error) {
+	report := &IntegrityReport{Relations: make([]*RelationIntegrity, 0, len(set.relations))}
+	for _, relation := range set.relations {
+		result, err := set.checkRelation(relation)
+		if err != nil {
+			return nil, newError("dbase-integrity-checkintegrity-1", fmt.Errorf("checking relation from '%s' to '%s' failed with error: %w", relation.Parent, relation.Child, err))
+		}
+		report.Relations = append(report.Relations, result)
+	}
+	return report, nil
+}
+
+// checkRelation collects the parent keys and checks every child key against them
+func (set *RelationSet) checkRelation(relation *Relation) (*RelationIntegrity, error) {
+	result := &RelationIntegrity{
+		Relation:       relation,
+		OrphanedRows:   make([]uint32, 0),
+		MissingParents: make([][]interface{}, 0),
+	}
+	parents := make(map[string]bool)
+	err := set.tables[relation.Parent].scanKeys(relation.ParentKey, func(position uint32, deleted bool, key string, values []interface{}) {
+		if deleted && relation.SkipDeleted {
+			return
+		}
+		result.ParentRows++
+		parents[key] = true
+	})
+	if err != nil {
+		return nil, newError("dbase-integrity-checkrelation-1", err)
+	}
+	missing := make(map[string]bool)
+	err = set.tables[relation.Child].scanKeys(relation.ChildKey, func(position uint32, deleted bool, key string, values []interface{}) {
+		if deleted && relation.SkipDeleted {
+			return
+		}
+		result.ChildRows++
+		if parents[key] {
+			return
+		}
+		if emptyKey(values) {
+			result.EmptyKeys++
+			return
+		}
+		result.OrphanedRows = append(result.OrphanedRows, position)
+		if !missing[key] {
+			missing[key] = true
+			result.MissingParents = append(result.MissingParents, values)
+		}
+	})
+	if err != nil {
+		return nil, newError("dbase-integrity-checkrelation-2", err)
+	}
+	return result, nil
+}
+
+// emptyKey returns true if all values of the key are nil or blank
+func emptyKey(values []interface{}) bool {
+	for _, val := range values {
+		if len(trimText(val)) > 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -1,9 +1,13 @@
 package dbase
 
 import (
+	"bytes"
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"math"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -38,6 +42,16 @@ func (file *File) Interpret(raw []byte, column *Column) (interface{}, error) {
 	if len(raw) != int(column.Length) {
 		return nil, newError("dbase-interpreter-datatovalue-1", fmt.Errorf("invalid length %v Bytes != %v Bytes at column field: %v", len(raw), column.Length, column.Name()))
 	}
+	value, err := file.interpret(raw, column)
+	// Empty fields are checked first, as e.g. a date of zeros is empty but can not be parsed
+	if file.config.NilPolicy != NilNone && file.isEmpty(raw, value, column) {
+		return nil, nil
+	}
+	return value, err
+}
+
+// Converts raw column data to the Go type of the column
+func (file *File) interpret(raw []byte, column *Column) (interface{}, error) {
 	switch DataType(column.DataType) {
 	case Memo:
 		// M values contain the address in the FPT file from where to read data
@@ -92,6 +106,40 @@ func (file *File) Interpret(raw []byte, column *Column) (interface{}, error) {
 	}
 }
 
+// Returns true if the field is empty and the nil policy converts empty fields of the column type to nil
+func (file *File) isEmpty(raw []byte, value interface{}, column *Column) bool {
+	blank := func() bool {
+		for _, b := range raw {
+			if b != ' ' && b != 0x00 {
+				return false
+			}
+		}
+		return true
+	}
+	policy := file.config.NilPolicy
+	switch DataType(column.DataType) {
+	case Character:
+		return policy&NilEmptyText != 0 && blank()
+	case Varchar, Memo:
+		if policy&NilEmptyText == 0 {
+			return false
+		}
+		switch v := value.(type) {
+		case string:
+			return len(strings.TrimRight(v, " \x00")) == 0
+		case []byte:
+			return len(v) == 0
+		}
+	case Numeric, Float:
+		return policy&NilEmptyNumber != 0 && blank()
+	case Date:
+		return policy&NilEmptyDate != 0 && (blank() || strings.Trim(string(raw), "0") == "")
+	case DateTime:
+		return policy&NilEmptyDate != 0 && bytes.Count(raw, []byte{0x00}) == len(raw)
+	}
+	return false
+}
+
 // Converts column data to the byte representation
 // For M values the data has to be written to the memo file
 func (file *File) GetRepresentation(field *Field, skipSpacing bool) ([]byte, error) {
@@ -154,25 +202,82 @@ func (file *File) GetRepresentation(field *Field, skipSpacing bool) ([]byte, err
 
 // Returns the value from the memo file as string or []byte
 func (file *File) parseMemo(raw []byte, column *Column) (interface{}, error) {
+	if file.memoMissing {
+		return nil, nil
+	}
 	// M values contain the address in the FPT file from where to read data
-	memo, isText, err := file.ReadMemo(raw)
+	memo, isText, err := file.readMemo(raw, column)
 	if err != nil {
 		return nil, newError("dbase-interpreter-parsememo-1", fmt.Errorf("parsing memo failed at column field: %v failed with error: %w", column.Name(), err))
 	}
 	if isText {
-		return string(memo), nil
+		return string(file.normalizeMemoText(memo)), nil
 	}
 	return memo, nil
 }
 
+// normalizeMemoText removes the padding and converts the line endings of memo text read from the memo file
+func (file *File) normalizeMemoText(memo []byte) []byte {
+	if file.config.TrimMemoPadding {
+		memo = bytes.TrimRight(memo, "\x00\x1a")
+	}
+	if file.config.MemoLineEnding != LineEndingKeep {
+		memo = bytes.ReplaceAll(memo, []byte("\r\n"), []byte("\n"))
+		memo = bytes.ReplaceAll(memo, []byte("\r"), []byte("\n"))
+	}
+	return memo
+}
+
+// columnConverter returns the encoding converter of the column or, if the column has no own encoding, of the table
+func (file *File) columnConverter(column *Column) EncodingConverter {
+	if converter, ok := file.table.converters[column]; ok {
+		return converter
+	}
+	return file.config.Converter
+}
+
+// decodeMemoText decodes memo text with the encoding of the column or the table.
+// If DetectMemoBOM is set, a byte order mark selects UTF-8 or UTF-16 instead.
+func (file *File) decodeMemoText(memo []byte, column *Column) ([]byte, error) {
+	converter := file.columnConverter(column)
+	if file.config.DetectMemoBOM {
+		switch {
+		case bytes.HasPrefix(memo, utf8BOM):
+			converter = NewUTF8Converter()
+		case bytes.HasPrefix(memo, []byte{0xFF, 0xFE}):
+			converter = NewUTF16Converter(false)
+		case bytes.HasPrefix(memo, []byte{0xFE, 0xFF}):
+			converter = NewUTF16Converter(true)
+		}
+	}
+	return converter.Decode(memo)
+}
+
+// denormalizeMemoText converts the line endings of memo text to the line ending of the memo file
+func (file *File) denormalizeMemoText(memo []byte) []byte {
+	if file.config.MemoLineEnding != LineEndingCRLF {
+		return memo
+	}
+	memo = bytes.ReplaceAll(memo, []byte("\r\n"), []byte("\n"))
+	return bytes.ReplaceAll(memo, []byte("\n"), []byte("\r\n"))
+}
+
 // Saves the value to the memo file and returns the address in the FPT file
 func (file *File) getMemoRepresentation(field *Field) ([]byte, error) {
 	memo := make([]byte, 0)
 	txt := false
 	s, sok := field.value.(string)
 	if sok {
-		memo = []byte(s)
+		memo = file.denormalizeMemoText([]byte(s))
 		txt = true
+		// Text is only encoded if the column has its own encoding
+		if converter, ok := file.table.converters[field.column]; ok {
+			encoded, err := converter.Encode(memo)
+			if err != nil {
+				return nil, newError("dbase-interpreter-getmemorepresentation-3", fmt.Errorf("encoding memo failed at column field: %v failed with error: %w", field.Name(), err))
+			}
+			memo = encoded
+		}
 	}
 	m, ok := field.value.([]byte)
 	if ok {
@@ -182,6 +287,13 @@ func (file *File) getMemoRepresentation(field *Field) ([]byte, error) {
 	if !ok && !sok {
 		return nil, newError("dbase-interpreter-getmemorepresentation-1", fmt.Errorf("invalid type for memo field: %T", field.value))
 	}
+	if file.config.MemoHistory {
+		address, err := file.writeMemoVersion(field, memo, txt)
+		if err != nil {
+			return nil, newError("dbase-interpreter-getmemorepresentation-4", fmt.Errorf("writing to memo file at column field: %v failed with error: %w", field.Name(), err))
+		}
+		return address, nil
+	}
 	// Write the memo to the memo file
 	address, err := file.WriteMemo(memo, txt, len(memo))
 	if err != nil {
@@ -193,7 +305,7 @@ func (file *File) getMemoRepresentation(field *Field) ([]byte, error) {
 // Returns the value as string
 func (file *File) parseCharacter(raw []byte, column *Column) (interface{}, error) {
 	// C values are stored as strings, the returned string is not trimmed
-	str, err := toUTF8String(raw, file.config.Converter)
+	str, err := toUTF8String(raw, file.columnConverter(column))
 	if err != nil {
 		return str, newError("dbase-interpreter-parsecharacter-1", fmt.Errorf("parsing to utf8 string failed at column field: %v failed with error: %w", column.Name(), err))
 	}
@@ -208,7 +320,7 @@ func (file *File) getCharacterRepresentation(field *Field, skipSpacing bool) ([]
 		return nil, newError("dbase-interpreter-getcharacterrepresentation-1", fmt.Errorf("invalid data type %T, expected string on column field: %v", field.value, field.Name()))
 	}
 	raw := make([]byte, field.column.Length)
-	bin, err := fromUtf8String([]byte(c), file.config.Converter)
+	bin, err := fromUtf8String([]byte(c), file.columnConverter(field.column))
 	if err != nil {
 		return nil, newError("dbase-interpreter-getcharacterrepresentation-2", fmt.Errorf("parsing from utf8 string at column field: %v failed with error %w", field.Name(), err))
 	}
@@ -344,17 +456,13 @@ func (file *File) parseDate(raw []byte, column *Column) (interface{}, error) {
 
 // Get the time.Time value as byte representation
 func (file *File) getDateRepresentation(field *Field) ([]byte, error) {
-	d, ok := field.value.(time.Time)
-	if !ok {
-		s, ok := field.value.(string)
-		if !ok {
-			return nil, newError("dbase-interpreter-getdaterepresentation-1", fmt.Errorf("invalid data type %T, expected time.Time at column field: %v", field.value, field.Name()))
-		}
-		t, err := time.Parse(time.RFC3339, s)
-		if err != nil {
-			return nil, newError("dbase-interpreter-getdaterepresentation-2", fmt.Errorf("parsing time failed at column field: %v failed with error: %w", field.Name(), err))
-		}
-		d = t
+	d, err := file.timeValue(field)
+	if err != nil {
+		return nil, newError("dbase-interpreter-getdaterepresentation-1", err)
+	}
+	// Empty dates are stored like nil values
+	if d.IsZero() {
+		return make([]byte, field.column.Length), nil
 	}
 	raw := make([]byte, field.column.Length)
 	bin := []byte(d.Format("20060102"))
@@ -372,17 +480,12 @@ func (file *File) parseDateTime(raw []byte) (interface{}, error) {
 
 // Get the time.Time value as byte representation consisting of 4 bytes for julian date and 4 bytes for time
 func (file *File) getDateTimeRepresentation(field *Field) ([]byte, error) {
-	t, ok := field.value.(time.Time)
-	if !ok {
-		s, ok := field.value.(string)
-		if !ok {
-			return nil, newError("dbase-interpreter-getdatetimerepresentation-1", fmt.Errorf("invalid data type %T, expected time.Time at column field: %v", field.value, field.Name()))
-		}
-		parsedTime, err := time.Parse(time.RFC3339, s)
-		if err != nil {
-			return nil, newError("dbase-interpreter-getdatetimerepresentation-2", fmt.Errorf("parsing time failed at column field: %v failed with error: %w", field.Name(), err))
-		}
-		t = parsedTime
+	t, err := file.timeValue(field)
+	if err != nil {
+		return nil, newError("dbase-interpreter-getdatetimerepresentation-1", err)
+	}
+	if t.IsZero() {
+		return make([]byte, field.column.Length), nil
 	}
 	raw := make([]byte, 8)
 	i := ymd2jd(t.Year(), int(t.Month()), t.Day())
@@ -403,6 +506,58 @@ func (file *File) getDateTimeRepresentation(field *Field) ([]byte, error) {
 	return raw, nil
 }
 
+// Returns the value of a D or T field as time.Time.
+// Strings are parsed with the configured date layouts, numbers are interpreted as Unix timestamps if EpochSeconds is configured.
+func (file *File) timeValue(field *Field) (time.Time, error) {
+	layouts := file.config.DateLayouts
+	if len(layouts) == 0 {
+		layouts = DefaultDateLayouts
+	}
+	epoch := false
+	for _, layout := range layouts {
+		if layout == EpochSeconds {
+			epoch = true
+			break
+		}
+	}
+	switch v := field.value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		s := strings.TrimSpace(v)
+		if len(s) == 0 {
+			return time.Time{}, nil
+		}
+		for _, layout := range layouts {
+			if layout == EpochSeconds {
+				if seconds, err := strconv.ParseFloat(s, 64); err == nil {
+					return epochTime(seconds), nil
+				}
+				continue
+			}
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("parsing time %q failed at column field: %v, accepted layouts are %q", v, field.Name(), layouts)
+	case json.Number:
+		if seconds, err := v.Float64(); err == nil && epoch {
+			return epochTime(seconds), nil
+		}
+	default:
+		if seconds, ok := toFloat(v); ok && epoch {
+			return epochTime(seconds), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid data type %T, expected time.Time at column field: %v", field.value, field.Name())
+}
+
+// Returns the time of the seconds since the Unix epoch in UTC
+func epochTime(seconds float64) time.Time {
+	whole, fraction := math.Modf(seconds)
+	return time.Unix(int64(whole), int64(math.Round(fraction*1e9))).UTC()
+}
+
 // Return the value (T or F) as bool
 func (file *File) parseLogical(raw []byte) (interface{}, error) {
 	return string(raw) == "T", nil
@@ -489,8 +644,10 @@ func (file *File) parseVarchar(raw []byte, column *Column) (interface{}, error)
 		return []byte{}, nil
 	}
 	if varlen {
-		length := raw[len(raw)-1]
-		raw = raw[:length]
+		if len(raw) == 0 || int(raw[len(raw)-1]) >= len(raw) {
+			return nil, newError("dbase-interpreter-parsevarchar-2", fmt.Errorf("%w, variable length exceeds the length %d of column field: %v", ErrIncomplete, column.Length, column.Name()))
+		}
+		raw = raw[:raw[len(raw)-1]]
 	}
 	return string(raw), nil
 }
@@ -516,8 +673,10 @@ func (file *File) parseVarbinary(raw []byte, column *Column) (interface{}, error
 		return []byte{}, nil
 	}
 	if varlen {
-		length := raw[len(raw)-1]
-		raw = raw[:length]
+		if len(raw) == 0 || int(raw[len(raw)-1]) >= len(raw) {
+			return nil, newError("dbase-interpreter-parsevarbinary-2", fmt.Errorf("%w, variable length exceeds the length %d of column field: %v", ErrIncomplete, column.Length, column.Name()))
+		}
+		raw = raw[:raw[len(raw)-1]]
 	}
 	return raw, nil
 }
@@ -2,22 +2,47 @@ package dbase
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // File is the main struct to handle a dBase file.
 // Each file type is basically a Table or a Memo file.
 type File struct {
-	config         *Config     // The config used when working with the DBF file.
-	handle         interface{} // DBase file handle.
-	relatedHandle  interface{} // Memo file handle.
-	io             IO          // The IO interface used to work with the DBF file.
-	header         *Header     // DBase file header containing relevant information.
-	memoHeader     *MemoHeader // Memo file header containing relevant information.
-	dbaseMutex     *sync.Mutex // Mutex locks for concurrent writing access to the DBF file.
-	memoMutex      *sync.Mutex // Mutex locks for concurrent writing access to the FPT file.
-	table          *Table      // Containing the columns and internal row pointer.
-	nullFlagColumn *Column     // The column containing the null flag column (if varchar or varbinary field exists).
+	config         *Config            // The config used when working with the DBF file.
+	handle         interface{}        // DBase file handle.
+	relatedHandle  interface{}        // Memo file handle.
+	io             IO                 // The IO interface used to work with the DBF file.
+	header         *Header            // DBase file header containing relevant information.
+	memoHeader     *MemoHeader        // Memo file header containing relevant information.
+	dbaseMutex     *sync.Mutex        // Mutex locks for concurrent writing access to the DBF file.
+	memoMutex      *sync.Mutex        // Mutex locks for concurrent writing access to the FPT file.
+	table          *Table             // Containing the columns and internal row pointer.
+	nullFlagColumn *Column            // The column containing the null flag column (if varchar or varbinary field exists).
+	rowCache       *RowCache          // Optional cache of decoded rows.
+	hooks          atomic.Value       // Registered read and write hooks (*hooks), replaced under writeMutex when a hook is registered.
+	confirmedRows  uint32             // Rows count stored in the header, if rows were recovered.
+	relations      *RelationSet       // Relation set the table belongs to, if any.
+	quarantine     *Quarantine        // Quarantine file for rows skipped because they could not be read.
+	path           string             // Path of the opened table file, used to detect replaced files.
+	stat           os.FileInfo        // File info of the opened table file, used to detect replaced files.
+	checked        time.Time          // Time of the last check for a replaced file, if AutoReopen is set.
+	scans          int                // Number of running scans, the table is not reopened during a scan.
+	drift          *SchemaDrift       // Differences to the reference schema, if set in the config.
+	writeMutex     sync.Mutex         // Serializes writes of rows, fields and columns, so concurrent writes can not interleave.
+	lockMutex      sync.Mutex         // Guards the shared locks.
+	sharedLocks    map[sharedLock]int // Byte range locks held by this file with Config.SharedWrite, counted by holder.
+	stats          fileStats          // Counters of reads, writes and decoding, see Stats.
+	lengthAudit    *LengthAudit       // Optional audit of the lengths of written values.
+	uniqueKeys     []*UniqueKey       // Keys checked before rows are written.
+	companions     *CompanionReport   // Consistency of the memo and index file, if checked on open.
+	memoMissing    bool               // The memo file is missing or not opened and memo columns are read as nil, see Config.SkipMissingMemo.
+	deletedHidden  bool               // Deleted rows are hidden, initialized from Config.IgnoreDeleted and changed under writeMutex, see SetDeletedHidden.
+	packs          packHistory        // Rows removed by the packs of this handle, used to resolve bookmarks.
 }
 
 // IO is the interface to work with the DBF file.
@@ -47,6 +72,33 @@ type IO interface {
 	Deleted(file *File) (bool, error)
 }
 
+// FieldWriter is implemented by IO implementations that write a single field of a row in place, like the default implementations.
+// UpdateField uses ReadRow and WriteRow of other implementations, which writes the memos of the row again.
+type FieldWriter interface {
+	WriteField(file *File, position uint32, field *Field) error
+}
+
+// RawIO is implemented by IO implementations with access to the bytes of the table and memo file, like the default implementations.
+// Without it ReadRaw reads only ranges of the rows with ReadRow, and Size and WriteRaw fail with ErrUnsupported.
+// Checks, repairs, backups and snapshots of the files require RawIO.
+type RawIO interface {
+	ReadRaw(file *File, related bool, offset int64, length int) ([]byte, error)
+	Size(file *File, related bool) (int64, error)
+	WriteRaw(file *File, related bool, offset int64, data []byte) error
+}
+
+// MemoReader is implemented by IO implementations that read memos without decoding text, like the default implementations.
+// Column encodings and DetectMemoBOM apply to memos of other implementations only if ReadMemo returns the text undecoded.
+type MemoReader interface {
+	ReadRawMemo(file *File, address []byte) ([]byte, bool, error)
+}
+
+// Truncater is implemented by IO implementations that can change the size of the table and memo file, like the default implementations.
+// Packing and repairing tables fail with ErrUnsupported without it.
+type Truncater interface {
+	Truncate(file *File, related bool, size int64) error
+}
+
 // Opens a dBase database file (and the memo file if needed).
 // The config parameter is required to specify the file path, encoding, file handles (IO) and others.
 // If IO is nil, the default implementation is used depending on the OS.
@@ -54,7 +106,60 @@ func OpenTable(config *Config) (*File, error) {
 	if config.IO == nil {
 		config.IO = DefaultIO
 	}
-	return config.IO.OpenTable(config)
+	file, err := config.IO.OpenTable(config)
+	if err != nil {
+		return nil, err
+	}
+	err = file.checkLayout()
+	if err != nil {
+		file.Close()
+		return nil, newError("dbase-io-opentable-3", err)
+	}
+	err = file.requireMemoFile()
+	if err != nil {
+		file.Close()
+		return nil, newError("dbase-io-opentable-5", err)
+	}
+	err = file.checkSharedWrite()
+	if err != nil {
+		file.Close()
+		return nil, newError("dbase-io-opentable-6", err)
+	}
+	file.identify()
+	file.detectDuplicates()
+	file.deletedHidden = config.IgnoreDeleted
+	if config.RowCache != nil {
+		file.rowCache = config.RowCache.share()
+	}
+	if len(config.Reference) > 0 {
+		file.drift = file.compareReference()
+		debugf("Schema drift of %s: %v", config.Filename, file.drift)
+	}
+	if config.RepairEOF && !config.ReadOnly {
+		err = file.RepairEOF()
+		if err != nil {
+			file.Close()
+			return nil, newError("dbase-io-opentable-1", err)
+		}
+	}
+	if config.RecoverRows {
+		err = file.recoverRows()
+		if err != nil {
+			file.Close()
+			return nil, newError("dbase-io-opentable-2", err)
+		}
+	}
+	if config.CheckCompanions {
+		file.companions, err = file.CheckCompanions()
+		if err != nil {
+			file.Close()
+			return nil, newError("dbase-io-opentable-4", err)
+		}
+		for _, issue := range file.companions.Issues {
+			debugf("Companion file of %s: %v", config.Filename, issue)
+		}
+	}
+	return file, nil
 }
 
 // Closes all file handlers.
@@ -78,7 +183,11 @@ func (file *File) WriteHeader() error {
 }
 
 // ReadColumns reads from DBF header, starting at pos 32, until it finds the Header row terminator END_OF_COLUMN(0x0D).
+// If Config.SchemaCache is set, the columns of tables with identical descriptors are taken from the cache.
 func (file *File) ReadColumns() ([]*Column, *Column, error) {
+	if file.config != nil && file.config.SchemaCache != nil {
+		return file.config.SchemaCache.readColumns(file)
+	}
 	return file.defaults().io.ReadColumns(file)
 }
 
@@ -100,23 +209,296 @@ func (file *File) WriteMemoHeader(size int) error {
 
 // Reads raw row data of one row at rowPosition
 func (file *File) ReadRow(position uint32) ([]byte, error) {
-	return file.defaults().io.ReadRow(file, position)
+	start := time.Now()
+	data, err := file.defaults().io.ReadRow(file, position)
+	if err == nil {
+		file.stats.rowsRead.Add(1)
+		file.stats.countRead(len(data), start)
+	}
+	return data, err
 }
 
-// WriteRow writes a raw row data to the given row position
+// WriteRow writes a raw row data to the given row position.
+// Concurrent writes through the same handle are serialized, the hooks run outside of the lock.
 func (file *File) WriteRow(row *Row) error {
-	return file.defaults().io.WriteRow(file, row)
+	return file.writeRow(row, false)
+}
+
+// writeRow writes the row, if appending is set the row is appended after the last row
+func (file *File) writeRow(row *Row, appending bool) error {
+	err := file.runBeforeWrite(row)
+	if err != nil {
+		return newError("dbase-io-writerow-1", err)
+	}
+	// Position and operation of the write, taken under the lock for the hooks
+	var position uint32
+	var appended bool
+	err = func() error {
+		file.writeMutex.Lock()
+		defer file.writeMutex.Unlock()
+		unlock, err := file.lockWrite(row.Position, appending)
+		if err != nil {
+			return newError("dbase-io-writerow-6", err)
+		}
+		defer unlock()
+		// The position is taken under the lock, so concurrently appended rows can not get the same position
+		if appending {
+			row.Position = file.header.RowsCount + 1
+		}
+		err = file.checkRow(row, appending)
+		if err != nil {
+			return newError("dbase-io-writerow-4", err)
+		}
+		err = file.readMemoPrevious(row.Position, row.fields...)
+		if err != nil {
+			return newError("dbase-io-writerow-5", err)
+		}
+		file.rowCache.Invalidate(row.Position)
+		// Rows at or behind the rows count are appended in place of the next row, like the IO implementations do
+		position = row.Position
+		if position >= file.header.RowsCount && position > 0 {
+			position--
+		}
+		tx := file.begin()
+		start := time.Now()
+		err = file.defaults().io.WriteRow(file, row)
+		if err != nil {
+			return newError("dbase-io-writerow-2", tx.rollback(err))
+		}
+		file.stats.rowsWritten.Add(1)
+		file.stats.countWrite(int(file.header.RowLength), start)
+		// Terminate the table after an appended row
+		appended = file.header.RowsCount != tx.rowsCount
+		if appended {
+			err = file.writeEOF()
+			if err != nil {
+				return newError("dbase-io-writerow-3", tx.rollback(err))
+			}
+		}
+		return nil
+	}()
+	if err != nil {
+		return err
+	}
+	file.runAfterWrite(row, position, appended)
+	return nil
+}
+
+// UpdateField encodes the value for the column and writes only the bytes of this field at the given row position.
+// Variable length fields (Varchar, Varbinary) can not be updated this way, as the null flags have to be maintained.
+func (file *File) UpdateField(position uint32, column string, value interface{}) error {
+	field, err := file.NewFieldByName(column, value)
+	if err != nil {
+		return newError("dbase-io-updatefield-1", err)
+	}
+	if field.column.System() {
+		return newError("dbase-io-updatefield-6", fmt.Errorf("%w, column '%s' is maintained by the table", ErrSystemColumn, column))
+	}
+	if field.column.DataType == byte(Varchar) || field.column.DataType == byte(Varbinary) {
+		return newError("dbase-io-updatefield-2", fmt.Errorf("updating variable length column '%s' is not supported, write the complete row instead", column))
+	}
+	file.writeMutex.Lock()
+	if position >= file.header.RowsCount {
+		file.writeMutex.Unlock()
+		return newError("dbase-io-updatefield-3", fmt.Errorf("%w, row %v >= %v", ErrEOF, position, file.header.RowsCount))
+	}
+	if file.config.SharedWrite {
+		unlock, err := file.lockInternal(rowLock(position))
+		if err != nil {
+			file.writeMutex.Unlock()
+			return newError("dbase-io-updatefield-9", err)
+		}
+		defer unlock()
+	}
+	err = file.checkField(position, field)
+	if err != nil {
+		file.writeMutex.Unlock()
+		return newError("dbase-io-updatefield-7", err)
+	}
+	err = file.readMemoPrevious(position, field)
+	if err != nil {
+		file.writeMutex.Unlock()
+		return newError("dbase-io-updatefield-8", err)
+	}
+	file.rowCache.Invalidate(position)
+	if file.lengthAudit != nil {
+		file.lengthAudit.observe(file, field)
+	}
+	tx := file.begin()
+	start := time.Now()
+	err = file.writeField(position, field)
+	if err != nil {
+		err = tx.rollback(err)
+	} else {
+		file.stats.fieldsWritten.Add(1)
+		file.stats.countWrite(int(field.column.Length), start)
+	}
+	file.writeMutex.Unlock()
+	if err != nil {
+		return newError("dbase-io-updatefield-4", err)
+	}
+	if file.afterWriteHooked() {
+		pointer := file.table.rowPointer
+		defer func() {
+			file.table.rowPointer = pointer
+		}()
+		file.table.rowPointer = position
+		row, err := file.Row()
+		if err != nil {
+			return newError("dbase-io-updatefield-5", err)
+		}
+		file.runAfterWrite(row, position, false)
+	}
+	return nil
+}
+
+// writeField writes the field with the FieldWriter of the IO or, if it has none, the complete row with the field replaced
+func (file *File) writeField(position uint32, field *Field) error {
+	if writer, ok := file.defaults().io.(FieldWriter); ok {
+		return writer.WriteField(file, position, field)
+	}
+	data, err := file.io.ReadRow(file, position)
+	if err != nil {
+		return err
+	}
+	pointer := file.table.rowPointer
+	file.table.rowPointer = position
+	row, err := file.bytesToRow(data)
+	file.table.rowPointer = pointer
+	if err != nil {
+		return err
+	}
+	for i, f := range row.fields {
+		if f.column == field.column {
+			row.fields[i] = field
+		}
+	}
+	return file.io.WriteRow(file, row)
+}
+
+// ReadRaw reads length bytes at offset from the DBF file or, if related is true, from the memo file.
+// If less bytes are available the read bytes are returned together with an ErrIncomplete error.
+func (file *File) ReadRaw(related bool, offset int64, length int) ([]byte, error) {
+	start := time.Now()
+	var data []byte
+	var err error
+	if raw, ok := file.defaults().io.(RawIO); ok {
+		data, err = raw.ReadRaw(file, related, offset, length)
+	} else {
+		data, err = file.readRows(related, offset, length)
+	}
+	file.stats.countRead(len(data), start)
+	return data, err
+}
+
+// readRows reads a range of the rows in the DBF file with ReadRow, for IO implementations without RawIO
+func (file *File) readRows(related bool, offset int64, length int) ([]byte, error) {
+	first := int64(file.header.FirstRow)
+	rowLength := int64(file.header.RowLength)
+	end := first + int64(file.header.RowsCount)*rowLength
+	if related || rowLength == 0 || length < 0 || offset < first || offset+int64(length) > end {
+		return nil, newError("dbase-io-readrows-1", fmt.Errorf("%w, %T does not implement RawIO and reads only the rows of the table", ErrUnsupported, file.io))
+	}
+	data := make([]byte, 0, length)
+	for position := (offset - first) / rowLength; len(data) < length; position++ {
+		row, err := file.io.ReadRow(file, uint32(position))
+		if err != nil {
+			return data, newError("dbase-io-readrows-2", err)
+		}
+		start := offset + int64(len(data)) - first - position*rowLength
+		count := int64(length - len(data))
+		if count > int64(len(row))-start {
+			count = int64(len(row)) - start
+		}
+		data = append(data, row[start:start+count]...)
+	}
+	return data, nil
+}
+
+// Size returns the current size in bytes of the DBF file or, if related is true, of the memo file.
+func (file *File) Size(related bool) (int64, error) {
+	raw, ok := file.defaults().io.(RawIO)
+	if !ok {
+		return 0, newError("dbase-io-size-1", fmt.Errorf("%w, %T does not implement RawIO", ErrUnsupported, file.io))
+	}
+	return raw.Size(file, related)
+}
+
+// WriteRaw writes the bytes at offset to the DBF file or, if related is true, to the memo file.
+func (file *File) WriteRaw(related bool, offset int64, data []byte) error {
+	raw, ok := file.defaults().io.(RawIO)
+	if !ok {
+		return newError("dbase-io-writeraw-1", fmt.Errorf("%w, %T does not implement RawIO", ErrUnsupported, file.io))
+	}
+	start := time.Now()
+	err := raw.WriteRaw(file, related, offset, data)
+	if err == nil {
+		file.stats.countWrite(len(data), start)
+	}
+	return err
+}
+
+// Truncate changes the size of the DBF file or, if related is true, the size of the memo file.
+func (file *File) Truncate(related bool, size int64) error {
+	truncater, ok := file.defaults().io.(Truncater)
+	if !ok {
+		return newError("dbase-io-truncate-1", fmt.Errorf("%w, %T does not implement Truncater", ErrUnsupported, file.io))
+	}
+	return truncater.Truncate(file, related, size)
 }
 
 // Reads one or more blocks from the FPT file, called for each memo column.
-// the return value is the raw data and true if the data read is text (false is RAW binary data).
+// the return value is the data and true if the data read is text (false is RAW binary data).
 func (file *File) ReadMemo(address []byte) ([]byte, bool, error) {
-	return file.defaults().io.ReadMemo(file, address)
+	return file.readMemo(address, nil)
 }
 
 // WriteMemo writes a memo to the memo file and returns the address of the memo.
+// With Config.SharedWrite the memo header is locked and the next free block is read again before the memo is written.
 func (file *File) WriteMemo(data []byte, text bool, length int) ([]byte, error) {
-	return file.defaults().io.WriteMemo(file, data, text, length)
+	unlock, err := file.lockMemo()
+	if err != nil {
+		return nil, newError("dbase-io-writememo-1", err)
+	}
+	defer unlock()
+	start := time.Now()
+	address, err := file.defaults().io.WriteMemo(file, data, text, length)
+	if err == nil {
+		file.stats.memosWritten.Add(1)
+		file.stats.countWrite(memoBlockHeader+len(data), start)
+	}
+	return address, err
+}
+
+// readMemo reads the memo at the address and decodes text with the encoding of the column.
+// If the IO does not implement MemoReader, text is decoded by its ReadMemo.
+func (file *File) readMemo(address []byte, column *Column) ([]byte, bool, error) {
+	start := time.Now()
+	reader, ok := file.defaults().io.(MemoReader)
+	if !ok {
+		memo, isText, err := file.defaults().io.ReadMemo(file, address)
+		if err == nil && len(memo) > 0 {
+			file.stats.memosRead.Add(1)
+			file.stats.countRead(memoBlockHeader+len(memo), start)
+		}
+		return memo, isText, err
+	}
+	memo, isText, err := reader.ReadRawMemo(file, address)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(memo) > 0 {
+		file.stats.memosRead.Add(1)
+		file.stats.countRead(memoBlockHeader+len(memo), start)
+	}
+	if !isText {
+		return memo, false, nil
+	}
+	memo, err = file.decodeMemoText(memo, column)
+	if err != nil {
+		return nil, false, newError("dbase-io-readmemo-1", err)
+	}
+	return memo, true, nil
 }
 
 // Read the nullFlag field at the end of the row
@@ -128,9 +510,20 @@ func (file *File) ReadNullFlag(position uint64, column *Column) (bool, bool, err
 	return file.defaults().io.ReadNullFlag(file, position, column)
 }
 
-// Search searches for a row with the given value in the given field
+// Search searches for a row with the given value in the given field.
+// Deleted rows are not returned if they are hidden, see SetDeletedHidden.
 func (file *File) Search(field *Field, exactMatch bool) ([]*Row, error) {
-	return file.defaults().io.Search(file, field, exactMatch)
+	rows, err := file.defaults().io.Search(file, field, exactMatch)
+	if err != nil || !file.deletedHidden {
+		return rows, err
+	}
+	active := rows[:0]
+	for _, row := range rows {
+		if !row.Deleted {
+			active = append(active, row)
+		}
+	}
+	return active, nil
 }
 
 // GoTo sets the internal row pointer to row rowNumber
@@ -183,3 +576,10 @@ func ValidateFileVersion(version byte, untested bool) error {
 		return nil
 	}
 }
+
+// upperFilename returns the path with the file name upper-cased, like the files created by FoxPro.
+// The directories are kept as given, as they are case-sensitive on most file systems.
+func upperFilename(path string) string {
+	dir, name := filepath.Split(path)
+	return dir + strings.ToUpper(name)
+}
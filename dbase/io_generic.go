@@ -3,6 +3,7 @@ package dbase
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"reflect"
@@ -64,9 +65,13 @@ func (g GenericIO) OpenTable(config *Config) (*File, error) {
 	// Check if there is an FPT according to the header.
 	// If there is we will try to open it in the same dir (using the same filename and case).
 	// If the FPT file does not exist an error is returned.
-	if MemoFlag.Defined(file.header.TableFlags) {
+	if MemoFlag.Defined(file.header.TableFlags) && !config.WithoutMemo {
 		if file.relatedHandle == nil {
-			return nil, newError("dbase-io-generic-opentable-6", fmt.Errorf("no related handle defined"))
+			if config.SkipMissingMemo {
+				debugf("No related handle defined, memo columns are skipped")
+				return file, nil
+			}
+			return nil, newError("dbase-io-generic-opentable-6", fmt.Errorf("%w, the table has memo columns but no related handle is defined", ErrMissingMemoFile))
 		}
 		err = file.ReadMemoHeader()
 		if err != nil {
@@ -123,7 +128,7 @@ func (g GenericIO) ReadHeader(file *File) error {
 		return newError("dbase-io-generic-generic-readheader-2", err)
 	}
 	b := make([]byte, 30)
-	n, err := handle.Read(b)
+	n, err := readFull(handle, b)
 	if err != nil {
 		return newError("dbase-io-generic-generic-readheader-3", err)
 	}
@@ -185,7 +190,7 @@ func (g GenericIO) ReadColumns(file *File) ([]*Column, *Column, error) {
 		if _, err := handle.Seek(offset, 0); err != nil {
 			return nil, nil, newError("dbase-io-generic-generic-readcolumns-2", err)
 		}
-		if _, err := handle.Read(b); err != nil {
+		if _, err := readFull(handle, b); err != nil {
 			return nil, nil, newError("dbase-io-generic-generic-readcolumns-3", err)
 		}
 		if Marker(b[0]) == ColumnEnd {
@@ -196,7 +201,7 @@ func (g GenericIO) ReadColumns(file *File) ([]*Column, *Column, error) {
 			return nil, nil, newError("dbase-io-generic-generic-readcolumns-4", err)
 		}
 		buf := make([]byte, 33)
-		n, err := handle.Read(buf)
+		n, err := readFull(handle, buf)
 		if err != nil {
 			return nil, nil, newError("dbase-io-generic-generic-readcolumns-5", err)
 		}
@@ -205,7 +210,7 @@ func (g GenericIO) ReadColumns(file *File) ([]*Column, *Column, error) {
 		if err != nil {
 			return nil, nil, newError("dbase-io-generic-generic-readcolumns-6", err)
 		}
-		if column.Name() == "_NullFlags" {
+		if column.Name() == nullFlagsName {
 			debugf("Found null flag column: %s", column.Name())
 			nullFlag = column
 			offset += 32
@@ -224,6 +229,10 @@ func (g GenericIO) WriteColumns(file *File) error {
 	if err != nil {
 		return newError("dbase-io-generic-writecolumns-1", err)
 	}
+	padding, err := file.headerPadding()
+	if err != nil {
+		return newError("dbase-io-generic-writecolumns-8", err)
+	}
 	// Seek to the beginning of the file
 	_, err = handle.Seek(32, 0)
 	if err != nil {
@@ -255,11 +264,7 @@ func (g GenericIO) WriteColumns(file *File) error {
 		return newError("dbase-io-generic-generic-writecolumns-6", err)
 	}
 	// Write null till the end of the header
-	pos := file.header.FirstRow - uint16(len(file.table.columns)*32) - 33
-	if file.nullFlagColumn != nil {
-		pos -= 32
-	}
-	_, err = handle.Write(make([]byte, pos))
+	_, err = handle.Write(make([]byte, padding))
 	if err != nil {
 		return newError("dbase-io-generic-writecolumns-7", err)
 	}
@@ -277,7 +282,7 @@ func (g GenericIO) ReadMemoHeader(file *File) error {
 		return newError("dbase-io-generic-readmemoheader-2", err)
 	}
 	b := make([]byte, 8)
-	n, err := relatedHandle.Read(b)
+	n, err := readFull(relatedHandle, b)
 	if err != nil {
 		return newError("dbase-io-generic-readmemoheader-3", err)
 	}
@@ -320,30 +325,57 @@ func (g GenericIO) WriteMemoHeader(file *File, size int) error {
 	return nil
 }
 
+// ReadMemo reads the memo at the address and decodes text with the converter of the table
 func (g GenericIO) ReadMemo(file *File, address []byte) ([]byte, bool, error) {
-	relatedHandle, err := g.getRelatedHandle(file)
+	memo, isText, err := g.ReadRawMemo(file, address)
 	if err != nil {
 		return nil, false, newError("dbase-io-generic-readmemo-1", err)
 	}
-	// Determine the block number
-	block := binary.LittleEndian.Uint32(address)
+	if !isText {
+		return memo, false, nil
+	}
+	memo, err = file.config.Converter.Decode(memo)
+	if err != nil {
+		return nil, false, newError("dbase-io-generic-readmemo-2", err)
+	}
+	return memo, true, nil
+}
+
+// ReadRawMemo reads the memo at the address without decoding text
+func (g GenericIO) ReadRawMemo(file *File, address []byte) ([]byte, bool, error) {
+	relatedHandle, err := g.getRelatedHandle(file)
+	if err != nil {
+		return nil, false, newError("dbase-io-generic-readrawmemo-1", err)
+	}
+	// Determine the block number, the position in the file is blocknumber*blocksize
+	block, position, err := file.memoBlock(address)
+	if err != nil {
+		return nil, false, newError("dbase-io-generic-readrawmemo-6", err)
+	}
 	if block == 0 {
 		return []byte{}, false, nil
 	}
-	position := int64(file.memoHeader.BlockSize) * int64(block)
+	size, err := g.Size(file, true)
+	if err != nil {
+		return nil, false, newError("dbase-io-generic-readrawmemo-7", err)
+	}
+	err = checkMemoRange(block, position, memoBlockHeader, size)
+	if err != nil {
+		return nil, false, newError("dbase-io-generic-readrawmemo-8", err)
+	}
 	debugf("Reading memo block %d at position %d", block, position)
 	// The position in the file is blocknumber*blocksize
 	_, err = relatedHandle.Seek(position, 0)
 	if err != nil {
-		return nil, false, newError("dbase-io-generic-readmemo-2", err)
+		return nil, false, newError("dbase-io-generic-readrawmemo-2", err)
 	}
 	// Read the memo block header, instead of reading into a struct using binary.Read we just read the two
 	// uints in one buffer and then convert, this saves seconds for large DBF files with many memo columns
 	// as it avoids using the reflection in binary.Read
 	hbuf := make([]byte, 8)
-	_, err = relatedHandle.Read(hbuf)
+	_, err = readFull(relatedHandle, hbuf)
 	if err != nil {
-		return nil, false, newError("dbase-io-generic-readmemo-3", err)
+		return nil, false, newError("dbase-io-generic-readrawmemo-3", err)
 	}
 	sign := binary.BigEndian.Uint32(hbuf[:4])
 	leng := binary.BigEndian.Uint32(hbuf[4:])
@@ -352,20 +384,18 @@ func (g GenericIO) ReadMemo(file *File, address []byte) ([]byte, bool, error) {
 		// No data according to block header? Not sure if this should be an error instead
 		return []byte{}, sign == 1, nil
 	}
+	err = checkMemoRange(block, position, memoBlockHeader+int64(leng), size)
+	if err != nil {
+		return nil, false, newError("dbase-io-generic-readrawmemo-9", err)
+	}
 	// Now read the actual data
 	buf := make([]byte, leng)
-	read, err := relatedHandle.Read(buf)
+	read, err := readFull(relatedHandle, buf)
 	if err != nil {
-		return buf, false, newError("dbase-io-generic-readmemo-4", err)
+		return buf, false, newError("dbase-io-generic-readrawmemo-4", err)
 	}
 	if read != int(leng) {
-		return buf, sign == 1, newError("dbase-io-generic-readmemo-5", ErrIncomplete)
-	}
-	if sign == 1 {
-		buf, err = file.config.Converter.Decode(buf)
-		if err != nil {
-			return []byte{}, false, newError("dbase-io-generic-readmemo-6", err)
-		}
+		return buf, sign == 1, newError("dbase-io-generic-readrawmemo-5", ErrIncomplete)
 	}
 	return buf, sign == 1, nil
 }
@@ -379,8 +409,9 @@ func (g GenericIO) WriteMemo(file *File, raw []byte, text bool, length int) ([]b
 	}
 	// Get the block position
 	blockPosition := file.memoHeader.NextFree
-	blocks := length / int(file.memoHeader.BlockSize)
-	if length%int(file.memoHeader.BlockSize) > 0 {
+	// The block header and raw data beyond the length, like the trailer of a memo with history, are part of the allocated blocks
+	blocks := (memoBlockHeader + len(raw)) / int(file.memoHeader.BlockSize)
+	if (memoBlockHeader+len(raw))%int(file.memoHeader.BlockSize) > 0 {
 		blocks++
 	}
 	// Write the memo header
@@ -398,8 +429,9 @@ func (g GenericIO) WriteMemo(file *File, raw []byte, text bool, length int) ([]b
 	}
 	// The next 4 bytes are the length of the data
 	binary.BigEndian.PutUint32(data[4:8], uint32(length))
-	// The rest is the data
+	// The rest is the data, padded to the allocated blocks
 	data = append(data, raw...)
+	data = append(data, make([]byte, blocks*int(file.memoHeader.BlockSize)-len(data))...)
 	position := int64(blockPosition) * int64(file.memoHeader.BlockSize)
 	debugf("Writing memo block %d at position %d", blockPosition, position)
 	// Seek to new the next free block
@@ -446,13 +478,16 @@ func (g GenericIO) ReadNullFlag(file *File, position uint64, column *Column) (bo
 		}
 	}
 	// Read the null flag field
-	position = uint64(file.header.FirstRow) + position*uint64(file.header.RowLength) + uint64(file.nullFlagColumn.Position)
-	_, err = handle.Seek(int64(position), 0)
+	offset, err := file.rowOffset(position)
+	if err != nil {
+		return false, false, newError("dbase-io-generic-readnullflag-7", err)
+	}
+	_, err = handle.Seek(offset+int64(file.columnOffset(file.nullFlagColumn)), 0)
 	if err != nil {
 		return false, false, newError("dbase-io-generic-readnullflag-4", err)
 	}
 	buf := make([]byte, file.nullFlagColumn.Length)
-	n, err := handle.Read(buf)
+	n, err := readFull(handle, buf)
 	if err != nil {
 		return false, false, newError("dbase-io-generic-readnullflag-5", err)
 	}
@@ -482,7 +517,7 @@ func (g GenericIO) ReadRow(file *File, position uint32) ([]byte, error) {
 	if err != nil {
 		return buf, newError("dbase-io-generic-readrow-3", err)
 	}
-	read, err := handle.Read(buf)
+	read, err := readFull(handle, buf)
 	if err != nil {
 		return buf, newError("dbase-io-generic-readrow-4", err)
 	}
@@ -505,15 +540,11 @@ func (g GenericIO) WriteRow(file *File, row *Row) error {
 	if err != nil {
 		return newError("dbase-io-generic-writerow-2", err)
 	}
-	// Update the header
 	position := int64(row.handle.header.FirstRow) + (int64(row.Position) * int64(row.handle.header.RowLength))
+	appended := false
 	if row.Position >= row.handle.header.RowsCount {
 		position = int64(row.handle.header.FirstRow) + (int64(row.Position-1) * int64(row.handle.header.RowLength))
-		row.handle.header.RowsCount++
-	}
-	err = row.handle.WriteHeader()
-	if err != nil {
-		return newError("dbase-io-generic-writerow-3", err)
+		appended = true
 	}
 	debugf("Writing row: %d at offset: %v", row.Position, position)
 	// Seek to the correct position
@@ -526,6 +557,152 @@ func (g GenericIO) WriteRow(file *File, row *Row) error {
 	if err != nil {
 		return newError("dbase-io-generic-writerow-5", err)
 	}
+	// Update the header after the row has been written, so the rows count never points to missing data
+	if appended {
+		row.handle.header.RowsCount++
+	}
+	err = row.handle.WriteHeader()
+	if err != nil {
+		return newError("dbase-io-generic-writerow-8", err)
+	}
+	return nil
+}
+
+func (g GenericIO) WriteField(file *File, position uint32, field *Field) error {
+	debugf("Writing field %s of row: %d ...", field.Name(), position)
+	file.dbaseMutex.Lock()
+	defer file.dbaseMutex.Unlock()
+	handle, err := g.getHandle(file)
+	if err != nil {
+		return newError("dbase-io-generic-writefield-1", err)
+	}
+	// Convert the field to raw bytes
+	data, err := file.GetRepresentation(field, false)
+	if err != nil {
+		return newError("dbase-io-generic-writefield-2", err)
+	}
+	if len(data) != int(field.column.Length) {
+		return newError("dbase-io-generic-writefield-3", fmt.Errorf("invalid length %v bytes != %v bytes at column field: %v", len(data), field.column.Length, field.Name()))
+	}
+	offset := int64(file.header.FirstRow) + (int64(position) * int64(file.header.RowLength)) + int64(file.columnOffset(field.column))
+	debugf("Writing field %s of row: %d at offset: %v", field.Name(), position, offset)
+	_, err = handle.Seek(offset, 0)
+	if err != nil {
+		return newError("dbase-io-generic-writefield-4", err)
+	}
+	_, err = handle.Write(data)
+	if err != nil {
+		return newError("dbase-io-generic-writefield-5", err)
+	}
+	return nil
+}
+
+func (g GenericIO) ReadRaw(file *File, related bool, offset int64, length int) ([]byte, error) {
+	var handle io.ReadWriteSeeker
+	var err error
+	if related {
+		handle, err = g.getRelatedHandle(file)
+	} else {
+		handle, err = g.getHandle(file)
+	}
+	if err != nil {
+		return nil, newError("dbase-io-generic-readraw-1", err)
+	}
+	if offset < 0 || length < 0 {
+		return nil, newError("dbase-io-generic-readraw-2", fmt.Errorf("%w, offset %d length %d", ErrInvalidPosition, offset, length))
+	}
+	_, err = handle.Seek(offset, 0)
+	if err != nil {
+		return nil, newError("dbase-io-generic-readraw-3", err)
+	}
+	buf := make([]byte, length)
+	read, err := io.ReadFull(handle, buf)
+	if err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return buf[:read], newError("dbase-io-generic-readraw-4", ErrIncomplete)
+		}
+		return buf[:read], newError("dbase-io-generic-readraw-5", err)
+	}
+	return buf, nil
+}
+
+func (g GenericIO) Size(file *File, related bool) (int64, error) {
+	var handle io.ReadWriteSeeker
+	var err error
+	if related {
+		handle, err = g.getRelatedHandle(file)
+	} else {
+		handle, err = g.getHandle(file)
+	}
+	if err != nil {
+		return 0, newError("dbase-io-generic-size-1", err)
+	}
+	size, err := handle.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, newError("dbase-io-generic-size-2", err)
+	}
+	return size, nil
+}
+
+func (g GenericIO) WriteRaw(file *File, related bool, offset int64, data []byte) error {
+	mutex := file.dbaseMutex
+	if related {
+		mutex = file.memoMutex
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	var handle io.ReadWriteSeeker
+	var err error
+	if related {
+		handle, err = g.getRelatedHandle(file)
+	} else {
+		handle, err = g.getHandle(file)
+	}
+	if err != nil {
+		return newError("dbase-io-generic-writeraw-1", err)
+	}
+	if offset < 0 {
+		return newError("dbase-io-generic-writeraw-2", fmt.Errorf("%w, offset %d", ErrInvalidPosition, offset))
+	}
+	_, err = handle.Seek(offset, 0)
+	if err != nil {
+		return newError("dbase-io-generic-writeraw-3", err)
+	}
+	_, err = handle.Write(data)
+	if err != nil {
+		return newError("dbase-io-generic-writeraw-4", err)
+	}
+	return nil
+}
+
+func (g GenericIO) Truncate(file *File, related bool, size int64) error {
+	mutex := file.dbaseMutex
+	if related {
+		mutex = file.memoMutex
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	var handle io.ReadWriteSeeker
+	var err error
+	if related {
+		handle, err = g.getRelatedHandle(file)
+	} else {
+		handle, err = g.getHandle(file)
+	}
+	if err != nil {
+		return newError("dbase-io-generic-truncate-1", err)
+	}
+	if size < 0 {
+		return newError("dbase-io-generic-truncate-2", fmt.Errorf("%w, size %d", ErrInvalidPosition, size))
+	}
+	truncater, ok := handle.(interface{ Truncate(size int64) error })
+	if !ok {
+		return newError("dbase-io-generic-truncate-3", fmt.Errorf("handle of type %T does not support truncation", handle))
+	}
+	err = truncater.Truncate(size)
+	if err != nil {
+		return newError("dbase-io-generic-truncate-4", err)
+	}
 	return nil
 }
 
@@ -548,7 +725,7 @@ func (g GenericIO) Search(file *File, field *Field, exactMatch bool) ([]*Row, er
 	position := uint64(file.header.FirstRow)
 	for i := uint32(0); i < file.header.RowsCount; i++ {
 		// Read the field value
-		p := int64(position) + int64(field.column.Position)
+		p := int64(position) + int64(file.columnOffset(field.column))
 		debugf("Searching at position: %d", p)
 		_, err := handle.Seek(p, 0)
 		position += uint64(file.header.RowLength)
@@ -556,7 +733,7 @@ func (g GenericIO) Search(file *File, field *Field, exactMatch bool) ([]*Row, er
 			continue
 		}
 		buf := make([]byte, field.column.Length)
-		read, err := handle.Read(buf)
+		read, err := readFull(handle, buf)
 		if err != nil {
 			continue
 		}
@@ -565,7 +742,7 @@ func (g GenericIO) Search(file *File, field *Field, exactMatch bool) ([]*Row, er
 		}
 		// Check if the value matches
 		if bytes.Contains(buf, val) {
-			debugf("Found matching field at position: %d - Record %v position: %v ", p, i+1, p-int64(field.column.Position))
+			debugf("Found matching field at position: %d - Record %v position: %v ", p, i+1, p-int64(file.columnOffset(field.column)))
 			err := file.GoTo(i)
 			if err != nil {
 				continue
@@ -616,7 +793,7 @@ func (g GenericIO) Deleted(file *File) (bool, error) {
 		return false, newError("dbase-io-generic-deleted-3", err)
 	}
 	buf := make([]byte, 1)
-	read, err := handle.Read(buf)
+	read, err := readFull(handle, buf)
 	if err != nil {
 		return false, newError("dbase-io-generic-deleted-4", err)
 	}
@@ -626,6 +803,16 @@ func (g GenericIO) Deleted(file *File) (bool, error) {
 	return Marker(buf[0]) == Deleted, nil
 }
 
+// readFull reads until the buffer is full or the handle ends, as custom handles may return less bytes than requested.
+// Like Read it returns the number of bytes read, io.EOF is only returned if no byte was read.
+func readFull(handle io.Reader, buf []byte) (int, error) {
+	n, err := io.ReadFull(handle, buf)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return n, nil
+	}
+	return n, err
+}
+
 func (g GenericIO) getHandle(file *File) (io.ReadWriteSeeker, error) {
 	handle, ok := file.handle.(io.ReadWriteSeeker)
 	if !ok {
@@ -0,0 +1,172 @@
+package dbase
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// plainIO hides the optional interfaces of the default IO, like IO implementations that only implement IO
+type plainIO struct{ IO }
+
+// TestOptionalIO writes and reads tables with the default IO and with an IO without the optional interfaces
+func TestOptionalIO(t *testing.T) {
+	implementations := []struct {
+		name string
+		io   IO
+	}{
+		{"default", DefaultIO},
+		{"plain", plainIO{DefaultIO}},
+	}
+	for _, implementation := range implementations {
+		t.Run(implementation.name, func(t *testing.T) {
+			name, err := NewColumn("NAME", Character, 10, 0, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			count, err := NewColumn("COUNT", Integer, 4, 0, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			file, err := CreateTable(&Config{Filename: filepath.Join(t.TempDir(), "OPTIONAL.DBF"), IO: implementation.io}, name, count)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+			if _, ok := file.io.(FieldWriter); ok != (implementation.io == DefaultIO) {
+				t.Fatalf("table uses %T instead of the configured IO", file.io)
+			}
+			for i, n := range []string{"first", "second"} {
+				row := file.NewRow()
+				err = row.FieldByName("NAME").SetValue(n)
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = row.FieldByName("COUNT").SetValue(int32(i + 1))
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = row.Add()
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err = file.UpdateField(1, "NAME", "updated")
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = file.GoTo(1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			row, err := file.Row()
+			if err != nil {
+				t.Fatal(err)
+			}
+			values, err := row.ToMap()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.TrimSpace(values["NAME"].(string)) != "updated" || values["COUNT"] != int32(2) {
+				t.Errorf("expected the updated name and the unchanged count, got %v", values)
+			}
+
+			// Ranges of the rows are read with ReadRow without RawIO, the size is unknown then
+			header := file.Header()
+			raw, err := file.ReadRaw(false, int64(header.FirstRow)+int64(header.RowLength)-1, int(header.RowLength)+1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			second, err := file.ReadRow(1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(raw[1:], second) {
+				t.Errorf("read %q across the rows, expected the last byte of the first row and %q", raw, second)
+			}
+			_, err = file.Size(false)
+			if _, ok := file.io.(RawIO); ok && err != nil {
+				t.Error(err)
+			} else if !ok && !errors.Is(err, ErrUnsupported) {
+				t.Errorf("expected ErrUnsupported for the size without RawIO, got %v", err)
+			}
+
+			// Packing shortens the table, without Truncater the table is left as it is
+			err = file.GoTo(0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			first, err := file.Row()
+			if err != nil {
+				t.Fatal(err)
+			}
+			first.Deleted = true
+			err = first.Write()
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = file.Pack(nil)
+			if _, ok := file.io.(Truncater); ok && err != nil {
+				t.Error(err)
+			} else if !ok && !errors.Is(err, ErrUnsupported) {
+				t.Errorf("expected ErrUnsupported for packing without Truncater, got %v", err)
+			}
+			if _, ok := file.io.(Truncater); ok == (file.RowsCount() != 1) {
+				t.Errorf("%d rows after packing with %T", file.RowsCount(), file.io)
+			}
+		})
+	}
+}
+
+// TestReadMemo reads decoded memo text from IO.ReadMemo and from tables with an IO without MemoReader
+func TestReadMemo(t *testing.T) {
+	for _, implementation := range []IO{DefaultIO, plainIO{DefaultIO}} {
+		t.Run(fmt.Sprintf("%T", implementation), func(t *testing.T) {
+			note, err := NewColumn("NOTE", Memo, 0, 0, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			file, err := CreateTable(&Config{Filename: filepath.Join(t.TempDir(), "MEMO.DBF"), Converter: NewDefaultConverter(charmap.Windows1252), IO: implementation}, note)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+			row := file.NewRow()
+			err = row.FieldByName("NOTE").SetValue("café")
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = row.Add()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := file.ReadRow(0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			column := file.Column(0)
+			address := data[column.Position : column.Position+uint32(column.Length)]
+			memo, isText, err := implementation.ReadMemo(file, address)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !isText || string(memo) != "café" {
+				t.Errorf("IO.ReadMemo returned %q, expected the decoded text", memo)
+			}
+			row, err = file.Row()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if value := row.FieldByName("NOTE").GetValue(); value != "café" {
+				t.Errorf("read memo %q, expected the decoded text", value)
+			}
+		})
+	}
+}
@@ -6,7 +6,9 @@ package dbase
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"path/filepath"
@@ -18,6 +20,15 @@ import (
 
 var DefaultIO UnixIO
 
+// defaultIO returns true for the default IO implementation, which opens the files by the path of the configuration
+func defaultIO(io IO) bool {
+	switch io.(type) {
+	case nil, UnixIO, *UnixIO:
+		return true
+	}
+	return false
+}
+
 // UnixIO implements the IO interface for unix systems.
 type UnixIO struct{}
 
@@ -86,7 +97,7 @@ func (u UnixIO) OpenTable(config *Config) (*File, error) {
 	// Check if there is an FPT according to the header.
 	// If there is we will try to open it in the same dir (using the same filename and case).
 	// If the FPT file does not exist an error is returned.
-	if MemoFlag.Defined(file.header.TableFlags) {
+	if MemoFlag.Defined(file.header.TableFlags) && !config.WithoutMemo {
 		ext := FPT
 		if fileExtension == DBC {
 			ext = DCT
@@ -94,6 +105,13 @@ func (u UnixIO) OpenTable(config *Config) (*File, error) {
 		relatedFile := strings.TrimSuffix(fileName, path.Ext(fileName)) + string(ext)
 		debugf("Opening related file: %s\n", relatedFile)
 		relatedHandle, err := os.OpenFile(relatedFile, mode, 0600)
+		if errors.Is(err, os.ErrNotExist) {
+			if config.SkipMissingMemo {
+				debugf("Memo file %s is missing, memo columns are skipped", relatedFile)
+				return file, nil
+			}
+			return nil, newError("dbase-io-unix-opentable-9", fmt.Errorf("%w, the table has memo columns but the memo file %v is missing", ErrMissingMemoFile, relatedFile))
+		}
 		if err != nil {
 			return nil, newError("dbase-io-unix-opentable-7", fmt.Errorf("opening FPT file failed with error: %w", err))
 		}
@@ -135,7 +153,7 @@ func (u UnixIO) Close(file *File) error {
 }
 
 func (u UnixIO) Create(file *File) error {
-	file.config.Filename = strings.ToUpper(strings.TrimSpace(file.config.Filename))
+	file.config.Filename = upperFilename(strings.TrimSpace(file.config.Filename))
 	// Check for valid file name
 	if len(file.config.Filename) == 0 {
 		return newError("dbase-io-unix-create-1", fmt.Errorf("missing filename"))
@@ -150,7 +168,7 @@ func (u UnixIO) Create(file *File) error {
 	}
 	// Create the file
 	debugf("Creating file: %s", file.config.Filename)
-	handle, err := os.Create(strings.ToUpper(file.config.Filename))
+	handle, err := os.Create(file.config.Filename)
 	if err != nil {
 		return newError("dbase-io-unix-create-4", fmt.Errorf("creating DBF file failed with error: %w", err))
 	}
@@ -255,7 +273,7 @@ func (u UnixIO) ReadColumns(file *File) ([]*Column, *Column, error) {
 		if err != nil {
 			return nil, nil, newError("dbase-io-unix-readcolumninfos-5", err)
 		}
-		if column.Name() == "_NullFlags" {
+		if column.Name() == nullFlagsName {
 			debugf("Found null flag column: %s", column.Name())
 			nullFlag = column
 			offset += 32
@@ -274,6 +292,10 @@ func (u UnixIO) WriteColumns(file *File) (err error) {
 	if err != nil {
 		return newError("dbase-io-unix-writecolumns-1", err)
 	}
+	padding, err := file.headerPadding()
+	if err != nil {
+		return newError("dbase-io-unix-writecolumns-9", err)
+	}
 	// Seek to the beginning of the file
 	_, err = handle.Seek(32, 0)
 	if err != nil {
@@ -305,11 +327,7 @@ func (u UnixIO) WriteColumns(file *File) (err error) {
 		return newError("dbase-io-unix-writecolumns-7", err)
 	}
 	// Write null till the end of the header
-	pos := file.header.FirstRow - uint16(len(file.table.columns)*32) - 33
-	if file.nullFlagColumn != nil {
-		pos -= 32
-	}
-	_, err = handle.Write(make([]byte, pos))
+	_, err = handle.Write(make([]byte, padding))
 	if err != nil {
 		return newError("dbase-io-unix-writecolumns-8", err)
 	}
@@ -342,8 +360,11 @@ func (u UnixIO) ReadNullFlag(file *File, rowPosition uint64, column *Column) (bo
 		}
 	}
 	// Read the null flag field
-	position := uint64(file.header.FirstRow) + rowPosition*uint64(file.header.RowLength) + uint64(file.nullFlagColumn.Position)
-	_, err = handle.Seek(int64(position), 0)
+	position, err := file.rowOffset(rowPosition)
+	if err != nil {
+		return false, false, newError("dbase-io-unix-readnullflag-4", err)
+	}
+	_, err = handle.Seek(position+int64(file.columnOffset(file.nullFlagColumn)), 0)
 	if err != nil {
 		return false, false, newError("dbase-io-unix-readnullflag-1", err)
 	}
@@ -390,19 +411,48 @@ func (u UnixIO) ReadMemoHeader(file *File) error {
 	return nil
 }
 
+// ReadMemo reads the memo at the address and decodes text with the converter of the table
 func (u UnixIO) ReadMemo(file *File, blockdata []byte) ([]byte, bool, error) {
-	relatedHandle, err := u.getRelatedHandle(file)
+	memo, isText, err := u.ReadRawMemo(file, blockdata)
 	if err != nil {
 		return nil, false, newError("dbase-io-unix-readmemo-1", err)
 	}
-	// Determine the block number
-	block := binary.LittleEndian.Uint32(blockdata)
-	// The position in the file is blocknumber*blocksize
-	position := int64(file.memoHeader.BlockSize) * int64(block)
+	if !isText {
+		return memo, false, nil
+	}
+	memo, err = file.config.Converter.Decode(memo)
+	if err != nil {
+		return nil, false, newError("dbase-io-unix-readmemo-2", err)
+	}
+	return memo, true, nil
+}
+
+// ReadRawMemo reads the memo at the address without decoding text
+func (u UnixIO) ReadRawMemo(file *File, blockdata []byte) ([]byte, bool, error) {
+	relatedHandle, err := u.getRelatedHandle(file)
+	if err != nil {
+		return nil, false, newError("dbase-io-unix-readrawmemo-1", err)
+	}
+	// Determine the block number, the position in the file is blocknumber*blocksize
+	block, position, err := file.memoBlock(blockdata)
+	if err != nil {
+		return nil, false, newError("dbase-io-unix-readrawmemo-6", err)
+	}
+	if block == 0 {
+		return []byte{}, false, nil
+	}
+	size, err := u.Size(file, true)
+	if err != nil {
+		return nil, false, newError("dbase-io-unix-readrawmemo-7", err)
+	}
+	err = checkMemoRange(block, position, memoBlockHeader, size)
+	if err != nil {
+		return nil, false, newError("dbase-io-unix-readrawmemo-8", err)
+	}
 	debugf("Reading memo block %d at position %d", block, position)
 	_, err = relatedHandle.Seek(position, 0)
 	if err != nil {
-		return nil, false, newError("dbase-io-unix-readmemo-2", err)
+		return nil, false, newError("dbase-io-unix-readrawmemo-2", err)
 	}
 	// Read the memo block header, instead of reading into a struct using binary.Read we just read the two
 	// uints in one buffer and then convert, this saves seconds for large DBF files with many memo columns
@@ -410,7 +460,7 @@ func (u UnixIO) ReadMemo(file *File, blockdata []byte) ([]byte, bool, error) {
 	hbuf := make([]byte, 8)
 	_, err = relatedHandle.Read(hbuf)
 	if err != nil {
-		return nil, false, newError("dbase-io-unix-readmemo-3", err)
+		return nil, false, newError("dbase-io-unix-readrawmemo-3", err)
 	}
 	sign := binary.BigEndian.Uint32(hbuf[:4])
 	leng := binary.BigEndian.Uint32(hbuf[4:])
@@ -419,20 +469,18 @@ func (u UnixIO) ReadMemo(file *File, blockdata []byte) ([]byte, bool, error) {
 		// No data according to block header? Not sure if this should be an error instead
 		return []byte{}, sign == 1, nil
 	}
+	err = checkMemoRange(block, position, memoBlockHeader+int64(leng), size)
+	if err != nil {
+		return nil, false, newError("dbase-io-unix-readrawmemo-9", err)
+	}
 	// Now read the actual data
 	buf := make([]byte, leng)
 	read, err := relatedHandle.Read(buf)
 	if err != nil {
-		return buf, false, newError("dbase-io-unix-readmemo-4", err)
+		return buf, false, newError("dbase-io-unix-readrawmemo-4", err)
 	}
 	if read != int(leng) {
-		return buf, sign == 1, newError("dbase-io-unix-readmemo-5", ErrIncomplete)
-	}
-	if sign == 1 {
-		buf, err = file.config.Converter.Decode(buf)
-		if err != nil {
-			return []byte{}, false, newError("dbase-io-unix-readmemo-6", err)
-		}
+		return buf, sign == 1, newError("dbase-io-unix-readrawmemo-5", ErrIncomplete)
 	}
 	return buf, sign == 1, nil
 }
@@ -446,8 +494,9 @@ func (u UnixIO) WriteMemo(file *File, raw []byte, text bool, length int) ([]byte
 	}
 	// Get the block position
 	blockPosition := file.memoHeader.NextFree
-	blocks := length / int(file.memoHeader.BlockSize)
-	if length%int(file.memoHeader.BlockSize) > 0 {
+	// The block header and raw data beyond the length, like the trailer of a memo with history, are part of the allocated blocks
+	blocks := (memoBlockHeader + len(raw)) / int(file.memoHeader.BlockSize)
+	if (memoBlockHeader+len(raw))%int(file.memoHeader.BlockSize) > 0 {
 		blocks++
 	}
 	// Write the memo header
@@ -465,8 +514,9 @@ func (u UnixIO) WriteMemo(file *File, raw []byte, text bool, length int) ([]byte
 	}
 	// The next 4 bytes are the length of the data
 	binary.BigEndian.PutUint32(data[4:8], uint32(length))
-	// The rest is the data
+	// The rest is the data, padded to the allocated blocks
 	data = append(data, raw...)
+	data = append(data, make([]byte, blocks*int(file.memoHeader.BlockSize)-len(data))...)
 	position := int64(blockPosition) * int64(file.memoHeader.BlockSize)
 	debugf("Writing memo block %d at position %d", blockPosition, position)
 	// Seek to new the next free block
@@ -555,15 +605,11 @@ func (u UnixIO) WriteRow(file *File, row *Row) (err error) {
 	if err != nil {
 		return newError("dbase-io-unix-writerow-2", err)
 	}
-	// Update the header
 	position := int64(row.handle.header.FirstRow) + (int64(row.Position) * int64(row.handle.header.RowLength))
+	appended := false
 	if row.Position >= row.handle.header.RowsCount {
 		position = int64(row.handle.header.FirstRow) + (int64(row.Position-1) * int64(row.handle.header.RowLength))
-		row.handle.header.RowsCount++
-	}
-	err = row.handle.WriteHeader()
-	if err != nil {
-		return newError("dbase-io-unix-writerow-3", err)
+		appended = true
 	}
 	debugf("Writing row: %d at offset: %v", row.Position, position)
 	// Seek to the correct position
@@ -576,6 +622,148 @@ func (u UnixIO) WriteRow(file *File, row *Row) (err error) {
 	if err != nil {
 		return newError("dbase-io-unix-writerow-7", err)
 	}
+	// Update the header after the row has been written, so the rows count never points to missing data
+	if appended {
+		row.handle.header.RowsCount++
+	}
+	err = row.handle.WriteHeader()
+	if err != nil {
+		return newError("dbase-io-unix-writerow-8", err)
+	}
+	return nil
+}
+
+func (u UnixIO) WriteField(file *File, position uint32, field *Field) error {
+	debugf("Writing field %s of row: %d ...", field.Name(), position)
+	file.dbaseMutex.Lock()
+	defer file.dbaseMutex.Unlock()
+	handle, err := u.getHandle(file)
+	if err != nil {
+		return newError("dbase-io-unix-writefield-1", err)
+	}
+	// Convert the field to raw bytes
+	data, err := file.GetRepresentation(field, false)
+	if err != nil {
+		return newError("dbase-io-unix-writefield-2", err)
+	}
+	if len(data) != int(field.column.Length) {
+		return newError("dbase-io-unix-writefield-3", fmt.Errorf("invalid length %v bytes != %v bytes at column field: %v", len(data), field.column.Length, field.Name()))
+	}
+	offset := int64(file.header.FirstRow) + (int64(position) * int64(file.header.RowLength)) + int64(file.columnOffset(field.column))
+	debugf("Writing field %s of row: %d at offset: %v", field.Name(), position, offset)
+	_, err = handle.Seek(offset, 0)
+	if err != nil {
+		return newError("dbase-io-unix-writefield-4", err)
+	}
+	_, err = handle.Write(data)
+	if err != nil {
+		return newError("dbase-io-unix-writefield-5", err)
+	}
+	return nil
+}
+
+func (u UnixIO) ReadRaw(file *File, related bool, offset int64, length int) ([]byte, error) {
+	var handle *os.File
+	var err error
+	if related {
+		handle, err = u.getRelatedHandle(file)
+	} else {
+		handle, err = u.getHandle(file)
+	}
+	if err != nil {
+		return nil, newError("dbase-io-unix-readraw-1", err)
+	}
+	if offset < 0 || length < 0 {
+		return nil, newError("dbase-io-unix-readraw-2", fmt.Errorf("%w, offset %d length %d", ErrInvalidPosition, offset, length))
+	}
+	_, err = handle.Seek(offset, 0)
+	if err != nil {
+		return nil, newError("dbase-io-unix-readraw-3", err)
+	}
+	buf := make([]byte, length)
+	read, err := io.ReadFull(handle, buf)
+	if err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return buf[:read], newError("dbase-io-unix-readraw-4", ErrIncomplete)
+		}
+		return buf[:read], newError("dbase-io-unix-readraw-5", err)
+	}
+	return buf, nil
+}
+
+func (u UnixIO) Size(file *File, related bool) (int64, error) {
+	var handle *os.File
+	var err error
+	if related {
+		handle, err = u.getRelatedHandle(file)
+	} else {
+		handle, err = u.getHandle(file)
+	}
+	if err != nil {
+		return 0, newError("dbase-io-unix-size-1", err)
+	}
+	size, err := handle.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, newError("dbase-io-unix-size-2", err)
+	}
+	return size, nil
+}
+
+func (u UnixIO) WriteRaw(file *File, related bool, offset int64, data []byte) error {
+	mutex := file.dbaseMutex
+	if related {
+		mutex = file.memoMutex
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	var handle *os.File
+	var err error
+	if related {
+		handle, err = u.getRelatedHandle(file)
+	} else {
+		handle, err = u.getHandle(file)
+	}
+	if err != nil {
+		return newError("dbase-io-unix-writeraw-1", err)
+	}
+	if offset < 0 {
+		return newError("dbase-io-unix-writeraw-2", fmt.Errorf("%w, offset %d", ErrInvalidPosition, offset))
+	}
+	_, err = handle.Seek(offset, 0)
+	if err != nil {
+		return newError("dbase-io-unix-writeraw-3", err)
+	}
+	_, err = handle.Write(data)
+	if err != nil {
+		return newError("dbase-io-unix-writeraw-4", err)
+	}
+	return nil
+}
+
+func (u UnixIO) Truncate(file *File, related bool, size int64) error {
+	mutex := file.dbaseMutex
+	if related {
+		mutex = file.memoMutex
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	var handle *os.File
+	var err error
+	if related {
+		handle, err = u.getRelatedHandle(file)
+	} else {
+		handle, err = u.getHandle(file)
+	}
+	if err != nil {
+		return newError("dbase-io-unix-truncate-1", err)
+	}
+	if size < 0 {
+		return newError("dbase-io-unix-truncate-2", fmt.Errorf("%w, size %d", ErrInvalidPosition, size))
+	}
+	err = handle.Truncate(size)
+	if err != nil {
+		return newError("dbase-io-unix-truncate-3", err)
+	}
 	return nil
 }
 
@@ -598,7 +786,7 @@ func (u UnixIO) Search(file *File, field *Field, exactMatch bool) ([]*Row, error
 	position := uint64(file.header.FirstRow)
 	for i := uint32(0); i < file.header.RowsCount; i++ {
 		// Read the field value
-		p := int64(position) + int64(field.column.Position)
+		p := int64(position) + int64(file.columnOffset(field.column))
 		debugf("Searching at position: %d", p)
 		_, err := handle.Seek(p, 0)
 		position += uint64(file.header.RowLength)
@@ -615,7 +803,7 @@ func (u UnixIO) Search(file *File, field *Field, exactMatch bool) ([]*Row, error
 		}
 		// Check if the value matches
 		if bytes.Contains(buf, val) {
-			debugf("Found matching row %v at position: %d", i, p-int64(field.column.Position))
+			debugf("Found matching row %v at position: %d", i, p-int64(file.columnOffset(field.column)))
 			err := file.GoTo(i)
 			if err != nil {
 				continue
@@ -712,3 +900,11 @@ func (u UnixIO) getRelatedHandle(file *File) (*os.File, error) {
 	}
 	return handle, nil
 }
+
+// flushTable writes the buffered data of the table file to disk, if the handle supports it
+func flushTable(file *File) error {
+	if syncer, ok := file.handle.(interface{ Sync() error }); ok {
+		return syncer.Sync()
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ package dbase
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"os"
 	"path"
@@ -20,6 +21,15 @@ import (
 
 var DefaultIO WindowsIO
 
+// defaultIO returns true for the default IO implementation, which opens the files by the path of the configuration
+func defaultIO(io IO) bool {
+	switch io.(type) {
+	case nil, WindowsIO, *WindowsIO:
+		return true
+	}
+	return false
+}
+
 // WindowsIO implements the IO interface for Windows systems.
 type WindowsIO struct{}
 
@@ -88,7 +98,7 @@ func (w WindowsIO) OpenTable(config *Config) (*File, error) {
 	// Check if there is an FPT according to the header.
 	// If there is we will try to open it in the same dir (using the same filename and case).
 	// If the FPT file does not exist an error is returned.
-	if MemoFlag.Defined(file.header.TableFlags) {
+	if MemoFlag.Defined(file.header.TableFlags) && !config.WithoutMemo {
 		ext := FPT
 		if fileExtension == DBC {
 			ext = DCT
@@ -96,6 +106,13 @@ func (w WindowsIO) OpenTable(config *Config) (*File, error) {
 		relatedFile := strings.TrimSuffix(fileName, path.Ext(fileName)) + string(ext)
 		debugf("Opening related file: %s\n", relatedFile)
 		relatedFD, err := windows.Open(relatedFile, mode, 0644)
+		if errors.Is(err, os.ErrNotExist) {
+			if config.SkipMissingMemo {
+				debugf("Memo file %s is missing, memo columns are skipped", relatedFile)
+				return file, nil
+			}
+			return nil, newError("dbase-io-windows-opentable-9", fmt.Errorf("%w, the table has memo columns but the memo file %v is missing", ErrMissingMemoFile, relatedFile))
+		}
 		if err != nil {
 			return nil, newError("dbase-io-windows-opentable-7", fmt.Errorf("opening related file %v failed with error: %w", relatedFile, err))
 		}
@@ -137,7 +154,7 @@ func (w WindowsIO) Close(file *File) error {
 }
 
 func (w WindowsIO) Create(file *File) error {
-	file.config.Filename = strings.ToUpper(strings.TrimSpace(file.config.Filename))
+	file.config.Filename = upperFilename(strings.TrimSpace(file.config.Filename))
 	// Check for valid file name
 	if len(file.config.Filename) == 0 {
 		return newError("dbase-io-windows-create-1", fmt.Errorf("missing filename"))
@@ -281,7 +298,7 @@ func (w WindowsIO) ReadColumns(file *File) ([]*Column, *Column, error) {
 		if err != nil {
 			return nil, nil, newError("dbase-io-windows-readcolumns-5", err)
 		}
-		if column.Name() == "_NullFlags" {
+		if column.Name() == nullFlagsName {
 			debugf("Found null flag column: %s", column.Name())
 			nullFlag = column
 			offset += 32
@@ -300,6 +317,10 @@ func (w WindowsIO) WriteColumns(file *File) (err error) {
 	if err != nil {
 		return newError("dbase-io-windows-writecolumns-1", err)
 	}
+	padding, err := file.headerPadding()
+	if err != nil {
+		return newError("dbase-io-windows-writecolumns-10", err)
+	}
 	// Lock the block we are writing to
 	position := uint32(32)
 	o := &windows.Overlapped{
@@ -350,11 +371,7 @@ func (w WindowsIO) WriteColumns(file *File) (err error) {
 		return newError("dbase-io-windows-writecolumns-8", err)
 	}
 	// Write null till the end of the header
-	pos := file.header.FirstRow - uint16(len(file.table.columns)*32) - 33
-	if file.nullFlagColumn != nil {
-		pos -= 32
-	}
-	_, err = windows.Write(*handle, make([]byte, pos))
+	_, err = windows.Write(*handle, make([]byte, padding))
 	if err != nil {
 		return newError("dbase-io-windows-writecolumns-9", err)
 	}
@@ -387,8 +404,11 @@ func (w WindowsIO) ReadNullFlag(file *File, position uint64, column *Column) (bo
 		}
 	}
 	// Read the null flag field
-	pos := uint64(file.header.FirstRow) + position*uint64(file.header.RowLength) + uint64(file.nullFlagColumn.Position)
-	_, err = windows.Seek(*handle, int64(pos), 0)
+	pos, err := file.rowOffset(position)
+	if err != nil {
+		return false, false, newError("dbase-io-windows-readnullflag-4", err)
+	}
+	_, err = windows.Seek(*handle, pos+int64(file.columnOffset(file.nullFlagColumn)), 0)
 	if err != nil {
 		return false, false, newError("dbase-io-windows-readnullflag-1", err)
 	}
@@ -433,25 +453,52 @@ func (w WindowsIO) ReadMemoHeader(file *File) error {
 	return nil
 }
 
+// ReadMemo reads the memo at the address and decodes text with the converter of the table
 func (w WindowsIO) ReadMemo(file *File, address []byte) ([]byte, bool, error) {
+	memo, isText, err := w.ReadRawMemo(file, address)
+	if err != nil {
+		return nil, false, newError("dbase-io-windows-readmemo-1", err)
+	}
+	if !isText {
+		return memo, false, nil
+	}
+	memo, err = file.config.Converter.Decode(memo)
+	if err != nil {
+		return nil, false, newError("dbase-io-windows-readmemo-2", err)
+	}
+	return memo, true, nil
+}
+
+// ReadRawMemo reads the memo at the address without decoding text
+func (w WindowsIO) ReadRawMemo(file *File, address []byte) ([]byte, bool, error) {
 	if file.relatedHandle == nil {
-		return nil, false, newError("dbase-io-windows-readmemo-1", ErrNoFPT)
+		return nil, false, newError("dbase-io-windows-readrawmemo-1", ErrNoFPT)
 	}
 	relatedHandle, err := w.getRelatedHandle(file)
 	if err != nil {
-		return nil, false, newError("dbase-io-windows-readmemo-2", err)
+		return nil, false, newError("dbase-io-windows-readrawmemo-2", err)
+	}
+	// Determine the block number, the position in the file is blocknumber*blocksize
+	block, position, err := file.memoBlock(address)
+	if err != nil {
+		return nil, false, newError("dbase-io-windows-readrawmemo-7", err)
 	}
-	// Determine the block number
-	block := binary.LittleEndian.Uint32(address)
 	if block == 0 {
 		return []byte{}, false, nil
 	}
-	position := int64(file.memoHeader.BlockSize) * int64(block)
+	size, err := w.Size(file, true)
+	if err != nil {
+		return nil, false, newError("dbase-io-windows-readrawmemo-8", err)
+	}
+	err = checkMemoRange(block, position, memoBlockHeader, size)
+	if err != nil {
+		return nil, false, newError("dbase-io-windows-readrawmemo-9", err)
+	}
 	debugf("Reading memo block %d at position %d", block, position)
 	// The position in the file is blocknumber*blocksize
 	_, err = windows.Seek(*relatedHandle, position, 0)
 	if err != nil {
-		return nil, false, newError("dbase-io-windows-readmemo-3", err)
+		return nil, false, newError("dbase-io-windows-readrawmemo-3", err)
 	}
 	// Read the memo block header, instead of reading into a struct using binary.Read we just read the two
 	// uints in one buffer and then convert, this saves seconds for large DBF files with many memo columns
@@ -459,7 +506,7 @@ func (w WindowsIO) ReadMemo(file *File, address []byte) ([]byte, bool, error) {
 	hbuf := make([]byte, 8)
 	_, err = windows.Read(*relatedHandle, hbuf)
 	if err != nil {
-		return nil, false, newError("dbase-io-windows-readmemo-4", err)
+		return nil, false, newError("dbase-io-windows-readrawmemo-4", err)
 	}
 	sign := binary.BigEndian.Uint32(hbuf[:4])
 	leng := binary.BigEndian.Uint32(hbuf[4:])
@@ -468,20 +515,18 @@ func (w WindowsIO) ReadMemo(file *File, address []byte) ([]byte, bool, error) {
 		// No data according to block header? Not sure if this should be an error instead
 		return []byte{}, sign == 1, nil
 	}
+	err = checkMemoRange(block, position, memoBlockHeader+int64(leng), size)
+	if err != nil {
+		return nil, false, newError("dbase-io-windows-readrawmemo-10", err)
+	}
 	// Now read the actual data
 	buf := make([]byte, leng)
 	read, err := windows.Read(*relatedHandle, buf)
 	if err != nil {
-		return buf, false, newError("dbase-io-windows-readmemo-5", err)
+		return buf, false, newError("dbase-io-windows-readrawmemo-5", err)
 	}
 	if read != int(leng) {
-		return buf, sign == 1, newError("dbase-io-windows-readmemo-6", ErrIncomplete)
-	}
-	if sign == 1 {
-		buf, err = file.config.Converter.Decode(buf)
-		if err != nil {
-			return []byte{}, false, newError("dbase-io-windows-readmemo-7", err)
-		}
+		return buf, sign == 1, newError("dbase-io-windows-readrawmemo-6", ErrIncomplete)
 	}
 	return buf, sign == 1, nil
 }
@@ -495,9 +540,10 @@ func (w WindowsIO) WriteMemo(file *File, raw []byte, text bool, length int) ([]b
 	}
 	blocks := 1
 	blockPosition := file.memoHeader.NextFree
-	if length > 0 && file.memoHeader.BlockSize > 0 {
-		blocks = length / int(file.memoHeader.BlockSize)
-		if length%int(file.memoHeader.BlockSize) > 0 {
+	// The block header and raw data beyond the length, like the trailer of a memo with history, are part of the allocated blocks
+	if file.memoHeader.BlockSize > 0 {
+		blocks = (memoBlockHeader + len(raw)) / int(file.memoHeader.BlockSize)
+		if (memoBlockHeader+len(raw))%int(file.memoHeader.BlockSize) > 0 {
 			blocks++
 		}
 	}
@@ -516,8 +562,11 @@ func (w WindowsIO) WriteMemo(file *File, raw []byte, text bool, length int) ([]b
 	}
 	// The next 4 bytes are the length of the data
 	binary.BigEndian.PutUint32(data[4:8], uint32(length))
-	// The rest is the data
+	// The rest is the data, padded to the allocated blocks
 	data = append(data, raw...)
+	if file.memoHeader.BlockSize > 0 {
+		data = append(data, make([]byte, blocks*int(file.memoHeader.BlockSize)-len(data))...)
+	}
 	// Lock the block we are writing to
 	if file.config.WriteLock {
 		o := &windows.Overlapped{
@@ -641,15 +690,11 @@ func (w WindowsIO) WriteRow(file *File, row *Row) (err error) {
 	if err != nil {
 		return newError("dbase-io-windows-writerow-2", err)
 	}
-	// Update the header
 	position := int64(row.handle.header.FirstRow) + (int64(row.Position) * int64(row.handle.header.RowLength))
+	appended := false
 	if row.Position >= row.handle.header.RowsCount {
 		position = int64(row.handle.header.FirstRow) + (int64(row.Position-1) * int64(row.handle.header.RowLength))
-		row.handle.header.RowsCount++
-	}
-	err = row.handle.WriteHeader()
-	if err != nil {
-		return newError("dbase-io-windows-writerow-3", err)
+		appended = true
 	}
 	// Lock the block we are writing to
 	if row.handle.config.WriteLock {
@@ -679,6 +724,173 @@ func (w WindowsIO) WriteRow(file *File, row *Row) (err error) {
 	if err != nil {
 		return newError("dbase-io-windows-writerow-7", err)
 	}
+	// Update the header after the row has been written, so the rows count never points to missing data
+	if appended {
+		row.handle.header.RowsCount++
+	}
+	err = row.handle.WriteHeader()
+	if err != nil {
+		return newError("dbase-io-windows-writerow-8", err)
+	}
+	return nil
+}
+
+func (w WindowsIO) WriteField(file *File, position uint32, field *Field) (err error) {
+	debugf("Writing field %s of row: %d ...", field.Name(), position)
+	file.dbaseMutex.Lock()
+	defer file.dbaseMutex.Unlock()
+	handle, err := w.getHandle(file)
+	if err != nil {
+		return newError("dbase-io-windows-writefield-1", err)
+	}
+	// Convert the field to raw bytes
+	data, err := file.GetRepresentation(field, false)
+	if err != nil {
+		return newError("dbase-io-windows-writefield-2", err)
+	}
+	if len(data) != int(field.column.Length) {
+		return newError("dbase-io-windows-writefield-3", fmt.Errorf("invalid length %v bytes != %v bytes at column field: %v", len(data), field.column.Length, field.Name()))
+	}
+	offset := int64(file.header.FirstRow) + (int64(position) * int64(file.header.RowLength)) + int64(file.columnOffset(field.column))
+	// Lock the bytes we are writing to
+	if file.config.WriteLock {
+		o := &windows.Overlapped{
+			Offset:     uint32(offset),
+			OffsetHigh: uint32(offset + int64(field.column.Length)),
+		}
+		err = windows.LockFileEx(*handle, windows.LOCKFILE_EXCLUSIVE_LOCK, 0, uint32(offset), uint32(offset+int64(field.column.Length)), o)
+		if err != nil {
+			return newError("dbase-io-windows-writefield-4", err)
+		}
+		defer func() {
+			ulockErr := windows.UnlockFileEx(*handle, 0, uint32(offset), uint32(offset+int64(field.column.Length)), o)
+			if ulockErr != nil && err == nil {
+				err = newError("dbase-io-windows-writefield-5", ulockErr)
+			}
+		}()
+	}
+	debugf("Writing field %s of row: %d at offset: %v", field.Name(), position, offset)
+	_, err = windows.Seek(*handle, offset, 0)
+	if err != nil {
+		return newError("dbase-io-windows-writefield-6", err)
+	}
+	_, err = windows.Write(*handle, data)
+	if err != nil {
+		return newError("dbase-io-windows-writefield-7", err)
+	}
+	return nil
+}
+
+func (w WindowsIO) ReadRaw(file *File, related bool, offset int64, length int) ([]byte, error) {
+	var handle *windows.Handle
+	var err error
+	if related {
+		handle, err = w.getRelatedHandle(file)
+	} else {
+		handle, err = w.getHandle(file)
+	}
+	if err != nil {
+		return nil, newError("dbase-io-windows-readraw-1", err)
+	}
+	if offset < 0 || length < 0 {
+		return nil, newError("dbase-io-windows-readraw-2", fmt.Errorf("%w, offset %d length %d", ErrInvalidPosition, offset, length))
+	}
+	_, err = windows.Seek(*handle, offset, 0)
+	if err != nil {
+		return nil, newError("dbase-io-windows-readraw-3", err)
+	}
+	buf := make([]byte, length)
+	read := 0
+	for read < length {
+		n, err := windows.Read(*handle, buf[read:])
+		if err != nil {
+			return buf[:read], newError("dbase-io-windows-readraw-4", err)
+		}
+		if n == 0 {
+			return buf[:read], newError("dbase-io-windows-readraw-5", ErrIncomplete)
+		}
+		read += n
+	}
+	return buf, nil
+}
+
+func (w WindowsIO) Size(file *File, related bool) (int64, error) {
+	var handle *windows.Handle
+	var err error
+	if related {
+		handle, err = w.getRelatedHandle(file)
+	} else {
+		handle, err = w.getHandle(file)
+	}
+	if err != nil {
+		return 0, newError("dbase-io-windows-size-1", err)
+	}
+	size, err := windows.Seek(*handle, 0, 2)
+	if err != nil {
+		return 0, newError("dbase-io-windows-size-2", err)
+	}
+	return size, nil
+}
+
+func (w WindowsIO) WriteRaw(file *File, related bool, offset int64, data []byte) error {
+	mutex := file.dbaseMutex
+	if related {
+		mutex = file.memoMutex
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	var handle *windows.Handle
+	var err error
+	if related {
+		handle, err = w.getRelatedHandle(file)
+	} else {
+		handle, err = w.getHandle(file)
+	}
+	if err != nil {
+		return newError("dbase-io-windows-writeraw-1", err)
+	}
+	if offset < 0 {
+		return newError("dbase-io-windows-writeraw-2", fmt.Errorf("%w, offset %d", ErrInvalidPosition, offset))
+	}
+	_, err = windows.Seek(*handle, offset, 0)
+	if err != nil {
+		return newError("dbase-io-windows-writeraw-3", err)
+	}
+	_, err = windows.Write(*handle, data)
+	if err != nil {
+		return newError("dbase-io-windows-writeraw-4", err)
+	}
+	return nil
+}
+
+func (w WindowsIO) Truncate(file *File, related bool, size int64) error {
+	mutex := file.dbaseMutex
+	if related {
+		mutex = file.memoMutex
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	var handle *windows.Handle
+	var err error
+	if related {
+		handle, err = w.getRelatedHandle(file)
+	} else {
+		handle, err = w.getHandle(file)
+	}
+	if err != nil {
+		return newError("dbase-io-windows-truncate-1", err)
+	}
+	if size < 0 {
+		return newError("dbase-io-windows-truncate-2", fmt.Errorf("%w, size %d", ErrInvalidPosition, size))
+	}
+	_, err = windows.Seek(*handle, size, 0)
+	if err != nil {
+		return newError("dbase-io-windows-truncate-3", err)
+	}
+	err = windows.SetEndOfFile(*handle)
+	if err != nil {
+		return newError("dbase-io-windows-truncate-4", err)
+	}
 	return nil
 }
 
@@ -701,7 +913,7 @@ func (w WindowsIO) Search(file *File, field *Field, exactMatch bool) ([]*Row, er
 	position := uint64(file.header.FirstRow)
 	for i := uint32(0); i < file.header.RowsCount; i++ {
 		// Read the field value
-		p := int64(position) + int64(field.column.Position)
+		p := int64(position) + int64(file.columnOffset(field.column))
 		debugf("Searching at position: %d", p)
 		_, err := windows.Seek(*handle, p, 0)
 		position += uint64(file.header.RowLength)
@@ -718,7 +930,7 @@ func (w WindowsIO) Search(file *File, field *Field, exactMatch bool) ([]*Row, er
 		}
 		// Check if the value matches
 		if bytes.Contains(buf, val) {
-			debugf("Found matching field at position: %d - Record %v position: %v ", p, i+1, p-int64(field.column.Position))
+			debugf("Found matching field at position: %d - Record %v position: %v ", p, i+1, p-int64(file.columnOffset(field.column)))
 			err := file.GoTo(i)
 			if err != nil {
 				continue
@@ -815,3 +1027,14 @@ func (w WindowsIO) getRelatedHandle(file *File) (*windows.Handle, error) {
 	}
 	return handle, nil
 }
+
+// flushTable writes the buffered data of the table file to disk, if the handle supports it
+func flushTable(file *File) error {
+	switch handle := file.handle.(type) {
+	case *windows.Handle:
+		return windows.FlushFileBuffers(*handle)
+	case interface{ Sync() error }:
+		return handle.Sync()
+	}
+	return nil
+}
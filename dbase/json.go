@@ -0,0 +1,356 @@
+package dbase
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// JSONOptions configures the JSON output of rows
+type JSONOptions struct {
+	KeyCase         KeyCase // Casing of the column names, external keys of modifications are used as defined
+	DateLayout      string  // Layout of D values, RFC3339 if empty
+	DateTimeLayout  string  // Layout of T values, RFC3339 if empty
+	NumbersAsString bool    // If true N, F and Y values are written as strings with the decimals of the column to keep their precision
+}
+
+// jsonValues calls fn in column order with the key and value of each field converted according to the JSON options
+func (row *Row) jsonValues(fn func(field *Field, key string, val interface{})) error {
+	options := row.handle.config.JSON
+	err := row.modifiedValues(func(field *Field, key string, val interface{}) {
+		// Suffixed names of duplicate columns are cased as well, external keys are used as defined
+		name := row.handle.columnName(field.column)
+		if _, duplicate := row.handle.table.duplicates[field.column]; key == name || duplicate && strings.HasPrefix(key, name+"_") {
+			key = options.key(key)
+		}
+		fn(field, key, options.value(field.column, val))
+	})
+	if err != nil {
+		return newError("dbase-json-jsonvalues-1", err)
+	}
+	return nil
+}
+
+// key returns the column name in the configured casing
+func (options JSONOptions) key(name string) string {
+	switch options.KeyCase {
+	case KeyCaseLower:
+		return strings.ToLower(name)
+	case KeyCaseUpper:
+		return strings.ToUpper(name)
+	case KeyCaseCamel:
+		words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+			return r == '_' || r == ' ' || r == '-'
+		})
+		for i := 1; i < len(words); i++ {
+			words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+		}
+		return strings.Join(words, "")
+	}
+	return name
+}
+
+// value formats dates and numbers according to the options, other values are returned unchanged
+func (options JSONOptions) value(column *Column, val interface{}) interface{} {
+	switch v := val.(type) {
+	case time.Time:
+		switch {
+		case DataType(column.DataType) == Date && len(options.DateLayout) > 0:
+			return v.Format(options.DateLayout)
+		case DataType(column.DataType) == DateTime && len(options.DateTimeLayout) > 0:
+			return v.Format(options.DateTimeLayout)
+		}
+	case float64:
+		if !options.NumbersAsString {
+			return val
+		}
+		switch DataType(column.DataType) {
+		case Numeric, Float:
+			return strconv.FormatFloat(v, 'f', int(column.Decimals), 64)
+		case Currency:
+			return strconv.FormatFloat(v, 'f', 4, 64)
+		}
+	case int64:
+		if !options.NumbersAsString {
+			return val
+		}
+		switch DataType(column.DataType) {
+		case Numeric, Float, Currency:
+			return strconv.FormatInt(v, 10)
+		}
+	}
+	return val
+}
+
+// MarshalJSON implements json.Marshaler, the row is encoded like ToJSON
+func (row *Row) MarshalJSON() ([]byte, error) {
+	if row.handle == nil {
+		return nil, newError("dbase-json-marshaljson-1", fmt.Errorf("row is not bound to a table"))
+	}
+	data, err := row.ToJSON()
+	if err != nil {
+		return nil, newError("dbase-json-marshaljson-2", err)
+	}
+	return data, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler for rows created with NewRow or read from the table.
+// Keys and values are expected as written by MarshalJSON, fields without key and redacted columns keep their value.
+func (row *Row) UnmarshalJSON(data []byte) error {
+	if row.handle == nil {
+		return newError("dbase-json-unmarshaljson-1", fmt.Errorf("row is not bound to a table, create it with NewRow"))
+	}
+	m := make(map[string]interface{})
+	err := json.Unmarshal(data, &m)
+	if err != nil {
+		return newError("dbase-json-unmarshaljson-2", err)
+	}
+	file := row.handle
+	options := file.config.JSON
+	known := make(map[string]bool, len(m))
+	for i, field := range row.fields {
+		key := options.key(field.Name())
+		if mod := file.table.mods[i]; mod != nil && len(mod.ExternalKey) != 0 {
+			key = mod.ExternalKey
+		}
+		val, ok := m[key]
+		if !ok {
+			continue
+		}
+		known[key] = true
+		if redact, hidden := file.table.hidden[field.column]; hidden && redact {
+			continue
+		}
+		field.value, err = file.jsonValue(field.column, val)
+		if err != nil {
+			return newError("dbase-json-unmarshaljson-3", err)
+		}
+	}
+	if file.config.StrictMapping {
+		err = strictMapping(m, known, nil)
+		if err != nil {
+			return newError("dbase-json-unmarshaljson-4", err)
+		}
+	}
+	return nil
+}
+
+// jsonValue converts a decoded JSON value back to the type of the column
+func (file *File) jsonValue(column *Column, val interface{}) (interface{}, error) {
+	options := file.config.JSON
+	switch DataType(column.DataType) {
+	case Date, DateTime:
+		s, ok := val.(string)
+		if !ok {
+			return val, nil
+		}
+		layout := options.DateLayout
+		if DataType(column.DataType) == DateTime {
+			layout = options.DateTimeLayout
+		}
+		if len(layout) > 0 {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, nil
+			}
+		}
+		t, err := file.timeValue(&Field{column: column, value: s})
+		if err != nil {
+			return nil, newError("dbase-json-jsonvalue-1", err)
+		}
+		return t, nil
+	case Numeric, Float, Currency:
+		f, ok := val.(float64)
+		if s, isString := val.(string); isString {
+			var err error
+			f, err = strconv.ParseFloat(strings.TrimSpace(s), 64)
+			if err != nil {
+				return nil, newError("dbase-json-jsonvalue-2", fmt.Errorf("parsing number failed at column field: %v failed with error: %w", column.Name(), err))
+			}
+			ok = true
+		}
+		if !ok {
+			return val, nil
+		}
+		// N values without decimals are read as int64
+		if DataType(column.DataType) == Numeric && column.Decimals == 0 {
+			return int64(f), nil
+		}
+		return f, nil
+	case Integer:
+		if f, ok := val.(float64); ok {
+			return int32(f), nil
+		}
+	case Blob, Varbinary, General, Picture:
+		// encoding/json writes byte slices as base64
+		if s, ok := val.(string); ok {
+			b, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return nil, newError("dbase-json-jsonvalue-3", fmt.Errorf("decoding base64 failed at column field: %v failed with error: %w", column.Name(), err))
+			}
+			return b, nil
+		}
+	}
+	return val, nil
+}
+
+// jsonHex are the digits of \u escapes
+const jsonHex = "0123456789abcdef"
+
+// writeJSONValue writes the value like json.Marshal into the buffer.
+// Strings, numbers, booleans, times and byte slices are encoded without allocations, other values with json.Marshal.
+func writeJSONValue(buf *bytes.Buffer, val interface{}) error {
+	var scratch [64]byte
+	switch v := val.(type) {
+	case nil:
+		buf.WriteString("null")
+	case string:
+		writeJSONString(buf, v)
+	case bool:
+		buf.Write(strconv.AppendBool(scratch[:0], v))
+	case int:
+		buf.Write(strconv.AppendInt(scratch[:0], int64(v), 10))
+	case int8:
+		buf.Write(strconv.AppendInt(scratch[:0], int64(v), 10))
+	case int16:
+		buf.Write(strconv.AppendInt(scratch[:0], int64(v), 10))
+	case int32:
+		buf.Write(strconv.AppendInt(scratch[:0], int64(v), 10))
+	case int64:
+		buf.Write(strconv.AppendInt(scratch[:0], v, 10))
+	case uint8:
+		buf.Write(strconv.AppendUint(scratch[:0], uint64(v), 10))
+	case uint16:
+		buf.Write(strconv.AppendUint(scratch[:0], uint64(v), 10))
+	case uint32:
+		buf.Write(strconv.AppendUint(scratch[:0], uint64(v), 10))
+	case uint64:
+		buf.Write(strconv.AppendUint(scratch[:0], v, 10))
+	case float32:
+		return writeJSONFloat(buf, float64(v), 32)
+	case float64:
+		return writeJSONFloat(buf, v, 64)
+	case time.Time:
+		// Years outside of 0 to 9999 are rejected by json.Marshal
+		if y := v.Year(); y < 0 || y >= 10000 {
+			return marshalJSONValue(buf, val)
+		}
+		b := v.AppendFormat(append(scratch[:0], '"'), time.RFC3339Nano)
+		buf.Write(append(b, '"'))
+	case []byte:
+		if v == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		// Encoded in blocks of 48 bytes, which are 64 characters without padding
+		buf.WriteByte('"')
+		for len(v) > 0 {
+			n := len(v)
+			if n > 48 {
+				n = 48
+			}
+			base64.StdEncoding.Encode(scratch[:], v[:n])
+			buf.Write(scratch[:base64.StdEncoding.EncodedLen(n)])
+			v = v[n:]
+		}
+		buf.WriteByte('"')
+	default:
+		return marshalJSONValue(buf, val)
+	}
+	return nil
+}
+
+// marshalJSONValue writes the value encoded by json.Marshal
+func marshalJSONValue(buf *bytes.Buffer, val interface{}) error {
+	data, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	return nil
+}
+
+// writeJSONFloat writes the number in the format of json.Marshal, exponents are used for very small and very large numbers
+func writeJSONFloat(buf *bytes.Buffer, f float64, bits int) error {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return marshalJSONValue(buf, f)
+	}
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	var scratch [32]byte
+	b := strconv.AppendFloat(scratch[:0], f, format, -1, bits)
+	// Exponents are written with at least two digits by strconv and without leading zero by json.Marshal
+	if n := len(b); format == 'e' && n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+		b[n-2] = b[n-1]
+		b = b[:n-1]
+	}
+	buf.Write(b)
+	return nil
+}
+
+// writeJSONString writes the string quoted and escaped like json.Marshal, including the escapes of <, > and & for HTML
+// and the replacement of invalid UTF-8
+func writeJSONString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' && b != '<' && b != '>' && b != '&' {
+				i++
+				continue
+			}
+			buf.WriteString(s[start:i])
+			switch b {
+			case '"', '\\':
+				buf.WriteByte('\\')
+				buf.WriteByte(b)
+			case '\b':
+				buf.WriteString(`\b`)
+			case '\f':
+				buf.WriteString(`\f`)
+			case '\n':
+				buf.WriteString(`\n`)
+			case '\r':
+				buf.WriteString(`\r`)
+			case '\t':
+				buf.WriteString(`\t`)
+			default:
+				buf.WriteString(`\u00`)
+				buf.WriteByte(jsonHex[b>>4])
+				buf.WriteByte(jsonHex[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			buf.WriteString(s[start:i])
+			buf.WriteRune(utf8.RuneError)
+			i += size
+			start = i
+			continue
+		}
+		// U+2028 and U+2029 end lines in JavaScript
+		if r == '\u2028' || r == '\u2029' {
+			buf.WriteString(s[start:i])
+			buf.WriteString(`\u202`)
+			buf.WriteByte(jsonHex[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	buf.WriteString(s[start:])
+	buf.WriteByte('"')
+}
@@ -0,0 +1,323 @@
+package dbase
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Visual FoxPro locks bytes beyond the end of the file, so locks never block reading the data.
+// The header is locked at sharedLockOffset and row n (starting at 1) at sharedLockOffset - n, FLOCK locks the whole range.
+const sharedLockOffset = 0x7FFFFFFE
+
+// Defaults of LockRetry
+const (
+	defaultLockTimeout  = 5 * time.Second
+	defaultLockDelay    = 10 * time.Millisecond
+	defaultLockMaxDelay = time.Second
+)
+
+// errLockBusy is returned by the platform lock functions if the range is locked by another process
+var errLockBusy = errors.New("lock busy")
+
+// LockRetry configures how locks held by other processes are retried with Config.SharedWrite, like SET REPROCESS in FoxPro.
+// The wait between two attempts starts at Delay and doubles up to MaxDelay. The zero value retries for up to 5 seconds.
+type LockRetry struct {
+	Attempts int           // Maximum number of attempts like SET REPROCESS TO n, 0 for no limit
+	Timeout  time.Duration // Maximum time to wait like SET REPROCESS TO n SECONDS, 0 for 5 seconds and negative to wait until the context of LockRow is done like SET REPROCESS TO -1, writes wait up to 5 seconds then
+	Delay    time.Duration // Wait after the first failed attempt (default: 10 milliseconds)
+	MaxDelay time.Duration // Maximum wait between two attempts (default: 1 second)
+}
+
+// LockError is returned if a lock held by another process was not released within the retries of Config.LockRetry
+type LockError struct {
+	Memo     bool          // Lock of the memo file
+	Header   bool          // Lock of the header, taken while rows are appended
+	Position uint32        // Position of the locked row, if it is not a header lock
+	Holder   int           // Process ID of the holder if the platform reports it, 0 otherwise. Locks of network clients are held by the file server process
+	Attempts int           // Number of attempts to take the lock
+	Waited   time.Duration // Time waited for the lock
+	Cause    error         // Error of the context if it was done before the retries ended
+}
+
+// Error describes the lock, its holder and the retries
+func (e *LockError) Error() string {
+	target := fmt.Sprintf("row %d of the table", e.Position)
+	switch {
+	case e.Memo:
+		target = "header of the memo file"
+	case e.Header:
+		target = "header of the table"
+	}
+	holder := "another process"
+	if e.Holder > 0 {
+		holder = fmt.Sprintf("process %d", e.Holder)
+	}
+	message := fmt.Sprintf("%v, %s is locked by %s, gave up after %d attempts in %v", ErrLocked, target, holder, e.Attempts, e.Waited.Round(time.Millisecond))
+	if e.Cause != nil {
+		message += ": " + e.Cause.Error()
+	}
+	return message
+}
+
+// Unwrap returns the error of the context or ErrLocked
+func (e *LockError) Unwrap() error {
+	if e.Cause != nil {
+		return e.Cause
+	}
+	return ErrLocked
+}
+
+// Is reports ErrLocked for all lock errors, also if the context was done
+func (e *LockError) Is(target error) bool {
+	return target == ErrLocked
+}
+
+// sharedLock is a byte range lock of the table or memo file
+type sharedLock struct {
+	related bool  // Lock of the memo file
+	offset  int64 // Locked byte
+}
+
+// rowLock returns the lock of the row at the position
+func rowLock(position uint32) sharedLock {
+	return sharedLock{offset: sharedLockOffset - int64(position) - 1}
+}
+
+// LockRow locks the row at the position like RLOCK() in FoxPro, for a read-modify-write of rows other applications change concurrently.
+// Requires the experimental Config.SharedWrite. Writes of the row while it is locked keep the lock, it is released by calling the returned function.
+// Whether FoxPro applications respect the lock is not verified yet, see the shared writes section of the README.
+// A row locked by another process is retried as configured by Config.LockRetry or until the context is done,
+// afterwards a *LockError wrapping ErrLocked names the holder of the lock.
+func (file *File) LockRow(ctx context.Context, position uint32) (func() error, error) {
+	if !file.config.SharedWrite {
+		return nil, newError("dbase-lock-lockrow-1", fmt.Errorf("locking rows requires Config.SharedWrite"))
+	}
+	if position >= file.header.RowsCount {
+		return nil, newError("dbase-lock-lockrow-2", fmt.Errorf("%w, row %v >= %v", ErrEOF, position, file.header.RowsCount))
+	}
+	unlock, err := file.lockShared(ctx, rowLock(position))
+	if err != nil {
+		return nil, newError("dbase-lock-lockrow-3", err)
+	}
+	return unlock, nil
+}
+
+// checkSharedWrite rejects shared writes to tables with a structural index, as written rows do not update the index
+// and the applications using it would find wrong or missing rows. Config.WithoutIndex does not help,
+// the running applications would still use the stale index.
+func (file *File) checkSharedWrite() error {
+	if !file.config.SharedWrite || file.config.ReadOnly || !StructuralFlag.Defined(file.header.TableFlags) {
+		return nil
+	}
+	return fmt.Errorf("shared writes do not update the structural index of %s, the applications using the table would read a stale index", file.config.Filename)
+}
+
+// lockShared takes the lock, retrying locks of other processes as configured by Config.LockRetry.
+// Locks taken more than once by this file are counted and released with the last unlock, as the operating system
+// does not count locks of the same process.
+func (file *File) lockShared(ctx context.Context, lock sharedLock) (func() error, error) {
+	handle := file.handle
+	if lock.related {
+		handle = file.relatedHandle
+	}
+	retry := file.config.LockRetry
+	if retry.Timeout == 0 {
+		retry.Timeout = defaultLockTimeout
+	}
+	if retry.Delay <= 0 {
+		retry.Delay = defaultLockDelay
+	}
+	if retry.MaxDelay <= 0 {
+		retry.MaxDelay = defaultLockMaxDelay
+	}
+	start := time.Now()
+	delay := retry.Delay
+	attempts := 0
+	for {
+		// The mutex is only held for a single attempt, so waiting for other processes does not block the unlocks of this file
+		acquired, err := file.tryLock(handle, lock)
+		attempts++
+		if acquired {
+			break
+		}
+		if !errors.Is(err, errLockBusy) {
+			return nil, err
+		}
+		waited := time.Since(start)
+		if (retry.Attempts > 0 && attempts >= retry.Attempts) || (retry.Timeout > 0 && waited >= retry.Timeout) {
+			return nil, lock.error(handle, attempts, waited, nil)
+		}
+		wait := delay
+		if retry.Timeout > 0 && retry.Timeout-waited < wait {
+			wait = retry.Timeout - waited
+		}
+		debugf("Byte %d of the %s is locked, attempt %d, retrying in %v", lock.offset, lock.name(), attempts, wait)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, lock.error(handle, attempts, time.Since(start), ctx.Err())
+		case <-timer.C:
+		}
+		delay *= 2
+		if delay > retry.MaxDelay {
+			delay = retry.MaxDelay
+		}
+	}
+	released := false
+	return func() error {
+		file.lockMutex.Lock()
+		defer file.lockMutex.Unlock()
+		if released {
+			return nil
+		}
+		released = true
+		file.sharedLocks[lock]--
+		if file.sharedLocks[lock] > 0 {
+			return nil
+		}
+		delete(file.sharedLocks, lock)
+		debugf("Unlocking byte %d of the %s", lock.offset, lock.name())
+		return lockRange(handle, lock.offset, false)
+	}, nil
+}
+
+// lockInternal takes a lock of a write, which holds the write mutex of the table and has no context of the caller.
+// A negative LockRetry.Timeout waits at most for the default timeout here, so a lock held by another process
+// does not block all writes of this file forever. Only LockRow waits until its context is done.
+func (file *File) lockInternal(lock sharedLock) (func() error, error) {
+	ctx := context.Background()
+	if file.config.LockRetry.Timeout < 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultLockTimeout)
+		defer cancel()
+	}
+	return file.lockShared(ctx, lock)
+}
+
+// holdsLocks returns true if the file holds locks, which are released when its handles are closed
+func (file *File) holdsLocks() bool {
+	file.lockMutex.Lock()
+	defer file.lockMutex.Unlock()
+	return len(file.sharedLocks) > 0
+}
+
+// tryLock takes the lock once, a lock already held by this file is counted
+func (file *File) tryLock(handle interface{}, lock sharedLock) (bool, error) {
+	file.lockMutex.Lock()
+	defer file.lockMutex.Unlock()
+	if file.sharedLocks == nil {
+		file.sharedLocks = make(map[sharedLock]int)
+	}
+	if file.sharedLocks[lock] == 0 {
+		err := lockRange(handle, lock.offset, true)
+		if err != nil {
+			return false, err
+		}
+		debugf("Locked byte %d of the %s", lock.offset, lock.name())
+	}
+	file.sharedLocks[lock]++
+	return true, nil
+}
+
+// error returns the LockError of the lock with the holder reported by the platform
+func (lock sharedLock) error(handle interface{}, attempts int, waited time.Duration, cause error) *LockError {
+	e := &LockError{
+		Memo:     lock.related,
+		Header:   lock.offset == sharedLockOffset,
+		Holder:   lockHolder(handle, lock.offset),
+		Attempts: attempts,
+		Waited:   waited,
+		Cause:    cause,
+	}
+	if !e.Header {
+		e.Position = uint32(sharedLockOffset - lock.offset - 1)
+	}
+	return e
+}
+
+// name returns the name of the locked file for messages
+func (lock sharedLock) name() string {
+	if lock.related {
+		return "memo file"
+	}
+	return "table"
+}
+
+// lockWrite takes the locks of the shared update protocol for writing the row at the position, if Config.SharedWrite is set.
+// Appended rows lock the header, updated rows lock the row and the header, as the header is written with the row.
+// The header is read again under the lock, so the rows count includes rows appended by other processes.
+func (file *File) lockWrite(position uint32, appending bool) (func(), error) {
+	if !file.config.SharedWrite {
+		return func() {}, nil
+	}
+	unlocks := make([]func() error, 0, 2)
+	unlock := func() {
+		for i := len(unlocks) - 1; i >= 0; i-- {
+			err := unlocks[i]()
+			if err != nil {
+				debugf("Unlocking failed with error: %v", err)
+			}
+		}
+	}
+	if !appending {
+		rowUnlock, err := file.lockInternal(rowLock(position))
+		if err != nil {
+			return nil, err
+		}
+		unlocks = append(unlocks, rowUnlock)
+	}
+	headerUnlock, err := file.lockInternal(sharedLock{offset: sharedLockOffset})
+	if err != nil {
+		unlock()
+		return nil, err
+	}
+	unlocks = append(unlocks, headerUnlock)
+	err = file.refreshHeader()
+	if err != nil {
+		unlock()
+		return nil, err
+	}
+	return unlock, nil
+}
+
+// refreshHeader reads the rows count of the header written by other processes
+func (file *File) refreshHeader() error {
+	header, err := file.currentHeader()
+	if err != nil {
+		return err
+	}
+	if header.FirstRow != file.header.FirstRow || header.RowLength != file.header.RowLength {
+		return fmt.Errorf("%w, first row %d and row length %d differ from %d and %d when opened", ErrHeaderChanged, header.FirstRow, header.RowLength, file.header.FirstRow, file.header.RowLength)
+	}
+	if header.RowsCount != file.header.RowsCount {
+		debugf("Rows count changed by another process from %d to %d", file.header.RowsCount, header.RowsCount)
+		file.header.RowsCount = header.RowsCount
+	}
+	return nil
+}
+
+// lockMemo locks the memo header and reads the next free block written by other processes, if Config.SharedWrite is set
+func (file *File) lockMemo() (func() error, error) {
+	if !file.config.SharedWrite {
+		return func() error { return nil }, nil
+	}
+	unlock, err := file.lockInternal(sharedLock{related: true, offset: sharedLockOffset})
+	if err != nil {
+		return nil, err
+	}
+	raw, err := file.ReadRaw(true, 0, 8)
+	if err != nil {
+		unlock()
+		return nil, err
+	}
+	nextFree := binary.BigEndian.Uint32(raw[:4])
+	if nextFree > file.memoHeader.NextFree {
+		debugf("Next free memo block changed by another process from %d to %d", file.memoHeader.NextFree, nextFree)
+		file.memoHeader.NextFree = nextFree
+	}
+	return unlock, nil
+}
@@ -0,0 +1,57 @@
+//go:build linux
+// +build linux
+
+package dbase
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// lockRange locks or unlocks one byte at the offset with an open file description lock, which Samba maps to the byte range locks of Windows clients.
+// Unlike POSIX record locks they belong to the handle, so closing another handle of the file, e.g. by Reopen
+// or another table of the same file in this process, does not release them, and locks of two handles conflict.
+func lockRange(handle interface{}, offset int64, lock bool) error {
+	file, ok := handle.(*os.File)
+	if !ok || file == nil {
+		return fmt.Errorf("shared writes require the handles of UnixIO, got %T", handle)
+	}
+	flock := &unix.Flock_t{
+		Type:   unix.F_UNLCK,
+		Whence: io.SeekStart,
+		Start:  offset,
+		Len:    1,
+	}
+	if lock {
+		flock.Type = unix.F_WRLCK
+	}
+	err := unix.FcntlFlock(file.Fd(), unix.F_OFD_SETLK, flock)
+	if errors.Is(err, unix.EAGAIN) || errors.Is(err, unix.EACCES) {
+		return errLockBusy
+	}
+	return err
+}
+
+// lockHolder returns the process ID of the holder of the lock at the offset, 0 if the byte is not locked
+// or the holder is not known, as for open file description locks of other processes
+func lockHolder(handle interface{}, offset int64) int {
+	file, ok := handle.(*os.File)
+	if !ok || file == nil {
+		return 0
+	}
+	flock := &unix.Flock_t{
+		Type:   unix.F_WRLCK,
+		Whence: io.SeekStart,
+		Start:  offset,
+		Len:    1,
+	}
+	err := unix.FcntlFlock(file.Fd(), unix.F_OFD_GETLK, flock)
+	if err != nil || flock.Type == unix.F_UNLCK || flock.Pid <= 0 {
+		return 0
+	}
+	return int(flock.Pid)
+}
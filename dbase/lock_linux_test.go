@@ -0,0 +1,148 @@
+//go:build linux
+// +build linux
+
+package dbase
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// lockHelperTable is the environment variable passing the table to the helper process
+const lockHelperTable = "DBF_LOCK_HELPER_TABLE"
+
+// lockHelperResult prefixes the result line of the helper process
+const lockHelperResult = "lock result: "
+
+func TestSharedLocksAcrossProcesses(t *testing.T) {
+	path := createLockTable(t)
+	holder, err := OpenTable(&Config{Filename: path, SharedWrite: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer holder.Close()
+	unlock, err := holder.LockRow(context.Background(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Closing another handle of the table in this process must not release the lock
+	other, err := OpenTable(&Config{Filename: path, ReadOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = other.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := lockInHelper(t, path); result != "locked" {
+		t.Fatalf("row locked by this process was %s by another process", result)
+	}
+
+	// A second table of the same file in this process does not share the lock
+	second, err := OpenTable(&Config{Filename: path, SharedWrite: true, LockRetry: LockRetry{Attempts: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = second.LockRow(context.Background(), 0)
+	if !errors.Is(err, ErrLocked) {
+		t.Fatalf("expected ErrLocked for the second table of the file, got %v", err)
+	}
+	err = second.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Reopening would close the handle holding the lock
+	err = holder.Reopen()
+	if err == nil {
+		t.Fatal("table holding a lock was reopened")
+	}
+
+	err = unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := lockInHelper(t, path); result != "acquired" {
+		t.Fatalf("released row was %s by another process", result)
+	}
+}
+
+// TestLockHelperProcess takes the lock of the first row in a separate process for TestSharedLocksAcrossProcesses
+func TestLockHelperProcess(t *testing.T) {
+	path := os.Getenv(lockHelperTable)
+	if len(path) == 0 {
+		t.Skip("helper process of TestSharedLocksAcrossProcesses")
+	}
+	file, err := OpenTable(&Config{Filename: path, SharedWrite: true, LockRetry: LockRetry{Attempts: 1}})
+	if err != nil {
+		fmt.Println(lockHelperResult + err.Error())
+		return
+	}
+	defer file.Close()
+	unlock, err := file.LockRow(context.Background(), 0)
+	switch {
+	case errors.Is(err, ErrLocked):
+		fmt.Println(lockHelperResult + "locked")
+	case err != nil:
+		fmt.Println(lockHelperResult + err.Error())
+	default:
+		fmt.Println(lockHelperResult + "acquired")
+		err = unlock()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// lockInHelper runs TestLockHelperProcess for the table and returns its result
+func lockInHelper(t *testing.T, path string) string {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestLockHelperProcess$", "-test.count=1")
+	cmd.Env = append(os.Environ(), lockHelperTable+"="+path)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("helper process failed with error: %v\n%s", err, out)
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), lockHelperResult) {
+			return strings.TrimPrefix(scanner.Text(), lockHelperResult)
+		}
+	}
+	t.Fatalf("helper process returned no result:\n%s", out)
+	return ""
+}
+
+// createLockTable creates a table with one row in a temporary directory
+func createLockTable(t *testing.T) string {
+	t.Helper()
+	column, err := NewColumn("NAME", Character, 10, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "LOCKS.DBF")
+	file, err := CreateTable(&Config{Filename: path}, column)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	row := file.NewRow()
+	err = row.FieldByName("NAME").SetValue("first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = row.Add()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
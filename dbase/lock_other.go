@@ -0,0 +1,16 @@
+//go:build !windows && !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+// +build !windows,!linux,!darwin,!freebsd,!netbsd,!openbsd,!dragonfly
+
+package dbase
+
+import "fmt"
+
+// lockRange is not available on this platform
+func lockRange(handle interface{}, offset int64, lock bool) error {
+	return fmt.Errorf("shared writes are not supported on this platform")
+}
+
+// lockHolder returns 0, as locks are not supported on this platform
+func lockHolder(handle interface{}, offset int64) int {
+	return 0
+}
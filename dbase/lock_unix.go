@@ -0,0 +1,55 @@
+//go:build darwin || freebsd || netbsd || openbsd || dragonfly
+// +build darwin freebsd netbsd openbsd dragonfly
+
+package dbase
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+)
+
+// lockRange locks or unlocks one byte at the offset with a POSIX record lock, which Samba maps to the byte range locks of Windows clients.
+// Record locks belong to the process, closing any handle of the file in this process releases them,
+// so tables written with SharedWrite must not be opened a second time by the same process on these platforms.
+func lockRange(handle interface{}, offset int64, lock bool) error {
+	file, ok := handle.(*os.File)
+	if !ok || file == nil {
+		return fmt.Errorf("shared writes require the handles of UnixIO, got %T", handle)
+	}
+	flock := &syscall.Flock_t{
+		Type:   syscall.F_UNLCK,
+		Whence: io.SeekStart,
+		Start:  offset,
+		Len:    1,
+	}
+	if lock {
+		flock.Type = syscall.F_WRLCK
+	}
+	err := syscall.FcntlFlock(file.Fd(), syscall.F_SETLK, flock)
+	if errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.EACCES) {
+		return errLockBusy
+	}
+	return err
+}
+
+// lockHolder returns the process ID of the holder of the lock at the offset, 0 if the byte is not locked
+func lockHolder(handle interface{}, offset int64) int {
+	file, ok := handle.(*os.File)
+	if !ok || file == nil {
+		return 0
+	}
+	flock := &syscall.Flock_t{
+		Type:   syscall.F_WRLCK,
+		Whence: io.SeekStart,
+		Start:  offset,
+		Len:    1,
+	}
+	err := syscall.FcntlFlock(file.Fd(), syscall.F_GETLK, flock)
+	if err != nil || flock.Type == syscall.F_UNLCK {
+		return 0
+	}
+	return int(flock.Pid)
+}
@@ -0,0 +1,36 @@
+//go:build windows
+// +build windows
+
+package dbase
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockRange locks or unlocks one byte at the offset, like Visual FoxPro does
+func lockRange(handle interface{}, offset int64, lock bool) error {
+	h, ok := handle.(*windows.Handle)
+	if !ok || h == nil {
+		return fmt.Errorf("shared writes require the handles of WindowsIO, got %T", handle)
+	}
+	o := &windows.Overlapped{
+		Offset:     uint32(offset),
+		OffsetHigh: uint32(offset >> 32),
+	}
+	if !lock {
+		return windows.UnlockFileEx(*h, 0, 1, 0, o)
+	}
+	err := windows.LockFileEx(*h, windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, o)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) || errors.Is(err, windows.ERROR_IO_PENDING) {
+		return errLockBusy
+	}
+	return err
+}
+
+// lockHolder returns 0, Windows does not report the holder of a byte range lock
+func lockHolder(handle interface{}, offset int64) int {
+	return 0
+}
@@ -0,0 +1,49 @@
+package dbase
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// requireMemoFile returns ErrMissingMemoFile naming the expected path, if the table has memo columns but no memo file was opened.
+// This also catches tables whose header lacks the memo flag, which would otherwise fail on the first memo read of a scan.
+// With Config.SkipMissingMemo the table is opened anyway and memo columns are read as nil, like with Config.WithoutMemo
+// where the memo file is not opened at all. As written rows would lose the addresses of their memos, both require a read-only table.
+func (file *File) requireMemoFile() error {
+	if file.relatedHandle != nil {
+		return nil
+	}
+	memos := 0
+	for _, column := range file.table.columns {
+		switch DataType(column.DataType) {
+		case Memo, General, Picture, Blob:
+			memos++
+		}
+	}
+	if memos == 0 {
+		return nil
+	}
+	if file.config.WithoutMemo {
+		if !file.config.ReadOnly {
+			return fmt.Errorf("reading a table without its memo file requires a read-only table")
+		}
+		debugf("Memo file of %s is not opened, %d memo columns are read as nil", file.config.Filename, memos)
+		file.memoMissing = true
+		return nil
+	}
+	if !file.config.SkipMissingMemo {
+		extension := FPT
+		if FileExtension(strings.ToUpper(filepath.Ext(file.config.Filename))) == DBC {
+			extension = DCT
+		}
+		path := strings.TrimSuffix(file.config.Filename, filepath.Ext(file.config.Filename)) + string(extension)
+		return fmt.Errorf("%w, the table has %d memo columns but the memo file %v is missing", ErrMissingMemoFile, memos, path)
+	}
+	if !file.config.ReadOnly {
+		return fmt.Errorf("skipping a missing memo file requires a read-only table")
+	}
+	debugf("Memo file of %s is missing, %d memo columns are read as nil", file.config.Filename, memos)
+	file.memoMissing = true
+	return nil
+}
@@ -0,0 +1,139 @@
+package dbase
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// memoHistoryMarker starts the trailer written after the data of a memo block with Config.MemoHistory.
+// The trailer is the marker and the previous block of the field (big endian), it is not part of the length in the block header,
+// so other applications read the memo without it.
+var memoHistoryMarker = []byte("PREV")
+
+// memoHistoryTrailer is the length of the trailer of a memo block with history
+const memoHistoryTrailer = 8
+
+// MemoVersion is a prior value of a memo field, read by Row.MemoHistory
+type MemoVersion struct {
+	Block uint32      // Memo block of the value
+	Value interface{} // Value as string for text and []byte for binary memos
+}
+
+// MemoHistory returns the prior values of the memo column of the row as stored in the table, the newest first.
+// The current value is not included. Prior values are only available for memos written with Config.MemoHistory,
+// the history ends at a memo written without it or at an empty field.
+func (row *Row) MemoHistory(name string) ([]*MemoVersion, error) {
+	file := row.handle
+	column := file.ColumnByName(name)
+	if column == nil {
+		return nil, newError("dbase-memohistory-memohistory-1", fmt.Errorf("column '%s' not found", name))
+	}
+	if DataType(column.DataType) != Memo {
+		return nil, newError("dbase-memohistory-memohistory-2", fmt.Errorf("column '%s' is not a memo column", name))
+	}
+	if file.memoHeader == nil || file.memoMissing {
+		return nil, newError("dbase-memohistory-memohistory-3", fmt.Errorf("%w, the memo file of the table is not opened", ErrMissingMemoFile))
+	}
+	block, err := file.storedMemoBlock(row.Position, column)
+	if err != nil {
+		return nil, newError("dbase-memohistory-memohistory-4", err)
+	}
+	versions := make([]*MemoVersion, 0)
+	for block != 0 {
+		previous, err := file.previousMemoBlock(block)
+		if err != nil {
+			return nil, newError("dbase-memohistory-memohistory-5", err)
+		}
+		// Blocks are only appended, so a previous version is always stored before the block referencing it
+		if previous == 0 || previous >= block {
+			break
+		}
+		address, err := toBinary(previous)
+		if err != nil {
+			return nil, newError("dbase-memohistory-memohistory-6", err)
+		}
+		value, err := file.parseMemo(address, column)
+		if err != nil {
+			return nil, newError("dbase-memohistory-memohistory-7", err)
+		}
+		versions = append(versions, &MemoVersion{Block: previous, Value: value})
+		block = previous
+	}
+	return versions, nil
+}
+
+// readMemoPrevious sets the memo blocks stored at the position as previous versions of the memo fields, if Config.MemoHistory is set.
+// Called before a row or field is written, fields of appended rows have no previous version.
+func (file *File) readMemoPrevious(position uint32, fields ...*Field) error {
+	if !file.config.MemoHistory || file.memoHeader == nil {
+		return nil
+	}
+	var raw []byte
+	for _, field := range fields {
+		if DataType(field.column.DataType) != Memo {
+			continue
+		}
+		if position >= file.header.RowsCount {
+			field.previous = 0
+			continue
+		}
+		if raw == nil {
+			var err error
+			raw, err = file.ReadRow(position)
+			if err != nil {
+				return err
+			}
+		}
+		offset := file.columnOffset(field.column)
+		field.previous = binary.LittleEndian.Uint32(raw[offset : offset+4])
+	}
+	return nil
+}
+
+// storedMemoBlock returns the memo block of the column stored at the position
+func (file *File) storedMemoBlock(position uint32, column *Column) (uint32, error) {
+	raw, err := file.ReadRow(position)
+	if err != nil {
+		return 0, err
+	}
+	offset := file.columnOffset(column)
+	return binary.LittleEndian.Uint32(raw[offset : offset+4]), nil
+}
+
+// previousMemoBlock returns the previous block of the trailer of the memo block, 0 if the block has no trailer
+func (file *File) previousMemoBlock(block uint32) (uint32, error) {
+	position := int64(block) * int64(file.memoHeader.BlockSize)
+	header, err := file.ReadRaw(true, position, memoBlockHeader)
+	if err != nil {
+		return 0, err
+	}
+	length := int64(binary.BigEndian.Uint32(header[4:]))
+	trailer, err := file.ReadRaw(true, position+memoBlockHeader+length, memoHistoryTrailer)
+	if err != nil || !bytes.Equal(trailer[:4], memoHistoryMarker) {
+		// A memo at the end of the file written without history has no trailer
+		return 0, nil
+	}
+	return binary.BigEndian.Uint32(trailer[4:]), nil
+}
+
+// writeMemoVersion writes the memo with a trailer pointing to the previous version of the field.
+// If the value did not change the previous block is kept, so rewriting a row does not add versions.
+// Without a MemoReader the stored memo can not be compared and a new version is written.
+func (file *File) writeMemoVersion(field *Field, memo []byte, text bool) ([]byte, error) {
+	if reader, ok := file.defaults().io.(MemoReader); ok && field.previous != 0 {
+		address, err := toBinary(field.previous)
+		if err != nil {
+			return nil, err
+		}
+		current, isText, err := reader.ReadRawMemo(file, address)
+		if err == nil && isText == text && bytes.Equal(current, memo) {
+			return address, nil
+		}
+	}
+	raw := make([]byte, 0, len(memo)+memoHistoryTrailer)
+	raw = append(raw, memo...)
+	raw = append(raw, memoHistoryMarker...)
+	raw = binary.BigEndian.AppendUint32(raw, field.previous)
+	return file.WriteMemo(raw, text, len(memo))
+}
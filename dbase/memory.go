@@ -0,0 +1,130 @@
+package dbase
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Approximate memory of decoded rows in addition to the row data, used for Config.MemoryLimit
+const (
+	rowOverhead   = 64 // Row struct and fields slice
+	fieldOverhead = 40 // Field struct, its pointer and the interface value
+)
+
+// memorySampleRows is the maximum number of rows EstimateMemory reads to sample the memo sizes
+const memorySampleRows = 100
+
+// MemoryEstimate is the predicted memory of rows read into memory, e.g. with Rows
+type MemoryEstimate struct {
+	Rows        uint32 `json:"rows"`         // Number of rows the estimate is for
+	RowBytes    int64  `json:"row_bytes"`    // Estimated bytes of a row without its memos
+	MemoBytes   int64  `json:"memo_bytes"`   // Average bytes of the memos of a row in the sampled rows
+	Total       int64  `json:"total"`        // Estimated bytes of all rows
+	SampledRows uint32 `json:"sampled_rows"` // Rows read to sample the memo sizes
+}
+
+// EstimateMemory predicts the memory needed to read the number of rows into memory, before they are read.
+// The size of a row is calculated from the columns like Config.MemoryLimit counts it, the size of the memos
+// is the average of up to 100 rows spread over the table, only the block headers of the memos are read.
+// Services can compare the total with their limits and stream the rows with StreamBatches or the exports instead.
+func (file *File) EstimateMemory(rows uint32) (*MemoryEstimate, error) {
+	estimate := &MemoryEstimate{Rows: rows, RowBytes: rowOverhead + int64(file.header.RowLength)}
+	memos := make([]*Column, 0)
+	for _, column := range file.table.columns {
+		estimate.RowBytes += fieldOverhead
+		switch DataType(column.DataType) {
+		case Character, Varchar, Varbinary, Blob, Picture, General:
+			// Decoded as string or []byte of the column length
+			estimate.RowBytes += int64(column.Length)
+		case Memo:
+			memos = append(memos, column)
+		}
+	}
+	if len(memos) > 0 && file.relatedHandle != nil && file.memoHeader != nil && file.header.RowsCount > 0 {
+		total, err := file.sampleMemoBytes(memos, estimate)
+		if err != nil {
+			return nil, newError("dbase-memory-estimatememory-1", err)
+		}
+		estimate.MemoBytes = total / int64(estimate.SampledRows)
+	}
+	estimate.Total = int64(rows) * (estimate.RowBytes + estimate.MemoBytes)
+	return estimate, nil
+}
+
+// sampleMemoBytes returns the total length of the memos of rows spread over the table and counts the sampled rows
+func (file *File) sampleMemoBytes(memos []*Column, estimate *MemoryEstimate) (int64, error) {
+	step := file.header.RowsCount / memorySampleRows
+	if step == 0 {
+		step = 1
+	}
+	total := int64(0)
+	for position := uint32(0); position < file.header.RowsCount && estimate.SampledRows < memorySampleRows; position += step {
+		data, err := file.ReadRow(position)
+		if err != nil {
+			return 0, err
+		}
+		estimate.SampledRows++
+		for _, column := range memos {
+			offset := file.columnOffset(column)
+			block, blockOffset, err := file.memoBlock(data[offset : offset+uint32(column.Length)])
+			if err != nil {
+				return 0, fmt.Errorf("sampling column %s of row %d failed with error: %w", column.Name(), position, err)
+			}
+			if block == 0 {
+				continue
+			}
+			header, err := file.ReadRaw(true, blockOffset, memoBlockHeader)
+			if err != nil {
+				return 0, fmt.Errorf("sampling column %s of row %d failed with error: %w", column.Name(), position, err)
+			}
+			total += int64(binary.BigEndian.Uint32(header[4:]))
+		}
+	}
+	return total, nil
+}
+
+// memoryLimit tracks the estimated memory of the rows collected by Rows and RowsWithErrors
+type memoryLimit struct {
+	limit int64
+	used  int64
+}
+
+// memoryLimit returns the limit for collecting rows from the row pointer on, nil if no limit is configured.
+// Fails right away if the remaining rows can not fit into the limit.
+func (file *File) memoryLimit() (*memoryLimit, error) {
+	limit := file.config.MemoryLimit
+	if limit <= 0 {
+		return nil, nil
+	}
+	remaining := int64(0)
+	if file.table.rowPointer < file.header.RowsCount {
+		remaining = int64(file.header.RowsCount - file.table.rowPointer)
+	}
+	minimum := remaining * (rowOverhead + int64(file.header.RowLength) + int64(len(file.table.columns))*fieldOverhead)
+	if minimum > limit {
+		return nil, fmt.Errorf("%w, %d rows need at least %d bytes, stream them with StreamBatches or the exports instead", ErrMemoryLimit, remaining, minimum)
+	}
+	return &memoryLimit{limit: limit}, nil
+}
+
+// add adds the estimated memory of the row, memo values are counted with their length
+func (b *memoryLimit) add(row *Row) error {
+	if b == nil {
+		return nil
+	}
+	size := int64(rowOverhead + int(row.handle.header.RowLength))
+	for _, field := range row.fields {
+		size += fieldOverhead
+		switch v := field.value.(type) {
+		case string:
+			size += int64(len(v))
+		case []byte:
+			size += int64(len(v))
+		}
+	}
+	b.used += size
+	if b.used > b.limit {
+		return fmt.Errorf("%w, the rows read up to row %d need about %d bytes, stream them with StreamBatches or the exports instead", ErrMemoryLimit, row.Position, b.used)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package dbase
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ColumnOffsets returns the byte offset of each column within a row.
+// The offsets are taken from the column descriptors (Position), so hidden bytes between fields are skipped.
+// If the descriptors contain no usable positions (e.g. dBase III tables), the offsets are calculated from the column lengths.
+func (file *File) ColumnOffsets() []uint32 {
+	offsets := file.offsets()
+	result := make([]uint32, len(file.table.columns))
+	copy(result, offsets)
+	return result
+}
+
+// offsets returns the cached byte offsets of the columns followed by the offset of the null flag column, if any
+func (file *File) offsets() []uint32 {
+	if file.table.offsets != nil {
+		return file.table.offsets
+	}
+	columns := file.rowColumns()
+	offsets := make([]uint32, len(columns))
+	if err := file.validatePositions(); err != nil {
+		debugf("Calculating column offsets from the column lengths: %v", err)
+		offset := 1
+		for i, column := range columns {
+			offsets[i] = uint32(offset)
+			offset += int(column.Length)
+		}
+	} else {
+		for i, column := range columns {
+			offsets[i] = column.Position
+		}
+	}
+	file.table.offsets = offsets
+	return offsets
+}
+
+// columnOffset returns the byte offset of the column within a row
+func (file *File) columnOffset(column *Column) uint32 {
+	for i, c := range file.rowColumns() {
+		if c == column {
+			return file.offsets()[i]
+		}
+	}
+	return column.Position
+}
+
+// rowColumns returns the columns of the table followed by the null flag column, if any
+func (file *File) rowColumns() []*Column {
+	if file.nullFlagColumn == nil {
+		return file.table.columns
+	}
+	return append(append(make([]*Column, 0, len(file.table.columns)+1), file.table.columns...), file.nullFlagColumn)
+}
+
+// checkLayout checks that the header can hold the column descriptors and the row length the columns,
+// so the offsets of the columns can be used on row data of the row length without further checks
+func (file *File) checkLayout() error {
+	if file.header.RowLength == 0 {
+		return fmt.Errorf("%w, row length 0 can not hold the deletion flag", ErrInvalidHeader)
+	}
+	_, err := file.headerPadding()
+	if err != nil {
+		return err
+	}
+	columns := file.rowColumns()
+	offsets := file.offsets()
+	for i, column := range columns {
+		if length := binaryLength(DataType(column.DataType)); int(column.Length) < length {
+			return fmt.Errorf("%w, column '%s' of type %s with length %d is shorter than the %d bytes of the type", ErrInvalidHeader, column.Name(), column.Type(), column.Length, length)
+		}
+		end := int(offsets[i]) + int(column.Length)
+		if end > int(file.header.RowLength) {
+			return fmt.Errorf("%w, column '%s' at offset %d with length %d exceeds the row length %d", ErrInvalidHeader, column.Name(), offsets[i], column.Length, file.header.RowLength)
+		}
+	}
+	return nil
+}
+
+// checkLimits returns ErrInvalidHeader if the header and the rows of a new table with the columns exceed the limits of the header fields
+func checkLimits(columns []*Column) error {
+	descriptors := len(columns)
+	rowLength := 1
+	variable := 0
+	for _, column := range columns {
+		rowLength += int(column.Length)
+		if column.DataType == byte(Varchar) || column.DataType == byte(Varbinary) {
+			variable++
+			if column.Flag == byte(NullableFlag) || column.Flag == byte(NullableFlag|BinaryFlag) {
+				variable++
+			}
+		}
+	}
+	if variable > 0 {
+		// The null flag column holds one bit per variable length column and one more per nullable one
+		descriptors++
+		rowLength += (variable + 7) / 8
+	}
+	if rowLength > MaxRowLength {
+		return fmt.Errorf("%w, row length %d exceeds the maximum of %d bytes", ErrInvalidHeader, rowLength, MaxRowLength)
+	}
+	if header := 296 + descriptors*32; header > MaxHeaderLength {
+		return fmt.Errorf("%w, header length %d of %d columns exceeds the maximum of %d bytes", ErrInvalidHeader, header, descriptors, MaxHeaderLength)
+	}
+	return nil
+}
+
+// headerPadding returns the number of bytes between the column terminator and the first row
+func (file *File) headerPadding() (int, error) {
+	descriptors := 32 + len(file.rowColumns())*32 + 1
+	if int(file.header.FirstRow) < descriptors {
+		return 0, fmt.Errorf("%w, first row at %d overlaps the %d column descriptors ending at %d", ErrInvalidHeader, file.header.FirstRow, len(file.rowColumns()), descriptors)
+	}
+	return int(file.header.FirstRow) - descriptors, nil
+}
+
+// validatePositions checks that the column positions are inside the row, behind the deletion flag and do not overlap
+func (file *File) validatePositions() error {
+	columns := file.rowColumns()
+	sorted := make([]*Column, len(columns))
+	copy(sorted, columns)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Position < sorted[j].Position })
+	end := int64(1)
+	for _, column := range sorted {
+		if int64(column.Position) < end {
+			return newError("dbase-offsets-validatepositions-1", fmt.Errorf("column '%s' at offset %d overlaps the previous field ending at %d", column.Name(), column.Position, end))
+		}
+		end = int64(column.Position) + int64(column.Length)
+		if end > int64(file.header.RowLength) {
+			return newError("dbase-offsets-validatepositions-2", fmt.Errorf("column '%s' at offset %d with length %d exceeds the row length %d", column.Name(), column.Position, column.Length, file.header.RowLength))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,338 @@
+package dbase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PackProgress is reported by Pack after every chunk
+type PackProgress struct {
+	Read  uint32 // Rows read so far
+	Kept  uint32 // Rows kept so far, deleted rows are removed
+	Total uint32 // Rows count before packing
+}
+
+// PackConfig configures Pack
+type PackConfig struct {
+	Checkpoint string                      // Path of the checkpoint file, if set an interrupted Pack continues from it
+	ChunkRows  uint32                      // Number of rows read per chunk (default: 10000)
+	Progress   func(progress PackProgress) // Called after every chunk
+}
+
+// packCheckpoint records the progress of Pack together with the rows of the chunk that is written,
+// so a chunk that was interrupted while it overwrote rows can be written again
+type packCheckpoint struct {
+	RowLength uint16 `json:"row_length"` // Row length of the table, used to detect schema changes
+	RowsCount uint32 `json:"rows_count"` // Rows count before packing
+	Start     uint32 `json:"start"`      // First row position of the chunk
+	Read      uint32 `json:"read"`       // Next row position to read
+	Written   uint32 `json:"written"`    // Position the rows of the chunk are written to
+	Kept      uint32 `json:"kept"`       // Number of kept rows of the chunk
+	Rows      []byte `json:"rows"`       // Kept rows of the chunk, empty if they stay at their position
+}
+
+// Pack removes the deleted rows from the table like PACK in FoxPro.
+// The kept rows are moved towards the start of the file in chunks, so no copy of the table and no temporary space
+// beyond one chunk in the checkpoint file is needed. Each chunk is recorded in the checkpoint file before it overwrites rows,
+// an interrupted Pack called again with the same checkpoint continues where it stopped.
+// After each chunk the rows left behind the moved rows are marked as deleted. An interrupted Pack without checkpoint
+// may leave the kept rows of the last chunk twice, at their new position and at their old position if it stopped
+// before they were marked, so continue an interrupted Pack with its checkpoint. The rows count is changed when the pack is finished.
+// Fails before anything is written if the checkpoint does not fit on the disk.
+// The memo file is not compacted, the memo blocks of removed rows are left unused.
+// Bookmarks of this handle created before the pack are moved with their rows, see GoToBookmark.
+// The tags of an index snapshot written by CreateIndex are rebuilt after packing. Tables with a structural index
+// fail with ErrStructuralIndex, as this package does not maintain the tags FoxPro relies on.
+func (file *File) Pack(config *PackConfig) error {
+	if config == nil {
+		config = &PackConfig{}
+	}
+	if file.config.ReadOnly {
+		return newError("dbase-pack-pack-1", fmt.Errorf("a read-only table can not be packed"))
+	}
+	if file.config.SharedWrite && !file.config.Exclusive {
+		return newError("dbase-pack-pack-9", fmt.Errorf("a shared table can not be packed, like in FoxPro packing requires exclusive access"))
+	}
+	// Checked first, so the table is not rewritten if it can not be shortened afterwards
+	if _, ok := file.defaults().io.(Truncater); !ok {
+		return newError("dbase-pack-pack-12", fmt.Errorf("%w, %T does not implement Truncater and can not shorten the packed table", ErrUnsupported, file.io))
+	}
+	// Checked first, the rows are renumbered and the keys of the index would point at other rows
+	snapshot, err := file.packIndex()
+	if err != nil {
+		return newError("dbase-pack-pack-13", err)
+	}
+	chunkRows := config.ChunkRows
+	if chunkRows == 0 {
+		chunkRows = 10000
+	}
+	file.writeMutex.Lock()
+	defer file.writeMutex.Unlock()
+	file.rowCache.Invalidate()
+	file.table.rowPointer = 0
+	total := file.header.RowsCount
+	read, written := uint32(0), uint32(0)
+	// The removed rows are recorded for the bookmarks, the mapping is complete only if the pack finished without resuming
+	mapping := packMapping{removed: make([]uint32, 0), incomplete: true}
+	resumed, moved := false, false
+	defer func() {
+		if moved {
+			file.packs.record(mapping)
+		}
+	}()
+	if len(config.Checkpoint) > 0 {
+		err := checkPackSpace(config.Checkpoint, uint64(chunkRows)*uint64(file.header.RowLength))
+		if err != nil {
+			return newError("dbase-pack-pack-10", err)
+		}
+		var checkpoint *packCheckpoint
+		checkpoint, err = file.loadPackCheckpoint(config.Checkpoint)
+		if err != nil {
+			return newError("dbase-pack-pack-2", err)
+		}
+		if checkpoint != nil {
+			resumed, moved = true, true
+			read, written, err = file.resumePack(checkpoint)
+			if err != nil {
+				return newError("dbase-pack-pack-3", err)
+			}
+			total = checkpoint.RowsCount
+		}
+	}
+	throttle := file.throttle()
+	length := uint32(file.header.RowLength)
+	for read < total {
+		start := read
+		chunk := make([]byte, 0, chunkRows*length)
+		for read < total && read-start < chunkRows {
+			data, err := file.ReadRow(read)
+			if err != nil {
+				return newError("dbase-pack-pack-4", err)
+			}
+			throttle.wait(1, uint64(len(data)))
+			if Marker(data[0]) != Deleted {
+				chunk = append(chunk, data...)
+			} else {
+				mapping.removed = append(mapping.removed, read)
+			}
+			read++
+		}
+		kept := uint32(len(chunk)) / length
+		// Rows that stay at their position do not have to be written
+		if written == start && kept == read-start {
+			chunk = chunk[:0]
+		}
+		if len(config.Checkpoint) > 0 {
+			checkpoint := &packCheckpoint{RowLength: file.header.RowLength, RowsCount: total, Start: start, Read: read, Written: written, Kept: kept, Rows: chunk}
+			err := savePackCheckpoint(config.Checkpoint, checkpoint)
+			if err != nil {
+				return newError("dbase-pack-pack-5", err)
+			}
+		}
+		moved = true
+		err := file.writePackChunk(written, chunk)
+		if err != nil {
+			return newError("dbase-pack-pack-6", err)
+		}
+		err = file.markPackVacated(start, written+kept, read)
+		if err != nil {
+			return newError("dbase-pack-pack-11", err)
+		}
+		written += kept
+		if config.Progress != nil {
+			config.Progress(PackProgress{Read: read, Kept: written, Total: total})
+		}
+	}
+	err = file.finishPack(written)
+	if err != nil {
+		return newError("dbase-pack-pack-7", err)
+	}
+	mapping.incomplete = resumed
+	debugf("Packed table from %d to %d rows", total, written)
+	if len(config.Checkpoint) > 0 {
+		err = os.Remove(config.Checkpoint)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return newError("dbase-pack-pack-8", err)
+		}
+	}
+	if snapshot != nil {
+		for _, tag := range snapshot.Tags {
+			err = file.CreateIndex(tag.Name, tag.Expression, &IndexOptions{Descending: tag.Descending, Unique: tag.Unique, Candidate: tag.Candidate})
+			if err != nil {
+				return newError("dbase-pack-pack-14", fmt.Errorf("rebuilding index tag %s failed with error: %w", tag.Name, err))
+			}
+		}
+	}
+	return nil
+}
+
+// packIndex returns the index snapshot that is rebuilt after packing, nil if the table has no index.
+// Fails for indexes that can not be rebuilt, before anything is written.
+func (file *File) packIndex() (*IndexInfo, error) {
+	if StructuralFlag.Defined(file.header.TableFlags) {
+		return nil, fmt.Errorf("%w, the tags of %s are maintained by FoxPro and would point at other rows after packing", ErrStructuralIndex, file.config.Filename)
+	}
+	if len(file.path) == 0 {
+		return nil, nil
+	}
+	if file.config.WithoutIndex {
+		path, err := _findFile(strings.TrimSuffix(file.path, filepath.Ext(file.path)) + string(CDX))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(path); err == nil {
+			return nil, fmt.Errorf("%w, the index snapshot %s of a table opened without index can not be rebuilt after packing", ErrUnsupported, path)
+		}
+		return nil, nil
+	}
+	info, err := file.IndexInfo()
+	if err != nil || info == nil {
+		return nil, err
+	}
+	for _, tag := range info.Tags {
+		if len(tag.Filter) > 0 {
+			return nil, fmt.Errorf("%w, index tag %s with FOR filter can not be rebuilt after packing", ErrUnsupported, tag.Name)
+		}
+		_, err := file.compileIndexExpression(tag.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("%w, index tag %s can not be rebuilt after packing: %v", ErrUnsupported, tag.Name, err)
+		}
+	}
+	return info, nil
+}
+
+// resumePack writes the rows of the interrupted chunk again and returns the positions to continue reading and writing
+func (file *File) resumePack(checkpoint *packCheckpoint) (uint32, uint32, error) {
+	if checkpoint.RowLength != file.header.RowLength {
+		return 0, 0, fmt.Errorf("checkpoint row length %d does not match table row length %d", checkpoint.RowLength, file.header.RowLength)
+	}
+	kept := checkpoint.Kept
+	written := checkpoint.Written + kept
+	if (len(checkpoint.Rows) > 0 && len(checkpoint.Rows) != int(kept)*int(checkpoint.RowLength)) || written > checkpoint.Read || checkpoint.Read > checkpoint.RowsCount {
+		return 0, 0, fmt.Errorf("%w, checkpoint at row %d with %d rows written at %d is inconsistent", ErrInvalidPosition, checkpoint.Read, kept, checkpoint.Written)
+	}
+	// The rows count of the header is only changed when the pack is finished
+	finished := checkpoint.Read == checkpoint.RowsCount && file.header.RowsCount == written
+	if file.header.RowsCount != checkpoint.RowsCount && !finished {
+		return 0, 0, fmt.Errorf("checkpoint rows count %d does not match table rows count %d", checkpoint.RowsCount, file.header.RowsCount)
+	}
+	debugf("Resuming pack from checkpoint at row %d, writing %d rows at %d", checkpoint.Read, kept, checkpoint.Written)
+	if !finished {
+		err := file.writePackChunk(checkpoint.Written, checkpoint.Rows)
+		if err != nil {
+			return 0, 0, err
+		}
+		err = file.markPackVacated(checkpoint.Start, written, checkpoint.Read)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	return checkpoint.Read, written, nil
+}
+
+// writePackChunk writes the rows at the position and flushes them to disk before the next checkpoint replaces the chunk
+func (file *File) writePackChunk(position uint32, rows []byte) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	offset, err := file.rowOffset(uint64(position))
+	if err != nil {
+		return err
+	}
+	err = file.WriteRaw(false, offset, rows)
+	if err != nil {
+		return err
+	}
+	return flushTable(file)
+}
+
+// markPackVacated marks the rows from the end of the moved rows to the next row to read as deleted.
+// Rows before the start of the chunk were marked with the previous chunk.
+func (file *File) markPackVacated(start uint32, from uint32, to uint32) error {
+	if from < start {
+		from = start
+	}
+	if from >= to {
+		return nil
+	}
+	offset, err := file.rowOffset(uint64(from))
+	if err != nil {
+		return err
+	}
+	length := int(file.header.RowLength)
+	rows, err := file.ReadRaw(false, offset, int(to-from)*length)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(rows); i += length {
+		rows[i] = byte(Deleted)
+	}
+	err = file.WriteRaw(false, offset, rows)
+	if err != nil {
+		return err
+	}
+	return flushTable(file)
+}
+
+// checkPackSpace checks that the checkpoint of a chunk fits on the disk.
+// The rows are stored base64 encoded and the old checkpoint is kept until the new one is complete.
+func checkPackSpace(path string, chunk uint64) error {
+	required := (chunk + 2) / 3 * 4 * 2
+	available, err := freeSpace(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("checking the free space for the checkpoint failed with error: %w", err)
+	}
+	if available < required {
+		return fmt.Errorf("the checkpoint needs up to %d bytes but only %d bytes are available in %s", required, available, filepath.Dir(path))
+	}
+	return nil
+}
+
+// finishPack sets the rows count, terminates the rows and cuts off the rows behind them
+func (file *File) finishPack(rows uint32) error {
+	file.rowCache.Invalidate()
+	file.header.RowsCount = rows
+	err := file.WriteHeader()
+	if err != nil {
+		return err
+	}
+	err = file.writeEOF()
+	if err != nil {
+		return err
+	}
+	err = file.Truncate(false, file.dataEnd()+1)
+	if err != nil {
+		return err
+	}
+	return flushTable(file)
+}
+
+// loadPackCheckpoint reads the checkpoint of Pack, returns nil if no checkpoint exists
+func (file *File) loadPackCheckpoint(path string) (*packCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	checkpoint := &packCheckpoint{}
+	err = json.Unmarshal(data, checkpoint)
+	if err != nil {
+		return nil, fmt.Errorf("reading pack checkpoint failed with error: %w", err)
+	}
+	return checkpoint, nil
+}
+
+// savePackCheckpoint writes the checkpoint of Pack atomically
+func savePackCheckpoint(path string, checkpoint *packCheckpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
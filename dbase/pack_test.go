@@ -0,0 +1,55 @@
+package dbase
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// TestPackIndex rebuilds index snapshots after packing and rejects tables with a structural index
+func TestPackIndex(t *testing.T) {
+	t.Run("snapshot", func(t *testing.T) {
+		file := createSortTable(t, filepath.Join(t.TempDir(), "PACK.DBF"), []string{"charlie", "alpha", "bravo"})
+		defer file.Close()
+		err := file.CreateIndex("NAME", "UPPER(NAME)", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		row, err := file.Row()
+		if err != nil {
+			t.Fatal(err)
+		}
+		row.Deleted = true
+		err = row.Write()
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = file.Pack(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		report, err := file.CheckCompanions()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if file.RowsCount() != 2 || report.IndexTags != 1 || len(report.Issues) > 0 {
+			t.Errorf("%d rows and %d tags after packing, index issues: %v", file.RowsCount(), report.IndexTags, report.Issues)
+		}
+	})
+	t.Run("structural", func(t *testing.T) {
+		path := copyCorpus(t, "employees.dbf", "employees.FPT", "employees.CDX")
+		file, err := OpenTable(&Config{Filename: path})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		rows := file.RowsCount()
+		err = file.Pack(nil)
+		if !errors.Is(err, ErrStructuralIndex) {
+			t.Fatalf("expected ErrStructuralIndex, got %v", err)
+		}
+		if file.RowsCount() != rows {
+			t.Errorf("%d rows after the rejected pack, expected %d", file.RowsCount(), rows)
+		}
+	})
+}
@@ -0,0 +1,417 @@
+package dbase
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PipelineTablePlaceholder is replaced by the table name in Pipeline.Target
+const PipelineTablePlaceholder = "{table}"
+
+// Pipeline is a declarative conversion of tables to an export format, executed by RunPipeline.
+// It is usually read from a JSON or YAML file with LoadPipeline, so recurring conversions are configuration instead of programs.
+type Pipeline struct {
+	Tables      []string               `json:"tables"`       // Paths of the source tables
+	CodePage    byte                   `json:"code_page"`    // Code page mark of the tables, 0 interprets the code page mark of each table
+	TrimSpaces  bool                   `json:"trim_spaces"`  // Trim spaces from text values
+	SkipDeleted bool                   `json:"skip_deleted"` // Deleted rows are not exported
+	Filter      map[string]interface{} `json:"filter"`       // Values the exported rows contain, compared like RowFilter.Equals
+	Where       []*PipelineCondition   `json:"where"`        // Conditions the exported rows match, compared with the decoded values after Filter
+	Columns     []*PipelineColumn      `json:"columns"`      // Exported columns, all columns if empty. The columns are exported in the order of the table
+	Format      string                 `json:"format"`       // Registered export format, e.g. "csv" or "jsonl"
+	Target      string                 `json:"target"`       // Path of the output file, each table is written to its own file if it contains {table}
+}
+
+// PipelineColumn maps a column of the source tables to the output
+type PipelineColumn struct {
+	Name    string `json:"name"`    // Column name in the source tables
+	As      string `json:"as"`      // Key in the output, the column name if empty
+	Cast    string `json:"cast"`    // Type the value is cast to: string, int, float, bool, time or bytes, see CastType
+	Convert string `json:"convert"` // Conversion of text values: trim, upper or lower
+}
+
+// PipelineCondition compares a column of the source tables with a value.
+// Values of date columns are written as 2006-01-02, 2006-01-02 15:04:05 or RFC 3339, text is compared without trailing spaces.
+type PipelineCondition struct {
+	Column string      `json:"column"` // Column name in the source tables
+	Op     string      `json:"op"`     // Operator: =, !=, <, <=, >, >=, in, contains or prefix
+	Value  interface{} `json:"value"`  // Compared value, a list of values for in
+}
+
+// PipelineResult reports the rows exported by RunPipeline
+type PipelineResult struct {
+	Rows  map[string]uint32 `json:"rows"`  // Exported rows by table path
+	Files []string          `json:"files"` // Written output files
+}
+
+// pipelineCasts are the names of the cast types of PipelineColumn.Cast
+var pipelineCasts = map[string]CastType{
+	"":       CastNone,
+	"string": CastString,
+	"int":    CastInt,
+	"float":  CastFloat,
+	"bool":   CastBool,
+	"time":   CastTime,
+	"bytes":  CastBytes,
+}
+
+// pipelineConverters are the conversions of PipelineColumn.Convert
+var pipelineConverters = map[string]func(string) string{
+	"trim":  strings.TrimSpace,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// pipelineOperators are the operators of PipelineCondition.Op, called with the value of the row and the value of the condition
+var pipelineOperators = map[string]func(value, expected interface{}, column *Column) bool{
+	"=":  Equal,
+	"!=": func(value, expected interface{}, column *Column) bool { return !Equal(value, expected, column) },
+	"<":  pipelineOrder(func(order int) bool { return order < 0 }),
+	"<=": pipelineOrder(func(order int) bool { return order <= 0 }),
+	">":  pipelineOrder(func(order int) bool { return order > 0 }),
+	">=": pipelineOrder(func(order int) bool { return order >= 0 }),
+	"in": func(value, expected interface{}, column *Column) bool {
+		for _, item := range expected.([]interface{}) {
+			if Equal(value, item, column) {
+				return true
+			}
+		}
+		return false
+	},
+	"contains": func(value, expected interface{}, column *Column) bool {
+		return strings.Contains(trimText(value), trimText(expected))
+	},
+	"prefix": func(value, expected interface{}, column *Column) bool {
+		return strings.HasPrefix(trimText(value), trimText(expected))
+	},
+}
+
+// pipelineOrder returns an operator comparing the order of the values, values that can not be ordered do not match
+func pipelineOrder(accept func(order int) bool) func(value, expected interface{}, column *Column) bool {
+	return func(value, expected interface{}, column *Column) bool {
+		order, ok := compareValues(value, expected, column)
+		return ok && accept(order)
+	}
+}
+
+// pipelineOutput is an output file of a pipeline, written to a temporary file that replaces the target when finished
+type pipelineOutput struct {
+	path     string
+	handle   *os.File
+	exporter Exporter
+	keys     []string // Keys of the first table, the following tables written to the file must match them
+}
+
+// LoadPipeline reads a pipeline definition from a JSON file, or a YAML file if the extension is .yaml or .yml.
+// YAML definitions have the keys of JSON definitions, anchors, tags and block scalars are not supported.
+func LoadPipeline(path string) (*Pipeline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, newError("dbase-pipeline-loadpipeline-1", err)
+	}
+	if extension := strings.ToLower(filepath.Ext(path)); extension == ".yaml" || extension == ".yml" {
+		document, err := parseYAML(data)
+		if err != nil {
+			return nil, newError("dbase-pipeline-loadpipeline-3", fmt.Errorf("reading pipeline %v failed with error: %w", path, err))
+		}
+		// The YAML document is decoded like a JSON definition, so both have the same keys and types
+		data, err = json.Marshal(document)
+		if err != nil {
+			return nil, newError("dbase-pipeline-loadpipeline-4", err)
+		}
+	}
+	pipeline := &Pipeline{}
+	err = json.Unmarshal(data, pipeline)
+	if err != nil {
+		return nil, newError("dbase-pipeline-loadpipeline-2", fmt.Errorf("decoding pipeline %v failed with error: %w", path, err))
+	}
+	return pipeline, nil
+}
+
+// RunPipeline exports the rows of the source tables matching the filter with the mapped columns.
+// The tables are opened read-only. Without {table} in the target all tables are written to one file
+// and must have the same output columns. Output files are replaced only after they were written completely.
+func RunPipeline(pipeline *Pipeline) (*PipelineResult, error) {
+	err := pipeline.validate()
+	if err != nil {
+		return nil, newError("dbase-pipeline-runpipeline-1", err)
+	}
+	result := &PipelineResult{Rows: make(map[string]uint32), Files: make([]string, 0)}
+	perTable := strings.Contains(pipeline.Target, PipelineTablePlaceholder)
+	var output *pipelineOutput
+	for _, table := range pipeline.Tables {
+		file, err := pipeline.open(table)
+		if err != nil {
+			output.abort()
+			return nil, newError("dbase-pipeline-runpipeline-2", err)
+		}
+		schema := file.exportSchema()
+		if output == nil {
+			path := strings.ReplaceAll(pipeline.Target, PipelineTablePlaceholder, schema.Name)
+			output, err = newPipelineOutput(path, pipeline.Format)
+			if err != nil {
+				file.Close()
+				return nil, newError("dbase-pipeline-runpipeline-3", err)
+			}
+		}
+		err = output.begin(schema)
+		if err != nil {
+			file.Close()
+			output.abort()
+			return nil, newError("dbase-pipeline-runpipeline-4", fmt.Errorf("table %v: %w", table, err))
+		}
+		match, err := pipeline.match(file)
+		if err != nil {
+			file.Close()
+			output.abort()
+			return nil, newError("dbase-pipeline-runpipeline-7", fmt.Errorf("table %v: %w", table, err))
+		}
+		count, err := file.selectRows(&RowFilter{Equals: pipeline.Filter, Match: match}, 0, output.exporter.WriteRow)
+		file.Close()
+		if err != nil {
+			output.abort()
+			return nil, newError("dbase-pipeline-runpipeline-5", fmt.Errorf("exporting table %v failed with error: %w", table, err))
+		}
+		debugf("Pipeline exported %d rows of %v to %v", count, table, output.path)
+		result.Rows[table] = count
+		if perTable {
+			err = output.finish()
+			if err != nil {
+				return nil, newError("dbase-pipeline-runpipeline-6", err)
+			}
+			result.Files = append(result.Files, output.path)
+			output = nil
+		}
+	}
+	if output != nil {
+		err = output.finish()
+		if err != nil {
+			return nil, newError("dbase-pipeline-runpipeline-6", err)
+		}
+		result.Files = append(result.Files, output.path)
+	}
+	return result, nil
+}
+
+// validate checks the definition before any table is opened
+func (pipeline *Pipeline) validate() error {
+	if len(pipeline.Tables) == 0 {
+		return fmt.Errorf("the pipeline has no tables")
+	}
+	if len(pipeline.Target) == 0 {
+		return fmt.Errorf("the pipeline has no target")
+	}
+	found := false
+	for _, name := range Exporters() {
+		if name == strings.ToLower(strings.TrimSpace(pipeline.Format)) {
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("no exporter registered for format '%s'", pipeline.Format)
+	}
+	for _, column := range pipeline.Columns {
+		if _, ok := pipelineCasts[strings.ToLower(column.Cast)]; !ok {
+			return fmt.Errorf("unknown cast '%s' of column %s", column.Cast, column.Name)
+		}
+		if _, ok := pipelineConverters[strings.ToLower(column.Convert)]; !ok && len(column.Convert) > 0 {
+			return fmt.Errorf("unknown conversion '%s' of column %s", column.Convert, column.Name)
+		}
+	}
+	for _, condition := range pipeline.Where {
+		if _, ok := pipelineOperators[strings.ToLower(condition.Op)]; !ok {
+			return fmt.Errorf("unknown operator '%s' of the condition on column %s", condition.Op, condition.Column)
+		}
+		if _, ok := condition.Value.([]interface{}); ok != strings.EqualFold(condition.Op, "in") {
+			return fmt.Errorf("the condition on column %s expects a list of values only for the operator in", condition.Column)
+		}
+	}
+	return nil
+}
+
+// match returns the RowFilter.Match of the conditions for the columns of the table, nil without conditions
+func (pipeline *Pipeline) match(file *File) (func(row *Row) bool, error) {
+	if len(pipeline.Where) == 0 {
+		return nil, nil
+	}
+	type compiled struct {
+		position int
+		column   *Column
+		operator func(value, expected interface{}, column *Column) bool
+		value    interface{}
+	}
+	conditions := make([]compiled, 0, len(pipeline.Where))
+	for _, condition := range pipeline.Where {
+		position := file.ColumnPosByName(strings.ToUpper(condition.Column))
+		if position < 0 {
+			return nil, fmt.Errorf("column '%s' of the condition not found", condition.Column)
+		}
+		column := file.table.columns[position]
+		value, err := pipelineValue(column, condition.Value)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, compiled{position: position, column: column, operator: pipelineOperators[strings.ToLower(condition.Op)], value: value})
+	}
+	return func(row *Row) bool {
+		for _, condition := range conditions {
+			if !condition.operator(row.Value(condition.position), condition.value, condition.column) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// pipelineValue converts text values of a condition on date and numeric columns, lists are converted item by item
+func pipelineValue(column *Column, value interface{}) (interface{}, error) {
+	if items, ok := value.([]interface{}); ok {
+		converted := make([]interface{}, len(items))
+		for i, item := range items {
+			v, err := pipelineValue(column, item)
+			if err != nil {
+				return nil, err
+			}
+			converted[i] = v
+		}
+		return converted, nil
+	}
+	text, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+	switch DataType(column.DataType) {
+	case Date, DateTime:
+		for _, layout := range []string{"2006-01-02", "2006-01-02 15:04:05", time.RFC3339} {
+			t, err := time.Parse(layout, text)
+			if err == nil {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("column %s expects a date like 2006-01-02 instead of %q", column.Name(), text)
+	case Numeric, Float, Double, Currency, Integer:
+		number, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil {
+			return nil, fmt.Errorf("column %s expects a number instead of %q", column.Name(), text)
+		}
+		return number, nil
+	}
+	return value, nil
+}
+
+// open opens the table read-only and applies the column mapping
+func (pipeline *Pipeline) open(path string) (*File, error) {
+	config := &Config{
+		Filename:          path,
+		ReadOnly:          true,
+		TrimSpaces:        pipeline.TrimSpaces,
+		IgnoreDeleted:     pipeline.SkipDeleted,
+		InterpretCodePage: pipeline.CodePage == 0,
+	}
+	if pipeline.CodePage != 0 {
+		config.Converter = ConverterFromCodePage(pipeline.CodePage)
+	}
+	if len(pipeline.Columns) > 0 {
+		config.Schema = make(map[string]CastType)
+	}
+	file, err := OpenTable(config)
+	if err != nil {
+		return nil, err
+	}
+	if len(pipeline.Columns) == 0 {
+		return file, nil
+	}
+	mapped := make(map[string]bool)
+	for _, column := range pipeline.Columns {
+		name := strings.ToUpper(column.Name)
+		mod := &Modification{ExternalKey: column.As}
+		if convert, ok := pipelineConverters[strings.ToLower(column.Convert)]; ok {
+			mod.Convert = func(value interface{}) (interface{}, error) {
+				if text, ok := value.(string); ok {
+					return convert(text), nil
+				}
+				return value, nil
+			}
+		}
+		err = file.SetColumnModificationByName(name, mod)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("table %v: %w", path, err)
+		}
+		config.Schema[name] = pipelineCasts[strings.ToLower(column.Cast)]
+		mapped[name] = true
+	}
+	hidden := make([]string, 0)
+	for _, name := range file.ColumnNames() {
+		if !mapped[name] {
+			hidden = append(hidden, name)
+		}
+	}
+	err = file.HideColumns(hidden...)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+// newPipelineOutput creates the temporary file of the output
+func newPipelineOutput(path string, format string) (*pipelineOutput, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return nil, err
+	}
+	handle, err := os.OpenFile(path+".tmp", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	exporter, err := NewExporter(format, handle)
+	if err != nil {
+		handle.Close()
+		os.Remove(handle.Name())
+		return nil, err
+	}
+	return &pipelineOutput{path: path, handle: handle, exporter: exporter}, nil
+}
+
+// begin starts the output with the schema of the first table and checks that the following tables have the same keys
+func (output *pipelineOutput) begin(schema *ExportSchema) error {
+	if output.keys == nil {
+		output.keys = schema.Keys
+		return output.exporter.Begin(schema)
+	}
+	if strings.Join(schema.Keys, ",") != strings.Join(output.keys, ",") {
+		return fmt.Errorf("%w, output columns %v do not match the columns %v of the first table", ErrSchemaMismatch, schema.Keys, output.keys)
+	}
+	return nil
+}
+
+// finish ends the export and replaces the target with the written file
+func (output *pipelineOutput) finish() error {
+	err := output.exporter.End()
+	if err == nil {
+		err = output.handle.Sync()
+	}
+	if closeErr := output.handle.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(output.handle.Name())
+		return err
+	}
+	return os.Rename(output.handle.Name(), output.path)
+}
+
+// abort removes the temporary file of an output that was not finished
+func (output *pipelineOutput) abort() {
+	if output == nil {
+		return
+	}
+	output.handle.Close()
+	os.Remove(output.handle.Name())
+}
@@ -0,0 +1,181 @@
+package dbase
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestLoadPipeline reads the same definition from a JSON and a YAML file
+func TestLoadPipeline(t *testing.T) {
+	dir := t.TempDir()
+	definitions := map[string]string{
+		"pipeline.json": `{
+  "tables": ["data/employees.dbf", "data/former employees.dbf"],
+  "code_page": 3,
+  "trim_spaces": true,
+  "filter": {"COUNTRY": "USA"},
+  "where": [
+    {"column": "HIREDATE", "op": ">=", "value": "1993-01-01"},
+    {"column": "TITLE", "op": "in", "value": ["Salesperson", "Sales Manager"]}
+  ],
+  "columns": [
+    {"name": "EMPLOYEEID", "as": "id", "cast": "string"},
+    {"name": "LASTNAME", "as": "last_name", "convert": "upper"}
+  ],
+  "format": "jsonl",
+  "target": "export/{table}.jsonl"
+}`,
+		"pipeline.yaml": `---
+# Employees in the USA hired since 1993
+tables:
+- data/employees.dbf
+- "data/former employees.dbf" # quoted because of the space
+code_page: 3
+trim_spaces: true
+filter: {COUNTRY: USA}
+where:
+  - column: HIREDATE
+    op: ">="
+    value: 1993-01-01
+  - {column: TITLE, op: in, value: [Salesperson, 'Sales Manager']}
+columns:
+  - name: EMPLOYEEID
+    as: id
+    cast: string
+  - name: LASTNAME
+    as: last_name
+    convert: upper
+format: jsonl
+target: 'export/{table}.jsonl'
+`,
+	}
+	pipelines := make([]*Pipeline, 0, len(definitions))
+	for _, name := range []string{"pipeline.json", "pipeline.yaml"} {
+		path := filepath.Join(dir, name)
+		err := os.WriteFile(path, []byte(definitions[name]), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pipeline, err := LoadPipeline(path)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		pipelines = append(pipelines, pipeline)
+	}
+	if !reflect.DeepEqual(pipelines[0], pipelines[1]) {
+		t.Errorf("the YAML definition %+v differs from the JSON definition %+v", pipelines[1], pipelines[0])
+	}
+
+	invalid := map[string]string{
+		"tabs":          "tables:\n\t- data/employees.dbf\n",
+		"block scalar":  "target: |\n  export.csv\n",
+		"unclosed":      "target: \"export.csv\n",
+		"indentation":   "format: csv\n  target: export.csv\n",
+		"duplicate key": "format: csv\nformat: jsonl\n",
+	}
+	for name, definition := range invalid {
+		path := filepath.Join(dir, "invalid.yml")
+		err := os.WriteFile(path, []byte(definition), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = LoadPipeline(path)
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+// TestPipelineWhere exports the rows matching the conditions of the pipeline
+func TestPipelineWhere(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "WHERE.DBF")
+	name, err := NewColumn("NAME", Character, 10, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count, err := NewColumn("COUNT", Integer, 4, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	date, err := NewColumn("DATE", Date, 8, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := CreateTable(&Config{Filename: path}, name, count, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := []struct {
+		name  string
+		count int32
+		date  time.Time
+	}{
+		{"anna", 1, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"bob", 5, time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{"carl", 10, time.Date(2022, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"anne", 7, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, r := range rows {
+		row := file.NewRow()
+		for column, value := range map[string]interface{}{"NAME": r.name, "COUNT": r.count, "DATE": r.date} {
+			err = row.FieldByName(column).SetValue(value)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		err = row.Add()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = file.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		where []*PipelineCondition
+		rows  uint32
+		fails bool
+	}{
+		{"greater or equal", []*PipelineCondition{{"COUNT", ">=", float64(5)}}, 3, false},
+		{"date before", []*PipelineCondition{{"DATE", "<", "2021-01-01"}}, 1, false},
+		{"date equal", []*PipelineCondition{{"date", "=", "2021-01-01"}}, 1, false},
+		{"number as text", []*PipelineCondition{{"COUNT", ">", "5"}}, 2, false},
+		{"in", []*PipelineCondition{{"NAME", "IN", []interface{}{"bob", "carl", "dora"}}}, 2, false},
+		{"prefix", []*PipelineCondition{{"NAME", "prefix", "ann"}}, 2, false},
+		{"contains", []*PipelineCondition{{"NAME", "contains", "o"}}, 1, false},
+		{"all conditions", []*PipelineCondition{{"NAME", "!=", "bob"}, {"COUNT", "<", float64(10)}}, 2, false},
+		{"unknown operator", []*PipelineCondition{{"COUNT", "~", float64(1)}}, 0, true},
+		{"in without list", []*PipelineCondition{{"NAME", "in", "bob"}}, 0, true},
+		{"list without in", []*PipelineCondition{{"NAME", "=", []interface{}{"bob"}}}, 0, true},
+		{"invalid date", []*PipelineCondition{{"DATE", ">", "soon"}}, 0, true},
+		{"unknown column", []*PipelineCondition{{"MISSING", "=", "bob"}}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := filepath.Join(t.TempDir(), "where.jsonl")
+			result, err := RunPipeline(&Pipeline{Tables: []string{path}, TrimSpaces: true, Where: tt.where, Format: "jsonl", Target: target})
+			if tt.fails {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				if _, err := os.Stat(target); !os.IsNotExist(err) {
+					t.Errorf("expected no output file after the error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Rows[path] != tt.rows {
+				t.Errorf("exported %d rows, expected %d", result.Rows[path], tt.rows)
+			}
+		})
+	}
+}
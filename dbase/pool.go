@@ -0,0 +1,205 @@
+package dbase
+
+import (
+	"container/list"
+	"fmt"
+	"path/filepath"
+	"sync"
+)
+
+// TablePool holds many tables opened with the same configuration and limits the number of open file handles.
+// If more tables are used than handles are allowed, the handles of the least recently used table are closed
+// and the table is reopened with Reopen on its next use, keeping modifications and column settings.
+// The tables share the converter, the schema cache and the row cache of the configuration, so the cached rows
+// of all tables are bounded together. Tables are only accessed with Use, which keeps their handles open.
+type TablePool struct {
+	mutex   *sync.Mutex             // Mutex to protect the pool
+	maxOpen int                     // Maximum number of tables with open handles (0 = unlimited)
+	tables  map[string]*pooledTable // Tables by path
+	paths   []string                // Paths in the order they were opened
+	open    *list.List              // Tables with open handles ordered by last use, most recent first
+	reopens uint64                  // Number of tables reopened after their handles were closed
+}
+
+// TablePoolStats contains the counters of the table pool
+type TablePoolStats struct {
+	Tables  int    // Number of tables in the pool
+	Open    int    // Number of tables with open handles
+	Reopens uint64 // Number of tables reopened after their handles were closed
+}
+
+// pooledTable is a table of the pool
+type pooledTable struct {
+	path    string
+	file    *File
+	element *list.Element // Element in the list of open tables, nil if the handles are closed
+	users   int           // Number of running Use calls, the handles are not closed while the table is used
+}
+
+// OpenAll opens the tables at the paths with the configuration, the file name of the configuration is ignored.
+// At most maxOpen tables keep their file handles open (0 = unlimited), see TablePool.
+// If the configuration has no schema cache, a cache shared by the tables is created.
+// Set Config.RowCache to a cache created with NewRowCache to share a bounded cache of decoded rows.
+// Only works with the default IO implementation, as closed tables are reopened by path.
+func OpenAll(paths []string, config *Config, maxOpen int) (*TablePool, error) {
+	if config == nil {
+		return nil, newError("dbase-pool-openall-1", fmt.Errorf("missing config"))
+	}
+	if !defaultIO(config.IO) {
+		return nil, newError("dbase-pool-openall-2", fmt.Errorf("%w, tables with IO of type %T can not be reopened by path", ErrUnsupported, config.IO))
+	}
+	shared := *config
+	if shared.SchemaCache == nil {
+		shared.SchemaCache = NewSchemaCache(0)
+	}
+	pool := &TablePool{
+		mutex:   &sync.Mutex{},
+		maxOpen: maxOpen,
+		tables:  make(map[string]*pooledTable, len(paths)),
+		paths:   make([]string, 0, len(paths)),
+		open:    list.New(),
+	}
+	for _, path := range paths {
+		path = filepath.Clean(path)
+		if _, ok := pool.tables[path]; ok {
+			continue
+		}
+		tableConfig := shared
+		tableConfig.Filename = path
+		file, err := OpenTable(&tableConfig)
+		if err != nil {
+			pool.Close()
+			return nil, newError("dbase-pool-openall-3", fmt.Errorf("opening table %s failed with error: %w", path, err))
+		}
+		table := &pooledTable{path: path, file: file}
+		table.element = pool.open.PushFront(table)
+		pool.tables[path] = table
+		pool.paths = append(pool.paths, path)
+		err = pool.evict()
+		if err != nil {
+			pool.Close()
+			return nil, newError("dbase-pool-openall-4", err)
+		}
+	}
+	debugf("Opened %d tables, %d with open handles", len(pool.paths), pool.open.Len())
+	return pool, nil
+}
+
+// Paths returns the paths of the tables in the order they were opened
+func (p *TablePool) Paths() []string {
+	return append([]string(nil), p.paths...)
+}
+
+// Use calls fn with the table at the path, the handles of the table are kept open until fn returns.
+// The table must not be used after fn returned, as its handles may be closed by the next use of another table.
+// If all tables are in use, the limit of open handles is exceeded until the tables are released.
+func (p *TablePool) Use(path string, fn func(file *File) error) error {
+	p.mutex.Lock()
+	table, err := p.acquire(path)
+	if err != nil {
+		p.mutex.Unlock()
+		return newError("dbase-pool-use-1", err)
+	}
+	table.users++
+	p.mutex.Unlock()
+	defer func() {
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+		table.users--
+		err := p.evict()
+		if err != nil {
+			debugf("Closing least recently used table failed with error: %v", err)
+		}
+	}()
+	return fn(table.file)
+}
+
+// Stats returns the current counters of the pool
+func (p *TablePool) Stats() TablePoolStats {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return TablePoolStats{
+		Tables:  len(p.tables),
+		Open:    p.open.Len(),
+		Reopens: p.reopens,
+	}
+}
+
+// Close closes the handles of all tables, the pool can not be used afterwards
+func (p *TablePool) Close() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	var first error
+	for element := p.open.Front(); element != nil; element = element.Next() {
+		err := element.Value.(*pooledTable).file.Close()
+		if err != nil && first == nil {
+			first = newError("dbase-pool-close-1", err)
+		}
+	}
+	p.open.Init()
+	p.tables = make(map[string]*pooledTable)
+	p.paths = nil
+	return first
+}
+
+// acquire returns the table at the path, reopens it if its handles were closed and marks it as most recently used
+func (p *TablePool) acquire(path string) (*pooledTable, error) {
+	table, ok := p.tables[filepath.Clean(path)]
+	if !ok {
+		return nil, fmt.Errorf("table %s is not part of the pool", path)
+	}
+	if table.element != nil {
+		p.open.MoveToFront(table.element)
+		return table, nil
+	}
+	debugf("Reopening pooled table %s", table.path)
+	err := table.file.Reopen()
+	if err != nil {
+		return nil, fmt.Errorf("reopening table %s failed with error: %w", table.path, err)
+	}
+	p.reopens++
+	table.element = p.open.PushFront(table)
+	err = p.evict()
+	if err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+// evict closes the handles of the least recently used tables that are not in use until the limit is reached
+func (p *TablePool) evict() error {
+	if p.maxOpen <= 0 {
+		return nil
+	}
+	element := p.open.Back()
+	for p.open.Len() > p.maxOpen && element != nil {
+		table := element.Value.(*pooledTable)
+		previous := element.Prev()
+		// The most recently used table is never closed, it is about to be returned
+		if table.users == 0 && element != p.open.Front() {
+			debugf("Closing handles of least recently used table %s", table.path)
+			err := table.file.suspend()
+			if err != nil {
+				return fmt.Errorf("closing table %s failed with error: %w", table.path, err)
+			}
+			p.open.Remove(element)
+			table.element = nil
+		}
+		element = previous
+	}
+	return nil
+}
+
+// suspend closes the handles of the table, Reopen opens them again
+func (file *File) suspend() error {
+	file.dbaseMutex.Lock()
+	file.memoMutex.Lock()
+	defer file.dbaseMutex.Unlock()
+	defer file.memoMutex.Unlock()
+	err := file.defaults().io.Close(file)
+	if err != nil {
+		return newError("dbase-pool-suspend-1", err)
+	}
+	file.handle, file.relatedHandle = nil, nil
+	return nil
+}
@@ -0,0 +1,105 @@
+package dbase
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestTablePool uses more tables than handles are allowed and shares the row cache between the tables
+func TestTablePool(t *testing.T) {
+	dir := t.TempDir()
+	paths := make([]string, 0, 3)
+	for i := 0; i < 3; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("POOL%d.DBF", i))
+		file := createSortTable(t, path, []string{"first", "second"})
+		file.Close()
+		paths = append(paths, path)
+	}
+	cache := NewRowCache(4, 0)
+	pool, err := OpenAll(paths, &Config{ReadOnly: true, TrimSpaces: true, RowCache: cache}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	read := func(path string) {
+		err := pool.Use(path, func(file *File) error {
+			for position := uint32(0); position < file.RowsCount(); position++ {
+				err := file.GoTo(position)
+				if err != nil {
+					return err
+				}
+				_, err = file.Row()
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, path := range paths {
+		read(path)
+	}
+	stats := pool.Stats()
+	if stats.Open != 1 || stats.Reopens == 0 {
+		t.Errorf("%d tables open and %d reopened, expected one open table", stats.Open, stats.Reopens)
+	}
+	// The rows of all tables are limited together, the rows of the first table were evicted by the later tables
+	if cached := cache.Stats(); cached.Rows != 4 || cached.Misses != 6 {
+		t.Errorf("%d rows cached after %d misses, expected 4 rows after 6 misses", cached.Rows, cached.Misses)
+	}
+	read(paths[2])
+	if cached := cache.Stats(); cached.Hits != 2 {
+		t.Errorf("%d cache hits reading the last table again, expected 2", cached.Hits)
+	}
+	// The first table is reopened, which invalidates its rows as the file may have changed while it was closed
+	read(paths[0])
+	if cached := cache.Stats(); cached.Hits != 2 || cached.Rows != 4 {
+		t.Errorf("%d cache hits and %d rows reading the first table again, expected its rows to be read again", cached.Hits, cached.Rows)
+	}
+}
+
+// TestTablePoolIO rejects IO implementations that can not reopen tables by path
+func TestTablePoolIO(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "POOL.DBF")
+	file := createSortTable(t, path, []string{"first"})
+	file.Close()
+	handle, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer handle.Close()
+	implementations := []struct {
+		name string
+		io   IO
+		ok   bool
+	}{
+		{"nil", nil, true},
+		{"default", DefaultIO, true},
+		{"default pointer", &DefaultIO, true},
+		{"generic", GenericIO{Handle: handle}, false},
+		{"generic pointer", &GenericIO{Handle: handle}, false},
+		{"custom", plainIO{DefaultIO}, false},
+	}
+	for _, implementation := range implementations {
+		t.Run(implementation.name, func(t *testing.T) {
+			pool, err := OpenAll([]string{path}, &Config{ReadOnly: true, IO: implementation.io}, 1)
+			if implementation.ok {
+				if err != nil {
+					t.Fatal(err)
+				}
+				pool.Close()
+				return
+			}
+			if !errors.Is(err, ErrUnsupported) {
+				t.Fatalf("expected ErrUnsupported for IO of type %T, got %v", implementation.io, err)
+			}
+		})
+	}
+}
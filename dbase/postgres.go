@@ -0,0 +1,371 @@
+package dbase
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// postgresCopySignature starts the binary COPY format
+var postgresCopySignature = []byte("PGCOPY\n\xff\r\n\x00")
+
+// postgresEpoch is the epoch of binary dates and timestamps
+var postgresEpoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// PostgresCopyOptions configures ToPostgresCopy
+type PostgresCopyOptions struct {
+	Binary      bool // Write the binary COPY format instead of the text format, requires the column types of ToPostgresCopy
+	SkipDeleted bool // Leave out deleted rows
+}
+
+// ToPostgresCopy streams the rows in the format of the PostgreSQL COPY command, so they can be piped into
+// COPY table FROM STDIN (psql \copy) or the CopyFrom of pgconn. The columns are written in the order of the export,
+// hidden columns are left out. Empty dates and nil values (see NilPolicy) are NULL.
+//
+// The text format escapes backslashes, tabs and line breaks, dates are written as 2006-01-02, date times as
+// 2006-01-02 15:04:05.000 in UTC and binary values as bytea in hex format. With Binary set the values are
+// encoded for these column types, which the target table must use:
+// I integer, N without decimals bigint, N with decimals and Y numeric, F and B double precision, L boolean,
+// D date, T timestamp, C, V and M text and Q, W, G and P bytea.
+func ToPostgresCopy(file *File, w io.Writer, opts *PostgresCopyOptions) error {
+	if opts == nil {
+		opts = &PostgresCopyOptions{}
+	}
+	err := file.Export(newPostgresCopyExporter(w, opts.Binary), opts.SkipDeleted)
+	if err != nil {
+		return newError("dbase-postgres-topostgrescopy-1", err)
+	}
+	return nil
+}
+
+// postgresCopyExporter writes the rows in the text or binary COPY format
+type postgresCopyExporter struct {
+	writer  *bufio.Writer
+	binary  bool
+	columns map[string]*Column
+	buffer  []byte
+}
+
+func newPostgresCopyExporter(w io.Writer, binary bool) *postgresCopyExporter {
+	return &postgresCopyExporter{writer: bufio.NewWriter(w), binary: binary}
+}
+
+func (e *postgresCopyExporter) Begin(schema *ExportSchema) error {
+	e.columns = make(map[string]*Column, len(schema.Columns))
+	for i, column := range schema.Columns {
+		e.columns[schema.Keys[i]] = column
+	}
+	if !e.binary {
+		return nil
+	}
+	// The signature is followed by the flags and the length of the header extension
+	header := append([]byte{}, postgresCopySignature...)
+	header = binary.BigEndian.AppendUint32(header, 0)
+	header = binary.BigEndian.AppendUint32(header, 0)
+	_, err := e.writer.Write(header)
+	return err
+}
+
+func (e *postgresCopyExporter) WriteRow(row *Row) error {
+	values, err := row.ExportValues()
+	if err != nil {
+		return err
+	}
+	e.buffer = e.buffer[:0]
+	if e.binary {
+		e.buffer = binary.BigEndian.AppendUint16(e.buffer, uint16(len(values)))
+	}
+	for i, value := range values {
+		column, ok := e.columns[value.Key]
+		if !ok {
+			return fmt.Errorf("key %s is not part of the exported columns", value.Key)
+		}
+		if e.binary {
+			e.buffer, err = appendPostgresBinary(e.buffer, column, value.Value)
+		} else {
+			if i > 0 {
+				e.buffer = append(e.buffer, '\t')
+			}
+			e.buffer, err = appendPostgresText(e.buffer, column, value.Value)
+		}
+		if err != nil {
+			return fmt.Errorf("encoding %s failed with error: %w", value.Key, err)
+		}
+	}
+	if !e.binary {
+		e.buffer = append(e.buffer, '\n')
+	}
+	_, err = e.writer.Write(e.buffer)
+	return err
+}
+
+func (e *postgresCopyExporter) End() error {
+	if e.binary {
+		// The trailer is a field count of -1
+		_, err := e.writer.Write([]byte{0xFF, 0xFF})
+		if err != nil {
+			return err
+		}
+	}
+	return e.writer.Flush()
+}
+
+// appendPostgresText appends the value in the text COPY format, \N for NULL
+func appendPostgresText(buf []byte, column *Column, val interface{}) ([]byte, error) {
+	if t, ok := val.(time.Time); val == nil || (ok && t.IsZero()) {
+		return append(buf, `\N`...), nil
+	}
+	switch DataType(column.DataType) {
+	case Date:
+		if t, ok := val.(time.Time); ok {
+			return t.AppendFormat(buf, "2006-01-02"), nil
+		}
+	case DateTime:
+		if t, ok := val.(time.Time); ok {
+			return t.UTC().AppendFormat(buf, "2006-01-02 15:04:05.000"), nil
+		}
+	case Integer, Numeric, Float, Currency, Double:
+		text, err := postgresNumber(column, val)
+		if err != nil {
+			return nil, err
+		}
+		return append(buf, text...), nil
+	case Logical:
+		if b, ok := val.(bool); ok {
+			if b {
+				return append(buf, 't'), nil
+			}
+			return append(buf, 'f'), nil
+		}
+	case Varbinary, Blob, General, Picture:
+		if data, ok := val.([]byte); ok {
+			// The backslash of the hex format is escaped
+			buf = append(buf, `\\x`...)
+			return append(buf, hex.EncodeToString(data)...), nil
+		}
+	}
+	text, err := postgresString(column, val)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; c {
+		case '\\':
+			buf = append(buf, `\\`...)
+		case '\n':
+			buf = append(buf, `\n`...)
+		case '\r':
+			buf = append(buf, `\r`...)
+		case '\t':
+			buf = append(buf, `\t`...)
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return buf, nil
+}
+
+// appendPostgresBinary appends the length and the value in the binary COPY format, a length of -1 for NULL
+func appendPostgresBinary(buf []byte, column *Column, val interface{}) ([]byte, error) {
+	if t, ok := val.(time.Time); val == nil || (ok && t.IsZero()) {
+		return binary.BigEndian.AppendUint32(buf, math.MaxUint32), nil
+	}
+	switch DataType(column.DataType) {
+	case Integer:
+		i, err := exportInt(val)
+		if err != nil {
+			return nil, err
+		}
+		if i < math.MinInt32 || i > math.MaxInt32 {
+			return nil, fmt.Errorf("value %d overflows integer", i)
+		}
+		buf = binary.BigEndian.AppendUint32(buf, 4)
+		return binary.BigEndian.AppendUint32(buf, uint32(int32(i))), nil
+	case Numeric, Currency:
+		if DataType(column.DataType) == Numeric && column.Decimals == 0 {
+			i, err := exportInt(val)
+			if err != nil {
+				return nil, err
+			}
+			buf = binary.BigEndian.AppendUint32(buf, 8)
+			return binary.BigEndian.AppendUint64(buf, uint64(i)), nil
+		}
+		text, err := postgresNumber(column, val)
+		if err != nil {
+			return nil, err
+		}
+		numeric, err := postgresNumeric(text)
+		if err != nil {
+			return nil, err
+		}
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(numeric)))
+		return append(buf, numeric...), nil
+	case Float, Double:
+		f, err := exportFloat(val)
+		if err != nil {
+			return nil, err
+		}
+		buf = binary.BigEndian.AppendUint32(buf, 8)
+		return binary.BigEndian.AppendUint64(buf, math.Float64bits(f)), nil
+	case Logical:
+		b, ok := val.(bool)
+		if !ok {
+			return nil, fmt.Errorf("invalid type %T for boolean", val)
+		}
+		buf = binary.BigEndian.AppendUint32(buf, 1)
+		if b {
+			return append(buf, 1), nil
+		}
+		return append(buf, 0), nil
+	case Date:
+		t, ok := val.(time.Time)
+		if !ok {
+			return nil, fmt.Errorf("invalid type %T for date", val)
+		}
+		// Days since 2000-01-01, the day is taken as it is
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		buf = binary.BigEndian.AppendUint32(buf, 4)
+		return binary.BigEndian.AppendUint32(buf, uint32(int32((day.Unix()-postgresEpoch.Unix())/86400))), nil
+	case DateTime:
+		t, ok := val.(time.Time)
+		if !ok {
+			return nil, fmt.Errorf("invalid type %T for timestamp", val)
+		}
+		// Microseconds since 2000-01-01 00:00:00
+		micros := (t.Unix()-postgresEpoch.Unix())*1e6 + int64(t.Nanosecond()/1e3)
+		buf = binary.BigEndian.AppendUint32(buf, 8)
+		return binary.BigEndian.AppendUint64(buf, uint64(micros)), nil
+	case Varbinary, Blob, General, Picture:
+		if data, ok := val.([]byte); ok {
+			buf = binary.BigEndian.AppendUint32(buf, uint32(len(data)))
+			return append(buf, data...), nil
+		}
+	}
+	text, err := postgresString(column, val)
+	if err != nil {
+		return nil, err
+	}
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(text)))
+	return append(buf, text...), nil
+}
+
+// postgresString returns the value of a text column, which must be valid UTF-8 without null characters.
+// Values changed by modifications are converted to text.
+func postgresString(column *Column, val interface{}) (string, error) {
+	var text string
+	switch v := val.(type) {
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	default:
+		text = formatValue(v)
+	}
+	if !utf8.ValidString(text) {
+		return "", fmt.Errorf("invalid UTF-8 in column %s", column.Name())
+	}
+	if strings.IndexByte(text, 0) >= 0 {
+		return "", fmt.Errorf("null character in column %s, which text columns can not contain", column.Name())
+	}
+	return text, nil
+}
+
+// postgresNumber returns the number as text with the decimals of the column and without exponent
+func postgresNumber(column *Column, val interface{}) (string, error) {
+	decimals := int(column.Decimals)
+	switch DataType(column.DataType) {
+	case Currency:
+		decimals = 4
+	case Double:
+		decimals = -1
+	}
+	switch v := val.(type) {
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			return "NaN", nil
+		case math.IsInf(v, 1):
+			return "Infinity", nil
+		case math.IsInf(v, -1):
+			return "-Infinity", nil
+		}
+		return strconv.FormatFloat(v, 'f', decimals, 64), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case json.Number:
+		return v.String(), nil
+	case string:
+		_, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(v), nil
+	}
+	return "", fmt.Errorf("invalid type %T for number", val)
+}
+
+// postgresNumeric returns the decimal number in the binary format of numeric: the number of digits, the weight of the
+// first digit, the sign and the display scale followed by the digits in base 10000
+func postgresNumeric(text string) ([]byte, error) {
+	if text == "NaN" {
+		return []byte{0, 0, 0, 0, 0xC0, 0, 0, 0}, nil
+	}
+	sign := uint16(0)
+	if strings.HasPrefix(text, "-") {
+		sign = 0x4000
+		text = text[1:]
+	}
+	integer, fraction, _ := strings.Cut(text, ".")
+	for _, part := range []string{integer, fraction} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return nil, fmt.Errorf("invalid numeric %s", text)
+			}
+		}
+	}
+	scale := len(fraction)
+	// The digits are aligned at the decimal point in groups of four
+	if pad := len(integer) % 4; pad != 0 {
+		integer = strings.Repeat("0", 4-pad) + integer
+	}
+	if pad := len(fraction) % 4; pad != 0 {
+		fraction += strings.Repeat("0", 4-pad)
+	}
+	groups := integer + fraction
+	digits := make([]uint16, 0, len(groups)/4)
+	for i := 0; i < len(groups); i += 4 {
+		digit, _ := strconv.Atoi(groups[i : i+4])
+		digits = append(digits, uint16(digit))
+	}
+	weight := len(integer)/4 - 1
+	for len(digits) > 0 && digits[0] == 0 {
+		digits = digits[1:]
+		weight--
+	}
+	for len(digits) > 0 && digits[len(digits)-1] == 0 {
+		digits = digits[:len(digits)-1]
+	}
+	if len(digits) == 0 {
+		weight = 0
+		sign = 0
+	}
+	buf := make([]byte, 0, 8+2*len(digits))
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(digits)))
+	buf = binary.BigEndian.AppendUint16(buf, uint16(int16(weight)))
+	buf = binary.BigEndian.AppendUint16(buf, sign)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(scale))
+	for _, digit := range digits {
+		buf = binary.BigEndian.AppendUint16(buf, digit)
+	}
+	return buf, nil
+}
@@ -0,0 +1,133 @@
+package dbase
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Prefetcher reads rows ahead in a background goroutine, hiding disk latency for random access patterns
+// like reading rows in index order. Row positions are requested in advance and returned by Next in the same order.
+// While the prefetcher is running the file must not be used by other goroutines, as the file handles are shared.
+type Prefetcher struct {
+	file    *File              // The file the rows are read from
+	mutex   *sync.Mutex        // Mutex to protect the queue and pending counter
+	queue   []uint32           // Requested row positions not yet read
+	pending int                // Number of requested rows not yet returned by Next
+	signal  chan struct{}      // Signals the worker that new positions were requested
+	results chan prefetchedRow // Rows read ahead by the worker
+	done    chan struct{}      // Closed to stop the worker
+	once    *sync.Once         // Ensures the prefetcher is only closed once
+	wg      *sync.WaitGroup    // Waits for the worker to finish
+	pointer uint32             // Row pointer of the file before the prefetcher was started
+}
+
+// prefetchedRow is the result of a prefetched read
+type prefetchedRow struct {
+	row *Row
+	err error
+}
+
+// NewPrefetcher starts a prefetcher that reads up to lookahead rows ahead of the consumer.
+// The internal row pointer is restored when the prefetcher is closed.
+func (file *File) NewPrefetcher(lookahead int) *Prefetcher {
+	if lookahead < 1 {
+		lookahead = 1
+	}
+	debugf("Starting prefetcher with lookahead of %d rows", lookahead)
+	p := &Prefetcher{
+		file:    file,
+		mutex:   &sync.Mutex{},
+		queue:   make([]uint32, 0),
+		signal:  make(chan struct{}, 1),
+		results: make(chan prefetchedRow, lookahead),
+		done:    make(chan struct{}),
+		once:    &sync.Once{},
+		wg:      &sync.WaitGroup{},
+		pointer: file.table.rowPointer,
+	}
+	p.wg.Add(1)
+	go p.work()
+	return p
+}
+
+// Request queues the given row positions to be read ahead
+func (p *Prefetcher) Request(positions ...uint32) {
+	if len(positions) == 0 {
+		return
+	}
+	p.mutex.Lock()
+	p.queue = append(p.queue, positions...)
+	p.pending += len(positions)
+	p.mutex.Unlock()
+	select {
+	case p.signal <- struct{}{}:
+	default:
+	}
+}
+
+// Pending returns the number of requested rows not yet returned by Next
+func (p *Prefetcher) Pending() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.pending
+}
+
+// Next returns the next requested row, blocking until it has been read
+func (p *Prefetcher) Next() (*Row, error) {
+	p.mutex.Lock()
+	if p.pending == 0 {
+		p.mutex.Unlock()
+		return nil, newError("dbase-prefetch-next-1", fmt.Errorf("%w, no rows requested", ErrEOF))
+	}
+	p.pending--
+	p.mutex.Unlock()
+	select {
+	case result := <-p.results:
+		if result.err != nil {
+			return nil, newError("dbase-prefetch-next-2", result.err)
+		}
+		return result.row, nil
+	case <-p.done:
+		return nil, newError("dbase-prefetch-next-3", fmt.Errorf("prefetcher is closed"))
+	}
+}
+
+// Close stops the prefetcher and restores the internal row pointer of the file
+func (p *Prefetcher) Close() {
+	p.once.Do(func() {
+		debugf("Stopping prefetcher")
+		close(p.done)
+		p.wg.Wait()
+		p.file.table.rowPointer = p.pointer
+	})
+}
+
+// work reads the requested rows in order until the prefetcher is closed
+func (p *Prefetcher) work() {
+	defer p.wg.Done()
+	for {
+		p.mutex.Lock()
+		if len(p.queue) == 0 {
+			p.mutex.Unlock()
+			select {
+			case <-p.signal:
+				continue
+			case <-p.done:
+				return
+			}
+		}
+		position := p.queue[0]
+		p.queue = p.queue[1:]
+		p.mutex.Unlock()
+		result := prefetchedRow{}
+		result.err = p.file.GoTo(position)
+		if result.err == nil {
+			result.row, result.err = p.file.Row()
+		}
+		select {
+		case p.results <- result:
+		case <-p.done:
+			return
+		}
+	}
+}
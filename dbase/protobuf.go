@@ -0,0 +1,255 @@
+package dbase
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Scalar types of the generated protobuf fields
+const (
+	protoString    = "string"
+	protoBytes     = "bytes"
+	protoInt32     = "int32"
+	protoInt64     = "int64"
+	protoDouble    = "double"
+	protoBool      = "bool"
+	protoTimestamp = "google.protobuf.Timestamp"
+)
+
+// Wire types of the protobuf encoding
+const (
+	protoWireVarint  = 0
+	protoWireFixed64 = 1
+	protoWireBytes   = 2
+)
+
+// protoField is a field of the message generated for the exported columns
+type protoField struct {
+	name     string
+	kind     string
+	number   int
+	optional bool
+	column   *Column
+}
+
+// protoMessage is the message generated for the exported columns
+type protoMessage struct {
+	name   string
+	fields []*protoField
+	keys   map[string]*protoField
+}
+
+// newProtoMessage returns the message of the schema. The fields are numbered in column order starting at 1,
+// so the numbers stay the same as long as the exported columns do not change.
+func newProtoMessage(schema *ExportSchema) *protoMessage {
+	message := &protoMessage{
+		name:   schemaTypeName(schema.Name),
+		fields: make([]*protoField, 0, len(schema.Columns)),
+		keys:   make(map[string]*protoField, len(schema.Columns)),
+	}
+	names := schemaFieldNames(schema.Keys)
+	for i, column := range schema.Columns {
+		field := &protoField{
+			name:     names[i],
+			kind:     protoKind(column),
+			number:   i + 1,
+			optional: column.Flag&byte(NullableFlag) != 0,
+			column:   column,
+		}
+		message.fields = append(message.fields, field)
+		message.keys[schema.Keys[i]] = field
+	}
+	return message
+}
+
+// protoKind returns the protobuf type of the column
+func protoKind(column *Column) string {
+	switch DataType(column.DataType) {
+	case Integer:
+		return protoInt32
+	case Numeric:
+		if column.Decimals == 0 {
+			return protoInt64
+		}
+		return protoDouble
+	case Float, Double, Currency:
+		return protoDouble
+	case Logical:
+		return protoBool
+	case Date, DateTime:
+		return protoTimestamp
+	case Varbinary, Blob, General, Picture:
+		return protoBytes
+	case Memo:
+		// Memos of binary columns (NOCPTRANS) hold data that is not text
+		if column.Flag&byte(BinaryFlag) != 0 {
+			return protoBytes
+		}
+	}
+	return protoString
+}
+
+// ProtoSchema returns a proto3 definition of a message with the exported columns of the table, matching the records
+// written by the "protobuf" exporter. The package is left out if pkg is empty.
+// Dates and times are google.protobuf.Timestamp, nullable columns are optional fields and memos are strings,
+// except memos of binary columns which are bytes.
+// The fields are numbered in column order, adding or removing columns changes the numbers of the following fields.
+func (file *File) ProtoSchema(pkg string) string {
+	schema := file.exportSchema()
+	message := newProtoMessage(schema)
+	var builder strings.Builder
+	builder.WriteString("syntax = \"proto3\";\n\n")
+	if len(pkg) > 0 {
+		fmt.Fprintf(&builder, "package %s;\n\n", pkg)
+	}
+	for _, field := range message.fields {
+		if field.kind == protoTimestamp {
+			builder.WriteString("import \"google/protobuf/timestamp.proto\";\n\n")
+			break
+		}
+	}
+	fmt.Fprintf(&builder, "// %s was generated from the table %s\n", message.name, schema.Name)
+	fmt.Fprintf(&builder, "message %s {\n", message.name)
+	for _, field := range message.fields {
+		label := ""
+		if field.optional {
+			label = "optional "
+		}
+		fmt.Fprintf(&builder, "  %s%s %s = %d; // %s\n", label, field.kind, field.name, field.number, field.column)
+	}
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+// protobufExporter writes one length-delimited protobuf record per row: the length of the message as varint followed by the message,
+// as read by parseDelimitedFrom in Java or protodelim in Go. The message is defined by File.ProtoSchema.
+type protobufExporter struct {
+	writer  *bufio.Writer
+	message *protoMessage
+	buffer  []byte
+	record  []byte
+}
+
+func newProtobufExporter(w io.Writer) *protobufExporter {
+	return &protobufExporter{writer: bufio.NewWriter(w)}
+}
+
+func (e *protobufExporter) Begin(schema *ExportSchema) error {
+	e.message = newProtoMessage(schema)
+	return nil
+}
+
+func (e *protobufExporter) WriteRow(row *Row) error {
+	values, err := row.ExportValues()
+	if err != nil {
+		return err
+	}
+	e.buffer = e.buffer[:0]
+	for _, value := range values {
+		field, ok := e.message.keys[value.Key]
+		if !ok {
+			return fmt.Errorf("key %s is not part of the message %s", value.Key, e.message.name)
+		}
+		e.buffer, err = field.append(e.buffer, value.Value)
+		if err != nil {
+			return fmt.Errorf("encoding %s failed with error: %w", value.Key, err)
+		}
+	}
+	e.record = binary.AppendUvarint(e.record[:0], uint64(len(e.buffer)))
+	e.record = append(e.record, e.buffer...)
+	_, err = e.writer.Write(e.record)
+	return err
+}
+
+func (e *protobufExporter) End() error {
+	return e.writer.Flush()
+}
+
+// append appends the value encoded as the field, nil values and zero times are left out.
+// Values changed by modifications are converted to the type of the field if possible.
+// Binary memo blocks of text memo columns are written base64 encoded to string fields, like the JSON exporter does.
+func (field *protoField) append(buf []byte, val interface{}) ([]byte, error) {
+	if val == nil {
+		return buf, nil
+	}
+	switch field.kind {
+	case protoString, protoBytes:
+		var data []byte
+		switch v := val.(type) {
+		case string:
+			data = []byte(v)
+		case []byte:
+			data = v
+			if field.kind == protoString {
+				data = make([]byte, base64.StdEncoding.EncodedLen(len(v)))
+				base64.StdEncoding.Encode(data, v)
+			}
+		default:
+			data = []byte(formatValue(v))
+		}
+		if field.kind == protoString && !utf8.Valid(data) {
+			return nil, fmt.Errorf("invalid UTF-8 in string field %s", field.name)
+		}
+		buf = appendProtoTag(buf, field.number, protoWireBytes)
+		buf = binary.AppendUvarint(buf, uint64(len(data)))
+		return append(buf, data...), nil
+	case protoInt32, protoInt64:
+		i, err := exportInt(val)
+		if err != nil {
+			return nil, err
+		}
+		if field.kind == protoInt32 && (i < math.MinInt32 || i > math.MaxInt32) {
+			return nil, fmt.Errorf("value %d overflows int32", i)
+		}
+		buf = appendProtoTag(buf, field.number, protoWireVarint)
+		return binary.AppendUvarint(buf, uint64(i)), nil
+	case protoDouble:
+		f, err := exportFloat(val)
+		if err != nil {
+			return nil, err
+		}
+		buf = appendProtoTag(buf, field.number, protoWireFixed64)
+		return binary.LittleEndian.AppendUint64(buf, math.Float64bits(f)), nil
+	case protoBool:
+		b, ok := val.(bool)
+		if !ok {
+			return nil, fmt.Errorf("invalid type %T for bool", val)
+		}
+		buf = appendProtoTag(buf, field.number, protoWireVarint)
+		if b {
+			return append(buf, 1), nil
+		}
+		return append(buf, 0), nil
+	case protoTimestamp:
+		t, ok := val.(time.Time)
+		if !ok {
+			return nil, fmt.Errorf("invalid type %T for timestamp", val)
+		}
+		if t.IsZero() {
+			return buf, nil
+		}
+		// google.protobuf.Timestamp has the seconds as field 1 and the nanoseconds as field 2
+		timestamp := appendProtoTag(nil, 1, protoWireVarint)
+		timestamp = binary.AppendUvarint(timestamp, uint64(t.Unix()))
+		if t.Nanosecond() != 0 {
+			timestamp = appendProtoTag(timestamp, 2, protoWireVarint)
+			timestamp = binary.AppendUvarint(timestamp, uint64(t.Nanosecond()))
+		}
+		buf = appendProtoTag(buf, field.number, protoWireBytes)
+		buf = binary.AppendUvarint(buf, uint64(len(timestamp)))
+		return append(buf, timestamp...), nil
+	}
+	return nil, fmt.Errorf("unsupported field type %s", field.kind)
+}
+
+// appendProtoTag appends the key of the field with the wire type
+func appendProtoTag(buf []byte, number int, wireType int) []byte {
+	return binary.AppendUvarint(buf, uint64(number)<<3|uint64(wireType))
+}
@@ -0,0 +1,116 @@
+package dbase
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// RejectedRow is a row written to a quarantine file
+type RejectedRow struct {
+	Position uint32 `json:"position"` // Position of the row in the table, or the record number of an import source
+	Error    string `json:"error"`    // Error message of the rejected row
+	Data     []byte `json:"data"`     // Raw bytes of the row, base64 encoded in the file
+}
+
+// Quarantine appends rejected rows to a quarantine file (usually with the extension .rej), so they can be inspected and repaired later.
+// Every rejected row is written as one JSON object per line.
+type Quarantine struct {
+	mutex  sync.Mutex
+	handle *os.File
+	count  uint32
+}
+
+// OpenQuarantine opens or creates the quarantine file at path, rejected rows are appended to existing ones
+func OpenQuarantine(path string) (*Quarantine, error) {
+	handle, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, newError("dbase-quarantine-openquarantine-1", err)
+	}
+	return &Quarantine{handle: handle}, nil
+}
+
+// Write appends a rejected row with its raw data and the error to the quarantine file.
+// Importers can use it to reject records of their source, e.g. a CSV line.
+func (q *Quarantine) Write(position uint32, data []byte, reason error) error {
+	rejected := RejectedRow{Position: position, Data: data}
+	if reason != nil {
+		rejected.Error = reason.Error()
+	}
+	line, err := json.Marshal(rejected)
+	if err != nil {
+		return newError("dbase-quarantine-write-1", err)
+	}
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	_, err = q.handle.Write(append(line, '\n'))
+	if err != nil {
+		return newError("dbase-quarantine-write-2", err)
+	}
+	q.count++
+	return nil
+}
+
+// Count returns the number of rows written since the quarantine file was opened
+func (q *Quarantine) Count() uint32 {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.count
+}
+
+// Close closes the quarantine file
+func (q *Quarantine) Close() error {
+	err := q.handle.Close()
+	if err != nil {
+		return newError("dbase-quarantine-close-1", err)
+	}
+	return nil
+}
+
+// ReadQuarantine reads all rejected rows of the quarantine file at path
+func ReadQuarantine(path string) ([]*RejectedRow, error) {
+	handle, err := os.Open(path)
+	if err != nil {
+		return nil, newError("dbase-quarantine-readquarantine-1", err)
+	}
+	defer handle.Close()
+	rows := make([]*RejectedRow, 0)
+	scanner := bufio.NewScanner(handle)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		row := &RejectedRow{}
+		err = json.Unmarshal(scanner.Bytes(), row)
+		if err != nil {
+			return nil, newError("dbase-quarantine-readquarantine-2", err)
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, newError("dbase-quarantine-readquarantine-3", err)
+	}
+	return rows, nil
+}
+
+// SetQuarantine sets the quarantine file for the rows skipped by Rows and RowsWithErrors, nil disables it.
+// The quarantine file is not closed with the table.
+func (file *File) SetQuarantine(quarantine *Quarantine) {
+	file.quarantine = quarantine
+}
+
+// reject writes the raw data of the row at position with the error to the quarantine file, if set
+func (file *File) reject(position uint32, reason error) error {
+	if file.quarantine == nil {
+		return nil
+	}
+	// The raw data is read again, it may be missing if the row itself could not be read
+	data, _ := file.ReadRow(position)
+	err := file.quarantine.Write(position, data, reason)
+	if err != nil {
+		return newError("dbase-quarantine-reject-1", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package dbase
+
+import (
+	"errors"
+	"fmt"
+)
+
+// UnconfirmedRows returns the number of rows that were recovered beyond the rows count of the header
+func (file *File) UnconfirmedRows() uint32 {
+	if !file.config.RecoverRows {
+		return 0
+	}
+	return file.header.RowsCount - file.confirmedRows
+}
+
+// recoverRows derives the rows count from the file size, if the header contains less rows than the file.
+// Rows are recovered until the end of the file, an incomplete row or a row without a valid deletion flag (e.g. the end of file marker).
+// The header is only changed in memory, so the table has to be opened read-only.
+func (file *File) recoverRows() error {
+	if !file.config.ReadOnly {
+		return newError("dbase-recover-recoverrows-1", fmt.Errorf("recovering rows requires a read-only table"))
+	}
+	file.confirmedRows = file.header.RowsCount
+	length := int64(file.header.RowLength)
+	if length == 0 {
+		return nil
+	}
+	size, err := file.Size(false)
+	if err != nil {
+		return newError("dbase-recover-recoverrows-2", err)
+	}
+	end := file.dataEnd()
+	available := (size - end) / length
+	if available <= 0 {
+		return nil
+	}
+	recovered := int64(0)
+	chunk := int64(1024)
+	for recovered < available {
+		count := chunk
+		if available-recovered < count {
+			count = available - recovered
+		}
+		data, err := file.ReadRaw(false, end+recovered*length, int(count*length))
+		if err != nil && !errors.Is(err, ErrIncomplete) {
+			return newError("dbase-recover-recoverrows-3", err)
+		}
+		valid := int64(0)
+		for ; (valid+1)*length <= int64(len(data)); valid++ {
+			marker := Marker(data[valid*length])
+			if marker != Active && marker != Deleted {
+				break
+			}
+		}
+		recovered += valid
+		if valid < count {
+			break
+		}
+	}
+	if recovered > 0 {
+		debugf("Recovered %d rows beyond the rows count %d of the header", recovered, file.confirmedRows)
+		file.header.RowsCount += uint32(recovered)
+	}
+	return nil
+}
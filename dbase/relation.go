@@ -0,0 +1,290 @@
+package dbase
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Relation links the rows of a parent table to the rows of a child table by key columns, like SET RELATION in FoxPro
+type Relation struct {
+	Parent      string   // Name of the parent table in the relation set
+	ParentKey   []string // Key columns of the parent table
+	Child       string   // Name of the child table in the relation set
+	ChildKey    []string // Key columns of the child table in the same order as ParentKey
+	SkipDeleted bool     // If true deleted child rows are not returned, Config.IgnoreDeleted of the child table has the same effect
+
+	mutex   sync.Mutex
+	index   map[string][]uint32 // Row positions of the child table by key
+	indexed uint32              // Rows count of the child table when the index was built
+}
+
+// RelationSet contains related tables and the relations between them.
+// The child rows of a relation are found with an index of the child key that is built in memory on first use.
+// Index files of the tables are not used, as they are not read by this package.
+type RelationSet struct {
+	tables    map[string]*File
+	relations []*Relation
+}
+
+// NewRelationSet returns an empty relation set
+func NewRelationSet() *RelationSet {
+	return &RelationSet{tables: make(map[string]*File)}
+}
+
+// Open opens the table with the given config and adds it to the relation set under the given name
+func (set *RelationSet) Open(name string, config *Config) (*File, error) {
+	file, err := OpenTable(config)
+	if err != nil {
+		return nil, newError("dbase-relation-open-1", err)
+	}
+	err = set.Add(name, file)
+	if err != nil {
+		file.Close()
+		return nil, newError("dbase-relation-open-2", err)
+	}
+	return file, nil
+}
+
+// Add adds an opened table to the relation set under the given name
+func (set *RelationSet) Add(name string, file *File) error {
+	if _, ok := set.tables[name]; ok {
+		return newError("dbase-relation-add-1", fmt.Errorf("table '%s' already exists in the relation set", name))
+	}
+	if file.relations != nil && file.relations != set {
+		return newError("dbase-relation-add-2", fmt.Errorf("table '%s' already belongs to another relation set", name))
+	}
+	file.relations = set
+	set.tables[name] = file
+	return nil
+}
+
+// Table returns the table with the given name or nil if not found
+func (set *RelationSet) Table(name string) *File {
+	return set.tables[name]
+}
+
+// Relations returns the declared relations
+func (set *RelationSet) Relations() []*Relation {
+	return set.relations
+}
+
+// Relate declares a relation between two tables of the relation set.
+// Only one relation can be declared from a parent to the same child table.
+func (set *RelationSet) Relate(relation *Relation) error {
+	parent, child := set.tables[relation.Parent], set.tables[relation.Child]
+	if parent == nil || child == nil {
+		return newError("dbase-relation-relate-1", fmt.Errorf("tables '%s' and '%s' have to be added to the relation set first", relation.Parent, relation.Child))
+	}
+	if len(relation.ParentKey) == 0 || len(relation.ParentKey) != len(relation.ChildKey) {
+		return newError("dbase-relation-relate-2", fmt.Errorf("parent key %v and child key %v have to contain the same number of columns", relation.ParentKey, relation.ChildKey))
+	}
+	for i := range relation.ParentKey {
+		if parent.ColumnPosByName(relation.ParentKey[i]) < 0 {
+			return newError("dbase-relation-relate-3", fmt.Errorf("column '%s' not found in table '%s'", relation.ParentKey[i], relation.Parent))
+		}
+		if child.ColumnPosByName(relation.ChildKey[i]) < 0 {
+			return newError("dbase-relation-relate-4", fmt.Errorf("column '%s' not found in table '%s'", relation.ChildKey[i], relation.Child))
+		}
+	}
+	if set.relation(relation.Parent, relation.Child) != nil {
+		return newError("dbase-relation-relate-5", fmt.Errorf("relation from '%s' to '%s' already exists", relation.Parent, relation.Child))
+	}
+	// Written rows may change the keys of the child table
+	child.AfterRowWrite(func(row *Row) {
+		relation.reset()
+	})
+	set.relations = append(set.relations, relation)
+	return nil
+}
+
+// Children returns the rows of the child table related to the given row of a parent table
+func (set *RelationSet) Children(row *Row, child string) ([]*Row, error) {
+	parent := set.name(row.handle)
+	relation := set.relation(parent, child)
+	if relation == nil {
+		return nil, newError("dbase-relation-children-1", fmt.Errorf("no relation from '%s' to '%s'", parent, child))
+	}
+	values := make([]interface{}, len(relation.ParentKey))
+	for i, name := range relation.ParentKey {
+		val, err := row.ValueByName(name)
+		if err != nil {
+			return nil, newError("dbase-relation-children-2", err)
+		}
+		values[i] = val
+	}
+	file := set.tables[child]
+	positions, err := relation.lookup(file, relationKey(values))
+	if err != nil {
+		return nil, newError("dbase-relation-children-3", err)
+	}
+	pointer := file.table.rowPointer
+	defer func() {
+		file.table.rowPointer = pointer
+	}()
+	rows := make([]*Row, 0, len(positions))
+	for _, position := range positions {
+		file.table.rowPointer = position
+		r, err := file.Row()
+		if err != nil {
+			return nil, newError("dbase-relation-children-4", err)
+		}
+		if r.Deleted && (relation.SkipDeleted || file.deletedHidden) {
+			continue
+		}
+		rows = append(rows, r)
+	}
+	return rows, nil
+}
+
+// Refresh drops the indexes of all relations, they are rebuilt on the next use.
+// This is only needed if the tables were changed outside of this relation set.
+func (set *RelationSet) Refresh() {
+	for _, relation := range set.relations {
+		relation.reset()
+	}
+}
+
+// Close closes all tables of the relation set
+func (set *RelationSet) Close() error {
+	for name, file := range set.tables {
+		if err := file.Close(); err != nil {
+			return newError("dbase-relation-close-1", fmt.Errorf("closing table '%s' failed with error: %w", name, err))
+		}
+		file.relations = nil
+	}
+	return nil
+}
+
+// Children returns the rows of the child table related to this row.
+// The table of the row has to be part of a relation set with a relation to the child table.
+func (row *Row) Children(child string) ([]*Row, error) {
+	if row.handle == nil || row.handle.relations == nil {
+		return nil, newError("dbase-relation-rowchildren-1", fmt.Errorf("table of the row is not part of a relation set"))
+	}
+	rows, err := row.handle.relations.Children(row, child)
+	if err != nil {
+		return nil, newError("dbase-relation-rowchildren-2", err)
+	}
+	return rows, nil
+}
+
+// name returns the name of the table in the relation set
+func (set *RelationSet) name(file *File) string {
+	for name, f := range set.tables {
+		if f == file {
+			return name
+		}
+	}
+	return ""
+}
+
+// relation returns the relation from parent to child or nil
+func (set *RelationSet) relation(parent, child string) *Relation {
+	for _, relation := range set.relations {
+		if relation.Parent == parent && relation.Child == child {
+			return relation
+		}
+	}
+	return nil
+}
+
+// reset drops the index of the relation
+func (relation *Relation) reset() {
+	relation.mutex.Lock()
+	defer relation.mutex.Unlock()
+	relation.index = nil
+}
+
+// lookup returns the row positions of the child table with the given key, the index is built if needed
+func (relation *Relation) lookup(file *File, key string) ([]uint32, error) {
+	relation.mutex.Lock()
+	defer relation.mutex.Unlock()
+	if relation.index == nil || relation.indexed != file.header.RowsCount {
+		index, err := buildKeyIndex(file, relation.ChildKey)
+		if err != nil {
+			return nil, newError("dbase-relation-lookup-1", err)
+		}
+		relation.index = index
+		relation.indexed = file.header.RowsCount
+	}
+	return relation.index[key], nil
+}
+
+// buildKeyIndex reads the key columns of all rows and returns the row positions by key
+func buildKeyIndex(file *File, columns []string) (map[string][]uint32, error) {
+	debugf("Building key index of columns %v", columns)
+	index := make(map[string][]uint32)
+	err := file.scanKeys(columns, func(position uint32, deleted bool, key string, values []interface{}) {
+		index[key] = append(index[key], position)
+	})
+	if err != nil {
+		return nil, newError("dbase-relation-buildkeyindex-1", err)
+	}
+	return index, nil
+}
+
+// scanKeys calls fn for every row with the deletion flag, the key and the values of the key columns.
+// Only the key columns are interpreted, the internal row pointer is restored afterwards.
+func (file *File) scanKeys(columns []string, fn func(position uint32, deleted bool, key string, values []interface{})) error {
+	keyColumns := make([]*Column, len(columns))
+	offsets := make([]uint32, len(columns))
+	for i, name := range columns {
+		pos := file.ColumnPosByName(name)
+		if pos < 0 {
+			return newError("dbase-relation-scankeys-1", fmt.Errorf("column '%s' not found", name))
+		}
+		keyColumns[i] = file.table.columns[pos]
+		offsets[i] = file.offsets()[pos]
+	}
+	err := file.autoReopen()
+	if err != nil {
+		return newError("dbase-relation-scankeys-5", err)
+	}
+	pointer := file.table.rowPointer
+	file.scans++
+	defer func() {
+		file.table.rowPointer = pointer
+		file.scans--
+	}()
+	throttle := file.throttle()
+	for position := uint32(0); position < file.header.RowsCount; position++ {
+		data, err := file.ReadRow(position)
+		if err != nil {
+			return newError("dbase-relation-scankeys-2", err)
+		}
+		throttle.wait(1, uint64(len(data)))
+		// The row pointer is required to read the null flags of variable length columns
+		file.table.rowPointer = position
+		values := make([]interface{}, len(keyColumns))
+		for i, column := range keyColumns {
+			start := int(offsets[i])
+			end := start + int(column.Length)
+			if end > len(data) {
+				return newError("dbase-relation-scankeys-3", fmt.Errorf("column '%s' exceeds the row length", column.Name()))
+			}
+			values[i], err = file.Interpret(data[start:end], column)
+			if err != nil {
+				return newError("dbase-relation-scankeys-4", err)
+			}
+		}
+		fn(position, Marker(data[0]) == Deleted, relationKey(values), values)
+	}
+	return nil
+}
+
+// relationKey returns the comparable key of the values, trailing spaces of strings are ignored like in dBase
+func relationKey(values []interface{}) string {
+	parts := make([]string, len(values))
+	for i, val := range values {
+		switch v := val.(type) {
+		case int32:
+			parts[i] = formatValue(float64(v))
+		case int64:
+			parts[i] = formatValue(float64(v))
+		default:
+			parts[i] = trimText(val)
+		}
+	}
+	return strings.Join(parts, "\x00")
+}
@@ -0,0 +1,299 @@
+package dbase
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ReportTemplate is a template executed by Report, *text/template.Template and *html/template.Template implement it
+type ReportTemplate interface {
+	Execute(w io.Writer, data interface{}) error
+}
+
+// ReportData is the data the template of Report is executed with
+type ReportData struct {
+	Table   string            // Table name, the file name without extension
+	Columns []*Column         // Columns of the table
+	Rows    <-chan *ReportRow // Rows in table order, read with {{range .Rows}}
+	summary *reportSummary
+}
+
+// ReportRow is a row of a report with the totals of the numeric values up to this row
+type ReportRow struct {
+	Number   int                    // Number of the row in the report, starting at 1
+	Position uint32                 // Position of the row in the table
+	Deleted  bool                   // Deleted flag
+	values   map[string]interface{} // Values by column name or external key, like ToMap
+	totals   map[string]float64     // Totals of all rows up to this row
+	starts   []bool                 // Group starts with this row, by group level
+	ends     []bool                 // Group ends with this row, by group level
+	groups   []map[string]float64   // Totals of the current group up to this row, by group level
+	counts   []int                  // Rows of the current group up to this row, by group level
+	levels   map[string]int         // Group level by column name
+}
+
+// reportSummary contains the totals after the last row, written before Rows is closed
+type reportSummary struct {
+	count  int
+	totals map[string]float64
+}
+
+// errReportStopped stops reading rows when the template execution ended before all rows were read
+var errReportStopped = errors.New("report stopped")
+
+// ReportFuncs returns the helper functions for report templates, add them with Funcs before parsing the template:
+//   - date formats a time with the layout, the zero time as empty text: {{date .Get "HIRED" "02.01.2006"}}
+//   - number formats a number with the decimals and a thousands separator: {{number (.Get "AMOUNT") 2}}
+//   - padLeft and padRight pad a value with spaces to the width, for fixed width reports: {{padLeft 10 (.Get "ID")}}
+//   - trim, upper and lower change text values
+func ReportFuncs() map[string]interface{} {
+	return map[string]interface{}{
+		"date": func(value interface{}, layout string) string {
+			t, ok := value.(time.Time)
+			if !ok || t.IsZero() {
+				return ""
+			}
+			return t.Format(layout)
+		},
+		"number": func(value interface{}, decimals int) string {
+			number, ok := toFloat(value)
+			if !ok {
+				return formatValue(value)
+			}
+			return groupThousands(strconv.FormatFloat(number, 'f', decimals, 64))
+		},
+		"padLeft": func(width int, value interface{}) string {
+			text := formatValue(value)
+			return padding(width, text) + text
+		},
+		"padRight": func(width int, value interface{}) string {
+			text := formatValue(value)
+			return text + padding(width, text)
+		},
+		"trim":  func(value interface{}) string { return strings.TrimSpace(formatValue(value)) },
+		"upper": func(value interface{}) string { return strings.ToUpper(formatValue(value)) },
+		"lower": func(value interface{}) string { return strings.ToLower(formatValue(value)) },
+	}
+}
+
+// Report executes the template with the rows of the table, replacing REPORT FORM jobs of FoxPro.
+// The rows are streamed to the template through ReportData.Rows, so the table is not read into memory.
+// Values are read like ToMap, so hidden columns and modifications apply, and deleted rows are included unless they are hidden.
+//
+// The groups are column names from the outermost to the innermost group, they must not be hidden.
+// A group starts when the value of its column or of an outer group changes, the rows have to be sorted by the groups. Group headers and footers are written with
+// {{if .GroupStart "DEPT"}} and {{if .GroupEnd "DEPT"}}, the totals with {{.GroupTotal "DEPT" "AMOUNT"}} and,
+// after the rows, {{.Total "AMOUNT"}}. The internal row pointer is restored afterwards.
+func (file *File) Report(tmpl ReportTemplate, w io.Writer, groups ...string) error {
+	levels := make(map[string]int, len(groups))
+	for i, group := range groups {
+		if file.ColumnPosByName(group) < 0 {
+			return newError("dbase-report-report-1", fmt.Errorf("group column '%s' not found", group))
+		}
+		levels[group] = i
+	}
+	rows := make(chan *ReportRow)
+	done := make(chan struct{})
+	data := &ReportData{Table: file.exportSchema().Name, Columns: file.Columns(), Rows: rows, summary: &reportSummary{totals: make(map[string]float64)}}
+	result := make(chan error, 1)
+	go func() {
+		defer close(rows)
+		result <- file.reportRows(data.summary, groups, levels, rows, done)
+	}()
+	err := tmpl.Execute(w, data)
+	close(done)
+	scanErr := <-result
+	if scanErr != nil && !errors.Is(scanErr, errReportStopped) {
+		return newError("dbase-report-report-2", scanErr)
+	}
+	if err != nil {
+		return newError("dbase-report-report-3", err)
+	}
+	return nil
+}
+
+// reportRows reads the rows and sends each row after the next row was read, to know which groups end with it
+func (file *File) reportRows(summary *reportSummary, groups []string, levels map[string]int, rows chan<- *ReportRow, done <-chan struct{}) error {
+	columns := make([]*Column, len(groups))
+	for level, group := range groups {
+		columns[level] = file.table.columns[file.ColumnPosByName(group)]
+	}
+	totals := make(map[string]float64)
+	groupTotals := make([]map[string]float64, len(groups))
+	counts := make([]int, len(groups))
+	var pending *ReportRow
+	send := func(row *ReportRow, next *ReportRow) error {
+		for level := range groups {
+			row.ends[level] = next == nil || next.starts[level]
+		}
+		select {
+		case rows <- row:
+			return nil
+		case <-done:
+			return errReportStopped
+		}
+	}
+	err := file.forEachRow(false, func(row *Row) error {
+		values, err := row.ToMap()
+		if err != nil {
+			return err
+		}
+		current := &ReportRow{
+			Number:   summary.count + 1,
+			Position: row.Position,
+			Deleted:  row.Deleted,
+			values:   values,
+			starts:   make([]bool, len(groups)),
+			ends:     make([]bool, len(groups)),
+			groups:   make([]map[string]float64, len(groups)),
+			counts:   make([]int, len(groups)),
+			levels:   levels,
+		}
+		changed := pending == nil
+		for level, group := range groups {
+			if !changed {
+				changed = !Equal(pending.Get(group), current.Get(group), columns[level])
+			}
+			current.starts[level] = changed
+			if changed {
+				groupTotals[level] = make(map[string]float64)
+				counts[level] = 0
+			}
+		}
+		for key, value := range values {
+			if number, ok := reportNumber(value); ok {
+				totals[key] += number
+				for level := range groups {
+					groupTotals[level][key] += number
+				}
+			}
+		}
+		current.totals = copyTotals(totals)
+		for level := range groups {
+			counts[level]++
+			current.groups[level] = copyTotals(groupTotals[level])
+			current.counts[level] = counts[level]
+		}
+		summary.count++
+		if pending != nil {
+			err = send(pending, current)
+			if err != nil {
+				return err
+			}
+		}
+		pending = current
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	summary.totals = totals
+	if pending != nil {
+		return send(pending, nil)
+	}
+	return nil
+}
+
+// Get returns the value of the column or external key, nil if the row has no such value
+func (r *ReportRow) Get(name string) interface{} {
+	return r.values[name]
+}
+
+// Total returns the total of the numeric values of the column up to and including this row
+func (r *ReportRow) Total(name string) float64 {
+	return r.totals[name]
+}
+
+// GroupStart returns true if the group starts with this row, the row of a group header
+func (r *ReportRow) GroupStart(group string) bool {
+	level, ok := r.levels[group]
+	return ok && r.starts[level]
+}
+
+// GroupEnd returns true if the group ends with this row, the row of a group footer
+func (r *ReportRow) GroupEnd(group string) bool {
+	level, ok := r.levels[group]
+	return ok && r.ends[level]
+}
+
+// GroupTotal returns the total of the numeric values of the column in the group up to and including this row
+func (r *ReportRow) GroupTotal(group string, name string) float64 {
+	level, ok := r.levels[group]
+	if !ok {
+		return 0
+	}
+	return r.groups[level][name]
+}
+
+// GroupCount returns the number of rows of the group up to and including this row
+func (r *ReportRow) GroupCount(group string) int {
+	level, ok := r.levels[group]
+	if !ok {
+		return 0
+	}
+	return r.counts[level]
+}
+
+// Total returns the total of the numeric values of the column of all rows, available after the rows were read
+func (d *ReportData) Total(name string) float64 {
+	return d.summary.totals[name]
+}
+
+// Count returns the number of rows, available after the rows were read
+func (d *ReportData) Count() int {
+	return d.summary.count
+}
+
+// reportNumber returns the value as float64 if it is a number
+func reportNumber(value interface{}) (float64, bool) {
+	if value == nil {
+		return 0, false
+	}
+	if _, ok := value.(bool); ok {
+		return 0, false
+	}
+	return toFloat(value)
+}
+
+// copyTotals returns a copy of the totals, so rows sent to the template are not changed by the following rows
+func copyTotals(totals map[string]float64) map[string]float64 {
+	result := make(map[string]float64, len(totals))
+	for key, value := range totals {
+		result[key] = value
+	}
+	return result
+}
+
+// padding returns the spaces that pad the text to the width
+func padding(width int, text string) string {
+	length := utf8.RuneCountInString(text)
+	if length >= width {
+		return ""
+	}
+	return strings.Repeat(" ", width-length)
+}
+
+// groupThousands inserts a comma between each group of three digits of the integer part
+func groupThousands(number string) string {
+	sign := ""
+	if strings.HasPrefix(number, "-") {
+		sign, number = "-", number[1:]
+	}
+	integer, fraction := number, ""
+	if i := strings.IndexByte(number, '.'); i >= 0 {
+		integer, fraction = number[:i], number[i:]
+	}
+	var b strings.Builder
+	for i, r := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	return sign + b.String() + fraction
+}
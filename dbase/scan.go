@@ -0,0 +1,261 @@
+package dbase
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// Typed accessors for the values of a row by column position, used by the scanners generated by cmd/dbase-gen.
+// Unlike the accessors by name the decoded values are used without reflection and without the casts of Config.Schema.
+// Nil values are returned as the zero value of the type, strings are trimmed according to the trim settings.
+// The setters convert the value to the type of the column, e.g. int32 for I and float64 for Y columns.
+
+// ColumnIndex resolves the positions of columns by name once per table, instead of for each row
+type ColumnIndex struct {
+	names []string
+	cache atomic.Value // *columnPositions of the last table
+}
+
+// columnPositions are the positions of the columns in a table
+type columnPositions struct {
+	file      *File
+	positions []int
+}
+
+// NewColumnIndex returns the index of the columns with the names
+func NewColumnIndex(names ...string) *ColumnIndex {
+	return &ColumnIndex{names: names}
+}
+
+// Positions returns the positions of the columns in the order of the names, -1 for columns the table does not have
+func (c *ColumnIndex) Positions(file *File) []int {
+	if cached, ok := c.cache.Load().(*columnPositions); ok && cached.file == file {
+		return cached.positions
+	}
+	positions := make([]int, len(c.names))
+	for i, name := range c.names {
+		positions[i] = file.ColumnPosByName(name)
+	}
+	c.cache.Store(&columnPositions{file: file, positions: positions})
+	return positions
+}
+
+// RowPositions returns the positions of the columns in the table of the row, see Positions
+func (c *ColumnIndex) RowPositions(row *Row) []int {
+	return c.Positions(row.handle)
+}
+
+// IsNilAt returns true if the value at the column position is nil
+func (row *Row) IsNilAt(pos int) bool {
+	return pos < 0 || pos >= len(row.fields) || row.fields[pos] == nil || row.fields[pos].value == nil
+}
+
+// StringAt returns the value at the column position as string
+func (row *Row) StringAt(pos int) (string, error) {
+	field, err := row.fieldAt(pos)
+	if err != nil {
+		return "", newError("dbase-scan-stringat-1", err)
+	}
+	switch v := field.value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return row.handle.trim(v, row.handle.table.mods[pos]), nil
+	case []byte:
+		return row.handle.trim(string(v), row.handle.table.mods[pos]), nil
+	}
+	return "", newError("dbase-scan-stringat-2", fmt.Errorf("invalid data type %T, expected string at column field: %v", field.value, field.Name()))
+}
+
+// BytesAt returns the value at the column position as byte slice, strings are not trimmed
+func (row *Row) BytesAt(pos int) ([]byte, error) {
+	field, err := row.fieldAt(pos)
+	if err != nil {
+		return nil, newError("dbase-scan-bytesat-1", err)
+	}
+	switch v := field.value.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	}
+	return nil, newError("dbase-scan-bytesat-2", fmt.Errorf("invalid data type %T, expected []byte at column field: %v", field.value, field.Name()))
+}
+
+// IntAt returns the value at the column position as int64, numbers with a fractional part are rejected
+func (row *Row) IntAt(pos int) (int64, error) {
+	field, err := row.fieldAt(pos)
+	if err != nil {
+		return 0, newError("dbase-scan-intat-1", err)
+	}
+	switch v := field.value.(type) {
+	case nil:
+		return 0, nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, newError("dbase-scan-intat-2", fmt.Errorf("value %v is not an integer at column field: %v", v, field.Name()))
+		}
+		return int64(v), nil
+	}
+	return 0, newError("dbase-scan-intat-3", fmt.Errorf("invalid data type %T, expected integer at column field: %v", field.value, field.Name()))
+}
+
+// FloatAt returns the value at the column position as float64
+func (row *Row) FloatAt(pos int) (float64, error) {
+	field, err := row.fieldAt(pos)
+	if err != nil {
+		return 0, newError("dbase-scan-floatat-1", err)
+	}
+	switch v := field.value.(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		return v, nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	}
+	return 0, newError("dbase-scan-floatat-2", fmt.Errorf("invalid data type %T, expected number at column field: %v", field.value, field.Name()))
+}
+
+// BoolAt returns the value at the column position as bool
+func (row *Row) BoolAt(pos int) (bool, error) {
+	field, err := row.fieldAt(pos)
+	if err != nil {
+		return false, newError("dbase-scan-boolat-1", err)
+	}
+	switch v := field.value.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return v, nil
+	}
+	return false, newError("dbase-scan-boolat-2", fmt.Errorf("invalid data type %T, expected bool at column field: %v", field.value, field.Name()))
+}
+
+// TimeAt returns the value at the column position as time.Time
+func (row *Row) TimeAt(pos int) (time.Time, error) {
+	field, err := row.fieldAt(pos)
+	if err != nil {
+		return time.Time{}, newError("dbase-scan-timeat-1", err)
+	}
+	switch v := field.value.(type) {
+	case nil:
+		return time.Time{}, nil
+	case time.Time:
+		return v, nil
+	}
+	return time.Time{}, newError("dbase-scan-timeat-2", fmt.Errorf("invalid data type %T, expected time.Time at column field: %v", field.value, field.Name()))
+}
+
+// SetNilAt sets the value at the column position to nil
+func (row *Row) SetNilAt(pos int) error {
+	return row.setAt(pos, nil)
+}
+
+// SetStringAt sets the value at the column position, binary columns get the bytes of the string
+func (row *Row) SetStringAt(pos int, value string) error {
+	field, err := row.fieldAt(pos)
+	if err != nil {
+		return newError("dbase-scan-setstringat-1", err)
+	}
+	switch DataType(field.column.DataType) {
+	case Blob, Varbinary, General, Picture:
+		return row.setAt(pos, []byte(value))
+	}
+	return row.setAt(pos, value)
+}
+
+// SetBytesAt sets the value at the column position, text columns get the bytes as string
+func (row *Row) SetBytesAt(pos int, value []byte) error {
+	field, err := row.fieldAt(pos)
+	if err != nil {
+		return newError("dbase-scan-setbytesat-1", err)
+	}
+	switch DataType(field.column.DataType) {
+	case Character, Varchar:
+		return row.setAt(pos, string(value))
+	}
+	return row.setAt(pos, value)
+}
+
+// SetIntAt sets the value at the column position converted to the number type of the column
+func (row *Row) SetIntAt(pos int, value int64) error {
+	field, err := row.fieldAt(pos)
+	if err != nil {
+		return newError("dbase-scan-setintat-1", err)
+	}
+	switch DataType(field.column.DataType) {
+	case Integer:
+		if value < math.MinInt32 || value > math.MaxInt32 {
+			return newError("dbase-scan-setintat-2", fmt.Errorf("value %v exceeds the range of column field: %v", value, field.Name()))
+		}
+		return row.setAt(pos, int32(value))
+	case Numeric:
+		if field.column.Decimals == 0 {
+			return row.setAt(pos, value)
+		}
+		return row.setAt(pos, float64(value))
+	case Float, Double, Currency:
+		return row.setAt(pos, float64(value))
+	}
+	return newError("dbase-scan-setintat-3", fmt.Errorf("invalid data type %v, expected number column at column field: %v", DataType(field.column.DataType), field.Name()))
+}
+
+// SetFloatAt sets the value at the column position converted to the number type of the column,
+// I columns and N columns without decimals only accept integral values
+func (row *Row) SetFloatAt(pos int, value float64) error {
+	field, err := row.fieldAt(pos)
+	if err != nil {
+		return newError("dbase-scan-setfloatat-1", err)
+	}
+	switch DataType(field.column.DataType) {
+	case Integer, Numeric:
+		if DataType(field.column.DataType) == Integer || field.column.Decimals == 0 {
+			if value != math.Trunc(value) {
+				return newError("dbase-scan-setfloatat-2", fmt.Errorf("value %v is not an integer at column field: %v", value, field.Name()))
+			}
+			return row.SetIntAt(pos, int64(value))
+		}
+		return row.setAt(pos, value)
+	case Float, Double, Currency:
+		return row.setAt(pos, value)
+	}
+	return newError("dbase-scan-setfloatat-3", fmt.Errorf("invalid data type %v, expected number column at column field: %v", DataType(field.column.DataType), field.Name()))
+}
+
+// SetBoolAt sets the value at the column position
+func (row *Row) SetBoolAt(pos int, value bool) error {
+	return row.setAt(pos, value)
+}
+
+// SetTimeAt sets the value at the column position
+func (row *Row) SetTimeAt(pos int, value time.Time) error {
+	return row.setAt(pos, value)
+}
+
+// fieldAt returns the field at the column position
+func (row *Row) fieldAt(pos int) (*Field, error) {
+	if pos < 0 || pos >= len(row.fields) || row.fields[pos] == nil {
+		return nil, fmt.Errorf("%w, column position %d out of range", ErrInvalidPosition, pos)
+	}
+	return row.fields[pos], nil
+}
+
+// setAt sets the value of the field at the column position
+func (row *Row) setAt(pos int, value interface{}) error {
+	field, err := row.fieldAt(pos)
+	if err != nil {
+		return newError("dbase-scan-setat-1", err)
+	}
+	return field.SetValue(value)
+}
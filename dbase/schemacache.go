@@ -0,0 +1,138 @@
+package dbase
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+)
+
+// SchemaCache is a least recently used cache of parsed column descriptors keyed by a fingerprint of the descriptor bytes.
+// Set it as Config.SchemaCache to share it between tables, so repeated opens of tables with identical structure
+// read the descriptors in one block and reuse the parsed columns instead of decoding every descriptor again.
+// Every table receives its own copy of the cached columns. The cache is safe for concurrent use.
+type SchemaCache struct {
+	mutex      *sync.Mutex                // Mutex to protect the cache
+	maxSchemas int                        // Maximum number of cached schemas (0 = unlimited)
+	entries    map[[32]byte]*list.Element // Cached schemas by fingerprint
+	order      *list.List                 // Cached schemas ordered by last access, most recent first
+	hits       uint64                     // Number of cache hits
+	misses     uint64                     // Number of cache misses
+}
+
+// SchemaCacheStats contains the counters of the schema cache
+type SchemaCacheStats struct {
+	Schemas int    // Number of cached schemas
+	Hits    uint64 // Number of cache hits
+	Misses  uint64 // Number of cache misses
+}
+
+// schemaEntry is a cached schema with its fingerprint
+type schemaEntry struct {
+	fingerprint [32]byte
+	columns     []*Column
+	nullFlag    *Column
+}
+
+// NewSchemaCache returns a schema cache limited to maxSchemas distinct schemas (0 = unlimited)
+func NewSchemaCache(maxSchemas int) *SchemaCache {
+	return &SchemaCache{
+		mutex:      &sync.Mutex{},
+		maxSchemas: maxSchemas,
+		entries:    make(map[[32]byte]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// Stats returns the current counters of the cache
+func (c *SchemaCache) Stats() SchemaCacheStats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return SchemaCacheStats{
+		Schemas: len(c.entries),
+		Hits:    c.hits,
+		Misses:  c.misses,
+	}
+}
+
+// Clear removes all cached schemas
+func (c *SchemaCache) Clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries = make(map[[32]byte]*list.Element)
+	c.order.Init()
+}
+
+// readColumns returns the columns of the table from the cache or reads them with the IO of the table.
+// The descriptors are read as one block up to the first row, tables with corrupt descriptors are not cached.
+func (c *SchemaCache) readColumns(file *File) ([]*Column, *Column, error) {
+	descriptors, ok := file.descriptorBytes()
+	if !ok {
+		return file.defaults().io.ReadColumns(file)
+	}
+	fingerprint := sha256.Sum256(descriptors)
+	c.mutex.Lock()
+	if element, ok := c.entries[fingerprint]; ok {
+		c.hits++
+		c.order.MoveToFront(element)
+		entry := element.Value.(*schemaEntry)
+		c.mutex.Unlock()
+		debugf("Schema cache hit for %s", file.config.Filename)
+		return copyColumns(entry.columns), copyColumn(entry.nullFlag), nil
+	}
+	c.misses++
+	c.mutex.Unlock()
+	columns, nullFlag, err := file.defaults().io.ReadColumns(file)
+	if err != nil {
+		return nil, nil, newError("dbase-schemacache-readcolumns-1", err)
+	}
+	entry := &schemaEntry{fingerprint: fingerprint, columns: copyColumns(columns), nullFlag: copyColumn(nullFlag)}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, ok := c.entries[fingerprint]; !ok {
+		c.entries[fingerprint] = c.order.PushFront(entry)
+		for c.maxSchemas > 0 && c.order.Len() > c.maxSchemas {
+			oldest := c.order.Back()
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*schemaEntry).fingerprint)
+		}
+	}
+	return columns, nullFlag, nil
+}
+
+// descriptorBytes reads the column descriptors including the terminator.
+// Returns false if the terminator is not found before the first row.
+func (file *File) descriptorBytes() ([]byte, bool) {
+	if file.header == nil || file.header.FirstRow <= 32 {
+		return nil, false
+	}
+	raw, err := file.ReadRaw(false, 32, int(file.header.FirstRow)-32)
+	if err != nil {
+		return nil, false
+	}
+	for offset := 0; offset < len(raw); offset += 32 {
+		if raw[offset] == byte(ColumnEnd) {
+			return raw[:offset+1], true
+		}
+	}
+	return nil, false
+}
+
+// copyColumns returns copies of the columns
+func copyColumns(columns []*Column) []*Column {
+	copied := make([]*Column, len(columns))
+	block := make([]Column, len(columns))
+	for i, column := range columns {
+		block[i] = *column
+		copied[i] = &block[i]
+	}
+	return copied
+}
+
+// copyColumn returns a copy of the column, nil if the column is nil
+func copyColumn(column *Column) *Column {
+	if column == nil {
+		return nil
+	}
+	copied := *column
+	return &copied
+}
@@ -0,0 +1,104 @@
+package dbase
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// snapshotHandle is the read-only in-memory handle of a snapshot
+type snapshotHandle struct {
+	*bytes.Reader
+}
+
+// Write returns an error, as snapshots can not be changed
+func (h *snapshotHandle) Write(p []byte) (int, error) {
+	return 0, fmt.Errorf("snapshot is read-only")
+}
+
+// Close does nothing, the data is released with the snapshot
+func (h *snapshotHandle) Close() error {
+	return nil
+}
+
+// Snapshot copies the current content of the table and memo file into memory and returns it as read-only table,
+// so a long running export sees a consistent state while the file keeps being written by other applications.
+// Only the rows covered by the rows count of the header are copied, rows appended after reading the header are not part of the snapshot.
+// The memo file is copied after the table file, so all memo blocks referenced by the copied rows are included.
+// Modifications and column settings like encodings, defaults and hidden columns are copied, hooks are not.
+func (file *File) Snapshot() (*File, error) {
+	header, err := file.currentHeader()
+	if err != nil {
+		return nil, newError("dbase-snapshot-snapshot-1", err)
+	}
+	end := int64(header.FirstRow) + int64(header.RowsCount)*int64(header.RowLength)
+	data, err := file.ReadRaw(false, 0, int(end))
+	if err != nil {
+		return nil, newError("dbase-snapshot-snapshot-2", err)
+	}
+	data = append(data, byte(EOFMarker))
+	debugf("Snapshot of %s with %d rows (%d bytes)", file.config.Filename, header.RowsCount, len(data))
+	io := GenericIO{Handle: &snapshotHandle{bytes.NewReader(data)}}
+	if file.relatedHandle != nil {
+		size, err := file.Size(true)
+		if err != nil {
+			return nil, newError("dbase-snapshot-snapshot-3", err)
+		}
+		memo, err := file.ReadRaw(true, 0, int(size))
+		if err != nil {
+			return nil, newError("dbase-snapshot-snapshot-4", err)
+		}
+		io.RelatedHandle = &snapshotHandle{bytes.NewReader(memo)}
+	}
+	config := *file.config
+	config.IO = io
+	config.ReadOnly = true
+	config.RepairEOF = false
+	config.RecoverRows = false
+	config.AutoReopen = false
+	snapshot, err := OpenTable(&config)
+	if err != nil {
+		return nil, newError("dbase-snapshot-snapshot-5", err)
+	}
+	err = snapshot.copySettings(file)
+	if err != nil {
+		return nil, newError("dbase-snapshot-snapshot-6", err)
+	}
+	return snapshot, nil
+}
+
+// copySettings copies the modifications and column settings of the table with the same columns
+func (file *File) copySettings(from *File) error {
+	err := from.sameColumns(file)
+	if err != nil {
+		return newError("dbase-snapshot-copysettings-1", err)
+	}
+	columns := make(map[*Column]*Column, len(from.table.columns))
+	for i, column := range from.table.columns {
+		columns[column] = file.table.columns[i]
+	}
+	copy(file.table.mods, from.table.mods)
+	for column, converter := range from.table.converters {
+		if file.table.converters == nil {
+			file.table.converters = make(map[*Column]EncodingConverter)
+		}
+		file.table.converters[columns[column]] = converter
+	}
+	for column, value := range from.table.defaults {
+		if file.table.defaults == nil {
+			file.table.defaults = make(map[*Column]func() interface{})
+		}
+		// Defaults of missing reference columns are stored by the reference column, which is shared by the config
+		target, ok := columns[column]
+		if !ok {
+			target = column
+		}
+		file.table.defaults[target] = value
+	}
+	for column, redact := range from.table.hidden {
+		if file.table.hidden == nil {
+			file.table.hidden = make(map[*Column]bool)
+		}
+		file.table.hidden[columns[column]] = redact
+	}
+	return nil
+}
@@ -0,0 +1,457 @@
+package dbase
+
+import (
+	"bufio"
+	"bytes"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// sortEntryOverhead is the approximate memory of a collected row in addition to its key and row data
+const sortEntryOverhead = 64
+
+// SortOptions configures Sort, Deduplicate and Join
+type SortOptions struct {
+	Descending  bool // Rows are returned in descending key order, rows with the same key stay in table order
+	SkipDeleted bool // Deleted rows are skipped, they are always skipped if the table hides them
+}
+
+// sortEntry is a row collected by a sort with the key of the sort expression
+type sortEntry struct {
+	key      []byte
+	position uint32
+	data     []byte
+}
+
+// sortRun is a sorted sequence of entries, next returns nil at the end
+type sortRun interface {
+	next() (*sortEntry, error)
+}
+
+// sorter collects the rows of a table sorted by key. The raw rows are kept in memory up to Config.MemoryLimit,
+// beyond it the collected rows are sorted and written to a temporary file and the files are merged while reading.
+type sorter struct {
+	file       *File
+	descending bool
+	limit      int64
+	used       int64
+	entries    []*sortEntry
+	spilled    []*os.File
+	runs       *sortHeap
+}
+
+// Sort calls fn for the rows of the table ordered by the key of the index expression, see CreateIndex for the expression.
+// The rows are collected as raw row data and decoded when fn is called. If the collected rows exceed Config.MemoryLimit
+// they are written to temporary files in Config.TempDir, which are merged while the rows are returned,
+// so tables larger than the memory can be sorted. Without a memory limit all rows are kept in memory.
+// The internal row pointer is restored afterwards.
+func (file *File) Sort(expression string, opts *SortOptions, fn func(row *Row) error) error {
+	s, err := file.sortRows(expression, opts)
+	if err != nil {
+		return newError("dbase-sort-sort-1", err)
+	}
+	defer s.close()
+	for {
+		entry, err := s.next()
+		if err != nil {
+			return newError("dbase-sort-sort-2", err)
+		}
+		if entry == nil {
+			return nil
+		}
+		row, err := file.sortedRow(entry)
+		if err != nil {
+			return newError("dbase-sort-sort-3", err)
+		}
+		err = fn(row)
+		if err != nil {
+			return newError("dbase-sort-sort-4", err)
+		}
+	}
+}
+
+// Deduplicate calls fn for the first row in table order of each key of the index expression, ordered by key like Sort.
+// Like Sort the rows beyond Config.MemoryLimit are written to temporary files.
+func (file *File) Deduplicate(expression string, opts *SortOptions, fn func(row *Row) error) error {
+	s, err := file.sortRows(expression, opts)
+	if err != nil {
+		return newError("dbase-sort-deduplicate-1", err)
+	}
+	defer s.close()
+	var previous []byte
+	for {
+		entry, err := s.next()
+		if err != nil {
+			return newError("dbase-sort-deduplicate-2", err)
+		}
+		if entry == nil {
+			return nil
+		}
+		// Rows with the same key are returned in table order, the first one is kept
+		if previous != nil && bytes.Equal(previous, entry.key) {
+			continue
+		}
+		previous = entry.key
+		row, err := file.sortedRow(entry)
+		if err != nil {
+			return newError("dbase-sort-deduplicate-3", err)
+		}
+		err = fn(row)
+		if err != nil {
+			return newError("dbase-sort-deduplicate-4", err)
+		}
+	}
+}
+
+// Join calls fn for each pair of a row of the table and a row of the other table whose keys of the index expressions
+// are equal (inner join), ordered by key. Both tables are sorted like Sort, each within its own Config.MemoryLimit.
+// The rows of the other table with the same key are kept in memory while the matching rows of the table are returned.
+// Both expressions must be character expressions or of the same type, shorter character keys are padded with blanks.
+func (file *File) Join(other *File, expression string, otherExpression string, opts *SortOptions, fn func(row *Row, other *Row) error) error {
+	if other == nil {
+		return newError("dbase-sort-join-1", fmt.Errorf("missing table to join"))
+	}
+	if other == file {
+		return newError("dbase-sort-join-2", fmt.Errorf("a table can not be joined with the same handle, open it twice"))
+	}
+	term, err := file.compileIndexExpression(expression)
+	if err != nil {
+		return newError("dbase-sort-join-3", err)
+	}
+	otherTerm, err := other.compileIndexExpression(otherExpression)
+	if err != nil {
+		return newError("dbase-sort-join-4", err)
+	}
+	if term.character != otherTerm.character || (!term.character && term.length != otherTerm.length) {
+		return newError("dbase-sort-join-5", fmt.Errorf("keys of %q and %q can not be compared", expression, otherExpression))
+	}
+	length := term.length
+	if otherTerm.length > length {
+		length = otherTerm.length
+	}
+	left, err := file.sortTerm(term, length, opts)
+	if err != nil {
+		return newError("dbase-sort-join-6", err)
+	}
+	defer left.close()
+	right, err := other.sortTerm(otherTerm, length, opts)
+	if err != nil {
+		return newError("dbase-sort-join-7", err)
+	}
+	defer right.close()
+	var group []*Row
+	var groupKey []byte
+	next, err := right.next()
+	if err != nil {
+		return newError("dbase-sort-join-8", err)
+	}
+	for {
+		entry, err := left.next()
+		if err != nil {
+			return newError("dbase-sort-join-9", err)
+		}
+		if entry == nil {
+			return nil
+		}
+		if groupKey == nil || !bytes.Equal(groupKey, entry.key) {
+			// Skip the rows of the other table ordered before the key and collect the rows with the key
+			group = group[:0]
+			groupKey = entry.key
+			for next != nil && left.compareKeys(next.key, entry.key) < 0 {
+				next, err = right.next()
+				if err != nil {
+					return newError("dbase-sort-join-10", err)
+				}
+			}
+			for next != nil && bytes.Equal(next.key, entry.key) {
+				row, err := other.sortedRow(next)
+				if err != nil {
+					return newError("dbase-sort-join-11", err)
+				}
+				group = append(group, row)
+				next, err = right.next()
+				if err != nil {
+					return newError("dbase-sort-join-12", err)
+				}
+			}
+		}
+		if len(group) == 0 {
+			continue
+		}
+		row, err := file.sortedRow(entry)
+		if err != nil {
+			return newError("dbase-sort-join-13", err)
+		}
+		for _, match := range group {
+			err = fn(row, match)
+			if err != nil {
+				return newError("dbase-sort-join-14", err)
+			}
+		}
+	}
+}
+
+// sortRows collects the rows of the table sorted by the key of the expression
+func (file *File) sortRows(expression string, opts *SortOptions) (*sorter, error) {
+	term, err := file.compileIndexExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+	return file.sortTerm(term, term.length, opts)
+}
+
+// sortTerm collects the rows of the table sorted by the key of the term, character keys are padded to the length
+func (file *File) sortTerm(term *indexTerm, length int, opts *SortOptions) (*sorter, error) {
+	if opts == nil {
+		opts = &SortOptions{}
+	}
+	err := file.autoReopen()
+	if err != nil {
+		return nil, err
+	}
+	file.scans++
+	defer func() {
+		file.scans--
+	}()
+	s := &sorter{
+		file:       file,
+		descending: opts.Descending,
+		limit:      file.config.MemoryLimit,
+		entries:    make([]*sortEntry, 0),
+	}
+	skipDeleted := opts.SkipDeleted || file.deletedHidden
+	throttle := file.throttle()
+	for position := uint32(0); position < file.header.RowsCount; position++ {
+		data, err := file.ReadRow(position)
+		if err != nil {
+			s.close()
+			return nil, err
+		}
+		throttle.wait(1, uint64(len(data)))
+		if skipDeleted && Marker(data[0]) == Deleted {
+			continue
+		}
+		key, err := term.eval(data)
+		if err != nil {
+			s.close()
+			return nil, fmt.Errorf("evaluating the key of row %d failed with error: %w", position, err)
+		}
+		for len(key) < length {
+			key = append(key, ' ')
+		}
+		err = s.add(&sortEntry{key: key, position: position, data: data})
+		if err != nil {
+			s.close()
+			return nil, err
+		}
+	}
+	err = s.merge()
+	if err != nil {
+		s.close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// sortedRow decodes the row data of the entry at its position
+func (file *File) sortedRow(entry *sortEntry) (*Row, error) {
+	pointer := file.table.rowPointer
+	defer func() {
+		file.table.rowPointer = pointer
+	}()
+	// The row pointer is required to read the null flags of variable length columns
+	file.table.rowPointer = entry.position
+	row, err := file.BytesToRow(entry.data)
+	if err != nil {
+		return nil, err
+	}
+	file.runRowRead(row)
+	return row, nil
+}
+
+// compareKeys compares two keys in the order of the sort
+func (s *sorter) compareKeys(a, b []byte) int {
+	if s.descending {
+		return bytes.Compare(b, a)
+	}
+	return bytes.Compare(a, b)
+}
+
+// less orders the entries by key and rows with the same key by position
+func (s *sorter) less(a, b *sortEntry) bool {
+	if c := s.compareKeys(a.key, b.key); c != 0 {
+		return c < 0
+	}
+	return a.position < b.position
+}
+
+// add collects the entry and writes the collected entries to a temporary file if they exceed the memory limit
+func (s *sorter) add(entry *sortEntry) error {
+	s.entries = append(s.entries, entry)
+	s.used += int64(sortEntryOverhead + len(entry.key) + len(entry.data))
+	if s.limit <= 0 || s.used <= s.limit {
+		return nil
+	}
+	return s.spill()
+}
+
+// spill writes the collected entries sorted to a temporary file
+func (s *sorter) spill() error {
+	s.sortEntries()
+	handle, err := os.CreateTemp(s.file.config.TempDir, "dbase-sort-*")
+	if err != nil {
+		return fmt.Errorf("creating temporary sort file failed with error: %w", err)
+	}
+	s.spilled = append(s.spilled, handle)
+	debugf("Writing %d sorted rows (about %d bytes) to %s", len(s.entries), s.used, handle.Name())
+	writer := bufio.NewWriter(handle)
+	buf := make([]byte, 0, binary.MaxVarintLen64+4)
+	for _, entry := range s.entries {
+		buf = binary.AppendUvarint(buf[:0], uint64(len(entry.key)))
+		buf = append(buf, entry.key...)
+		buf = binary.LittleEndian.AppendUint32(buf, entry.position)
+		_, err = writer.Write(buf)
+		if err == nil {
+			_, err = writer.Write(entry.data)
+		}
+		if err != nil {
+			return fmt.Errorf("writing temporary sort file failed with error: %w", err)
+		}
+	}
+	err = writer.Flush()
+	if err != nil {
+		return fmt.Errorf("writing temporary sort file failed with error: %w", err)
+	}
+	s.entries = s.entries[:0]
+	s.used = 0
+	return nil
+}
+
+// sortEntries sorts the collected entries
+func (s *sorter) sortEntries() {
+	sort.Slice(s.entries, func(i, j int) bool { return s.less(s.entries[i], s.entries[j]) })
+}
+
+// merge prepares reading the collected entries and the temporary files in order
+func (s *sorter) merge() error {
+	s.sortEntries()
+	runs := []sortRun{&memoryRun{entries: s.entries}}
+	for _, handle := range s.spilled {
+		_, err := handle.Seek(0, io.SeekStart)
+		if err != nil {
+			return fmt.Errorf("reading temporary sort file failed with error: %w", err)
+		}
+		runs = append(runs, &fileRun{reader: bufio.NewReader(handle), rowLength: int(s.file.header.RowLength)})
+	}
+	s.runs = &sortHeap{sorter: s}
+	for _, run := range runs {
+		err := s.runs.pushNext(run)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// next returns the next entry in sort order, nil at the end
+func (s *sorter) next() (*sortEntry, error) {
+	if s.runs == nil || len(s.runs.items) == 0 {
+		return nil, nil
+	}
+	item := heap.Pop(s.runs).(*sortHeapItem)
+	err := s.runs.pushNext(item.run)
+	if err != nil {
+		return nil, err
+	}
+	return item.entry, nil
+}
+
+// close removes the temporary files
+func (s *sorter) close() {
+	for _, handle := range s.spilled {
+		handle.Close()
+		os.Remove(handle.Name())
+	}
+	s.spilled = nil
+	s.entries = nil
+	s.runs = nil
+}
+
+// memoryRun returns the sorted entries kept in memory
+type memoryRun struct {
+	entries []*sortEntry
+	pos     int
+}
+
+func (r *memoryRun) next() (*sortEntry, error) {
+	if r.pos >= len(r.entries) {
+		return nil, nil
+	}
+	entry := r.entries[r.pos]
+	r.entries[r.pos] = nil
+	r.pos++
+	return entry, nil
+}
+
+// fileRun reads the sorted entries of a temporary file
+type fileRun struct {
+	reader    *bufio.Reader
+	rowLength int
+}
+
+func (r *fileRun) next() (*sortEntry, error) {
+	length, err := binary.ReadUvarint(r.reader)
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading temporary sort file failed with error: %w", err)
+	}
+	buf := make([]byte, int(length)+4+r.rowLength)
+	_, err = io.ReadFull(r.reader, buf)
+	if err != nil {
+		return nil, fmt.Errorf("reading temporary sort file failed with error: %w", err)
+	}
+	return &sortEntry{
+		key:      buf[:length],
+		position: binary.LittleEndian.Uint32(buf[length : length+4]),
+		data:     buf[length+4:],
+	}, nil
+}
+
+// sortHeapItem is the next entry of a run
+type sortHeapItem struct {
+	entry *sortEntry
+	run   sortRun
+}
+
+// sortHeap merges the runs by their next entries
+type sortHeap struct {
+	sorter *sorter
+	items  []*sortHeapItem
+}
+
+func (h *sortHeap) Len() int           { return len(h.items) }
+func (h *sortHeap) Less(i, j int) bool { return h.sorter.less(h.items[i].entry, h.items[j].entry) }
+func (h *sortHeap) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *sortHeap) Push(x interface{}) { h.items = append(h.items, x.(*sortHeapItem)) }
+func (h *sortHeap) Pop() interface{} {
+	item := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return item
+}
+
+// pushNext adds the next entry of the run, runs at their end are dropped
+func (h *sortHeap) pushNext(run sortRun) error {
+	entry, err := run.next()
+	if err != nil || entry == nil {
+		return err
+	}
+	heap.Push(h, &sortHeapItem{entry: entry, run: run})
+	return nil
+}
@@ -0,0 +1,140 @@
+package dbase
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestSortSpill sorts, deduplicates and joins tables in memory and with a memory limit that spills to temporary files
+func TestSortSpill(t *testing.T) {
+	dir := t.TempDir()
+	names := []string{"delta", "alpha", "charlie", "alpha", "bravo", "delta", "echo", "alpha"}
+	orders := createSortTable(t, filepath.Join(dir, "ORDERS.DBF"), names)
+	defer orders.Close()
+	customers := createSortTable(t, filepath.Join(dir, "CUSTOMERS.DBF"), []string{"echo", "alpha", "bravo", "zulu"})
+	defer customers.Close()
+
+	limits := []struct {
+		name  string
+		limit int64
+	}{
+		{"memory", 0},
+		// Each row exceeds the limit, so every row is written to its own temporary file
+		{"spilled", 1},
+		{"partially spilled", 200},
+	}
+	for _, limit := range limits {
+		t.Run(limit.name, func(t *testing.T) {
+			temp := t.TempDir()
+			for _, file := range []*File{orders, customers} {
+				file.config.MemoryLimit = limit.limit
+				file.config.TempDir = temp
+			}
+
+			sorted := make([]string, 0)
+			spilled := 0
+			err := orders.Sort("NAME", nil, func(row *Row) error {
+				sorted = append(sorted, sortRowName(t, row))
+				files, err := os.ReadDir(temp)
+				spilled = len(files)
+				return err
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (spilled > 0) != (limit.limit > 0) {
+				t.Errorf("%d temporary files were written with a memory limit of %d", spilled, limit.limit)
+			}
+			expected := []string{"alpha 1", "alpha 3", "alpha 7", "bravo 4", "charlie 2", "delta 0", "delta 5", "echo 6"}
+			if !reflect.DeepEqual(sorted, expected) {
+				t.Errorf("sorted %v, expected %v", sorted, expected)
+			}
+
+			descending := make([]string, 0)
+			err = orders.Sort("NAME", &SortOptions{Descending: true}, func(row *Row) error {
+				descending = append(descending, sortRowName(t, row))
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected = []string{"echo 6", "delta 0", "delta 5", "charlie 2", "bravo 4", "alpha 1", "alpha 3", "alpha 7"}
+			if !reflect.DeepEqual(descending, expected) {
+				t.Errorf("sorted descending %v, expected %v", descending, expected)
+			}
+
+			unique := make([]string, 0)
+			err = orders.Deduplicate("NAME", nil, func(row *Row) error {
+				unique = append(unique, sortRowName(t, row))
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected = []string{"alpha 1", "bravo 4", "charlie 2", "delta 0", "echo 6"}
+			if !reflect.DeepEqual(unique, expected) {
+				t.Errorf("deduplicated %v, expected %v", unique, expected)
+			}
+
+			joined := make([]string, 0)
+			err = orders.Join(customers, "NAME", "NAME", nil, func(row *Row, other *Row) error {
+				joined = append(joined, sortRowName(t, row)+"/"+sortRowName(t, other))
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected = []string{"alpha 1/alpha 1", "alpha 3/alpha 1", "alpha 7/alpha 1", "bravo 4/bravo 2", "echo 6/echo 0"}
+			if !reflect.DeepEqual(joined, expected) {
+				t.Errorf("joined %v, expected %v", joined, expected)
+			}
+
+			files, err := os.ReadDir(temp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(files) > 0 {
+				t.Errorf("%d temporary files were not removed", len(files))
+			}
+		})
+	}
+}
+
+// createSortTable creates a table with a row for each name
+func createSortTable(t *testing.T, path string, names []string) *File {
+	t.Helper()
+	column, err := NewColumn("NAME", Character, 10, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := CreateTable(&Config{Filename: path}, column)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		row := file.NewRow()
+		err = row.FieldByName("NAME").SetValue(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = row.Add()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return file
+}
+
+// sortRowName returns the name and position of the row
+func sortRowName(t *testing.T, row *Row) string {
+	t.Helper()
+	name, err := row.ValueByName("NAME")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fmt.Sprintf("%s %d", strings.TrimSpace(name.(string)), row.Position)
+}
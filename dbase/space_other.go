@@ -0,0 +1,11 @@
+//go:build !windows && !linux && !darwin && !freebsd && !dragonfly
+// +build !windows,!linux,!darwin,!freebsd,!dragonfly
+
+package dbase
+
+import "math"
+
+// freeSpace is not available on this platform, the space is reported as unlimited
+func freeSpace(dir string) (uint64, error) {
+	return math.MaxUint64, nil
+}
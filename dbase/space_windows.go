@@ -0,0 +1,20 @@
+//go:build windows
+// +build windows
+
+package dbase
+
+import "golang.org/x/sys/windows"
+
+// freeSpace returns the bytes available to the user on the volume of the directory
+func freeSpace(dir string) (uint64, error) {
+	path, err := windows.UTF16PtrFromString(dir)
+	if err != nil {
+		return 0, err
+	}
+	var available, total, free uint64
+	err = windows.GetDiskFreeSpaceEx(path, &available, &total, &free)
+	if err != nil {
+		return 0, err
+	}
+	return available, nil
+}
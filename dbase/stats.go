@@ -0,0 +1,81 @@
+package dbase
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Stats contains the counters of a file handle since it was opened or since ResetStats.
+// The counters are updated atomically, so Stats can be called while other goroutines read or write the table,
+// e.g. by a metrics collector polling it.
+type Stats struct {
+	RowsRead      uint64        `json:"rows_read"`      // Rows read from the table file
+	RowsWritten   uint64        `json:"rows_written"`   // Rows written or appended
+	FieldsWritten uint64        `json:"fields_written"` // Fields written with UpdateField
+	MemosRead     uint64        `json:"memos_read"`     // Memos read from the memo file
+	MemosWritten  uint64        `json:"memos_written"`  // Memos written to the memo file
+	BytesRead     uint64        `json:"bytes_read"`     // Bytes read from the table and memo file
+	BytesWritten  uint64        `json:"bytes_written"`  // Bytes written to the table and memo file
+	CacheHits     uint64        `json:"cache_hits"`     // Rows returned from the row cache, see EnableRowCache
+	CacheMisses   uint64        `json:"cache_misses"`   // Rows not found in the row cache
+	DecodeErrors  uint64        `json:"decode_errors"`  // Rows that could not be decoded
+	IOTime        time.Duration `json:"io_time"`        // Time spent reading and writing the table and memo file
+	DecodeTime    time.Duration `json:"decode_time"`    // Time spent decoding rows, including the memos read for them
+}
+
+// fileStats are the counters of a file handle
+type fileStats struct {
+	rowsRead      atomic.Uint64
+	rowsWritten   atomic.Uint64
+	fieldsWritten atomic.Uint64
+	memosRead     atomic.Uint64
+	memosWritten  atomic.Uint64
+	bytesRead     atomic.Uint64
+	bytesWritten  atomic.Uint64
+	cacheHits     atomic.Uint64
+	cacheMisses   atomic.Uint64
+	decodeErrors  atomic.Uint64
+	ioTime        atomic.Int64
+	decodeTime    atomic.Int64
+}
+
+// Stats returns the current counters of the file handle
+func (file *File) Stats() Stats {
+	s := &file.stats
+	return Stats{
+		RowsRead:      s.rowsRead.Load(),
+		RowsWritten:   s.rowsWritten.Load(),
+		FieldsWritten: s.fieldsWritten.Load(),
+		MemosRead:     s.memosRead.Load(),
+		MemosWritten:  s.memosWritten.Load(),
+		BytesRead:     s.bytesRead.Load(),
+		BytesWritten:  s.bytesWritten.Load(),
+		CacheHits:     s.cacheHits.Load(),
+		CacheMisses:   s.cacheMisses.Load(),
+		DecodeErrors:  s.decodeErrors.Load(),
+		IOTime:        time.Duration(s.ioTime.Load()),
+		DecodeTime:    time.Duration(s.decodeTime.Load()),
+	}
+}
+
+// ResetStats sets all counters of the file handle to zero, e.g. to measure a single job
+func (file *File) ResetStats() {
+	s := &file.stats
+	for _, counter := range []*atomic.Uint64{&s.rowsRead, &s.rowsWritten, &s.fieldsWritten, &s.memosRead, &s.memosWritten, &s.bytesRead, &s.bytesWritten, &s.cacheHits, &s.cacheMisses, &s.decodeErrors} {
+		counter.Store(0)
+	}
+	s.ioTime.Store(0)
+	s.decodeTime.Store(0)
+}
+
+// countRead adds a read of the bytes that started at the time
+func (s *fileStats) countRead(bytes int, start time.Time) {
+	s.bytesRead.Add(uint64(bytes))
+	s.ioTime.Add(int64(time.Since(start)))
+}
+
+// countWrite adds a write of the bytes that started at the time
+func (s *fileStats) countWrite(bytes int, start time.Time) {
+	s.bytesWritten.Add(uint64(bytes))
+	s.ioTime.Add(int64(time.Since(start)))
+}
@@ -0,0 +1,165 @@
+package dbase
+
+import "fmt"
+
+// Support describes how far this package supports the files created by a product
+type Support struct {
+	Product  string        // Product that creates the files, e.g. "Clipper"
+	Family   Family        // Family reported by Detect for the files of the product
+	Versions []FileVersion // File type bytes written by the product
+	Memo     MemoType      // Memo file format of the product
+	Indexes  []IndexType   // Index file formats of the product
+	Read     SupportLevel  // Rows and columns can be read
+	Write    SupportLevel  // Rows can be written
+	Memos    SupportLevel  // Memo fields can be read and written
+	Index    SupportLevel  // Index files can be read or created
+	Notes    string        // Restrictions of the support
+}
+
+// String returns the name of the support level
+func (l SupportLevel) String() string {
+	switch l {
+	case SupportNone:
+		return "none"
+	case SupportUntested:
+		return "untested"
+	case SupportTested:
+		return "tested"
+	}
+	return fmt.Sprintf("SupportLevel(%d)", byte(l))
+}
+
+// SupportMatrix returns which features are known to work for the files of the dBase products.
+// Tested features are verified by the tests of this package with files created by the product in testdata,
+// written files are verified by reading them back. Untested features are implemented for the file format
+// but have not been verified, and tables of untested file versions require Config.Untested.
+// The returned slice is a copy and can be modified.
+func SupportMatrix() []*Support {
+	return []*Support{
+		{
+			Product:  "Visual FoxPro",
+			Family:   FamilyVisualFoxPro,
+			Versions: []FileVersion{FoxPro, FoxProAutoincrement, FoxProVar},
+			Memo:     MemoFPT,
+			Indexes:  []IndexType{IndexCDX},
+			Read:     SupportTested,
+			Write:    SupportTested,
+			Memos:    SupportTested,
+			Index:    SupportTested,
+			Notes: "CDX tags are read with IndexInfo. CreateIndex writes index snapshots of tables without a structural index, " +
+				"they are not structural and not maintained when rows are written, structural indexes are never written. " +
+				"Written rows and created tags are verified by reading them back, not by opening them in Visual FoxPro",
+		},
+		{
+			Product:  "FoxPro 2.x",
+			Family:   FamilyFoxPro2,
+			Versions: []FileVersion{FoxBasePlus, FoxPro2Memo},
+			Memo:     MemoFPT,
+			Indexes:  []IndexType{IndexCDX, IndexIDX},
+			Read:     SupportUntested,
+			Write:    SupportUntested,
+			Memos:    SupportUntested,
+			Index:    SupportUntested,
+			Notes:    "tables without memo are detected by their CDX or IDX file, IDX files are not supported",
+		},
+		{
+			Product:  "FoxBASE",
+			Family:   FamilyFoxBase,
+			Versions: []FileVersion{FoxBase, FoxBase2},
+			Read:     SupportUntested,
+			Write:    SupportUntested,
+			Memos:    SupportNone,
+			Index:    SupportNone,
+		},
+		{
+			Product:  "dBase III",
+			Family:   FamilyDBase3,
+			Versions: []FileVersion{FoxBasePlus, FoxBasePlusMemo},
+			Memo:     MemoDBT,
+			Indexes:  []IndexType{IndexNDX},
+			Read:     SupportUntested,
+			Write:    SupportUntested,
+			Memos:    SupportNone,
+			Index:    SupportNone,
+			Notes:    "DBT memo files are not supported",
+		},
+		{
+			Product:  "dBase IV",
+			Family:   FamilyDBase4,
+			Versions: []FileVersion{FoxBasePlus, DBaseMemo},
+			Memo:     MemoDBT,
+			Indexes:  []IndexType{IndexMDX},
+			Read:     SupportUntested,
+			Write:    SupportUntested,
+			Memos:    SupportNone,
+			Index:    SupportNone,
+			Notes:    "DBT memo files are not supported",
+		},
+		{
+			Product:  "dBase IV SQL",
+			Family:   FamilyDBaseSQLTable,
+			Versions: []FileVersion{DBaseSQLTable, DBaseSQLSystem, DBaseSQLMemo},
+			Memo:     MemoDBT,
+			Read:     SupportUntested,
+			Write:    SupportUntested,
+			Memos:    SupportNone,
+			Index:    SupportNone,
+			Notes:    "DBT memo files are not supported",
+		},
+		{
+			Product:  "dBase V",
+			Family:   FamilyDBase5,
+			Versions: []FileVersion{DBase5},
+			Read:     SupportUntested,
+			Write:    SupportUntested,
+			Memos:    SupportNone,
+			Index:    SupportNone,
+		},
+		{
+			Product:  "dBase 7",
+			Family:   FamilyDBase7,
+			Versions: []FileVersion{DBase7, DBase7Memo},
+			Memo:     MemoDBT,
+			Indexes:  []IndexType{IndexMDX},
+			Read:     SupportNone,
+			Write:    SupportNone,
+			Memos:    SupportNone,
+			Index:    SupportNone,
+			Notes:    "the 48 byte column descriptors of level 7 tables are not supported",
+		},
+		{
+			Product:  "Clipper",
+			Family:   FamilyClipper,
+			Versions: []FileVersion{FoxBasePlus, FoxBasePlusMemo},
+			Memo:     MemoDBT,
+			Indexes:  []IndexType{IndexNTX},
+			Read:     SupportUntested,
+			Write:    SupportUntested,
+			Memos:    SupportNone,
+			Index:    SupportNone,
+			Notes:    "tables are detected by their NTX file, DBT memo files are not supported",
+		},
+		{
+			Product:  "HiPer-Six",
+			Family:   FamilyHiPerSix,
+			Versions: []FileVersion{HiPerSixMemo},
+			Memo:     MemoSMT,
+			Read:     SupportUntested,
+			Write:    SupportUntested,
+			Memos:    SupportNone,
+			Index:    SupportNone,
+			Notes:    "SMT memo files are not supported",
+		},
+		{
+			Product:  "LibreOffice",
+			Family:   FamilyDBase3,
+			Versions: []FileVersion{FoxBasePlus, FoxBasePlusMemo},
+			Memo:     MemoDBT,
+			Read:     SupportUntested,
+			Write:    SupportUntested,
+			Memos:    SupportNone,
+			Index:    SupportNone,
+			Notes:    "LibreOffice writes dBase III tables, DBT memo files are not supported",
+		},
+	}
+}
@@ -0,0 +1,356 @@
+package dbase
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// corpora are the directories of the tables created by each product, they back the tested entries of SupportMatrix.
+// Files of other products are added in a directory of their own, see testdata/README.md.
+var corpora = map[string]string{
+	"Visual FoxPro": filepath.Join("testdata", "vfp"),
+}
+
+// vfpCorpus is the directory of the tables created by Visual FoxPro
+var vfpCorpus = corpora["Visual FoxPro"]
+
+func TestSupportMatrixTested(t *testing.T) {
+	for _, support := range SupportMatrix() {
+		dir, ok := corpora[support.Product]
+		if ok {
+			files, err := os.ReadDir(dir)
+			if err != nil || len(files) == 0 {
+				t.Errorf("corpus %s of %s is missing or empty", dir, support.Product)
+			}
+			continue
+		}
+		for feature, level := range map[string]SupportLevel{"Read": support.Read, "Write": support.Write, "Memos": support.Memos, "Index": support.Index} {
+			if level == SupportTested {
+				t.Errorf("%s of %s is reported as tested without a corpus", feature, support.Product)
+			}
+		}
+	}
+	// Every directory of testdata has to be read by TestCorpusRead
+	entries, err := os.ReadDir("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	known := make(map[string]bool)
+	for _, dir := range corpora {
+		known[dir] = true
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && !known[filepath.Join("testdata", entry.Name())] {
+			t.Errorf("directory testdata/%s is not assigned to a product", entry.Name())
+		}
+	}
+}
+
+// TestCorpusRead decodes the tables of the corpus of each product
+func TestCorpusRead(t *testing.T) {
+	tests := []struct {
+		product string
+		file    string
+		family  Family
+		version FileVersion
+		rows    uint32
+		values  map[string]interface{} // Values of the first row
+	}{
+		{
+			product: "Visual FoxPro",
+			file:    "TEST.DBF",
+			family:  FamilyVisualFoxPro,
+			version: FoxProVar,
+			rows:    3,
+			values: map[string]interface{}{
+				"PRODUCTID": int32(1),
+				"PRODNAME":  "TEST PRODUCT",
+				"DOUBLE":    78.9,
+				"DATE":      time.Date(2022, 4, 10, 0, 0, 0, 0, time.UTC),
+				"INTEGER":   4.56,
+				"FLOAT":     int32(123),
+				"ACTIVE":    true,
+				"DESC":      "PRODUCT DESCRIPTION",
+				"TAX":       19.99,
+				"INSTOCK":   int64(1),
+				"VAR_NIL":   "Test value with variable length",
+				"VAR":       "",
+			},
+		},
+		{
+			product: "Visual FoxPro",
+			file:    "employees.dbf",
+			family:  FamilyVisualFoxPro,
+			version: FoxPro,
+			rows:    3,
+			values: map[string]interface{}{
+				"EMPLOYEEID": int32(1),
+				"LASTNAME":   "Davolio",
+				"FIRSTNAME":  "Nancy",
+				"ADDRESS":    "908 W. Capital Way",
+				"TITLE":      "Salesperson",
+			},
+		},
+		{
+			product: "Visual FoxPro",
+			file:    "expense reports.dbf",
+			family:  FamilyVisualFoxPro,
+			version: FoxPro,
+			rows:    3,
+			values: map[string]interface{}{
+				"EXPENSERP2": "Expenses during sales trip.",
+				"DATESUBMIT": time.Date(1995, 3, 1, 0, 0, 0, 0, time.UTC),
+				"EXPENSERPT": "Feb. '95 Sales Trip",
+			},
+		},
+		{
+			product: "Visual FoxPro",
+			file:    "EXPENSES.DBC",
+			family:  FamilyVisualFoxPro,
+			version: FoxPro,
+			rows:    59,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.product+"/"+tt.file, func(t *testing.T) {
+			path := filepath.Join(corpora[tt.product], tt.file)
+			detection, err := Detect(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if detection.Family != tt.family || detection.Version != tt.version {
+				t.Fatalf("detected %s version 0x%02x, expected %s version 0x%02x", detection.Family, byte(detection.Version), tt.family, byte(tt.version))
+			}
+			if !detection.Capabilities.Tested {
+				t.Fatal("file version of the corpus is not reported as tested")
+			}
+			file, err := OpenTable(&Config{Filename: path, ReadOnly: true, TrimSpaces: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+			if file.RowsCount() != tt.rows {
+				t.Fatalf("expected %d rows, got %d", tt.rows, file.RowsCount())
+			}
+			rows, err := file.Rows(false, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rows) != int(tt.rows) {
+				t.Fatalf("expected %d rows to be read, got %d", tt.rows, len(rows))
+			}
+			compareRow(t, rows[0], tt.values)
+		})
+	}
+}
+
+func TestVisualFoxProWrite(t *testing.T) {
+	path := copyCorpus(t, "TEST.DBF", "TEST.FPT")
+	file, err := OpenTable(&Config{Filename: path, TrimSpaces: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := map[string]interface{}{
+		"PRODNAME": "WRITTEN PRODUCT",
+		"DOUBLE":   1.25,
+		"DATE":     time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		"ACTIVE":   true,
+		"DESC":     "A memo written by the test",
+		"TAX":      7.5,
+		"VAR":      "short",
+	}
+	row := file.NewRow()
+	for name, val := range values {
+		err = row.FieldByName(name).SetValue(val)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+	}
+	err = row.Add()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Replace the memo of an existing row
+	err = file.GoTo(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := file.Row()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = first.FieldByName("DESC").SetValue("A longer product description replacing the memo of the first row")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = first.Write()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = file.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, err = OpenTable(&Config{Filename: path, ReadOnly: true, TrimSpaces: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if file.RowsCount() != 4 {
+		t.Fatalf("expected 4 rows after adding a row, got %d", file.RowsCount())
+	}
+	rows, err := file.Rows(false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compareRow(t, rows[3], values)
+	compareRow(t, rows[0], map[string]interface{}{
+		"PRODNAME": "TEST PRODUCT",
+		"DESC":     "A longer product description replacing the memo of the first row",
+	})
+	report, err := file.CheckCompanions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Valid() {
+		t.Errorf("memo file is inconsistent after writing: %v", report.Issues)
+	}
+}
+
+func TestVisualFoxProIndex(t *testing.T) {
+	file, err := OpenTable(&Config{Filename: filepath.Join(vfpCorpus, "employees.dbf"), ReadOnly: true, TrimSpaces: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := file.IndexInfo()
+	file.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"PRIMARYKEY": "employeeid",
+		"DEPARTMENT": "departmentname",
+		"LASTNAME":   "lastname",
+		"EMAILNAME":  "emailname",
+		"POSTALCODE": "postalcode",
+	}
+	if info == nil || len(info.Tags) != len(expected) {
+		t.Fatalf("expected %d tags, got %+v", len(expected), info)
+	}
+	for _, tag := range info.Tags {
+		if expected[tag.Name] != tag.Expression {
+			t.Errorf("tag %s: expected expression %q, got %q", tag.Name, expected[tag.Name], tag.Expression)
+		}
+	}
+
+	// The structural index of the product is not touched by CreateIndex
+	path := copyCorpus(t, "employees.dbf", "employees.FPT", "employees.CDX")
+	file, err = OpenTable(&Config{Filename: path, TrimSpaces: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cdx := filepath.Join(filepath.Dir(path), "employees.CDX")
+	original, err := os.ReadFile(cdx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = file.CreateIndex("FIRSTNAME", "UPPER(FIRSTNAME)", nil)
+	file.Close()
+	if !errors.Is(err, ErrStructuralIndex) {
+		t.Fatalf("expected ErrStructuralIndex for a table with a structural index, got %v", err)
+	}
+	current, err := os.ReadFile(cdx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(original, current) {
+		t.Error("structural index was changed by CreateIndex")
+	}
+
+	// Tags of a snapshot are read back, the table stays without structural index and the file does not grow when a tag is replaced
+	path = copyCorpus(t, "TEST.DBF", "TEST.FPT")
+	file, err = OpenTable(&Config{Filename: path, TrimSpaces: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	// The memo file of the corpus is shorter than its header states, only issues added by CreateIndex are reported
+	before, err := file.CheckCompanions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = file.CreateIndex("PRODNAME", "UPPER(PRODNAME)", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cdx = filepath.Join(filepath.Dir(path), "TEST.CDX")
+	created, err := os.Stat(cdx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = file.CreateIndex("PRODNAME", "UPPER(PRODNAME)", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replaced, err := os.Stat(cdx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replaced.Size() != created.Size() {
+		t.Errorf("index file grew from %d to %d bytes when the tag was replaced", created.Size(), replaced.Size())
+	}
+	if StructuralFlag.Defined(file.Header().TableFlags) {
+		t.Error("structural flag was set by CreateIndex")
+	}
+	info, err = file.IndexInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info == nil || len(info.Tags) != 1 || info.Tags[0].Expression != "UPPER(PRODNAME)" {
+		t.Fatalf("expected the created tag, got %+v", info)
+	}
+	report, err := file.CheckCompanions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(report.Issues, before.Issues) || report.IndexTags != 1 {
+		t.Errorf("index file is inconsistent after CreateIndex: %d tags, %v", report.IndexTags, report.Issues)
+	}
+}
+
+// compareRow compares the values of the row as returned by ToMap, so text values are trimmed like in the exports
+func compareRow(t *testing.T, row *Row, expected map[string]interface{}) {
+	t.Helper()
+	values, err := row.ToMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, val := range expected {
+		if !reflect.DeepEqual(values[name], val) {
+			t.Errorf("%s: expected %#v, got %#v", name, val, values[name])
+		}
+	}
+}
+
+// copyCorpus copies files of the corpus into a temporary directory and returns the path of the first one
+func copyCorpus(t *testing.T, names ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(vfpCorpus, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(filepath.Join(dir, name), data, 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return filepath.Join(dir, names[0])
+}
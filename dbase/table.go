@@ -6,9 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode"
+
+	"golang.org/x/text/encoding/charmap"
 )
 
 // Configures the file you want to open.
@@ -16,17 +21,52 @@ import (
 // If Converter and InterpretCodePage are both not set the package will try to interpret the code page mark.
 // To open untested files set Untested to true. Tested files are defined in the constants.go file.
 type Config struct {
-	Filename                          string            // The filename of the DBF file.
-	Converter                         EncodingConverter // The encoding converter to use.
-	Exclusive                         bool              // If true the file is opened in exclusive mode.
-	Untested                          bool              // If true the file version is not checked.
-	TrimSpaces                        bool              // Trimspaces default value
-	DisableConvertFilenameUnderscores bool              // If false underscores in the table filename are converted to spaces.
-	ReadOnly                          bool              // If true the file is opened in read-only mode.
-	WriteLock                         bool              // Whether or not the write operations should lock the record
-	ValidateCodePage                  bool              // Whether or not the code page mark should be validated.
-	InterpretCodePage                 bool              // Whether or not the code page mark should be interpreted. Ignores the defined converter.
-	IO                                IO                // The IO interface to use.
+	Filename                          string              // The filename of the DBF file.
+	Converter                         EncodingConverter   // The encoding converter to use.
+	Exclusive                         bool                // If true the file is opened in exclusive mode.
+	Untested                          bool                // If true the file version is not checked.
+	TrimSpaces                        bool                // Trimspaces default value
+	TrimMode                          TrimMode            // Trim mode of string values, overrides TrimSpaces if set.
+	TrimCutset                        string              // Characters to trim, e.g. "\x00" to only remove null bytes. If empty white space is trimmed.
+	DisableConvertFilenameUnderscores bool                // If false underscores in the table filename are converted to spaces.
+	ReadOnly                          bool                // If true the file is opened in read-only mode.
+	WriteLock                         bool                // Whether or not the write operations should lock the record
+	ValidateCodePage                  bool                // Whether or not the code page mark should be validated.
+	InterpretCodePage                 bool                // Whether or not the code page mark should be interpreted. Ignores the defined converter.
+	RepairEOF                         bool                // If true a missing or repeated end of file marker is repaired when the table is opened.
+	RecoverRows                       bool                // If true rows beyond the rows count of the header are read and flagged as unconfirmed. Requires ReadOnly.
+	SkipMissingMemo                   bool                // If true a table whose memo file is missing is opened anyway and memo columns are read as nil. Requires ReadOnly.
+	WithoutMemo                       bool                // If true the memo file is not opened and memo columns are read as nil, for workloads that only need scalar columns. Requires ReadOnly.
+	WithoutIndex                      bool                // If true the index file is neither read nor written, IndexInfo reports no structural index and CreateIndex fails.
+	MemoLineEnding                    LineEnding          // Line ending of memo text in the memo file, text is normalized to LF on read and converted back on write.
+	TrimMemoPadding                   bool                // If true trailing null bytes and end of file markers are removed from memo text on read.
+	DetectMemoBOM                     bool                // If true memo text starting with a UTF-8 or UTF-16 byte order mark is decoded accordingly.
+	CaseInsensitiveNames              bool                // If true column names are compared case-insensitive when looking up columns and fields by name.
+	StrictMapping                     bool                // If true RowFromMap, RowFromJSON and RowFromStruct fail on unknown keys and missing non-nullable columns.
+	NilPolicy                         NilPolicy           // Empty fields that are decoded to nil instead of the zero value of the column type.
+	JSON                              JSONOptions         // Options of ToJSON, ToJSONInto and the JSONL, YAML and TOML exports.
+	DateLayouts                       []string            // Layouts accepted for string values of D and T columns, tried in order. Add EpochSeconds to accept Unix timestamps. Defaults to DefaultDateLayouts.
+	FastRead                          bool                // If true rows are decoded without validating the deletion flag and without wrapping field errors. Use for trusted files only.
+	Throttle                          Throttle            // Limits the throughput of full table scans like Validate, Analyze and the exports.
+	AutoReopen                        bool                // If true the table is reopened when the file was replaced, checked before reading rows.
+	ReopenInterval                    time.Duration       // Minimum time between two checks for a replaced file (default: 1 second).
+	Reproducible                      bool                // If true exports of unchanged tables are byte-identical, see ExportCSV.
+	Schema                            map[string]CastType // Go type of columns by name, the values are cast to it on read, see Row.ToMap.
+	Reference                         []*Column           // Reference schema, the output of rows is mapped by column name against it, see SchemaDrift.
+	SchemaCache                       *SchemaCache        // Cache of parsed columns shared by tables with identical structure, see NewSchemaCache.
+	RowCache                          *RowCache           // Cache of decoded rows shared by the tables opened with this configuration, see NewRowCache.
+	CastPolicy                        CastPolicy          // Handling of casts that lose information, e.g. numbers with fractions cast to int64.
+	MemoryLimit                       int64               // Maximum estimated memory in bytes of the rows collected by Rows and RowsWithErrors, which fail beyond it, and of the rows Sort, Deduplicate and Join keep in memory before writing them to temporary files. 0 for no limit.
+	TempDir                           string              // Directory of the temporary files of Sort, Deduplicate and Join (default: os.TempDir()).
+	MaxArchiveSize                    int64               // Maximum size in bytes of the table and memo data of an archive, which OpenArchive keeps in memory (default: DefaultMaxArchiveSize).
+	VerifyReads                       uint8               // Number of re-reads of a row that can not be decoded, to tell corrupted reads (ErrIntegrity) from corrupt files. 0 disables the verification.
+	CheckCompanions                   bool                // If true the memo and index file are checked against the table on open, see CompanionReport.
+	MemoHistory                       bool                // If true changed memos are written with a link to the previous version of the field, see Row.MemoHistory.
+	SharedWrite                       bool                // Experimental: if true writes follow the locking protocol of Visual FoxPro, header locks while appending and row locks while updating, see File.LockRow. The interplay with running FoxPro applications is not verified. Tables with a structural index are rejected, as the index is not updated.
+	LockRetry                         LockRetry           // Retries of locks held by other processes with SharedWrite, like SET REPROCESS in FoxPro.
+	IgnoreDeleted                     bool                // If true deleted rows are hidden from reads, searches, counts, exports, relation lookups and unique key checks, like SET DELETED ON in FoxPro.
+	DuplicateNames                    DuplicatePolicy     // Keys of columns whose name is already used by a previous column in ToMap, ToJSON and the exports, see DuplicateColumns.
+	IO                                IO                  // The IO interface to use.
 }
 
 // Containing DBF header information like dBase FileType, last change and rows count.
@@ -53,9 +93,15 @@ type MemoHeader struct {
 
 // Table is a struct containing the table columns, modifications and the row pointer
 type Table struct {
-	columns    []*Column       // Columns defined in this table
-	mods       []*Modification // Modification to change values or name of fields
-	rowPointer uint32          // Internal row pointer, can be moved
+	columns    []*Column                      // Columns defined in this table
+	mods       []*Modification                // Modification to change values or name of fields
+	rowPointer uint32                         // Internal row pointer, can be moved
+	offsets    []uint32                       // Byte offsets of the columns within a row, calculated on first use
+	converters map[*Column]EncodingConverter  // Encoding of columns that differ from the table encoding
+	defaults   map[*Column]func() interface{} // Default values of columns applied to new rows
+	hidden     map[*Column]bool               // Columns excluded from the output, true if the value is redacted instead
+	names      atomic.Value                   // Positions and names of the columns, built on first lookup, see columnNames
+	duplicates map[*Column]int                // Occurrence of columns whose name is already used by a previous column, 2 for the first duplicate
 }
 
 // Column is a struct containing the column information
@@ -73,34 +119,55 @@ type Column struct {
 
 // Row is a struct containing the row Position, deleted flag and data fields
 type Row struct {
-	handle     *File    // Pointer to the DBF object this row belongs to
-	Position   uint32   // Position of the row in the file
-	ByteOffset int64    // Byte offset of the row in the file
-	Deleted    bool     // Deleted flag
-	fields     []*Field // Fields in this row
+	handle      *File    // Pointer to the DBF object this row belongs to
+	Position    uint32   // Position of the row in the file
+	ByteOffset  int64    // Byte offset of the row in the file
+	Deleted     bool     // Deleted flag
+	Unconfirmed bool     // True if the row was recovered beyond the rows count of the header
+	fields      []*Field // Fields in this row
 }
 
 // Field is a row data field
 type Field struct {
-	column *Column     // Pointer to the column this field belongs to
-	value  interface{} // Value of the field
+	column   *Column     // Pointer to the column this field belongs to
+	value    interface{} // Value of the field
+	previous uint32      // Memo block of the stored value, linked as previous version with Config.MemoHistory
 }
 
 // Modification allows to change the column name or value type
 type Modification struct {
 	TrimSpaces  bool                                   // Trim spaces from string values
+	TrimMode    TrimMode                               // Trim mode of string values, overrides TrimSpaces and the trim mode of the config if set
+	TrimCutset  string                                 // Characters to trim, overrides the trim cutset of the config if set
 	Convert     func(interface{}) (interface{}, error) // Conversion function to convert the value
 	ExternalKey string                                 // External key to use for the column
 }
 
-// Create a new DBF file with the specified version, configuration and columns
+// Create a new DBF file with the specified version, configuration and columns.
+// Column names must be unique regardless of case, the config is copied so the caller's config is not changed.
+// CreateTable chooses the version, converter and memo block size for Visual FoxPro tables.
 func New(version FileVersion, config *Config, columns []*Column, memoBlockSize uint16, io IO) (*File, error) {
 	if len(columns) == 0 {
 		return nil, errors.New("no columns defined")
 	}
-	if config.Converter == nil {
+	if config == nil || config.Converter == nil {
 		return nil, errors.New("no converter defined")
 	}
+	names := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		name := normalizeColumnName(column.Name())
+		if names[name] {
+			return nil, newError("dbase-table-new-1", fmt.Errorf("%w, column %v is defined more than once", ErrDuplicateColumn, column.Name()))
+		}
+		names[name] = true
+	}
+	err := checkLimits(columns)
+	if err != nil {
+		return nil, err
+	}
+	// The config of the caller is not changed, the table keeps its own copy
+	copied := *config
+	config = &copied
 	file := &File{
 		config: config,
 		io:     io,
@@ -143,10 +210,16 @@ func New(version FileVersion, config *Config, columns []*Column, memoBlockSize u
 		// Add columns to the table
 		file.table.columns = append(file.table.columns, column)
 	}
-	// If there are memo fields, add the memo header
+	file.invalidateColumnNames()
+	file.detectDuplicates()
+	file.table.mods = make([]*Modification, len(file.table.columns))
+	// If there are memo fields, add the memo header, the first memo is written behind it
 	if memoField {
+		if memoBlockSize == 0 {
+			memoBlockSize = defaultMemoBlockSize
+		}
 		file.memoHeader = &MemoHeader{
-			NextFree:  0,
+			NextFree:  uint32((memoHeaderSize + int(memoBlockSize) - 1) / int(memoBlockSize)),
 			Unused:    [2]byte{0x00, 0x00},
 			BlockSize: memoBlockSize,
 		}
@@ -174,7 +247,7 @@ func New(version FileVersion, config *Config, columns []*Column, memoBlockSize u
 		debugf("Initializing null flag column - length: %v", length)
 	}
 	// Create the files
-	err := file.Create()
+	err = file.Create()
 	if err != nil {
 		return nil, err
 	}
@@ -188,6 +261,11 @@ func New(version FileVersion, config *Config, columns []*Column, memoBlockSize u
 	if err != nil {
 		return nil, err
 	}
+	// Write the end of file marker
+	err = file.writeEOF()
+	if err != nil {
+		return nil, err
+	}
 	// Write the memo header
 	if file.memoHeader != nil {
 		err = file.WriteMemoHeader(0)
@@ -195,6 +273,40 @@ func New(version FileVersion, config *Config, columns []*Column, memoBlockSize u
 			return nil, err
 		}
 	}
+	file.identify()
+	file.deletedHidden = config.IgnoreDeleted
+	return file, nil
+}
+
+// CreateTable creates a new Visual FoxPro table at config.Filename with the columns.
+// Without config.Converter the text is encoded as Windows-1252. The file version is chosen by the columns,
+// FoxProVar for tables with varchar, varbinary or nullable columns, FoxProAutoincrement for tables with autoincrement columns
+// and FoxPro otherwise. Memos are written in blocks of 64 bytes, use New for other block sizes and file versions.
+// The file name is upper-cased like by FoxPro, the directories are kept. The table is created by New,
+// so column names must be unique regardless of case and the caller's config is not changed.
+func CreateTable(config *Config, columns ...*Column) (*File, error) {
+	if config == nil || len(config.Filename) == 0 {
+		return nil, newError("dbase-table-createtable-1", fmt.Errorf("no filename defined"))
+	}
+	if config.Converter == nil {
+		// New copies the config, the converter is set on a copy as well
+		copied := *config
+		copied.Converter = NewDefaultConverter(charmap.Windows1252)
+		config = &copied
+	}
+	version := FoxPro
+	for _, column := range columns {
+		switch {
+		case column.DataType == byte(Varchar) || column.DataType == byte(Varbinary) || column.Flag&byte(NullableFlag) != 0:
+			version = FoxProVar
+		case column.Flag&byte(AutoincrementFlag) != 0 && version == FoxPro:
+			version = FoxProAutoincrement
+		}
+	}
+	file, err := New(version, config, columns, defaultMemoBlockSize, config.IO)
+	if err != nil {
+		return nil, newError("dbase-table-createtable-2", err)
+	}
 	return file, nil
 }
 
@@ -217,7 +329,7 @@ func NewColumn(name string, dataType DataType, length uint8, decimals uint8, nul
 		Step:      uint16(0),
 		Reserved:  [7]byte{},
 	}
-	copy(column.FieldName[:], []byte(strings.ToUpper(name))[:11])
+	copy(column.FieldName[:], strings.ToUpper(name))
 	debugf("Creating new column: %v - type: %v - length: %v - decimals: %v - nullable: %v - position: %v - flag: %v", name, dataType, length, decimals, nullable, column.Position, column.Flag)
 	// Set the appropriate flag for nullable fields
 	if nullable {
@@ -291,9 +403,17 @@ func (h *Header) Modified(base int) time.Time {
 // Returns the calculated number of columns from the header info alone (without the need to read the columninfo from the header).
 // This is the fastest way to determine the number of rows in the file.
 func (h *Header) ColumnsCount() uint16 {
+	if h.FirstRow < 296 {
+		return 0
+	}
 	return (h.FirstRow - 296) / 32
 }
 
+// Returns the file version of the table
+func (h *Header) Version() FileVersion {
+	return FileVersion(h.FileType)
+}
+
 // Returns the amount of records in the table
 func (h *Header) RecordsCount() uint32 {
 	return h.RowsCount
@@ -301,7 +421,7 @@ func (h *Header) RecordsCount() uint32 {
 
 // Returns the calculated file size based on the header info
 func (h *Header) FileSize() int64 {
-	return 296 + int64(h.ColumnsCount()*32) + int64(h.RowsCount*uint32(h.RowLength))
+	return 296 + int64(h.ColumnsCount())*32 + int64(h.RowsCount)*int64(h.RowLength)
 }
 
 // Returns if the internal row pointer is at end of file
@@ -358,8 +478,14 @@ func (file *File) ColumnNames() []string {
 }
 
 // Returns the column position of a column by name or -1 if not found.
+// The name is compared case-insensitive if Config.CaseInsensitiveNames is set.
 func (file *File) ColumnPosByName(colname string) int {
-	for i := 0; i < len(file.table.columns); i++ {
+	pos := file.ColumnPosByNameFold(colname)
+	if pos < 0 || file.config.CaseInsensitiveNames || file.table.columns[pos].Name() == colname {
+		return pos
+	}
+	// Another column may have the same name in a different case
+	for i := pos + 1; i < len(file.table.columns); i++ {
 		if file.table.columns[i].Name() == colname {
 			return i
 		}
@@ -367,6 +493,128 @@ func (file *File) ColumnPosByName(colname string) int {
 	return -1
 }
 
+// ColumnPosByNameFold returns the position of the column with the name compared case-insensitive
+// and without surrounding spaces, regardless of Config.CaseInsensitiveNames. Returns -1 if not found.
+func (file *File) ColumnPosByNameFold(colname string) int {
+	pos, ok := file.columnNames()[normalizeColumnName(colname)]
+	if !ok {
+		return -1
+	}
+	return pos
+}
+
+// columnIndex contains the names of the columns, built on the first lookup
+type columnIndex struct {
+	positions map[string]int     // Positions by normalized name, the first column wins for duplicate names
+	names     map[*Column]string // Names of the columns, so the output does not convert the name of each field again
+}
+
+// columnNames returns the positions of the columns by normalized name, the first column wins for duplicate names.
+// The index is built on the first lookup and rebuilt after the columns changed, see invalidateColumnNames.
+func (file *File) columnNames() map[string]int {
+	return file.columnIndex().positions
+}
+
+// columnName returns the name of the column like Column.Name without converting it again
+func (file *File) columnName(column *Column) string {
+	if name, ok := file.columnIndex().names[column]; ok {
+		return name
+	}
+	return column.Name()
+}
+
+// columnIndex returns the index of the column names, see columnNames
+func (file *File) columnIndex() *columnIndex {
+	if index, ok := file.table.names.Load().(*columnIndex); ok && index != nil {
+		return index
+	}
+	index := &columnIndex{
+		positions: make(map[string]int, len(file.table.columns)),
+		names:     make(map[*Column]string, len(file.table.columns)),
+	}
+	for i, column := range file.table.columns {
+		name := column.Name()
+		index.names[column] = name
+		if _, ok := index.positions[normalizeColumnName(name)]; !ok {
+			index.positions[normalizeColumnName(name)] = i
+		}
+	}
+	file.table.names.Store(index)
+	return index
+}
+
+// invalidateColumnNames discards the name index after columns were added, removed or renamed
+func (file *File) invalidateColumnNames() {
+	file.table.names.Store((*columnIndex)(nil))
+}
+
+// detectDuplicates records the columns whose name is already used by a previous column, names are compared like by the name index
+func (file *File) detectDuplicates() {
+	seen := make(map[string]int, len(file.table.columns))
+	file.table.duplicates = make(map[*Column]int)
+	for _, column := range file.table.columns {
+		if column.System() {
+			continue
+		}
+		name := normalizeColumnName(column.Name())
+		seen[name]++
+		if seen[name] > 1 {
+			file.table.duplicates[column] = seen[name]
+			debugf("Column %v of %v is defined %d times", column.Name(), file.config.Filename, seen[name])
+		}
+	}
+}
+
+// DuplicateColumns returns the names of the columns whose name is already used by a previous column, in column order.
+// Broken writers produce such tables, the keys of the duplicates in ToMap, ToJSON and the exports are defined by Config.DuplicateNames.
+func (file *File) DuplicateColumns() []string {
+	names := make([]string, 0, len(file.table.duplicates))
+	for _, column := range file.table.columns {
+		if _, ok := file.table.duplicates[column]; ok {
+			names = append(names, column.Name())
+		}
+	}
+	return names
+}
+
+// duplicateKey returns the key of the column according to Config.DuplicateNames, false if the column is left out
+func (file *File) duplicateKey(column *Column, key string) (string, bool, error) {
+	n, ok := file.table.duplicates[column]
+	if !ok {
+		return key, true, nil
+	}
+	switch file.config.DuplicateNames {
+	case DuplicateError:
+		return "", false, fmt.Errorf("%w, column %v is defined %d times", ErrDuplicateColumn, column.Name(), n)
+	case DuplicateSuffix:
+		return fmt.Sprintf("%s_%d", key, n), true, nil
+	case DuplicateKeepFirst:
+		return "", false, nil
+	}
+	// The keys are suffixed with DuplicateOverwrite as well, so the keys of ordered results and exports are unique.
+	// Only ToMap uses the column name, see mapKey.
+	return fmt.Sprintf("%s_%d", key, n), true, nil
+}
+
+// mapKey returns the key of the field in ToMap, the column name for duplicates with DuplicateOverwrite so the value of the last column wins
+func (file *File) mapKey(field *Field, key string) string {
+	n, ok := file.table.duplicates[field.column]
+	if ok && file.config.DuplicateNames == DuplicateOverwrite && key == fmt.Sprintf("%s_%d", field.Name(), n) {
+		return field.Name()
+	}
+	return key
+}
+
+// normalizeColumnName returns the name upper-cased and without surrounding spaces, as used by the name index
+func normalizeColumnName(name string) string {
+	return strings.ToUpper(strings.TrimSpace(name))
+}
+
+// Returns the column with the given name or nil if not found.
+func (file *File) ColumnByName(name string) *Column {
+	return file.Column(file.ColumnPosByName(name))
+}
+
 // Returns the column position of a column or -1 if not found.
 func (file *File) ColumnPos(column *Column) int {
 	for i := 0; i < len(file.table.columns); i++ {
@@ -396,11 +644,125 @@ func (file *File) SetColumnModificationByName(name string, mod *Modification) er
 	return nil
 }
 
+// SetColumnEncoding sets the encoding of a character or memo column that differs from the table encoding.
+// Values of the column are decoded and encoded with the converter, a nil converter removes the override.
+func (file *File) SetColumnEncoding(name string, converter EncodingConverter) error {
+	position := file.ColumnPosByName(name)
+	if position < 0 {
+		return newError("dbase-table-setcolumnencoding-1", fmt.Errorf("Column '%s' not found", name))
+	}
+	column := file.table.columns[position]
+	if DataType(column.DataType) != Memo && DataType(column.DataType) != Character {
+		return newError("dbase-table-setcolumnencoding-2", fmt.Errorf("encoding of column '%s' with type %s can not be changed", name, column.Type()))
+	}
+	// Cached rows were decoded with the previous encoding
+	file.rowCache.Invalidate()
+	if converter == nil {
+		delete(file.table.converters, column)
+		return nil
+	}
+	if file.table.converters == nil {
+		file.table.converters = make(map[*Column]EncodingConverter)
+	}
+	file.table.converters[column] = converter
+	return nil
+}
+
+// Sets a function returning the default value of the column with the given name.
+// The default is applied by NewRow and by RowFromMap, RowFromJSON and RowFromStruct if no value is supplied for the column.
+// The function is called for every new row, a nil function removes the default.
+// For reference columns missing in the table the default fills the column in the output, see SchemaDrift.
+func (file *File) SetDefault(name string, value func() interface{}) error {
+	column := file.missingReference(name)
+	if position := file.ColumnPosByName(name); position >= 0 {
+		column = file.table.columns[position]
+	}
+	if column == nil {
+		return newError("dbase-table-setdefault-1", fmt.Errorf("Column '%s' not found", name))
+	}
+	if value == nil {
+		delete(file.table.defaults, column)
+		return nil
+	}
+	if file.table.defaults == nil {
+		file.table.defaults = make(map[*Column]func() interface{})
+	}
+	file.table.defaults[column] = value
+	return nil
+}
+
+// Excludes the columns with the given names from ToMap, ToJSON, ToStruct and the exports.
+// The values can still be read with Field, ValueByName and the other row accessors.
+func (file *File) HideColumns(names ...string) error {
+	err := file.setHidden(names, false)
+	if err != nil {
+		return newError("dbase-table-hidecolumns-1", err)
+	}
+	return nil
+}
+
+// Replaces the values of the columns with the given names by RedactedValue in ToMap, ToJSON, ToStruct and the exports.
+// Unlike HideColumns the keys stay in the output.
+func (file *File) RedactColumns(names ...string) error {
+	err := file.setHidden(names, true)
+	if err != nil {
+		return newError("dbase-table-redactcolumns-1", err)
+	}
+	return nil
+}
+
+// Removes the columns with the given names from the hidden and redacted columns.
+// If no name is given all columns are shown again.
+func (file *File) ShowColumns(names ...string) error {
+	if len(names) == 0 {
+		file.table.hidden = nil
+		return nil
+	}
+	for _, name := range names {
+		position := file.ColumnPosByName(name)
+		if position < 0 {
+			return newError("dbase-table-showcolumns-1", fmt.Errorf("Column '%s' not found", name))
+		}
+		delete(file.table.hidden, file.table.columns[position])
+	}
+	return nil
+}
+
+// setHidden marks the columns as hidden or redacted, no column is changed if a name is not found
+func (file *File) setHidden(names []string, redact bool) error {
+	columns := make([]*Column, 0, len(names))
+	for _, name := range names {
+		position := file.ColumnPosByName(name)
+		if position < 0 {
+			return fmt.Errorf("Column '%s' not found", name)
+		}
+		columns = append(columns, file.table.columns[position])
+	}
+	if file.table.hidden == nil {
+		file.table.hidden = make(map[*Column]bool)
+	}
+	for _, column := range columns {
+		file.table.hidden[column] = redact
+	}
+	return nil
+}
+
 // Returns the column modification for a column at the given position
 func (file *File) GetColumnModification(position int) *Modification {
 	return file.table.mods[position]
 }
 
+// required returns true if the column is neither nullable, hidden nor filled automatically
+func (c *Column) required() bool {
+	return c.Flag&byte(NullableFlag|HiddenFlag) == 0 && c.Flag&byte(AutoincrementFlag) != byte(AutoincrementFlag)
+}
+
+// System returns true for columns maintained by the table: the _NullFlags column and columns with the hidden flag.
+// They can not be written through the field API, see ErrSystemColumn.
+func (c *Column) System() bool {
+	return c.Flag&byte(HiddenFlag) != 0 || c.Name() == nullFlagsName
+}
+
 // Returns the name of the column as a trimmed string (max length 10)
 func (c *Column) Name() string {
 	return string(bytes.TrimRight(c.FieldName[:], "\x00"))
@@ -411,17 +773,61 @@ func (c *Column) Type() string {
 	return string(c.DataType)
 }
 
+// Returns the data type of the column, e.g. to compare it with Character
+func (c *Column) Kind() DataType {
+	return DataType(c.DataType)
+}
+
 func (c *Column) Reflect() reflect.Type {
 	return DataType(c.DataType).Reflect()
 }
 
-// Returns all rows as a slice
+// RowError is the error of a row that could not be read
+type RowError struct {
+	Position uint32 // Position of the row in the table
+	Err      error  // Error returned when reading the row
+}
+
+// Error returns the position and the message of the error
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Position, e.Err)
+}
+
+// Unwrap returns the error of the row
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// RowsResult contains the rows that were read and the errors of the rows that could not be read
+type RowsResult struct {
+	Rows   []*Row      // Rows that were read successfully
+	Errors []*RowError // Errors of the rows that could not be read, in row order
+}
+
+// Valid returns true if all rows were read successfully
+func (r *RowsResult) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+// Returns all rows as a slice.
+// If skipInvalid is true, rows that can not be read are skipped and written to the quarantine file, if set.
+// Use RowsWithErrors to get their errors.
+// Fails with ErrMemoryLimit if the rows exceed Config.MemoryLimit, large tables should be streamed instead.
 func (file *File) Rows(skipInvalid bool, skipDeleted bool) ([]*Row, error) {
+	limit, err := file.memoryLimit()
+	if err != nil {
+		return nil, newError("dbase-table-rows-3", err)
+	}
 	rows := make([]*Row, 0)
-	for !file.EOF() {
+	// Hidden deleted rows are skipped before the position is taken, so errors name the row that was read
+	for file.skipHidden(); !file.EOF(); file.skipHidden() {
+		position := file.table.rowPointer
 		row, err := file.Next()
 		if err != nil {
 			if skipInvalid {
+				if err := file.reject(position, err); err != nil {
+					return nil, newError("dbase-table-rows-2", err)
+				}
 				continue
 			}
 			return nil, newError("dbase-table-rows-1", err)
@@ -431,42 +837,226 @@ func (file *File) Rows(skipInvalid bool, skipDeleted bool) ([]*Row, error) {
 		if row.Deleted && skipDeleted {
 			continue
 		}
+		err = limit.add(row)
+		if err != nil {
+			return nil, newError("dbase-table-rows-4", err)
+		}
 		rows = append(rows, row)
 	}
 	return rows, nil
 }
 
-// Reads the row and increments the row pointer by one
+// Returns all rows from the current row pointer like Rows with skipInvalid set,
+// but the errors of the skipped rows are collected with their positions instead of being discarded.
+// Skipped rows are written to the quarantine file, if set. Errors writing the quarantine file are collected as well.
+// If the rows exceed Config.MemoryLimit reading stops with an ErrMemoryLimit error.
+func (file *File) RowsWithErrors(skipDeleted bool) *RowsResult {
+	result := &RowsResult{
+		Rows:   make([]*Row, 0),
+		Errors: make([]*RowError, 0),
+	}
+	limit, err := file.memoryLimit()
+	if err != nil {
+		result.Errors = append(result.Errors, &RowError{Position: file.table.rowPointer, Err: err})
+		return result
+	}
+	for file.skipHidden(); !file.EOF(); file.skipHidden() {
+		position := file.table.rowPointer
+		row, err := file.Next()
+		if err != nil {
+			result.Errors = append(result.Errors, &RowError{Position: position, Err: err})
+			if err := file.reject(position, err); err != nil {
+				result.Errors = append(result.Errors, &RowError{Position: position, Err: err})
+			}
+			continue
+		}
+		// skip deleted rows
+		if row.Deleted && skipDeleted {
+			continue
+		}
+		if err := limit.add(row); err != nil {
+			result.Errors = append(result.Errors, &RowError{Position: row.Position, Err: err})
+			return result
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	return result
+}
+
+// ReadColumnBatch decodes the values of a single column for the rows from (inclusive) to to (exclusive).
+// Only the bytes of the requested column are interpreted, all other fields of the row are skipped.
+// The internal row pointer is restored after the batch has been read.
+func (file *File) ReadColumnBatch(name string, from, to uint32) ([]interface{}, error) {
+	pos := file.ColumnPosByName(name)
+	if pos < 0 {
+		return nil, newError("dbase-table-readcolumnbatch-1", fmt.Errorf("column '%s' not found", name))
+	}
+	if to > file.header.RowsCount {
+		to = file.header.RowsCount
+	}
+	if from > to {
+		return nil, newError("dbase-table-readcolumnbatch-2", fmt.Errorf("%w, invalid row range %v - %v", ErrInvalidPosition, from, to))
+	}
+	column := file.table.columns[pos]
+	start := int(file.offsets()[pos])
+	end := start + int(column.Length)
+	if end > int(file.header.RowLength) {
+		return nil, newError("dbase-table-readcolumnbatch-3", fmt.Errorf("column '%s' exceeds the row length", name))
+	}
+	debugf("Reading column batch %v for rows %d - %d", name, from, to)
+	pointer := file.table.rowPointer
+	defer func() {
+		file.table.rowPointer = pointer
+	}()
+	values := make([]interface{}, 0, to-from)
+	for i := from; i < to; i++ {
+		data, err := file.ReadRow(i)
+		if err != nil {
+			return nil, newError("dbase-table-readcolumnbatch-4", err)
+		}
+		// The row pointer is required to read the null flags of variable length columns
+		file.table.rowPointer = i
+		val, err := file.Interpret(data[start:end], column)
+		if err != nil {
+			return nil, newError("dbase-table-readcolumnbatch-5", err)
+		}
+		values = append(values, val)
+	}
+	return values, nil
+}
+
+// Reads the row and increments the row pointer by one.
+// If deleted rows are hidden the row pointer is moved past them, before and after the row is read.
 func (file *File) Next() (*Row, error) {
+	file.skipHidden()
 	row, err := file.Row()
 	file.Skip(1)
 	if err != nil {
 		return nil, newError("dbase-table-next-1", err)
 	}
+	file.skipHidden()
 	return row, err
 }
 
+// SetDeletedHidden hides deleted rows like SET DELETED ON in FoxPro, initially set by Config.IgnoreDeleted.
+// Next, Rows, the exports, Search and Count skip deleted rows, so call sites do not have to pass skipDeleted flags.
+// Row and GoTo still return deleted rows at an explicit position.
+// The setting belongs to the file, the config and other tables opened with it are not changed.
+// It waits for running writes, like the row pointer it must not be changed during a scan of another goroutine.
+func (file *File) SetDeletedHidden(hidden bool) {
+	debugf("Setting deleted rows hidden: %v", hidden)
+	file.writeMutex.Lock()
+	defer file.writeMutex.Unlock()
+	file.deletedHidden = hidden
+}
+
+// DeletedHidden returns true if deleted rows are hidden
+func (file *File) DeletedHidden() bool {
+	return file.deletedHidden
+}
+
+// Count returns the number of rows, without the deleted rows if they are hidden.
+// Only the deletion flags are read to count the rows.
+func (file *File) Count() (uint32, error) {
+	if !file.deletedHidden {
+		return file.header.RowsCount, nil
+	}
+	throttle := file.throttle()
+	count := uint32(0)
+	for position := uint32(0); position < file.header.RowsCount; position++ {
+		deleted, err := file.deletedAt(position)
+		if err != nil {
+			return 0, newError("dbase-table-count-1", err)
+		}
+		throttle.wait(1, 1)
+		if !deleted {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// skipHidden moves the row pointer past deleted rows if they are hidden.
+// Rows that can not be read are not skipped, so reading them returns the error.
+func (file *File) skipHidden() {
+	if !file.deletedHidden {
+		return
+	}
+	for file.table.rowPointer < file.header.RowsCount {
+		deleted, err := file.deletedAt(file.table.rowPointer)
+		if err != nil || !deleted {
+			return
+		}
+		file.table.rowPointer++
+	}
+}
+
+// deletedAt reads the deletion flag of the row at the position
+func (file *File) deletedAt(position uint32) (bool, error) {
+	offset, err := file.rowOffset(uint64(position))
+	if err != nil {
+		return false, err
+	}
+	flag, err := file.ReadRaw(false, offset, 1)
+	if err != nil {
+		return false, err
+	}
+	return Marker(flag[0]) == Deleted, nil
+}
+
 // Returns the requested row at file.rowPointer.
 func (file *File) Row() (*Row, error) {
+	err := file.autoReopen()
+	if err != nil {
+		return nil, newError("dbase-table-row-2", err)
+	}
+	if row, ok := file.rowCache.get(file.table.rowPointer); ok {
+		file.stats.cacheHits.Add(1)
+		file.runRowRead(row)
+		return row, nil
+	}
+	if file.rowCache != nil {
+		file.stats.cacheMisses.Add(1)
+	}
 	data, err := file.ReadRow(file.table.rowPointer)
 	if err != nil {
 		return nil, newError("dbase-table-row-1", err)
 	}
-	return file.BytesToRow(data)
+	row, err := file.BytesToRow(data)
+	if err != nil && file.config.VerifyReads > 0 {
+		row, err = file.verifyRow(file.table.rowPointer, data, row, err)
+	}
+	if err != nil {
+		return row, err
+	}
+	file.rowCache.put(row)
+	file.runRowRead(row)
+	return row, nil
+}
+
+// nextPosition returns the position of the next appended row, read under the write lock
+func (file *File) nextPosition() uint32 {
+	file.writeMutex.Lock()
+	defer file.writeMutex.Unlock()
+	return file.header.RowsCount + 1
 }
 
 // Returns a new Row struct with the same column structure as the dbf and the next row pointer
 func (file *File) NewRow() *Row {
 	row := &Row{
 		handle:   file,
-		Position: file.header.RowsCount + 1,
+		Position: file.nextPosition(),
 		Deleted:  false,
 		fields:   make([]*Field, 0),
 	}
 	for _, column := range file.table.columns {
+		var value interface{}
+		if def, ok := file.table.defaults[column]; ok {
+			value = def()
+		}
 		row.fields = append(row.fields, &Field{
 			column: column,
-			value:  nil,
+			value:  value,
 		})
 	}
 	debugf("Initiliazing new at position %d", row.Position)
@@ -486,6 +1076,9 @@ func (file *File) NewField(pos int, value interface{}) (*Field, error) {
 func (file *File) NewFieldByName(name string, value interface{}) (*Field, error) {
 	pos := file.ColumnPosByName(name)
 	if pos < 0 {
+		if strings.EqualFold(name, nullFlagsName) {
+			return nil, newError("dbase-table-newfieldbyname-2", fmt.Errorf("%w, column '%s' is maintained by the table", ErrSystemColumn, name))
+		}
 		return nil, newError("dbase-table-newfieldbyname-1", fmt.Errorf("column '%s' not found", name))
 	}
 	return file.NewField(pos, value)
@@ -500,6 +1093,8 @@ func (row *Row) Write() error {
 // Also increases the Next value by the amount of Step
 // Rewrites the columns header
 func (row *Row) Increment() error {
+	row.handle.writeMutex.Lock()
+	defer row.handle.writeMutex.Unlock()
 	for _, field := range row.fields {
 		if field.column.Flag == byte(AutoincrementFlag) {
 			field.value = int32(field.column.Next)
@@ -514,10 +1109,11 @@ func (row *Row) Increment() error {
 	return nil
 }
 
-// Appends the row as a new entry to the file
+// Appends the row as a new entry to the file.
+// The position is assigned when the row is written, so concurrently added rows get distinct positions.
 func (row *Row) Add() error {
-	row.Position = row.handle.header.RowsCount + 1
-	return row.Write()
+	row.Position = row.handle.nextPosition()
+	return row.handle.writeRow(row, true)
 }
 
 // Returns all values of a row as a slice of interface{}
@@ -563,11 +1159,24 @@ func (row *Row) FieldByName(name string) *Field {
 	return row.Field(row.handle.ColumnPosByName(name))
 }
 
-// SetValue allows to change the field value
+// Returns the field of a row by name or an error if the column does not exist
+func (row *Row) LookupField(name string) (*Field, error) {
+	field := row.FieldByName(name)
+	if field == nil {
+		return nil, newError("dbase-table-lookupfield-1", fmt.Errorf("column %v not found", name))
+	}
+	return field, nil
+}
+
+// SetValue allows to change the field value.
+// Returns an error wrapping ErrSystemColumn for system columns, they are maintained by the table.
 func (field *Field) SetValue(value interface{}) error {
 	if field == nil {
 		return newError("dbase-table-setvalue-1", fmt.Errorf("field is not defined by table"))
 	}
+	if field.column.System() {
+		return newError("dbase-table-setvalue-2", fmt.Errorf("%w, column '%s' is maintained by the table", ErrSystemColumn, field.Name()))
+	}
 	field.value = value
 	return nil
 }
@@ -595,9 +1204,24 @@ func (field Field) Column() *Column {
 // Converts raw row data to a Row struct
 // If the data points to a memo (FPT) file this file is also read
 func (file *File) BytesToRow(data []byte) (*Row, error) {
+	start := time.Now()
+	row, err := file.bytesToRow(data)
+	file.stats.decodeTime.Add(int64(time.Since(start)))
+	if err != nil {
+		file.stats.decodeErrors.Add(1)
+	}
+	return row, err
+}
+
+// bytesToRow decodes the row data, without validation and error wrapping if Config.FastRead is set
+func (file *File) bytesToRow(data []byte) (*Row, error) {
+	if file.config.FastRead {
+		return file.bytesToRowFast(data)
+	}
 	debugf("Converting row data (%d bytes) to row struct...", len(data))
 	rec := &Row{}
 	rec.Position = file.table.rowPointer
+	rec.Unconfirmed = file.config.RecoverRows && rec.Position >= file.confirmedRows
 	rec.handle = file
 	rec.fields = make([]*Field, 0)
 	if len(data) < int(file.header.RowLength) {
@@ -609,10 +1233,11 @@ func (file *File) BytesToRow(data []byte) (*Row, error) {
 		return nil, newError("dbase-table-bytestorow-2", fmt.Errorf("invalid row data, no delete flag found at beginning of row"))
 	}
 	// deleted flag already read
-	offset := uint16(1)
+	offsets := file.offsets()
 	for i := 0; i < int(file.ColumnsCount()); i++ {
 		column := file.table.columns[i]
-		val, err := file.Interpret(data[offset:offset+uint16(column.Length)], file.table.columns[i])
+		offset := int(offsets[i])
+		val, err := file.Interpret(data[offset:offset+int(column.Length)], file.table.columns[i])
 		if err != nil {
 			return rec, newError("dbase-table-bytestorow-3", err)
 		}
@@ -620,7 +1245,37 @@ func (file *File) BytesToRow(data []byte) (*Row, error) {
 			column: column,
 			value:  val,
 		})
-		offset += uint16(column.Length)
+	}
+	return rec, nil
+}
+
+// bytesToRowFast converts the row data without validating the deletion flag and the field lengths.
+// The fields are allocated in one block and errors of the interpreter are returned as they are.
+func (file *File) bytesToRowFast(data []byte) (*Row, error) {
+	if len(data) < int(file.header.RowLength) {
+		return nil, newError("dbase-table-bytestorowfast-1", fmt.Errorf("invalid row data size %v Bytes < %v Bytes", len(data), int(file.header.RowLength)))
+	}
+	columns := file.table.columns
+	offsets := file.offsets()
+	fields := make([]Field, len(columns))
+	rec := &Row{
+		handle:      file,
+		Position:    file.table.rowPointer,
+		Unconfirmed: file.config.RecoverRows && file.table.rowPointer >= file.confirmedRows,
+		Deleted:     Marker(data[0]) == Deleted,
+		fields:      make([]*Field, len(columns)),
+	}
+	for i, column := range columns {
+		offset := int(offsets[i])
+		val, err := file.interpret(data[offset:offset+int(column.Length)], column)
+		if file.config.NilPolicy != NilNone && file.isEmpty(data[offset:offset+int(column.Length)], val, column) {
+			val, err = nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		fields[i] = Field{column: column, value: val}
+		rec.fields[i] = &fields[i]
 	}
 	return rec, nil
 }
@@ -635,11 +1290,13 @@ func (row *Row) ToBytes() ([]byte, error) {
 	} else {
 		data[0] = byte(Active)
 	}
-	// deleted flag already read
-	offset := uint16(1)
+	offsets := row.handle.offsets()
 	varPos := 0
 	nullFlag := make([]byte, 1)
 	for _, field := range row.fields {
+		if row.handle.lengthAudit != nil {
+			row.handle.lengthAudit.observe(row.handle, field)
+		}
 		val, err := row.handle.GetRepresentation(field, false)
 		if err != nil {
 			return nil, newError("dbase-table-rowtobytes-1", err)
@@ -673,66 +1330,197 @@ func (row *Row) ToBytes() ([]byte, error) {
 				varPos++
 			}
 		}
-		copy(data[offset:offset+uint16(field.column.Length)], val)
-		offset += uint16(field.column.Length)
+		offset := int(row.handle.columnOffset(field.column))
+		copy(data[offset:offset+int(field.column.Length)], val)
 	}
 	// Append null flag column at the end of the row
 	if row.handle.nullFlagColumn != nil {
 		debugf("Appending null flag column at the end of the row => %b", nullFlag)
-		copy(data[offset:offset+uint16(row.handle.nullFlagColumn.Length)], nullFlag)
+		offset := int(offsets[len(offsets)-1])
+		copy(data[offset:offset+int(row.handle.nullFlagColumn.Length)], nullFlag)
 	}
 	return data, nil
 }
 
 // Returns a complete row as a map.
+// Values of columns declared in Config.Schema are cast to the declared type, like in all other outputs of rows,
+// so the types do not depend on the column types of a particular file.
 func (row *Row) ToMap() (map[string]interface{}, error) {
+	out := make(map[string]interface{}, len(row.fields))
+	err := row.ToMapInto(out)
+	if err != nil {
+		return nil, newError("dbase-table-tomap-2", err)
+	}
+	return out, nil
+}
+
+// ToMapInto writes the complete row into the given map.
+// The map is cleared before it is filled, so it can be reused across rows to reduce allocations.
+func (row *Row) ToMapInto(out map[string]interface{}) error {
 	debugf("Converting row %v to map...", row.Position)
-	out := make(map[string]interface{})
+	if out == nil {
+		return newError("dbase-table-tomapinto-1", fmt.Errorf("map is nil"))
+	}
+	for key := range out {
+		delete(out, key)
+	}
+	err := row.modifiedValues(func(field *Field, key string, val interface{}) {
+		out[row.handle.mapKey(field, key)] = val
+	})
+	if err != nil {
+		return newError("dbase-table-tomapinto-2", err)
+	}
+	return nil
+}
+
+// modifiedValues calls fn in column order with the field, key and value of each field after applying the modifications.
+// With a reference schema the values are returned in the order of the reference, see SchemaDrift.
+func (row *Row) modifiedValues(fn func(field *Field, key string, val interface{})) error {
+	if row.handle.drift != nil {
+		return row.referenceValues(fn)
+	}
+	for i := range row.fields {
+		err := row.modifiedValue(i, fn)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// modifiedValue calls fn with the field at the position after applying the modifications, hidden fields are skipped
+func (row *Row) modifiedValue(i int, fn func(field *Field, key string, val interface{})) error {
+	field := row.fields[i]
+	redact, hidden := row.handle.table.hidden[field.column]
+	if hidden && !redact {
+		return nil
+	}
+	val := field.GetValue()
+	if redact {
+		val = RedactedValue
+	}
+	mod := row.handle.table.mods[i]
+	// Unchanged strings are not converted to an interface again, so untrimmed values are passed on without allocation
+	if str, ok := val.(string); ok {
+		if trimmed := row.handle.trim(str, mod); len(trimmed) != len(str) {
+			val = trimmed
+		}
+	}
 	var err error
-	for i, field := range row.fields {
-		val := field.GetValue()
-		mod := row.handle.table.mods[i]
-		if row.handle.config.TrimSpaces {
-			if str, ok := val.(string); ok {
-				val = strings.TrimSpace(str)
-			}
+	if !redact {
+		val, err = row.handle.castValue(field.column, val)
+		if err != nil {
+			return newError("dbase-table-modifiedvalues-1", err)
 		}
-		if mod != nil {
-			if mod.TrimSpaces {
-				if str, ok := val.(string); ok {
-					val = strings.TrimSpace(str)
-				}
-			}
-			if mod.Convert != nil {
-				debugf("Converting field %v due to modification", field.Name())
-				val, err = mod.Convert(val)
-				if err != nil {
-					return nil, newError("dbase-table-tomap-1", err)
-				}
-			}
-			if len(mod.ExternalKey) != 0 {
-				debugf("Resolving external key %v for field %v due to modification", mod.ExternalKey, field.Name())
-				out[mod.ExternalKey] = val
-				continue
+	}
+	if mod != nil {
+		if mod.Convert != nil && !redact {
+			debugf("Converting field %v due to modification", field.Name())
+			val, err = mod.Convert(val)
+			if err != nil {
+				return newError("dbase-table-tomap-1", err)
 			}
 		}
-		out[field.Name()] = val
+		if len(mod.ExternalKey) != 0 {
+			debugf("Resolving external key %v for field %v due to modification", mod.ExternalKey, field.Name())
+			fn(field, mod.ExternalKey, val)
+			return nil
+		}
+	}
+	key, ok, err := row.handle.duplicateKey(field.column, row.handle.columnName(field.column))
+	if err != nil {
+		return newError("dbase-table-modifiedvalues-2", err)
+	}
+	if ok {
+		fn(field, key, val)
+	}
+	return nil
+}
+
+// trim trims the string value according to the trim mode and cutset of the modification and the config
+func (file *File) trim(val string, mod *Modification) string {
+	mode := file.config.TrimMode
+	if mode == TrimDefault && file.config.TrimSpaces {
+		mode = TrimBoth
+	}
+	cutset := file.config.TrimCutset
+	if mod != nil {
+		switch {
+		case mod.TrimMode != TrimDefault:
+			mode = mod.TrimMode
+		case mod.TrimSpaces:
+			mode = TrimBoth
+		}
+		if len(mod.TrimCutset) > 0 {
+			cutset = mod.TrimCutset
+		}
+	}
+	if len(cutset) == 0 {
+		switch mode {
+		case TrimLeft:
+			return strings.TrimLeftFunc(val, unicode.IsSpace)
+		case TrimRight:
+			return strings.TrimRightFunc(val, unicode.IsSpace)
+		case TrimBoth:
+			return strings.TrimSpace(val)
+		}
+		return val
+	}
+	switch mode {
+	case TrimLeft:
+		return strings.TrimLeft(val, cutset)
+	case TrimRight:
+		return strings.TrimRight(val, cutset)
+	case TrimBoth:
+		return strings.Trim(val, cutset)
+	}
+	return val
+}
+
+// KeyValue is a key and value pair of a row
+type KeyValue struct {
+	Key   string      // Column name or external key of the modification
+	Value interface{} // Value after applying the modifications
+}
+
+// ToOrderedMap returns the complete row as key and value pairs in column order.
+// Unlike ToMap the order of the columns is preserved, the modifications are applied the same way.
+func (row *Row) ToOrderedMap() ([]KeyValue, error) {
+	debugf("Converting row %v to ordered map...", row.Position)
+	out := make([]KeyValue, 0, len(row.fields))
+	err := row.modifiedValues(func(field *Field, key string, val interface{}) {
+		out = append(out, KeyValue{Key: key, Value: val})
+	})
+	if err != nil {
+		return nil, newError("dbase-table-toorderedmap-1", err)
 	}
 	return out, nil
 }
 
 // Returns a complete row as a JSON object.
+// The keys are written in column order.
 func (row *Row) ToJSON() ([]byte, error) {
 	debugf("Converting row %v to JSON...", row.Position)
-	m, err := row.ToMap()
+	j, err := row.orderedJSON()
 	if err != nil {
 		return nil, newError("dbase-table-tojson-1", err)
 	}
-	j, err := json.Marshal(m)
+	return j, nil
+}
+
+// ToJSONInto writes the complete row as a JSON object with the keys in column order into the given buffer.
+// The buffer is reset before writing, so it can be reused across rows. Keys and values are encoded directly into the buffer,
+// once it has grown to the size of a row no allocations are made unless values are trimmed, cast, converted or formatted by the options.
+func (row *Row) ToJSONInto(buf *bytes.Buffer) error {
+	if buf == nil {
+		return newError("dbase-table-tojsoninto-1", fmt.Errorf("buffer is nil"))
+	}
+	buf.Reset()
+	err := row.writeJSON(buf)
 	if err != nil {
-		return j, newError("dbase-table-tojson-2", err)
+		return newError("dbase-table-tojsoninto-2", err)
 	}
-	return j, nil
+	return nil
 }
 
 // Converts a row to a struct.
@@ -759,11 +1547,21 @@ func (row *Row) ToStruct(v interface{}) error {
 }
 
 // Converts a map of interfaces into the row representation
+// Columns without a value in the map get their default value, see SetDefault.
+// If Config.StrictMapping is set, unknown keys and missing non-nullable columns without default are returned as ErrSchemaMismatch.
 func (file *File) RowFromMap(m map[string]interface{}) (*Row, error) {
 	debugf("Converting map to row...")
 	row := file.NewRow()
+	known := make(map[string]bool, len(m))
+	missing := make([]string, 0)
+	known[nullFlagsName] = true
 	for i := range row.fields {
 		field := &Field{column: file.table.columns[i]}
+		// System columns keep the value of the new row, e.g. values of a row converted by ToMap are ignored
+		if field.column.System() {
+			known[field.Name()] = true
+			continue
+		}
 		mod := file.table.mods[i]
 		if mod != nil {
 			if len(mod.ExternalKey) != 0 {
@@ -771,15 +1569,27 @@ func (file *File) RowFromMap(m map[string]interface{}) (*Row, error) {
 					debugf("Resolving external key %v for field %v due to modification", mod.ExternalKey, field.Name())
 					field.value = val
 					row.fields[i] = field
+					known[mod.ExternalKey] = true
 					continue
 				}
 			}
 		}
 		if val, ok := m[field.Name()]; ok {
 			field.value = val
+			known[field.Name()] = true
+		} else if _, ok := file.table.defaults[field.column]; ok {
+			field.value = row.fields[i].value
+		} else if field.column.required() {
+			missing = append(missing, field.Name())
 		}
 		row.fields[i] = field
 	}
+	if file.config.StrictMapping {
+		err := strictMapping(m, known, missing)
+		if err != nil {
+			return nil, newError("dbase-file-rowfrommap-2", err)
+		}
+	}
 	err := row.Increment()
 	if err != nil {
 		return nil, newError("dbase-file-rowfrommap-1", err)
@@ -787,6 +1597,28 @@ func (file *File) RowFromMap(m map[string]interface{}) (*Row, error) {
 	return row, nil
 }
 
+// strictMapping returns an error listing the unknown keys of the map and the missing columns
+func strictMapping(m map[string]interface{}, known map[string]bool, missing []string) error {
+	unknown := make([]string, 0)
+	for key := range m {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 && len(missing) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	problems := make([]string, 0, 2)
+	if len(unknown) > 0 {
+		problems = append(problems, fmt.Sprintf("unknown keys: %s", strings.Join(unknown, ", ")))
+	}
+	if len(missing) > 0 {
+		problems = append(problems, fmt.Sprintf("missing columns: %s", strings.Join(missing, ", ")))
+	}
+	return fmt.Errorf("%w, %s", ErrSchemaMismatch, strings.Join(problems, "; "))
+}
+
 // Converts a JSON-encoded row into the row representation
 func (file *File) RowFromJSON(j []byte) (*Row, error) {
 	debugf("Converting JSON to row...")
@@ -0,0 +1,46 @@
+package dbase
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// TestNewTable creates tables with New and CreateTable, which share the checks and copy the config
+func TestNewTable(t *testing.T) {
+	name, err := NewColumn("NAME", Character, 10, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	duplicate, err := NewColumn("name", Character, 5, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	create := map[string]func(config *Config, columns ...*Column) (*File, error){
+		"New": func(config *Config, columns ...*Column) (*File, error) {
+			return New(FoxPro, config, columns, 0, nil)
+		},
+		"CreateTable": CreateTable,
+	}
+	for method, fn := range create {
+		t.Run(method, func(t *testing.T) {
+			config := &Config{Filename: filepath.Join(dir, method+".DBF"), Converter: NewDefaultConverter(charmap.Windows1252)}
+			_, err := fn(config, name, duplicate)
+			if !errors.Is(err, ErrDuplicateColumn) {
+				t.Fatalf("expected ErrDuplicateColumn for columns with the same name, got %v", err)
+			}
+			file, err := fn(config, name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+			config.TrimSpaces = true
+			if file.config == config || file.config.TrimSpaces {
+				t.Errorf("the table uses the config of the caller")
+			}
+		})
+	}
+}
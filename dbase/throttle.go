@@ -0,0 +1,57 @@
+package dbase
+
+import (
+	"time"
+)
+
+// minThrottleDelay is the minimum delay a scan sleeps, shorter delays are accumulated
+const minThrottleDelay = 10 * time.Millisecond
+
+// Throttle limits the throughput of full table scans, so maintenance jobs on a shared file server
+// do not starve other applications working with the same files.
+// It applies to Validate, Analyze, UnusedColumns, the exports, StreamBatches, CheckIntegrity, Pack and the relation index.
+// A zero value of a limit disables it, if both limits are set the stricter one applies.
+type Throttle struct {
+	RowsPerSecond  uint32 // Maximum number of rows read per second
+	BytesPerSecond uint64 // Maximum number of bytes read per second, e.g. 5 << 20 for 5 MB/s
+}
+
+// throttler tracks the throughput of a single scan
+type throttler struct {
+	limit Throttle
+	start time.Time
+	rows  uint64
+	bytes uint64
+}
+
+// throttle returns a throttler for a new scan, nil if no limit is configured
+func (file *File) throttle() *throttler {
+	limit := file.config.Throttle
+	if limit.RowsPerSecond == 0 && limit.BytesPerSecond == 0 {
+		return nil
+	}
+	return &throttler{limit: limit, start: time.Now()}
+}
+
+// wait adds the read rows and bytes and sleeps until the throughput is within the limits
+func (t *throttler) wait(rows uint64, bytes uint64) {
+	if t == nil {
+		return
+	}
+	t.rows += rows
+	t.bytes += bytes
+	var expected time.Duration
+	if t.limit.RowsPerSecond > 0 {
+		expected = time.Duration(float64(t.rows) / float64(t.limit.RowsPerSecond) * float64(time.Second))
+	}
+	if t.limit.BytesPerSecond > 0 {
+		if d := time.Duration(float64(t.bytes) / float64(t.limit.BytesPerSecond) * float64(time.Second)); d > expected {
+			expected = d
+		}
+	}
+	delay := expected - time.Since(t.start)
+	if delay >= minThrottleDelay {
+		debugf("Throttling scan for %v after %d rows", delay, t.rows)
+		time.Sleep(delay)
+	}
+}
@@ -0,0 +1,101 @@
+package dbase
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// tomlBareKey matches keys that can be written without quotes
+var tomlBareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// tomlExporter writes the rows as TOML array of tables named like the table, one table per row.
+// Keys and values are converted with the JSON options of the table. TOML has no null value, nil values are left out.
+type tomlExporter struct {
+	writer *bufio.Writer
+	header string
+}
+
+func newTOMLExporter(w io.Writer) *tomlExporter {
+	return &tomlExporter{writer: bufio.NewWriter(w)}
+}
+
+func (e *tomlExporter) Begin(schema *ExportSchema) error {
+	e.header = "[[" + tomlKey(schema.Name) + "]]\n"
+	return nil
+}
+
+func (e *tomlExporter) WriteRow(row *Row) error {
+	values, err := row.documentValues()
+	if err != nil {
+		return err
+	}
+	_, err = e.writer.WriteString(e.header)
+	if err != nil {
+		return err
+	}
+	for _, value := range values {
+		text, ok, err := tomlValue(value.Value)
+		if err != nil {
+			return fmt.Errorf("encoding %s failed with error: %w", value.Key, err)
+		}
+		if !ok {
+			continue
+		}
+		_, err = fmt.Fprintf(e.writer, "%s = %s\n", tomlKey(value.Key), text)
+		if err != nil {
+			return err
+		}
+	}
+	return e.writer.WriteByte('\n')
+}
+
+func (e *tomlExporter) End() error {
+	return e.writer.Flush()
+}
+
+// tomlKey returns the key, quoted if it is not a bare key
+func tomlKey(key string) string {
+	if tomlBareKey.MatchString(key) {
+		return key
+	}
+	data, _ := json.Marshal(key)
+	return string(data)
+}
+
+// tomlValue returns the value as TOML value, false if the value is nil and has to be left out.
+// JSON strings are valid TOML basic strings.
+func tomlValue(val interface{}) (string, bool, error) {
+	switch v := val.(type) {
+	case nil:
+		return "", false, nil
+	case bool:
+		return strconv.FormatBool(v), true, nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), true, nil
+	case int64:
+		return strconv.FormatInt(v, 10), true, nil
+	case float64:
+		return formatDocumentFloat(v, "inf", "nan"), true, nil
+	case json.Number:
+		return v.String(), true, nil
+	case time.Time:
+		if v.IsZero() {
+			return "", false, nil
+		}
+		return v.Format(time.RFC3339Nano), true, nil
+	case []byte:
+		data, _ := json.Marshal(base64.StdEncoding.EncodeToString(v))
+		return string(data), true, nil
+	}
+	data, err := json.Marshal(val)
+	if err != nil {
+		return "", false, err
+	}
+	return string(data), true, nil
+}
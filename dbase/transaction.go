@@ -0,0 +1,52 @@
+package dbase
+
+import "fmt"
+
+// transaction coordinates a write that spans the table and the memo file.
+// The IO implementations write memo blocks first, then the row and the table header last.
+// If any step fails, rollback restores the rows count and the next free memo block,
+// so a failed write can not leave the header pointing to missing rows or orphan memo blocks.
+type transaction struct {
+	file      *File
+	rowsCount uint32
+	nextFree  uint32
+}
+
+// begin records the state of the table and memo header before a write
+func (file *File) begin() *transaction {
+	tx := &transaction{
+		file:      file,
+		rowsCount: file.header.RowsCount,
+	}
+	if file.memoHeader != nil {
+		tx.nextFree = file.memoHeader.NextFree
+	}
+	return tx
+}
+
+// rollback restores the recorded state and writes the headers again.
+// The returned error contains the original error and any error that occurred during the rollback.
+func (tx *transaction) rollback(cause error) error {
+	file := tx.file
+	debugf("Rolling back write - rows count: %d, next free memo block: %d", tx.rowsCount, tx.nextFree)
+	// Shared tables keep the allocated memo blocks, other processes may have allocated blocks after them
+	if file.memoHeader != nil && file.memoHeader.NextFree != tx.nextFree && !file.config.SharedWrite {
+		file.memoMutex.Lock()
+		file.memoHeader.NextFree = tx.nextFree
+		err := file.WriteMemoHeader(0)
+		file.memoMutex.Unlock()
+		if err != nil {
+			return newError("dbase-transaction-rollback-1", fmt.Errorf("%w, rollback of memo header failed: %v", cause, err))
+		}
+	}
+	if file.header.RowsCount != tx.rowsCount {
+		file.dbaseMutex.Lock()
+		file.header.RowsCount = tx.rowsCount
+		err := file.WriteHeader()
+		file.dbaseMutex.Unlock()
+		if err != nil {
+			return newError("dbase-transaction-rollback-2", fmt.Errorf("%w, rollback of header failed: %v", cause, err))
+		}
+	}
+	return cause
+}
@@ -0,0 +1,241 @@
+package dbase
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// EOFState describes the end of file marker (0x1A) found after the last row
+type EOFState int
+
+const (
+	EOFValid        EOFState = iota // Exactly one marker directly after the last row
+	EOFMissing                      // No marker and no data after the last row
+	EOFDuplicated                   // More than one marker after the last row
+	EOFTrailingData                 // Data after the last row that is not covered by the rows count
+)
+
+// Returns the name of the state
+func (s EOFState) String() string {
+	switch s {
+	case EOFValid:
+		return "valid"
+	case EOFMissing:
+		return "missing"
+	case EOFDuplicated:
+		return "duplicated"
+	case EOFTrailingData:
+		return "trailing data"
+	default:
+		return fmt.Sprintf("unknown (%d)", int(s))
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler, the state is encoded by its name
+func (s EOFState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// ValidationReport contains the result of the structural validation of a table file
+type ValidationReport struct {
+	EOF             EOFState `json:"eof"`              // State of the end of file marker
+	DataEnd         int64    `json:"data_end"`         // Offset directly after the last row according to the header
+	Size            int64    `json:"size"`             // Actual size of the table file
+	InvalidRows     []uint32 `json:"invalid_rows"`     // Positions of rows with a deletion flag that is neither active nor deleted
+	EmbeddedMarkers uint32   `json:"embedded_markers"` // Number of rows containing 0x1A bytes in their field data (valid, e.g. in binary fields)
+	HiddenBytes     uint32   `json:"hidden_bytes"`     // Number of bytes in a row that do not belong to the deletion flag or any column
+	Duplicates      []string `json:"duplicates"`       // Names of columns whose name is already used by a previous column, see DuplicateColumns
+	Issues          []string `json:"issues"`           // Description of all problems found
+}
+
+// Valid returns true if no problems were found
+func (r *ValidationReport) Valid() bool {
+	return len(r.Issues) == 0
+}
+
+// Validate checks the structure of the table file against the header and reports what was found
+func (file *File) Validate() (*ValidationReport, error) {
+	size, err := file.Size(false)
+	if err != nil {
+		return nil, newError("dbase-validate-validate-1", err)
+	}
+	report := &ValidationReport{
+		DataEnd: file.dataEnd(),
+		Size:    size,
+	}
+	report.EOF, err = file.eofState(report.DataEnd, size)
+	if err != nil {
+		return nil, newError("dbase-validate-validate-2", err)
+	}
+	switch report.EOF {
+	case EOFMissing:
+		report.Issues = append(report.Issues, "end of file marker is missing")
+	case EOFDuplicated:
+		report.Issues = append(report.Issues, fmt.Sprintf("end of file marker is repeated %d times", size-report.DataEnd))
+	case EOFTrailingData:
+		report.Issues = append(report.Issues, fmt.Sprintf("%d bytes after the last row are not covered by the rows count", size-report.DataEnd))
+	}
+	if size < report.DataEnd {
+		report.Issues = append(report.Issues, fmt.Sprintf("file size %d is smaller than the expected %d bytes of %d rows", size, report.DataEnd, file.header.RowsCount))
+	}
+	file.validateOffsets(report)
+	report.Duplicates = file.DuplicateColumns()
+	for _, name := range report.Duplicates {
+		report.Issues = append(report.Issues, fmt.Sprintf("column name %v is already used by a previous column", name))
+	}
+	err = file.validateRows(report)
+	if err != nil {
+		return nil, newError("dbase-validate-validate-3", err)
+	}
+	return report, nil
+}
+
+// validateOffsets checks that the column offsets match the row length
+func (file *File) validateOffsets(report *ValidationReport) {
+	covered := uint32(1)
+	for _, column := range file.rowColumns() {
+		covered += uint32(column.Length)
+	}
+	defined := false
+	for _, column := range file.rowColumns() {
+		if column.Position != 0 {
+			defined = true
+			break
+		}
+	}
+	// Tables without column positions (e.g. dBase III) are read by the column lengths
+	if !defined {
+		if covered != uint32(file.header.RowLength) {
+			report.Issues = append(report.Issues, fmt.Sprintf("column lengths cover %d bytes of the row length %d", covered, file.header.RowLength))
+		}
+		return
+	}
+	if err := file.validatePositions(); err != nil {
+		report.Issues = append(report.Issues, fmt.Sprintf("invalid column positions: %v", err))
+		if covered != uint32(file.header.RowLength) {
+			report.Issues = append(report.Issues, fmt.Sprintf("column lengths cover %d bytes of the row length %d", covered, file.header.RowLength))
+		}
+		return
+	}
+	if covered < uint32(file.header.RowLength) {
+		report.HiddenBytes = uint32(file.header.RowLength) - covered
+	}
+}
+
+// validateRows checks the deletion flag of every row covered by the rows count.
+// Rows are located by rows count and row length only, so 0x1A bytes inside the field data are no end of file marker.
+func (file *File) validateRows(report *ValidationReport) error {
+	length := int64(file.header.RowLength)
+	if length == 0 {
+		return nil
+	}
+	chunk := uint32(1024)
+	throttle := file.throttle()
+	for start := uint32(0); start < file.header.RowsCount; start += chunk {
+		count := chunk
+		if file.header.RowsCount-start < count {
+			count = file.header.RowsCount - start
+		}
+		data, err := file.ReadRaw(false, int64(file.header.FirstRow)+int64(start)*length, int(int64(count)*length))
+		if err != nil && !errors.Is(err, ErrIncomplete) {
+			return newError("dbase-validate-validaterows-1", err)
+		}
+		throttle.wait(uint64(count), uint64(len(data)))
+		// The last row may be incomplete if the file is truncated, its deletion flag is checked nevertheless
+		for i := int64(0); i*length < int64(len(data)); i++ {
+			row := data[i*length:]
+			if int64(len(row)) > length {
+				row = row[:length]
+			}
+			position := start + uint32(i)
+			switch Marker(row[0]) {
+			case Active, Deleted:
+				if bytes.IndexByte(row[1:], byte(EOFMarker)) >= 0 {
+					report.EmbeddedMarkers++
+				}
+			case EOFMarker:
+				report.InvalidRows = append(report.InvalidRows, position)
+				report.Issues = append(report.Issues, fmt.Sprintf("end of file marker at the start of row %d, the rows count may be too large", position))
+			default:
+				report.InvalidRows = append(report.InvalidRows, position)
+				report.Issues = append(report.Issues, fmt.Sprintf("invalid deletion flag 0x%02x at row %d", row[0], position))
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// RepairEOF writes a missing end of file marker and removes repeated markers after the last row.
+// Trailing data that is not covered by the rows count is left untouched, as it may contain rows that can be recovered.
+func (file *File) RepairEOF() error {
+	if file.config.ReadOnly {
+		return newError("dbase-validate-repaireof-1", fmt.Errorf("table is opened read-only"))
+	}
+	size, err := file.Size(false)
+	if err != nil {
+		return newError("dbase-validate-repaireof-2", err)
+	}
+	end := file.dataEnd()
+	state, err := file.eofState(end, size)
+	if err != nil {
+		return newError("dbase-validate-repaireof-3", err)
+	}
+	if size < end {
+		return newError("dbase-validate-repaireof-6", fmt.Errorf("%w, file size %d is smaller than the expected %d bytes", ErrIncomplete, size, end))
+	}
+	switch state {
+	case EOFMissing:
+		debugf("Repairing missing end of file marker at offset %d", end)
+		err = file.writeEOF()
+		if err != nil {
+			return newError("dbase-validate-repaireof-4", err)
+		}
+	case EOFDuplicated:
+		debugf("Removing %d repeated end of file markers", size-end-1)
+		err = file.Truncate(false, end+1)
+		if err != nil {
+			return newError("dbase-validate-repaireof-5", err)
+		}
+	case EOFTrailingData:
+		debugf("Not repairing end of file marker, %d bytes of trailing data found", size-end)
+	}
+	return nil
+}
+
+// eofState determines the state of the end of file marker from the bytes after the last row
+func (file *File) eofState(end int64, size int64) (EOFState, error) {
+	if size <= end {
+		return EOFMissing, nil
+	}
+	trailing, err := file.ReadRaw(false, end, int(size-end))
+	if err != nil && !errors.Is(err, ErrIncomplete) {
+		return EOFValid, newError("dbase-validate-eofstate-1", err)
+	}
+	for _, b := range trailing {
+		if Marker(b) != EOFMarker {
+			return EOFTrailingData, nil
+		}
+	}
+	if len(trailing) > 1 {
+		return EOFDuplicated, nil
+	}
+	return EOFValid, nil
+}
+
+// writeEOF writes the end of file marker directly after the last row.
+// IO implementations without RawIO write the marker with the rows themselves.
+func (file *File) writeEOF() error {
+	if _, ok := file.defaults().io.(RawIO); !ok {
+		return nil
+	}
+	return file.WriteRaw(false, file.dataEnd(), []byte{byte(EOFMarker)})
+}
+
+// dataEnd returns the offset directly after the last row according to the header
+func (file *File) dataEnd() int64 {
+	return int64(file.header.FirstRow) + int64(file.header.RowsCount)*int64(file.header.RowLength)
+}
@@ -0,0 +1,124 @@
+package dbase
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// shortReads returns at most three bytes per read, like network handles that return short reads
+type shortReads struct{ *os.File }
+
+func (s *shortReads) Read(p []byte) (int, error) {
+	if len(p) > 3 {
+		p = p[:3]
+	}
+	return s.File.Read(p)
+}
+
+// TestEmbeddedEOFMarkers reads and validates rows with 0x1A bytes in their fields, which are data and no end of file marker
+func TestEmbeddedEOFMarkers(t *testing.T) {
+	type value struct {
+		name  string
+		count int32
+	}
+	tests := []struct {
+		name    string
+		rows    []value
+		markers uint32 // Rows with 0x1A bytes in their fields
+	}{
+		{"no marker", []value{{"first", 1}, {"second", 2}}, 0},
+		{"integer with a marker", []value{{"first", 0x1A}, {"second", 2}}, 1},
+		{"integer of markers", []value{{"first", 0x1A1A1A1A}, {"second", 0x1A1A1A1A}}, 2},
+		{"text with a marker", []value{{"a\x1ab", 1}, {"second", 2}}, 1},
+		{"marker in the last row", []value{{"first", 1}, {"\x1a\x1a", 0x1A}}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "MARKERS.DBF")
+			name, err := NewColumn("NAME", Character, 10, 0, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			count, err := NewColumn("COUNT", Integer, 4, 0, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			file, err := CreateTable(&Config{Filename: path}, name, count)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, v := range tt.rows {
+				row := file.NewRow()
+				err = row.FieldByName("NAME").SetValue(v.name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = row.FieldByName("COUNT").SetValue(v.count)
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = row.Add()
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			err = file.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			open := []struct {
+				name string
+				open func() (*File, error)
+			}{
+				{"default", func() (*File, error) {
+					return OpenTable(&Config{Filename: path, ReadOnly: true})
+				}},
+				{"generic with short reads", func() (*File, error) {
+					handle, err := os.Open(path)
+					if err != nil {
+						return nil, err
+					}
+					t.Cleanup(func() { handle.Close() })
+					return OpenTable(&Config{IO: GenericIO{Handle: &shortReads{handle}}, ReadOnly: true})
+				}},
+			}
+			for _, o := range open {
+				file, err := o.open()
+				if err != nil {
+					t.Fatalf("%s: %v", o.name, err)
+				}
+				rows, err := file.Rows(false, false)
+				if err != nil {
+					t.Fatalf("%s: %v", o.name, err)
+				}
+				if len(rows) != len(tt.rows) {
+					t.Fatalf("%s: read %d rows, expected %d", o.name, len(rows), len(tt.rows))
+				}
+				for i, row := range rows {
+					values, err := row.ToMap()
+					if err != nil {
+						t.Fatalf("%s: %v", o.name, err)
+					}
+					if strings.TrimRight(values["NAME"].(string), " ") != tt.rows[i].name || values["COUNT"] != tt.rows[i].count {
+						t.Errorf("%s: row %d is %v, expected %v", o.name, i, values, tt.rows[i])
+					}
+				}
+				report, err := file.Validate()
+				if err != nil {
+					t.Fatalf("%s: %v", o.name, err)
+				}
+				// Without RawIO the size is unknown, so only the rows are validated
+				if report.EmbeddedMarkers != tt.markers || len(report.InvalidRows) > 0 {
+					t.Errorf("%s: %d rows with markers and invalid rows %v, expected %d rows with markers", o.name, report.EmbeddedMarkers, report.InvalidRows, tt.markers)
+				}
+				if o.name == "default" && !report.Valid() {
+					t.Errorf("%s: %v", o.name, report.Issues)
+				}
+				file.Close()
+			}
+		})
+	}
+}
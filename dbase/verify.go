@@ -0,0 +1,39 @@
+package dbase
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// verifyRow re-reads a row whose data could not be decoded, up to Config.VerifyReads times.
+// Network filesystems like SMB occasionally return corrupted pages, so a re-read returning other bytes
+// means the read and not the file is corrupt. Such bytes are used if a further read confirms them and they can be decoded,
+// otherwise ErrIntegrity is returned. If every re-read returns the same bytes, the file is corrupt and the decode error is returned.
+func (file *File) verifyRow(position uint32, data []byte, row *Row, decodeErr error) (*Row, error) {
+	differs := false
+	for i := 0; i < int(file.config.VerifyReads); i++ {
+		reread, err := file.ReadRow(position)
+		if err != nil {
+			debugf("Re-reading row %d failed with error: %v", position, err)
+			differs = true
+			continue
+		}
+		if bytes.Equal(reread, data) {
+			continue
+		}
+		differs = true
+		confirm, err := file.ReadRow(position)
+		if err != nil || !bytes.Equal(confirm, reread) {
+			continue
+		}
+		verified, err := file.BytesToRow(reread)
+		if err == nil {
+			debugf("Row %d was read corrupted, the re-read data is used", position)
+			return verified, nil
+		}
+	}
+	if differs {
+		return row, newError("dbase-verify-verifyrow-1", fmt.Errorf("%w, row %d returned different data when read again: %v", ErrIntegrity, position, decodeErr))
+	}
+	return row, decodeErr
+}
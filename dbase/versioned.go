@@ -0,0 +1,97 @@
+package dbase
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Layouts of backup directory names that are interpreted as the time of the backup
+var versionLayouts = []string{
+	"2006-01-02T15-04-05",
+	"2006-01-02_15-04-05",
+	"2006-01-02_150405",
+	"20060102150405",
+	"20060102_150405",
+	"2006-01-02",
+	"20060102",
+}
+
+// Version is a dated backup copy of a table
+type Version struct {
+	Time time.Time // Time of the backup, parsed from the directory name or the modification time of the file
+	Path string    // Path of the DBF file
+}
+
+// ListVersions returns the backup copies of a table in dir sorted from oldest to newest.
+// Each backup is expected in its own subdirectory, named after the date of the backup (e.g. 2023-01-31 or 20230131_235900).
+// If the name of a subdirectory is not a date, the modification time of the table file is used.
+func ListVersions(dir string, table string) ([]Version, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, newError("dbase-versioned-listversions-1", err)
+	}
+	if len(filepath.Ext(table)) == 0 {
+		table += string(DBF)
+	}
+	versions := make([]Version, 0)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		tablePath, err := _findFile(filepath.Join(dir, entry.Name(), table))
+		if err != nil {
+			return nil, newError("dbase-versioned-listversions-2", err)
+		}
+		info, err := os.Stat(tablePath)
+		if err != nil {
+			debugf("Skipping backup directory %v, table %v not found", entry.Name(), table)
+			continue
+		}
+		version := Version{Time: info.ModTime(), Path: tablePath}
+		for _, layout := range versionLayouts {
+			if t, err := time.ParseInLocation(layout, entry.Name(), time.Local); err == nil {
+				version.Time = t
+				break
+			}
+		}
+		debugf("Found version of table %v from %v at %v", table, version.Time, version.Path)
+		versions = append(versions, version)
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].Time.Before(versions[j].Time)
+	})
+	return versions, nil
+}
+
+// OpenVersioned opens the newest backup copy of the table in dir that is not newer than asOf.
+// The memo file is opened from the same backup directory. The filename of the config is replaced.
+func OpenVersioned(dir string, table string, asOf time.Time, config *Config) (*File, error) {
+	versions, err := ListVersions(dir, table)
+	if err != nil {
+		return nil, newError("dbase-versioned-openversioned-1", err)
+	}
+	var selected *Version
+	for i := range versions {
+		if versions[i].Time.After(asOf) {
+			break
+		}
+		selected = &versions[i]
+	}
+	if selected == nil {
+		return nil, newError("dbase-versioned-openversioned-2", fmt.Errorf("no version of table %v found as of %v", strings.TrimSuffix(table, filepath.Ext(table)), asOf))
+	}
+	if config == nil {
+		config = &Config{}
+	}
+	config.Filename = selected.Path
+	debugf("Opening version of table %v from %v", table, selected.Time)
+	file, err := OpenTable(config)
+	if err != nil {
+		return nil, newError("dbase-versioned-openversioned-3", err)
+	}
+	return file, nil
+}
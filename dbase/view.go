@@ -0,0 +1,73 @@
+package dbase
+
+// RowView is an ordered representation of a row for text/template and html/template.
+// Fields can be ranged over in column order or addressed by name, e.g. {{range .Fields}}{{.Name}}={{.Value}}{{end}} or {{.Get "PRODNAME"}}.
+type RowView struct {
+	Position uint32      // Position of the row in the table
+	Deleted  bool        // True if the row is marked as deleted
+	Fields   []FieldView // Fields in column order
+	index    map[string]int
+}
+
+// FieldView is a field of a RowView
+type FieldView struct {
+	Name   string      // Column name or external key of the modification
+	Column string      // Column name in the table
+	Type   string      // Column data type, e.g. "C" or "N"
+	Value  interface{} // Value after applying the modifications
+}
+
+// String returns the value as text like it is exported to CSV
+func (f FieldView) String() string {
+	return formatValue(f.Value)
+}
+
+// View returns the row as RowView with the values after applying the modifications.
+// Hidden columns are left out, redacted columns contain RedactedValue.
+func (row *Row) View() (*RowView, error) {
+	view := &RowView{
+		Position: row.Position,
+		Deleted:  row.Deleted,
+		Fields:   make([]FieldView, 0, len(row.fields)),
+		index:    make(map[string]int, len(row.fields)),
+	}
+	err := row.modifiedValues(func(field *Field, key string, val interface{}) {
+		view.index[key] = len(view.Fields)
+		view.Fields = append(view.Fields, FieldView{
+			Name:   key,
+			Column: field.Name(),
+			Type:   field.column.Type(),
+			Value:  val,
+		})
+	})
+	if err != nil {
+		return nil, newError("dbase-view-view-1", err)
+	}
+	return view, nil
+}
+
+// Get returns the value of the field with the given name, nil if there is no such field
+func (v *RowView) Get(name string) interface{} {
+	if field, ok := v.Field(name); ok {
+		return field.Value
+	}
+	return nil
+}
+
+// Field returns the field with the given name and true if it exists
+func (v *RowView) Field(name string) (FieldView, bool) {
+	i, ok := v.index[name]
+	if !ok {
+		return FieldView{}, false
+	}
+	return v.Fields[i], true
+}
+
+// Names returns the field names in column order
+func (v *RowView) Names() []string {
+	names := make([]string, len(v.Fields))
+	for i, field := range v.Fields {
+		names[i] = field.Name
+	}
+	return names
+}
@@ -0,0 +1,348 @@
+package dbase
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// String returns the name of the warehouse as used in the names of the exporters, e.g. "bigquery"
+func (w Warehouse) String() string {
+	switch w {
+	case WarehouseBigQuery:
+		return "bigquery"
+	case WarehouseSnowflake:
+		return "snowflake"
+	}
+	return fmt.Sprintf("Warehouse(%d)", byte(w))
+}
+
+// ParseWarehouse returns the warehouse of the name, e.g. "snowflake". Names are case-insensitive.
+func ParseWarehouse(name string) (Warehouse, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "bigquery":
+		return WarehouseBigQuery, nil
+	case "snowflake":
+		return WarehouseSnowflake, nil
+	}
+	return 0, newError("dbase-warehouse-parsewarehouse-1", fmt.Errorf("unknown warehouse '%s', expected bigquery or snowflake", name))
+}
+
+// columnType returns the column type in the DDL of the warehouse.
+// N columns with decimals and Y columns keep their precision as NUMERIC, F and B columns are floating point.
+func (w Warehouse) columnType(column *Column) string {
+	precision := int(column.Length) - 1
+	if precision < int(column.Decimals) {
+		precision = int(column.Decimals)
+	}
+	switch DataType(column.DataType) {
+	case Integer:
+		if w == WarehouseSnowflake {
+			return "INTEGER"
+		}
+		return "INT64"
+	case Numeric:
+		if w == WarehouseSnowflake {
+			if column.Decimals == 0 {
+				precision = int(column.Length)
+			}
+			if precision > 38 {
+				precision = 38
+			}
+			return fmt.Sprintf("NUMBER(%d,%d)", precision, column.Decimals)
+		}
+		if column.Decimals == 0 {
+			return "INT64"
+		}
+		// NUMERIC supports up to 9 decimals
+		if column.Decimals > 9 {
+			return fmt.Sprintf("BIGNUMERIC(%d,%d)", precision, column.Decimals)
+		}
+		return fmt.Sprintf("NUMERIC(%d,%d)", precision, column.Decimals)
+	case Currency:
+		if w == WarehouseSnowflake {
+			return "NUMBER(19,4)"
+		}
+		return "NUMERIC(19,4)"
+	case Float, Double:
+		if w == WarehouseSnowflake {
+			return "FLOAT"
+		}
+		return "FLOAT64"
+	case Logical:
+		if w == WarehouseSnowflake {
+			return "BOOLEAN"
+		}
+		return "BOOL"
+	case Date:
+		return "DATE"
+	case DateTime:
+		// Date times have no time zone, they are exported as UTC
+		if w == WarehouseSnowflake {
+			return "TIMESTAMP_NTZ"
+		}
+		return "TIMESTAMP"
+	case Varbinary, Blob, General, Picture:
+		if w == WarehouseSnowflake {
+			return "BINARY"
+		}
+		return "BYTES"
+	case Character, Varchar:
+		if w == WarehouseSnowflake {
+			return fmt.Sprintf("VARCHAR(%d)", column.Length)
+		}
+	}
+	if w == WarehouseSnowflake {
+		return "VARCHAR"
+	}
+	return "STRING"
+}
+
+// quote returns the identifier quoted for the warehouse, so reserved words can be used as column names
+func (w Warehouse) quote(identifier string) string {
+	if w == WarehouseSnowflake {
+		return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+	}
+	return "`" + identifier + "`"
+}
+
+// WarehouseDDL returns the CREATE TABLE statement of the warehouse for the exported columns of the table,
+// matching the files written by the load-file exporters of the warehouse. The table name is used as given,
+// e.g. "dataset.employees", and defaults to the file name. All columns are nullable, as empty fields can be exported as NULL.
+func (file *File) WarehouseDDL(warehouse Warehouse, table string) string {
+	schema := file.exportSchema()
+	if len(table) == 0 {
+		table = warehouse.quote(schemaFieldName(schema.Name))
+	}
+	names := schemaFieldNames(schema.Keys)
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "CREATE TABLE %s (\n", table)
+	for i, column := range schema.Columns {
+		separator := ","
+		if i == len(schema.Columns)-1 {
+			separator = ""
+		}
+		fmt.Fprintf(&builder, "  %s %s%s\n", warehouse.quote(names[i]), warehouse.columnType(column), separator)
+	}
+	builder.WriteString(");\n")
+	return builder.String()
+}
+
+// warehouseExporter writes the rows as load file of a data warehouse, either as CSV or newline delimited JSON.
+// The keys are the column names of WarehouseDDL, dates are written as 2006-01-02 and date times as RFC 3339 in UTC
+// with milliseconds. Numbers are written without exponent and with the decimals of the column. Binary values are
+// base64 encoded for BigQuery and hex encoded for Snowflake.
+//
+// CSV files have a header line and LF line endings. NULL is an empty field, empty strings are written as "",
+// fields containing separators, quotes or line breaks are quoted with doubled quotes.
+// Load them with --skip_leading_rows=1 --allow_quoted_newlines in BigQuery
+// and with SKIP_HEADER = 1 FIELD_OPTIONALLY_ENCLOSED_BY = '"' in Snowflake.
+type warehouseExporter struct {
+	warehouse Warehouse
+	csv       bool
+	writer    *bufio.Writer
+	columns   map[string]*Column
+	names     map[string]string
+	header    []string
+}
+
+func newWarehouseExporter(warehouse Warehouse, csv bool, w io.Writer) *warehouseExporter {
+	return &warehouseExporter{warehouse: warehouse, csv: csv, writer: bufio.NewWriter(w)}
+}
+
+func (e *warehouseExporter) Begin(schema *ExportSchema) error {
+	e.header = schemaFieldNames(schema.Keys)
+	e.columns = make(map[string]*Column, len(schema.Columns))
+	e.names = make(map[string]string, len(schema.Columns))
+	for i, column := range schema.Columns {
+		e.columns[schema.Keys[i]] = column
+		e.names[schema.Keys[i]] = e.header[i]
+	}
+	if !e.csv {
+		return nil
+	}
+	for i, name := range e.header {
+		if i > 0 {
+			e.writer.WriteByte(',')
+		}
+		e.writer.WriteString(csvField(name))
+	}
+	return e.writer.WriteByte('\n')
+}
+
+func (e *warehouseExporter) WriteRow(row *Row) error {
+	values, err := row.ExportValues()
+	if err != nil {
+		return err
+	}
+	if !e.csv {
+		e.writer.WriteByte('{')
+	}
+	for i, value := range values {
+		column, ok := e.columns[value.Key]
+		if !ok {
+			return fmt.Errorf("key %s is not part of the exported columns", value.Key)
+		}
+		text, quoted, err := e.format(column, value.Value)
+		if err != nil {
+			return fmt.Errorf("encoding %s failed with error: %w", value.Key, err)
+		}
+		if i > 0 {
+			e.writer.WriteByte(',')
+		}
+		if e.csv {
+			if quoted && len(text) == 0 {
+				text = `""`
+			} else {
+				text = csvField(text)
+			}
+			e.writer.WriteString(text)
+			continue
+		}
+		key, err := jsonString(e.names[value.Key])
+		if err != nil {
+			return err
+		}
+		e.writer.WriteString(key)
+		e.writer.WriteByte(':')
+		switch {
+		case text == "" && !quoted:
+			e.writer.WriteString("null")
+		case quoted:
+			text, err = jsonString(text)
+			if err != nil {
+				return err
+			}
+			e.writer.WriteString(text)
+		default:
+			e.writer.WriteString(text)
+		}
+	}
+	if !e.csv {
+		e.writer.WriteByte('}')
+	}
+	return e.writer.WriteByte('\n')
+}
+
+func (e *warehouseExporter) End() error {
+	return e.writer.Flush()
+}
+
+// format returns the value as text for the warehouse and true if it is a string, an empty text that is not a string is NULL
+func (e *warehouseExporter) format(column *Column, val interface{}) (string, bool, error) {
+	if val == nil {
+		return "", false, nil
+	}
+	switch DataType(column.DataType) {
+	case Date, DateTime:
+		t, ok := val.(time.Time)
+		if !ok {
+			break
+		}
+		if t.IsZero() {
+			return "", false, nil
+		}
+		if DataType(column.DataType) == Date {
+			return t.Format("2006-01-02"), true, nil
+		}
+		return t.UTC().Format("2006-01-02T15:04:05.000Z07:00"), true, nil
+	case Numeric, Float, Currency, Double:
+		decimals := int(column.Decimals)
+		switch DataType(column.DataType) {
+		case Currency:
+			decimals = 4
+		case Double:
+			decimals = -1
+		}
+		switch v := val.(type) {
+		case float64:
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				return e.specialFloat(v), !e.csv, nil
+			}
+			return strconv.FormatFloat(v, 'f', decimals, 64), false, nil
+		case int32:
+			return strconv.FormatInt(int64(v), 10), false, nil
+		case int64:
+			return strconv.FormatInt(v, 10), false, nil
+		case json.Number:
+			return v.String(), false, nil
+		}
+	case Integer:
+		switch v := val.(type) {
+		case int32:
+			return strconv.FormatInt(int64(v), 10), false, nil
+		case int64:
+			return strconv.FormatInt(v, 10), false, nil
+		}
+	case Logical:
+		if b, ok := val.(bool); ok {
+			return strconv.FormatBool(b), false, nil
+		}
+	case Varbinary, Blob, General, Picture:
+		if data, ok := val.([]byte); ok {
+			if e.warehouse == WarehouseSnowflake {
+				return hex.EncodeToString(data), true, nil
+			}
+			return base64.StdEncoding.EncodeToString(data), true, nil
+		}
+	}
+	switch v := val.(type) {
+	case string:
+		if !utf8.ValidString(v) {
+			return "", false, fmt.Errorf("invalid UTF-8 in column %s", column.Name())
+		}
+		return v, true, nil
+	case []byte:
+		// Binary memos are exported as text
+		if !utf8.Valid(v) {
+			return "", false, fmt.Errorf("invalid UTF-8 in column %s", column.Name())
+		}
+		return string(v), true, nil
+	}
+	// Values changed by modifications are exported as text
+	return formatValue(val), true, nil
+}
+
+// specialFloat returns the text of NaN and infinite values accepted by the warehouse
+func (e *warehouseExporter) specialFloat(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case e.warehouse == WarehouseSnowflake && math.IsInf(v, 1):
+		return "inf"
+	case e.warehouse == WarehouseSnowflake:
+		return "-inf"
+	case math.IsInf(v, 1):
+		return "Infinity"
+	}
+	return "-Infinity"
+}
+
+// csvField returns the field quoted if it contains separators, quotes, line breaks or leading or trailing spaces
+func csvField(field string) string {
+	if !strings.ContainsAny(field, ",\"\r\n") && strings.TrimSpace(field) == field {
+		return field
+	}
+	return `"` + strings.ReplaceAll(field, `"`, `""`) + `"`
+}
+
+// jsonString returns the string as JSON string without escaping HTML characters
+func jsonString(s string) (string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	err := encoder.Encode(s)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
@@ -0,0 +1,150 @@
+package dbase
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// RowFilter selects the rows of CountWhere and ExistsWhere
+type RowFilter struct {
+	// Values the columns have to contain. The values are encoded like they are written and compared with the raw bytes of the rows,
+	// so no rows are decoded. Only columns of fixed length types can be compared, memo and variable length columns are not supported.
+	Equals map[string]interface{}
+	// Called with the decoded row for rows matching Equals, all rows matching Equals are selected if nil
+	Match func(row *Row) bool
+}
+
+// rawCondition is a value of RowFilter.Equals encoded for the comparison with the raw row
+type rawCondition struct {
+	offset int
+	value  []byte
+}
+
+// CountWhere returns the number of rows selected by the filter, all rows if the filter is nil.
+// Deleted rows are counted unless they are hidden.
+func (file *File) CountWhere(filter *RowFilter) (uint32, error) {
+	count, err := file.where(filter, 0)
+	if err != nil {
+		return 0, newError("dbase-where-countwhere-1", err)
+	}
+	return count, nil
+}
+
+// ExistsWhere returns true if a row is selected by the filter, the scan stops at the first selected row.
+// Deleted rows are selected unless they are hidden.
+func (file *File) ExistsWhere(filter *RowFilter) (bool, error) {
+	count, err := file.where(filter, 1)
+	if err != nil {
+		return false, newError("dbase-where-existswhere-1", err)
+	}
+	return count > 0, nil
+}
+
+// compile encodes the values of Equals for the columns of the table.
+// Returns false if a value is longer than its column, no row can contain it then.
+func (filter *RowFilter) compile(file *File) ([]rawCondition, bool, error) {
+	conditions := make([]rawCondition, 0, len(filter.Equals))
+	for name, value := range filter.Equals {
+		field, err := file.NewFieldByName(name, value)
+		if err != nil {
+			return nil, false, err
+		}
+		switch DataType(field.column.DataType) {
+		case Memo, Blob, General, Picture, Varchar, Varbinary:
+			return nil, false, fmt.Errorf("column '%s' of type %s can not be compared with the raw row, use Match instead", name, field.column.Type())
+		}
+		// Values are cut to the column length when encoded
+		unpadded, err := file.GetRepresentation(field, true)
+		if err == nil && len(unpadded) > int(field.column.Length) {
+			debugf("Value of %d bytes for column %s can not match any row", len(unpadded), field.Name())
+			return nil, false, nil
+		}
+		raw, err := file.GetRepresentation(field, false)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(raw) != int(field.column.Length) {
+			return nil, false, fmt.Errorf("value of %d bytes does not match column '%s' of %d bytes", len(raw), name, field.column.Length)
+		}
+		conditions = append(conditions, rawCondition{offset: int(file.columnOffset(field.column)), value: raw})
+	}
+	return conditions, true, nil
+}
+
+// where counts the selected rows and stops after limit rows, if limit is greater than 0.
+// The internal row pointer is restored afterwards.
+func (file *File) where(filter *RowFilter, limit uint32) (uint32, error) {
+	return file.selectRows(filter, limit, nil)
+}
+
+// selectRows calls fn with each selected row and returns the number of selected rows, see where.
+// Without fn rows are only decoded for RowFilter.Match.
+func (file *File) selectRows(filter *RowFilter, limit uint32, fn func(row *Row) error) (uint32, error) {
+	if filter == nil {
+		filter = &RowFilter{}
+	}
+	conditions, possible, err := filter.compile(file)
+	if err != nil || !possible {
+		return 0, err
+	}
+	if len(conditions) == 0 && filter.Match == nil && fn == nil && !file.deletedHidden {
+		if limit > 0 && file.header.RowsCount > limit {
+			return limit, nil
+		}
+		return file.header.RowsCount, nil
+	}
+	err = file.autoReopen()
+	if err != nil {
+		return 0, err
+	}
+	pointer := file.table.rowPointer
+	file.scans++
+	defer func() {
+		file.table.rowPointer = pointer
+		file.scans--
+	}()
+	throttle := file.throttle()
+	count := uint32(0)
+	for position := uint32(0); position < file.header.RowsCount; position++ {
+		data, err := file.ReadRow(position)
+		if err != nil {
+			return 0, err
+		}
+		throttle.wait(1, uint64(len(data)))
+		if file.deletedHidden && Marker(data[0]) == Deleted {
+			continue
+		}
+		matches := true
+		for _, condition := range conditions {
+			if !bytes.Equal(data[condition.offset:condition.offset+len(condition.value)], condition.value) {
+				matches = false
+				break
+			}
+		}
+		if !matches {
+			continue
+		}
+		if filter.Match != nil || fn != nil {
+			// The row pointer is required to read the null flags of variable length columns
+			file.table.rowPointer = position
+			row, err := file.BytesToRow(data)
+			if err != nil {
+				return 0, fmt.Errorf("reading row %d failed with error: %w", position, err)
+			}
+			if filter.Match != nil && !filter.Match(row) {
+				continue
+			}
+			if fn != nil {
+				err = fn(row)
+				if err != nil {
+					return 0, err
+				}
+			}
+		}
+		count++
+		if limit > 0 && count >= limit {
+			break
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,505 @@
+package dbase
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// yamlPlainKey matches keys that can be written without quotes
+var yamlPlainKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// yamlReservedKeys are plain keys that YAML 1.1 parsers read as booleans or null, they are quoted
+var yamlReservedKeys = map[string]bool{"y": true, "n": true, "yes": true, "no": true, "on": true, "off": true, "true": true, "false": true, "null": true}
+
+// yamlExporter writes the rows as YAML sequence of mappings, one mapping per row.
+// Keys and values are converted with the JSON options of the table, strings are double-quoted.
+type yamlExporter struct {
+	writer *bufio.Writer
+	rows   int
+}
+
+func newYAMLExporter(w io.Writer) *yamlExporter {
+	return &yamlExporter{writer: bufio.NewWriter(w)}
+}
+
+func (e *yamlExporter) Begin(schema *ExportSchema) error {
+	_, err := fmt.Fprintf(e.writer, "# %s\n", schema.Name)
+	return err
+}
+
+func (e *yamlExporter) WriteRow(row *Row) error {
+	values, err := row.documentValues()
+	if err != nil {
+		return err
+	}
+	if len(values) == 0 {
+		_, err = e.writer.WriteString("- {}\n")
+		if err != nil {
+			return err
+		}
+	}
+	for i, value := range values {
+		text, err := yamlValue(value.Value)
+		if err != nil {
+			return fmt.Errorf("encoding %s failed with error: %w", value.Key, err)
+		}
+		prefix := "  "
+		if i == 0 {
+			prefix = "- "
+		}
+		_, err = fmt.Fprintf(e.writer, "%s%s: %s\n", prefix, yamlKey(value.Key), text)
+		if err != nil {
+			return err
+		}
+	}
+	e.rows++
+	return nil
+}
+
+func (e *yamlExporter) End() error {
+	if e.rows == 0 {
+		_, err := e.writer.WriteString("[]\n")
+		if err != nil {
+			return err
+		}
+	}
+	return e.writer.Flush()
+}
+
+// yamlKey returns the key, quoted if it would not be read back as the same string
+func yamlKey(key string) string {
+	if yamlPlainKey.MatchString(key) && !yamlReservedKeys[strings.ToLower(key)] {
+		return key
+	}
+	data, _ := json.Marshal(key)
+	return string(data)
+}
+
+// yamlValue returns the value as YAML scalar, JSON strings are valid double-quoted YAML scalars
+func yamlValue(val interface{}) (string, error) {
+	switch v := val.(type) {
+	case nil:
+		return "null", nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return formatDocumentFloat(v, ".inf", ".nan"), nil
+	case json.Number:
+		return v.String(), nil
+	case time.Time:
+		if v.IsZero() {
+			return "null", nil
+		}
+		return v.Format(time.RFC3339Nano), nil
+	case []byte:
+		return "!!binary \"" + base64.StdEncoding.EncodeToString(v) + "\"", nil
+	}
+	data, err := json.Marshal(val)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// yamlNumber matches plain scalars that are read as numbers
+var yamlNumber = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+
+// yamlLine is a line of a YAML document without comment and indentation
+type yamlLine struct {
+	number int
+	indent int
+	text   string
+}
+
+// yamlParser reads the block YAML used by configuration files: mappings, sequences, flow collections on one line,
+// quoted and plain scalars and comments. Anchors, aliases, tags, block scalars and multiple documents are not supported.
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// parseYAML returns the document as map[string]interface{}, []interface{}, string, int64, float64, bool or nil,
+// the types encoding/json decodes into interface{} apart from the integers
+func parseYAML(data []byte) (interface{}, error) {
+	p := &yamlParser{}
+	for i, text := range strings.Split(string(data), "\n") {
+		text = yamlStripComment(strings.TrimRight(text, "\r"))
+		content := strings.TrimLeft(text, " ")
+		if len(strings.TrimSpace(content)) == 0 {
+			continue
+		}
+		if strings.HasPrefix(content, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", i+1)
+		}
+		if content == "---" && len(p.lines) == 0 {
+			continue
+		}
+		p.lines = append(p.lines, yamlLine{number: i + 1, indent: len(text) - len(content), text: strings.TrimRight(content, " \t")})
+	}
+	if len(p.lines) == 0 {
+		return nil, nil
+	}
+	var value interface{}
+	var err error
+	if len(p.lines) == 1 && !yamlItem(p.lines[0].text) && !yamlKeyLine(p.lines[0].text) {
+		value, err = yamlScalar(p.lines[0])
+		p.pos++
+	} else {
+		value, err = p.node()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, fmt.Errorf("line %d: unexpected content %q", p.lines[p.pos].number, p.lines[p.pos].text)
+	}
+	return value, nil
+}
+
+// node reads the sequence or mapping starting at the current line
+func (p *yamlParser) node() (interface{}, error) {
+	line := p.lines[p.pos]
+	if yamlItem(line.text) {
+		return p.sequence(line.indent)
+	}
+	return p.mapping(line.indent)
+}
+
+// sequence reads the items starting with "- " at the indentation
+func (p *yamlParser) sequence(indent int) ([]interface{}, error) {
+	items := make([]interface{}, 0)
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent || (line.indent == indent && !yamlItem(line.text)) {
+			break
+		}
+		if line.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", line.number)
+		}
+		rest := strings.TrimLeft(line.text[1:], " ")
+		var value interface{}
+		var err error
+		switch {
+		case len(rest) == 0:
+			p.pos++
+			if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+				value, err = p.node()
+			}
+		case yamlItem(rest) || yamlKeyLine(rest):
+			// A compact collection starts on the line of the item, it is indented by the position of its first character
+			p.lines[p.pos] = yamlLine{number: line.number, indent: indent + len(line.text) - len(rest), text: rest}
+			value, err = p.node()
+		default:
+			value, err = yamlScalar(yamlLine{number: line.number, text: rest})
+			p.pos++
+		}
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, value)
+	}
+	return items, nil
+}
+
+// mapping reads the "key: value" lines at the indentation
+func (p *yamlParser) mapping(indent int) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent || (line.indent == indent && yamlItem(line.text)) {
+			break
+		}
+		if line.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", line.number)
+		}
+		key, rest, ok := yamlSplitKey(line.text)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key: value instead of %q", line.number, line.text)
+		}
+		key, err := yamlKeyText(key, line.number)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := values[key]; ok {
+			return nil, fmt.Errorf("line %d: duplicate key %q", line.number, key)
+		}
+		var value interface{}
+		if len(rest) == 0 {
+			p.pos++
+			// Sequences may be indented like the key they belong to
+			if p.pos < len(p.lines) && (p.lines[p.pos].indent > indent || (p.lines[p.pos].indent == indent && yamlItem(p.lines[p.pos].text))) {
+				value, err = p.node()
+			}
+		} else {
+			value, err = yamlScalar(yamlLine{number: line.number, text: rest})
+			p.pos++
+		}
+		if err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// yamlItem returns true if the line is an item of a sequence
+func yamlItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// yamlKeyLine returns true if the line starts a mapping
+func yamlKeyLine(text string) bool {
+	_, _, ok := yamlSplitKey(text)
+	return ok
+}
+
+// yamlSplitKey splits the line at the colon after the key, which is followed by a space or ends the line
+func yamlSplitKey(text string) (string, string, bool) {
+	if len(text) == 0 || strings.ContainsRune("[{", rune(text[0])) {
+		return "", "", false
+	}
+	start := 0
+	if text[0] == '"' || text[0] == '\'' {
+		end := yamlQuoteEnd(text, 0)
+		if end < 0 {
+			return "", "", false
+		}
+		start = end + 1
+	}
+	for i := start; i < len(text); i++ {
+		if text[i] == ':' && (i+1 == len(text) || text[i+1] == ' ') {
+			return strings.TrimRight(text[:i], " "), strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// yamlKeyText returns the key without quotes
+func yamlKeyText(key string, number int) (string, error) {
+	if len(key) > 0 && (key[0] == '"' || key[0] == '\'') {
+		value, err := yamlScalar(yamlLine{number: number, text: key})
+		if err != nil {
+			return "", err
+		}
+		text, _ := value.(string)
+		return text, nil
+	}
+	return key, nil
+}
+
+// yamlQuoteEnd returns the position of the quote closing the scalar quoted at start, -1 if it is not closed
+func yamlQuoteEnd(text string, start int) int {
+	quote := text[start]
+	for i := start + 1; i < len(text); i++ {
+		switch {
+		case quote == '"' && text[i] == '\\':
+			i++
+		case quote == '\'' && text[i] == '\'' && i+1 < len(text) && text[i+1] == '\'':
+			i++
+		case text[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// yamlStripComment removes a comment, which starts with # at the beginning of the line or after a space outside of quotes
+func yamlStripComment(text string) string {
+	for i := 0; i < len(text); i++ {
+		switch {
+		case (text[i] == '"' || text[i] == '\'') && (i == 0 || strings.ContainsRune(" [{,:-", rune(text[i-1]))):
+			end := yamlQuoteEnd(text, i)
+			if end < 0 {
+				return text
+			}
+			i = end
+		case text[i] == '#' && (i == 0 || text[i-1] == ' ' || text[i-1] == '\t'):
+			return text[:i]
+		}
+	}
+	return text
+}
+
+// yamlScalar reads the value of a line, a quoted or plain scalar or a flow collection
+func yamlScalar(line yamlLine) (interface{}, error) {
+	text := line.text
+	switch text[0] {
+	case '|', '>':
+		return nil, fmt.Errorf("line %d: block scalars are not supported", line.number)
+	case '&', '*', '!':
+		return nil, fmt.Errorf("line %d: anchors, aliases and tags are not supported", line.number)
+	case '[', '{', '"', '\'':
+		flow := &yamlFlow{text: text, number: line.number}
+		value, err := flow.value()
+		if err != nil {
+			return nil, err
+		}
+		flow.skipSpaces()
+		if flow.pos < len(text) {
+			return nil, fmt.Errorf("line %d: unexpected %q after the value", line.number, text[flow.pos:])
+		}
+		return value, nil
+	}
+	return yamlPlain(text), nil
+}
+
+// yamlPlain resolves a plain scalar to null, a boolean, a number or a string
+func yamlPlain(text string) interface{} {
+	switch text {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	}
+	if integer, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return integer
+	}
+	if yamlNumber.MatchString(text) {
+		if number, err := strconv.ParseFloat(text, 64); err == nil {
+			return number
+		}
+	}
+	return text
+}
+
+// yamlFlow reads a flow collection or quoted scalar of a single line
+type yamlFlow struct {
+	text   string
+	number int
+	pos    int
+}
+
+func (f *yamlFlow) skipSpaces() {
+	for f.pos < len(f.text) && f.text[f.pos] == ' ' {
+		f.pos++
+	}
+}
+
+func (f *yamlFlow) value() (interface{}, error) {
+	f.skipSpaces()
+	if f.pos >= len(f.text) {
+		return nil, fmt.Errorf("line %d: unexpected end of the line", f.number)
+	}
+	switch f.text[f.pos] {
+	case '[':
+		return f.sequence()
+	case '{':
+		return f.mapping()
+	case '"', '\'':
+		return f.quoted()
+	}
+	start := f.pos
+	for f.pos < len(f.text) && !strings.ContainsRune(",]}", rune(f.text[f.pos])) {
+		f.pos++
+	}
+	return yamlPlain(strings.TrimSpace(f.text[start:f.pos])), nil
+}
+
+func (f *yamlFlow) sequence() ([]interface{}, error) {
+	items := make([]interface{}, 0)
+	f.pos++
+	for {
+		f.skipSpaces()
+		if f.pos < len(f.text) && f.text[f.pos] == ']' {
+			f.pos++
+			return items, nil
+		}
+		item, err := f.value()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		err = f.separator(']')
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (f *yamlFlow) mapping() (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	f.pos++
+	for {
+		f.skipSpaces()
+		if f.pos < len(f.text) && f.text[f.pos] == '}' {
+			f.pos++
+			return values, nil
+		}
+		var key string
+		if f.pos < len(f.text) && (f.text[f.pos] == '"' || f.text[f.pos] == '\'') {
+			quoted, err := f.quoted()
+			if err != nil {
+				return nil, err
+			}
+			key = quoted
+		} else {
+			start := f.pos
+			for f.pos < len(f.text) && !strings.ContainsRune(":,}", rune(f.text[f.pos])) {
+				f.pos++
+			}
+			key = strings.TrimSpace(f.text[start:f.pos])
+		}
+		f.skipSpaces()
+		if f.pos >= len(f.text) || f.text[f.pos] != ':' {
+			return nil, fmt.Errorf("line %d: expected ':' after key %q", f.number, key)
+		}
+		f.pos++
+		if _, ok := values[key]; ok {
+			return nil, fmt.Errorf("line %d: duplicate key %q", f.number, key)
+		}
+		value, err := f.value()
+		if err != nil {
+			return nil, err
+		}
+		values[key] = value
+		err = f.separator('}')
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// separator consumes the comma between the entries, the closing bracket is left for the collection
+func (f *yamlFlow) separator(closing byte) error {
+	f.skipSpaces()
+	switch {
+	case f.pos < len(f.text) && f.text[f.pos] == ',':
+		f.pos++
+		return nil
+	case f.pos < len(f.text) && f.text[f.pos] == closing:
+		return nil
+	}
+	return fmt.Errorf("line %d: expected ',' or '%c'", f.number, closing)
+}
+
+// quoted reads a double-quoted scalar with the escapes of JSON or a single-quoted scalar with doubled quotes as quote
+func (f *yamlFlow) quoted() (string, error) {
+	end := yamlQuoteEnd(f.text, f.pos)
+	if end < 0 {
+		return "", fmt.Errorf("line %d: unclosed quote", f.number)
+	}
+	raw := f.text[f.pos : end+1]
+	f.pos = end + 1
+	if raw[0] == '\'' {
+		return strings.ReplaceAll(raw[1:len(raw)-1], "''", "'"), nil
+	}
+	var text string
+	err := json.Unmarshal([]byte(raw), &text)
+	if err != nil {
+		return "", fmt.Errorf("line %d: invalid double-quoted scalar %s", f.number, raw)
+	}
+	return text, nil
+}
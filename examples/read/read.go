@@ -95,7 +95,7 @@ func main() {
 		// === Modifications ===
 
 		// Disable space trimming for the company name
-		err = table.SetColumnModificationByName("PRODNAME", &dbase.Modification{TrimSpaces: false})
+		err = table.SetColumnModificationByName("PRODNAME", &dbase.Modification{TrimMode: dbase.TrimNone})
 		if err != nil {
 			panic(dbase.GetErrorTrace(err))
 		}
@@ -2,6 +2,7 @@ module github.com/Valentin-Kaiser/go-dbase
 
 go 1.19
 
-require golang.org/x/text v0.11.0
-
-require golang.org/x/sys v0.10.0
+require (
+	golang.org/x/sys v0.10.0
+	golang.org/x/text v0.11.0
+)
@@ -0,0 +1,306 @@
+// Command shimgen generates a package re-exporting the API of a package of the version 1 module in the version 2 module.
+// For each file of the implementation a file of the same name and build constraints is written,
+// declaring its exported types as aliases, its exported constants and variables by value
+// and its exported functions as wrappers, with the doc comments of the implementation.
+// Methods are available through the aliases, so the API of the package stays unchanged.
+// Files generated before are removed first, so declarations removed from the implementation are removed as well.
+//
+// Usage:
+//
+//	shimgen <implementation dir> <import path> <package name>
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// header marks the generated files
+const header = "// Code generated by shimgen. DO NOT EDIT.\n"
+
+func main() {
+	if len(os.Args) != 4 {
+		fmt.Fprintln(os.Stderr, "usage: shimgen <implementation dir> <import path> <package name>")
+		os.Exit(2)
+	}
+	err := run(os.Args[1], os.Args[2], os.Args[3])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "shimgen:", err)
+		os.Exit(1)
+	}
+}
+
+// run writes the compatibility layer of the implementation in dir into the current directory
+func run(dir string, importPath string, name string) error {
+	fset := token.NewFileSet()
+	packages, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+	if len(packages) != 1 {
+		return fmt.Errorf("expected one package in %s, found %d", dir, len(packages))
+	}
+	var pkg *ast.Package
+	for _, p := range packages {
+		pkg = p
+	}
+	err = removeGenerated()
+	if err != nil {
+		return err
+	}
+	files := make([]string, 0, len(pkg.Files))
+	for file := range pkg.Files {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		src, err := generate(pkg.Files[file], importPath, pkg.Name, name)
+		if err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(file), err)
+		}
+		if src == nil {
+			continue
+		}
+		err = os.WriteFile(filepath.Base(file), src, 0644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeGenerated removes the files of the current directory generated before
+func removeGenerated() error {
+	entries, err := os.ReadDir(".")
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+			continue
+		}
+		data, err := os.ReadFile(entry.Name())
+		if err != nil {
+			return err
+		}
+		if bytes.HasPrefix(data, []byte(header)) {
+			err = os.Remove(entry.Name())
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// generate returns the compatibility layer of the file, nil if the file has no exported declarations
+func generate(file *ast.File, importPath string, qualifier string, name string) ([]byte, error) {
+	imports := make(map[string]string)
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return nil, err
+		}
+		imports[importName(spec, path)] = path
+	}
+	used := make(map[string]bool)
+	var body bytes.Buffer
+	count := 0
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			if d.Tok == token.IMPORT {
+				continue
+			}
+			specs := exportedSpecs(d, qualifier)
+			if len(specs) == 0 {
+				continue
+			}
+			count++
+			if len(d.Specs) == 1 {
+				fmt.Fprintf(&body, "\n%s%s %s\n", comment(d.Doc), d.Tok, specs[0])
+				continue
+			}
+			fmt.Fprintf(&body, "\n%s%s (\n%s\n)\n", comment(d.Doc), d.Tok, strings.Join(specs, "\n"))
+		case *ast.FuncDecl:
+			if d.Recv != nil || !d.Name.IsExported() {
+				continue
+			}
+			count++
+			err := wrapFunc(&body, d, qualifier, used)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if count == 0 {
+		return nil, nil
+	}
+	var buf bytes.Buffer
+	buf.WriteString(header)
+	buf.WriteString("\n")
+	constraints := buildConstraints(file)
+	if len(constraints) > 0 {
+		fmt.Fprintf(&buf, "%s\n\n", strings.Join(constraints, "\n"))
+	}
+	fmt.Fprintf(&buf, "package %s\n\n", name)
+	// Imports of the standard library and other packages in two groups, the implementation is imported last
+	std := make([]string, 0)
+	paths := make([]string, 0)
+	for used := range used {
+		path, ok := imports[used]
+		if !ok {
+			continue
+		}
+		line := strconv.Quote(path)
+		if filepath.Base(path) != used {
+			line = used + " " + line
+		}
+		if strings.Contains(strings.Split(path, "/")[0], ".") {
+			paths = append(paths, line)
+			continue
+		}
+		std = append(std, line)
+	}
+	sort.Strings(std)
+	sort.Strings(paths)
+	groups := make([]string, 0, 3)
+	for _, group := range [][]string{std, paths, {strconv.Quote(importPath)}} {
+		if len(group) > 0 {
+			groups = append(groups, strings.Join(group, "\n"))
+		}
+	}
+	if len(groups) == 1 {
+		fmt.Fprintf(&buf, "import %q\n", importPath)
+	} else {
+		fmt.Fprintf(&buf, "import (\n%s\n)\n", strings.Join(groups, "\n\n"))
+	}
+	buf.Write(body.Bytes())
+	return format.Source(buf.Bytes())
+}
+
+// importName returns the name the file uses for the import
+func importName(spec *ast.ImportSpec, path string) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+	return filepath.Base(path)
+}
+
+// exportedSpecs returns the exported types, constants and variables of the declaration as aliases and values
+func exportedSpecs(decl *ast.GenDecl, qualifier string) []string {
+	specs := make([]string, 0)
+	for _, spec := range decl.Specs {
+		switch s := spec.(type) {
+		case *ast.TypeSpec:
+			if s.Name.IsExported() {
+				specs = append(specs, comment(s.Doc)+fmt.Sprintf("%s = %s.%s%s", s.Name.Name, qualifier, s.Name.Name, trailing(s.Comment)))
+			}
+		case *ast.ValueSpec:
+			for _, n := range s.Names {
+				if n.IsExported() {
+					specs = append(specs, comment(s.Doc)+fmt.Sprintf("%s = %s.%s%s", n.Name, qualifier, n.Name, trailing(s.Comment)))
+				}
+			}
+		}
+	}
+	return specs
+}
+
+// wrapFunc writes a function with the signature of the declaration calling the implementation
+func wrapFunc(buf *bytes.Buffer, decl *ast.FuncDecl, qualifier string, used map[string]bool) error {
+	args := make([]string, 0)
+	for _, field := range decl.Type.Params.List {
+		if len(field.Names) == 0 {
+			field.Names = []*ast.Ident{ast.NewIdent("")}
+		}
+		for _, n := range field.Names {
+			// Unnamed parameters and parameters shadowing the implementation package get a name
+			if n.Name == "" || n.Name == "_" || n.Name == qualifier {
+				n.Name = fmt.Sprintf("p%d", len(args))
+			}
+			arg := n.Name
+			if _, ok := field.Type.(*ast.Ellipsis); ok {
+				arg += "..."
+			}
+			args = append(args, arg)
+		}
+	}
+	if decl.Type.Results != nil {
+		for _, field := range decl.Type.Results.List {
+			for _, n := range field.Names {
+				if n.Name == qualifier {
+					return fmt.Errorf("result %s of %s shadows the implementation package", n.Name, decl.Name.Name)
+				}
+			}
+		}
+	}
+	ast.Inspect(decl.Type, func(node ast.Node) bool {
+		if sel, ok := node.(*ast.SelectorExpr); ok {
+			if x, ok := sel.X.(*ast.Ident); ok {
+				used[x.Name] = true
+			}
+		}
+		return true
+	})
+	var signature bytes.Buffer
+	err := printer.Fprint(&signature, token.NewFileSet(), decl.Type)
+	if err != nil {
+		return err
+	}
+	call := fmt.Sprintf("%s.%s(%s)", qualifier, decl.Name.Name, strings.Join(args, ", "))
+	if decl.Type.Results != nil && len(decl.Type.Results.List) > 0 {
+		call = "return " + call
+	}
+	fmt.Fprintf(buf, "\n%sfunc %s%s {\n%s\n}\n", comment(decl.Doc), decl.Name.Name, strings.TrimPrefix(signature.String(), "func"), call)
+	return nil
+}
+
+// buildConstraints returns the build constraint lines of the file
+func buildConstraints(file *ast.File) []string {
+	lines := make([]string, 0)
+	for _, group := range file.Comments {
+		if group.Pos() >= file.Package {
+			break
+		}
+		for _, c := range group.List {
+			if strings.HasPrefix(c.Text, "//go:build") || strings.HasPrefix(c.Text, "// +build") {
+				lines = append(lines, c.Text)
+			}
+		}
+	}
+	return lines
+}
+
+// comment returns the comment group as source lines
+func comment(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	var builder strings.Builder
+	for _, c := range doc.List {
+		builder.WriteString(c.Text)
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// trailing returns the comment behind a declaration
+func trailing(c *ast.CommentGroup) string {
+	if c == nil {
+		return ""
+	}
+	return " " + c.List[0].Text
+}
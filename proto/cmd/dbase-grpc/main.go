@@ -0,0 +1,59 @@
+// Command dbase-grpc serves the tables of a directory read-only with the gRPC service defined in dbase.proto.
+//
+// Usage:
+//
+//	dbase-grpc [-addr :50051] [-untested] <dir>
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	dbasepb "github.com/Valentin-Kaiser/go-dbase/proto"
+	"github.com/Valentin-Kaiser/go-dbase/v2/dbf"
+	"google.golang.org/grpc"
+)
+
+func main() {
+	addr := flag.String("addr", ":50051", "Address to listen on")
+	untested := flag.Bool("untested", false, "Open tables of versions that are not tested")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: dbase-grpc [-addr :50051] [-untested] <dir>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	info, err := os.Stat(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !info.IsDir() {
+		log.Fatalf("%s is not a directory", flag.Arg(0))
+	}
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	server := grpc.NewServer()
+	dbasepb.RegisterDBaseServer(server, dbasepb.NewServer(flag.Arg(0), &dbf.Config{TrimSpaces: true, Untested: *untested}))
+	// Finish running streams on interrupt
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		server.GracefulStop()
+	}()
+	log.Printf("Serving the tables of %s on %s", flag.Arg(0), listener.Addr())
+	err = server.Serve(listener)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
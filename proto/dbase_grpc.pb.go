@@ -0,0 +1,182 @@
+// Service definition to stream the schema and rows of dBase tables to non-Go consumers.
+// The rows are sent in batches, the projection (columns) and the filter are applied on the server side
+// using File.StreamBatches of the dbase package.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: dbase.proto
+
+package dbasepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	DBase_Schema_FullMethodName = "/dbase.DBase/Schema"
+	DBase_Rows_FullMethodName   = "/dbase.DBase/Rows"
+)
+
+// DBaseClient is the client API for DBase service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DBaseClient interface {
+	// Returns the columns of a table
+	Schema(ctx context.Context, in *SchemaRequest, opts ...grpc.CallOption) (*SchemaResponse, error)
+	// Streams the rows of a table in batches
+	Rows(ctx context.Context, in *RowsRequest, opts ...grpc.CallOption) (DBase_RowsClient, error)
+}
+
+type dBaseClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDBaseClient(cc grpc.ClientConnInterface) DBaseClient {
+	return &dBaseClient{cc}
+}
+
+func (c *dBaseClient) Schema(ctx context.Context, in *SchemaRequest, opts ...grpc.CallOption) (*SchemaResponse, error) {
+	out := new(SchemaResponse)
+	err := c.cc.Invoke(ctx, DBase_Schema_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dBaseClient) Rows(ctx context.Context, in *RowsRequest, opts ...grpc.CallOption) (DBase_RowsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DBase_ServiceDesc.Streams[0], DBase_Rows_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &dBaseRowsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DBase_RowsClient interface {
+	Recv() (*RowBatch, error)
+	grpc.ClientStream
+}
+
+type dBaseRowsClient struct {
+	grpc.ClientStream
+}
+
+func (x *dBaseRowsClient) Recv() (*RowBatch, error) {
+	m := new(RowBatch)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DBaseServer is the server API for DBase service.
+// All implementations must embed UnimplementedDBaseServer
+// for forward compatibility
+type DBaseServer interface {
+	// Returns the columns of a table
+	Schema(context.Context, *SchemaRequest) (*SchemaResponse, error)
+	// Streams the rows of a table in batches
+	Rows(*RowsRequest, DBase_RowsServer) error
+	mustEmbedUnimplementedDBaseServer()
+}
+
+// UnimplementedDBaseServer must be embedded to have forward compatible implementations.
+type UnimplementedDBaseServer struct {
+}
+
+func (UnimplementedDBaseServer) Schema(context.Context, *SchemaRequest) (*SchemaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Schema not implemented")
+}
+func (UnimplementedDBaseServer) Rows(*RowsRequest, DBase_RowsServer) error {
+	return status.Errorf(codes.Unimplemented, "method Rows not implemented")
+}
+func (UnimplementedDBaseServer) mustEmbedUnimplementedDBaseServer() {}
+
+// UnsafeDBaseServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DBaseServer will
+// result in compilation errors.
+type UnsafeDBaseServer interface {
+	mustEmbedUnimplementedDBaseServer()
+}
+
+func RegisterDBaseServer(s grpc.ServiceRegistrar, srv DBaseServer) {
+	s.RegisterService(&DBase_ServiceDesc, srv)
+}
+
+func _DBase_Schema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DBaseServer).Schema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DBase_Schema_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DBaseServer).Schema(ctx, req.(*SchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DBase_Rows_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RowsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DBaseServer).Rows(m, &dBaseRowsServer{stream})
+}
+
+type DBase_RowsServer interface {
+	Send(*RowBatch) error
+	grpc.ServerStream
+}
+
+type dBaseRowsServer struct {
+	grpc.ServerStream
+}
+
+func (x *dBaseRowsServer) Send(m *RowBatch) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// DBase_ServiceDesc is the grpc.ServiceDesc for DBase service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DBase_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dbase.DBase",
+	HandlerType: (*DBaseServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Schema",
+			Handler:    _DBase_Schema_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Rows",
+			Handler:       _DBase_Rows_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "dbase.proto",
+}
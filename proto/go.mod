@@ -0,0 +1,23 @@
+module github.com/Valentin-Kaiser/go-dbase/proto
+
+go 1.19
+
+require (
+	github.com/Valentin-Kaiser/go-dbase/v2 v2.0.0
+	google.golang.org/grpc v1.64.0
+	google.golang.org/protobuf v1.33.0
+)
+
+require (
+	github.com/Valentin-Kaiser/go-dbase v0.0.0-00010101000000-000000000000 // indirect
+	golang.org/x/net v0.22.0 // indirect
+	golang.org/x/sys v0.18.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240318140521-94a12d6c2237 // indirect
+)
+
+// The modules of this repository are built from the same revision, also without go.work
+replace (
+	github.com/Valentin-Kaiser/go-dbase => ../
+	github.com/Valentin-Kaiser/go-dbase/v2 => ../v2
+)
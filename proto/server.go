@@ -0,0 +1,170 @@
+// Package dbasepb contains the gRPC service defined in dbase.proto and its server,
+// which streams the schema and rows of the tables in a directory to non-Go consumers.
+// The code of dbase.pb.go and dbase_grpc.pb.go is generated with protoc-gen-go and protoc-gen-go-grpc.
+package dbasepb
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Valentin-Kaiser/go-dbase/v2/dbf"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server implements DBaseServer for the tables in a directory.
+// Each request opens the table read-only, so tables in use by other applications can be served.
+type Server struct {
+	UnimplementedDBaseServer
+	dir    string
+	config dbf.Config
+}
+
+// NewServer returns a server for the tables in the directory.
+// The tables are opened with a copy of the config, its Filename and ReadOnly are set per request.
+// If config is nil the tables are opened with TrimSpaces.
+func NewServer(dir string, config *dbf.Config) *Server {
+	if config == nil {
+		config = &dbf.Config{TrimSpaces: true}
+	}
+	return &Server{dir: dir, config: *config}
+}
+
+// Schema returns the columns and the rows count of the table
+func (s *Server) Schema(ctx context.Context, req *SchemaRequest) (*SchemaResponse, error) {
+	file, err := s.open(req.GetTable())
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	res := &SchemaResponse{Table: req.GetTable(), RowsCount: file.RowsCount()}
+	for _, column := range file.Columns() {
+		res.Columns = append(res.Columns, &Column{
+			Name:     column.Name(),
+			DataType: column.Type(),
+			Length:   uint32(column.Length),
+			Decimals: uint32(column.Decimals),
+			Nullable: column.Flag&byte(dbf.NullableFlag) != 0,
+		})
+	}
+	return res, nil
+}
+
+// Rows streams the rows of the table in batches, the projection and the conditions are applied by the server
+func (s *Server) Rows(req *RowsRequest, stream DBase_RowsServer) error {
+	file, err := s.open(req.GetTable())
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	conditions := make([]dbf.BatchCondition, 0, len(req.GetConditions()))
+	for _, c := range req.GetConditions() {
+		conditions = append(conditions, dbf.BatchCondition{Column: c.GetColumn(), Value: c.GetValue(), Exact: c.GetExact()})
+	}
+	filter, err := file.ConditionFilter(conditions)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	for _, name := range req.GetColumns() {
+		if file.ColumnPosByName(name) < 0 {
+			return status.Errorf(codes.InvalidArgument, "column '%s' not found", name)
+		}
+	}
+	config := &dbf.BatchConfig{
+		Columns:     req.GetColumns(),
+		Filter:      filter,
+		Size:        int(req.GetBatchSize()),
+		SkipDeleted: req.GetSkipDeleted(),
+	}
+	err = file.StreamBatches(config, func(batch *dbf.RowBatch) error {
+		// Stop reading the table if the client went away
+		err := stream.Context().Err()
+		if err != nil {
+			return err
+		}
+		res := &RowBatch{Columns: batch.Columns, Rows: make([]*Row, 0, len(batch.Values))}
+		for i, values := range batch.Values {
+			row := &Row{Position: batch.Positions[i], Deleted: batch.Deleted[i], Values: make([]*Value, 0, len(values))}
+			for _, val := range values {
+				row.Values = append(row.Values, toValue(val))
+			}
+			res.Rows = append(res.Rows, row)
+		}
+		return stream.Send(res)
+	})
+	if err != nil {
+		if ctxErr := stream.Context().Err(); ctxErr != nil {
+			return status.FromContextError(ctxErr).Err()
+		}
+		// Errors of Send are passed on with their status
+		if st, ok := status.FromError(err); ok {
+			return st.Err()
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+// open opens the table of the request in the directory, the name may be given with or without extension
+func (s *Server) open(table string) (*dbf.File, error) {
+	if len(table) == 0 || filepath.Base(table) != table || table == "." || table == ".." {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid table name %q", table)
+	}
+	if !strings.EqualFold(filepath.Ext(table), string(dbf.DBF)) {
+		table += string(dbf.DBF)
+	}
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	path := ""
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.EqualFold(entry.Name(), table) {
+			path = filepath.Join(s.dir, entry.Name())
+			break
+		}
+	}
+	if len(path) == 0 {
+		return nil, status.Errorf(codes.NotFound, "table %s not found", strings.TrimSuffix(table, filepath.Ext(table)))
+	}
+	config := s.config
+	config.Filename = path
+	config.ReadOnly = true
+	file, err := dbf.OpenTable(&config)
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("opening table failed with error: %v", err))
+	}
+	return file, nil
+}
+
+// toValue converts a field value to its message, types without a matching field are sent as string
+func toValue(val interface{}) *Value {
+	switch v := val.(type) {
+	case nil:
+		return &Value{Kind: &Value_Null{Null: true}}
+	case string:
+		return &Value{Kind: &Value_StringValue{StringValue: v}}
+	case int32:
+		return &Value{Kind: &Value_IntValue{IntValue: int64(v)}}
+	case int64:
+		return &Value{Kind: &Value_IntValue{IntValue: v}}
+	case float64:
+		return &Value{Kind: &Value_DoubleValue{DoubleValue: v}}
+	case bool:
+		return &Value{Kind: &Value_BoolValue{BoolValue: v}}
+	case []byte:
+		return &Value{Kind: &Value_BytesValue{BytesValue: v}}
+	case time.Time:
+		if v.IsZero() {
+			return &Value{Kind: &Value_Null{Null: true}}
+		}
+		return &Value{Kind: &Value_TimeValue{TimeValue: timestamppb.New(v)}}
+	default:
+		return &Value{Kind: &Value_StringValue{StringValue: fmt.Sprint(v)}}
+	}
+}
@@ -0,0 +1,93 @@
+package dbase
+
+import (
+	"github.com/Valentin-Kaiser/go-dbase/v2/dbf"
+)
+
+// Cursor iterates the rows of a table:
+//
+//	cursor := table.Cursor(nil)
+//	defer cursor.Close()
+//	for cursor.Next() {
+//		row := cursor.Row()
+//	}
+//	if err := cursor.Err(); err != nil {
+//		return err
+//	}
+//
+// A cursor keeps its own position, several cursors of a table can be used one after another but not concurrently.
+type Cursor interface {
+	Next() bool       // Reads the next row, false at the end of the table or on error
+	Row() *Row        // Row read by the last call of Next
+	Position() uint32 // Position of the next row
+	Err() error       // Error that stopped the cursor, nil at the end of the table
+	Close() error     // Stops the cursor
+}
+
+// CursorOptions configures a cursor
+type CursorOptions struct {
+	Start       uint32              // Position of the first row
+	SkipDeleted bool                // Skip deleted rows, they are always skipped if the table hides them
+	Filter      func(row *Row) bool // Only rows for which the filter returns true are returned, all rows if nil
+}
+
+// cursor implements Cursor on top of the row pointer of the dbf package
+type cursor struct {
+	file     *dbf.File
+	opts     CursorOptions
+	position uint32
+	row      *Row
+	err      error
+	closed   bool
+}
+
+func newCursor(file *dbf.File, opts *CursorOptions) *cursor {
+	if opts == nil {
+		opts = &CursorOptions{}
+	}
+	return &cursor{file: file, opts: *opts, position: opts.Start}
+}
+
+func (c *cursor) Next() bool {
+	c.row = nil
+	for !c.closed && c.err == nil && c.position < c.file.Header().RowsCount {
+		err := c.file.GoTo(c.position)
+		if err != nil {
+			c.err = wrap("next", err)
+			return false
+		}
+		row, err := c.file.Row()
+		if err != nil {
+			c.err = wrap("next", err)
+			return false
+		}
+		c.position++
+		if row.Deleted && (c.opts.SkipDeleted || c.file.DeletedHidden()) {
+			continue
+		}
+		if c.opts.Filter != nil && !c.opts.Filter(row) {
+			continue
+		}
+		c.row = row
+		return true
+	}
+	return false
+}
+
+func (c *cursor) Row() *Row {
+	return c.row
+}
+
+func (c *cursor) Position() uint32 {
+	return c.position
+}
+
+func (c *cursor) Err() error {
+	return c.err
+}
+
+func (c *cursor) Close() error {
+	c.closed = true
+	c.row = nil
+	return nil
+}
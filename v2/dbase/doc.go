@@ -0,0 +1,15 @@
+// Package dbase is the version 2 API of go-dbase. It groups the features of the dbf package
+// into coherent interfaces instead of the methods of one File type and the 40 fields of one Config:
+//
+//   - Open takes Options that group the text, memo and lock settings, the IO implementation replaces the file handles.
+//   - Errors are of type *Error with an ErrorKind, so callers can handle missing, corrupt and locked files
+//     without comparing sentinel errors.
+//   - Table is the opened table. It returns a Cursor to iterate the rows, an Appender to write rows,
+//     and the Index and Memo of the table.
+//   - A Cursor has its own position, so reading does not depend on the row pointer of the table.
+//
+// The dbf package of this module re-exports the file format implementation of the dbase package of the version 1 module,
+// Row, Column and Header are its types and File returns the underlying *dbf.File for features not covered yet.
+// The types of both packages are the same, so Wrap turns a table opened with the version 1 API into a Table
+// and both APIs can be used side by side while migrating.
+package dbase
@@ -0,0 +1,113 @@
+package dbase
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/Valentin-Kaiser/go-dbase/v2/dbf"
+)
+
+// ErrorKind classifies the errors of the API
+type ErrorKind byte
+
+const (
+	ErrorUnknown      ErrorKind = iota // The error is not classified
+	ErrorNotFound                      // The table or memo file does not exist
+	ErrorCorrupt                       // The header, a row, a memo or the index is corrupt
+	ErrorUnavailable                   // The file was removed, replaced or changed by another process
+	ErrorLocked                        // A lock is held by another process
+	ErrorSchema                        // A value or column does not match the schema of the table
+	ErrorDuplicateKey                  // A written row violates a unique key
+	ErrorEncoding                      // Text can not be converted with the code page of the table
+	ErrorMemoryLimit                   // The rows exceed the memory limit
+)
+
+// String returns the name of the error kind
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorNotFound:
+		return "not found"
+	case ErrorCorrupt:
+		return "corrupt"
+	case ErrorUnavailable:
+		return "unavailable"
+	case ErrorLocked:
+		return "locked"
+	case ErrorSchema:
+		return "schema"
+	case ErrorDuplicateKey:
+		return "duplicate key"
+	case ErrorEncoding:
+		return "encoding"
+	case ErrorMemoryLimit:
+		return "memory limit"
+	}
+	return "unknown"
+}
+
+// Error is returned by all functions of the API
+type Error struct {
+	Op   string    // Operation that failed, e.g. "open"
+	Kind ErrorKind // Classification of the error
+	Err  error     // Error of the dbf package, the sentinel errors can be checked with errors.Is
+}
+
+// Error returns the error message
+func (e *Error) Error() string {
+	return fmt.Sprintf("dbase %s: %v", e.Op, e.Err)
+}
+
+// Unwrap returns the wrapped error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// IsKind returns true if err is an *Error of the kind
+func IsKind(err error, kind ErrorKind) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Kind == kind
+}
+
+// kinds maps the sentinel errors of the dbf package to the error kinds
+var kinds = []struct {
+	err  error
+	kind ErrorKind
+}{
+	{dbf.ErrNoDBF, ErrorNotFound},
+	{dbf.ErrNoFPT, ErrorNotFound},
+	{dbf.ErrMissingMemoFile, ErrorNotFound},
+	{fs.ErrNotExist, ErrorNotFound},
+	{dbf.ErrIncomplete, ErrorCorrupt},
+	{dbf.ErrInvalidHeader, ErrorCorrupt},
+	{dbf.ErrInvalidMemo, ErrorCorrupt},
+	{dbf.ErrInvalidIndex, ErrorCorrupt},
+	{dbf.ErrIntegrity, ErrorCorrupt},
+	{dbf.ErrFileUnavailable, ErrorUnavailable},
+	{dbf.ErrFileReplaced, ErrorUnavailable},
+	{dbf.ErrHeaderChanged, ErrorUnavailable},
+	{dbf.ErrLocked, ErrorLocked},
+	{dbf.ErrSchemaMismatch, ErrorSchema},
+	{dbf.ErrSystemColumn, ErrorSchema},
+	{dbf.ErrInvalidPosition, ErrorSchema},
+	{dbf.ErrDuplicateKey, ErrorDuplicateKey},
+	{dbf.ErrInvalidEncoding, ErrorEncoding},
+	{dbf.ErrMemoryLimit, ErrorMemoryLimit},
+}
+
+// wrap returns the error of the dbf package as *Error of the operation, nil if err is nil
+func wrap(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	for _, k := range kinds {
+		if errors.Is(err, k.err) {
+			return &Error{Op: op, Kind: k.kind, Err: err}
+		}
+	}
+	return &Error{Op: op, Kind: ErrorUnknown, Err: err}
+}
@@ -0,0 +1,31 @@
+package dbase
+
+import (
+	"github.com/Valentin-Kaiser/go-dbase/v2/dbf"
+)
+
+// Index is the compound index (CDX) of a table, a structural index or a snapshot written by Create
+type Index interface {
+	Tags() ([]*IndexTag, error)                                     // Tags of the index, empty if the table has none
+	Create(tag string, expression string, opts *IndexOptions) error // Creates or replaces the tag of a snapshot, fails for tables with a structural index
+}
+
+// index implements Index on top of the dbf package
+type index struct {
+	file *dbf.File
+}
+
+func (i *index) Tags() ([]*IndexTag, error) {
+	info, err := i.file.IndexInfo()
+	if err != nil {
+		return nil, wrap("index tags", err)
+	}
+	if info == nil {
+		return make([]*IndexTag, 0), nil
+	}
+	return info.Tags, nil
+}
+
+func (i *index) Create(tag string, expression string, opts *IndexOptions) error {
+	return wrap("create index", i.file.CreateIndex(tag, expression, opts))
+}
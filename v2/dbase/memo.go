@@ -0,0 +1,33 @@
+package dbase
+
+import (
+	"github.com/Valentin-Kaiser/go-dbase/v2/dbf"
+)
+
+// Memo is the memo file of a table. Memo fields are read and written with the rows,
+// Memo is used for memos that are not bound to a field, e.g. when copying memo files.
+type Memo interface {
+	Read(address []byte) ([]byte, bool, error)    // Memo at the address and true if it is text, text is decoded
+	Write(data []byte, text bool) ([]byte, error) // Writes the memo and returns its address, text has to be encoded with the code page of the table
+}
+
+// memo implements Memo on top of the dbf package
+type memo struct {
+	file *dbf.File
+}
+
+func (m *memo) Read(address []byte) ([]byte, bool, error) {
+	data, text, err := m.file.ReadMemo(address)
+	if err != nil {
+		return nil, false, wrap("read memo", err)
+	}
+	return data, text, nil
+}
+
+func (m *memo) Write(data []byte, text bool) ([]byte, error) {
+	address, err := m.file.WriteMemo(data, text, len(data))
+	if err != nil {
+		return nil, wrap("write memo", err)
+	}
+	return address, nil
+}
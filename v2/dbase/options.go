@@ -0,0 +1,83 @@
+package dbase
+
+import (
+	"github.com/Valentin-Kaiser/go-dbase/v2/dbf"
+)
+
+// Options configures how a table is opened. The zero value opens the table for reading and writing,
+// with the code page of the table and without trimming text.
+type Options struct {
+	ReadOnly     bool                     // Open the table read-only
+	Exclusive    bool                     // Open the table exclusively
+	Untested     bool                     // Allow file versions that are not tested, see dbf.SupportMatrix
+	HideDeleted  bool                     // Hide deleted rows from cursors and counts, like SET DELETED ON in FoxPro
+	WithoutIndex bool                     // Neither read nor write the index file
+	Encoding     dbf.EncodingConverter    // Converter of text, nil to use the code page mark of the table
+	Text         TextOptions              // Decoding of text and dates
+	Memo         MemoOptions              // Handling of the memo file
+	Lock         LockOptions              // Locking of written rows
+	IO           dbf.IO                   // IO implementation, nil for the default of the OS
+	Legacy       func(config *dbf.Config) // Sets options of the dbf package that are not covered by Options yet
+}
+
+// TextOptions configures the decoding of text and dates
+type TextOptions struct {
+	TrimMode    dbf.TrimMode  // Trimmed side of text values
+	TrimCutset  string        // Characters to trim, white space if empty
+	NilPolicy   dbf.NilPolicy // Empty fields that are decoded to nil
+	DateLayouts []string      // Layouts accepted for string values of date columns, dbf.DefaultDateLayouts if empty
+}
+
+// MemoOptions configures the handling of the memo file
+type MemoOptions struct {
+	Skip         bool           // Do not open the memo file, memo columns are read as nil. Requires ReadOnly.
+	AllowMissing bool           // Open tables whose memo file is missing, memo columns are read as nil. Requires ReadOnly.
+	LineEnding   dbf.LineEnding // Line ending of memo text in the memo file
+	TrimPadding  bool           // Remove trailing null bytes and end of file markers from memo text
+	DetectBOM    bool           // Decode memo text with a byte order mark accordingly
+}
+
+// LockOptions configures the locking of written rows
+type LockOptions struct {
+	Shared bool          // Experimental: take the row and header locks of the Visual FoxPro protocol while writing, see dbf.Config.SharedWrite
+	Rows   bool          // Lock the row while it is written
+	Retry  dbf.LockRetry // Retries of locks held by other processes
+}
+
+// config returns the configuration of the dbf package for the table at path.
+// Unlike dbf.OpenTable the path is used as given, underscores in the file name are not converted to spaces.
+func (o *Options) config(path string) *dbf.Config {
+	if o == nil {
+		o = &Options{}
+	}
+	config := &dbf.Config{
+		Filename:                          path,
+		Converter:                         o.Encoding,
+		Exclusive:                         o.Exclusive,
+		Untested:                          o.Untested,
+		TrimMode:                          o.Text.TrimMode,
+		TrimCutset:                        o.Text.TrimCutset,
+		DisableConvertFilenameUnderscores: true,
+		ReadOnly:                          o.ReadOnly,
+		WriteLock:                         o.Lock.Rows,
+		SkipMissingMemo:                   o.Memo.AllowMissing,
+		WithoutMemo:                       o.Memo.Skip,
+		WithoutIndex:                      o.WithoutIndex,
+		MemoLineEnding:                    o.Memo.LineEnding,
+		TrimMemoPadding:                   o.Memo.TrimPadding,
+		DetectMemoBOM:                     o.Memo.DetectBOM,
+		NilPolicy:                         o.Text.NilPolicy,
+		DateLayouts:                       o.Text.DateLayouts,
+		SharedWrite:                       o.Lock.Shared,
+		LockRetry:                         o.Lock.Retry,
+		IgnoreDeleted:                     o.HideDeleted,
+		IO:                                o.IO,
+	}
+	if o.Text.TrimMode == dbf.TrimDefault {
+		config.TrimMode = dbf.TrimNone
+	}
+	if o.Legacy != nil {
+		o.Legacy(config)
+	}
+	return config
+}
@@ -0,0 +1,113 @@
+package dbase
+
+import (
+	"github.com/Valentin-Kaiser/go-dbase/v2/dbf"
+)
+
+// Types of the dbf package
+type (
+	Row          = dbf.Row
+	Column       = dbf.Column
+	Header       = dbf.Header
+	IndexTag     = dbf.IndexTag
+	IndexOptions = dbf.IndexOptions
+)
+
+// Table is an opened dBase table
+type Table interface {
+	Header() *Header                   // Header of the table
+	Columns() []*Column                // Columns of the table without system columns
+	Count() (uint32, error)            // Number of rows, without deleted rows if they are hidden
+	Get(position uint32) (*Row, error) // Row at the position, starting at 0
+	Cursor(opts *CursorOptions) Cursor // Iterates the rows
+	NewRow() *Row                      // Empty row to append
+	Update(row *Row) error             // Writes the row at its position
+	Appender() Appender                // Appends rows
+	Index() Index                      // Compound index of the table
+	Memo() Memo                        // Memo file of the table
+	File() *dbf.File                   // Table of the dbf package, for features not covered by Table
+	Close() error                      // Closes the table and memo file
+}
+
+// table implements Table on top of the dbf package
+type table struct {
+	file *dbf.File
+}
+
+// Open opens the table at path, opts can be nil
+func Open(path string, opts *Options) (Table, error) {
+	file, err := dbf.OpenTable(opts.config(path))
+	if err != nil {
+		return nil, wrap("open", err)
+	}
+	return &table{file: file}, nil
+}
+
+// Wrap returns the Table of a table opened with the dbf package.
+// The file stays usable with the dbf package, cursors move its row pointer.
+func Wrap(file *dbf.File) Table {
+	return &table{file: file}
+}
+
+func (t *table) Header() *Header {
+	return t.file.Header()
+}
+
+func (t *table) Columns() []*Column {
+	columns := make([]*Column, 0, len(t.file.Columns()))
+	for _, column := range t.file.Columns() {
+		if !column.System() {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+func (t *table) Count() (uint32, error) {
+	count, err := t.file.Count()
+	return count, wrap("count", err)
+}
+
+func (t *table) Get(position uint32) (*Row, error) {
+	err := t.file.GoTo(position)
+	if err != nil {
+		return nil, wrap("get", err)
+	}
+	row, err := t.file.Row()
+	if err != nil {
+		return nil, wrap("get", err)
+	}
+	return row, nil
+}
+
+func (t *table) Cursor(opts *CursorOptions) Cursor {
+	return newCursor(t.file, opts)
+}
+
+func (t *table) NewRow() *Row {
+	return t.file.NewRow()
+}
+
+func (t *table) Update(row *Row) error {
+	return wrap("update", t.file.WriteRow(row))
+}
+
+func (t *table) Appender() Appender {
+	return &appender{file: t.file}
+}
+
+func (t *table) Index() Index {
+	return &index{file: t.file}
+}
+
+func (t *table) Memo() Memo {
+	return &memo{file: t.file}
+}
+
+func (t *table) File() *dbf.File {
+	return t.file
+}
+
+func (t *table) Close() error {
+	return wrap("close", t.file.Close())
+}
@@ -0,0 +1,48 @@
+package dbase
+
+import (
+	"github.com/Valentin-Kaiser/go-dbase/v2/dbf"
+)
+
+// Appender appends rows to a table. The position of a row is assigned when it is written.
+type Appender interface {
+	Append(row *Row) error                         // Appends the row
+	AppendMap(values map[string]interface{}) error // Appends a row with the values by column name
+	AppendStruct(v interface{}) error              // Appends a row with the fields of the struct, see dbf.File.RowFromStruct
+	Appended() int                                 // Number of rows appended
+}
+
+// appender implements Appender on top of Row.Add of the dbf package
+type appender struct {
+	file     *dbf.File
+	appended int
+}
+
+func (a *appender) Append(row *Row) error {
+	err := row.Add()
+	if err != nil {
+		return wrap("append", err)
+	}
+	a.appended++
+	return nil
+}
+
+func (a *appender) AppendMap(values map[string]interface{}) error {
+	row, err := a.file.RowFromMap(values)
+	if err != nil {
+		return wrap("append", err)
+	}
+	return a.Append(row)
+}
+
+func (a *appender) AppendStruct(v interface{}) error {
+	row, err := a.file.RowFromStruct(v)
+	if err != nil {
+		return wrap("append", err)
+	}
+	return a.Append(row)
+}
+
+func (a *appender) Appended() int {
+	return a.appended
+}
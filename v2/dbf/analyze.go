@@ -0,0 +1,8 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// ColumnProfile contains the observed values of a column and the suggested target type for a schema migration
+type ColumnProfile = dbase.ColumnProfile
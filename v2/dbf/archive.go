@@ -0,0 +1,41 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import (
+	"io"
+
+	"github.com/Valentin-Kaiser/go-dbase/dbase"
+)
+
+// DBZ is the file extension of the compressed archive format
+const DBZ = dbase.DBZ
+
+// DefaultMaxArchiveSize is the maximum size in bytes of the table and memo data of an archive if Config.MaxArchiveSize is not set.
+// Restore uses it as well, as the data is restored in memory before it is written.
+const DefaultMaxArchiveSize = dbase.DefaultMaxArchiveSize
+
+// Archive writes the table (and memo file) in the compressed archive format to out.
+// The header and column descriptors are stored as is, the rows are split into their columns and
+// each column is compressed on its own, which compresses much better than row oriented data.
+// The archive can be read with OpenArchive and restored to the original files with Restore.
+func Archive(file *File, out io.Writer) error {
+	return dbase.Archive(file, out)
+}
+
+// OpenArchive opens a compressed archive in memory and returns it as read-only table.
+// The rows can be read with the same API as regular tables. The config is copied, Filename, ReadOnly and IO are set for the archive.
+// Archives whose table and memo data exceed Config.MaxArchiveSize fail with ErrMemoryLimit before the data is decompressed.
+func OpenArchive(path string, config *Config) (*File, error) {
+	return dbase.OpenArchive(path, config)
+}
+
+// Restore regenerates the original DBF file (and memo file) from an archive.
+// The memo file is written next to the DBF file using the FPT (or DCT for databases) extension.
+// Existing files are not overwritten. The files are written to temporary files next to the targets, read back
+// and verified against the checksums of the original files stored in the archive. Only if all files match they are
+// renamed to the targets, the DBF file last, so a failed restore leaves no partial files behind.
+// Archives whose table and memo data exceed DefaultMaxArchiveSize fail with ErrMemoryLimit.
+func Restore(archivePath string, outDBF string) error {
+	return dbase.Restore(archivePath, outDBF)
+}
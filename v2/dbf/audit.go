@@ -0,0 +1,13 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// LengthAudit tracks the maximum length of the values written to C, N, F, V and Q columns,
+// so columns that are about to overflow can be found before values are cut off.
+// Values longer than the column are counted as overflows, as they are truncated when written.
+type LengthAudit = dbase.LengthAudit
+
+// ColumnLength contains the written lengths of a column
+type ColumnLength = dbase.ColumnLength
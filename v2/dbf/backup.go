@@ -0,0 +1,23 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// BackupManifestName is the name of the manifest file, Backup writes it after all other files of the backup
+const BackupManifestName = dbase.BackupManifestName
+
+// BackupManifest describes a backup written by Backup
+type BackupManifest = dbase.BackupManifest
+
+// BackupFile is a file of a backup
+type BackupFile = dbase.BackupFile
+
+// RestoreBackup restores the backup in the directory to the target table file.
+// The memo and index file are written next to the target with the extensions of the backup, existing files are not overwritten.
+// The files are copied to temporary files next to the targets and checked against the checksums of the manifest,
+// they are only renamed to the targets if all files match. The table file is renamed last,
+// so an interrupted restore does not leave a table without its memo file.
+func RestoreBackup(dir string, target string) error {
+	return dbase.RestoreBackup(dir, target)
+}
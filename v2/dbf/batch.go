@@ -0,0 +1,14 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// BatchConfig configures the projection and filtering of StreamBatches
+type BatchConfig = dbase.BatchConfig
+
+// BatchCondition matches the value of a column in its textual representation used by ExportCSV
+type BatchCondition = dbase.BatchCondition
+
+// RowBatch is a batch of projected rows
+type RowBatch = dbase.RowBatch
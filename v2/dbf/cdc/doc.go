@@ -0,0 +1,7 @@
+// Package cdc publishes row changes of a dBase table as JSON encoded change events.
+//
+// The implementation lives in github.com/Valentin-Kaiser/go-dbase/dbase/cdc,
+// this package re-exports it for the version 2 module.
+package cdc
+
+//go:generate go run ../../../internal/shimgen ../../../dbase/cdc github.com/Valentin-Kaiser/go-dbase/dbase/cdc cdc
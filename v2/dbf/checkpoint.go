@@ -0,0 +1,16 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// Checkpoint records the progress of a long running export, so it can be continued after a failure
+type Checkpoint = dbase.Checkpoint
+
+// CheckpointConfig configures a resumable export
+type CheckpointConfig = dbase.CheckpointConfig
+
+// LoadCheckpoint reads the checkpoint file at path. Returns nil if no checkpoint exists.
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	return dbase.LoadCheckpoint(path)
+}
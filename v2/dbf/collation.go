@@ -0,0 +1,16 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// CaseMapper converts the case of decoded text like FoxPro does for the code page of a table.
+// Letters are only mapped if the result is a character of the code page, e.g. 'ÿ' is not converted to 'Ÿ' in code page 437,
+// so normalized search values and index keys match the values produced by the legacy application.
+// Runes outside of the code page are left unchanged. For Unicode converters the simple Unicode case mapping is used.
+type CaseMapper = dbase.CaseMapper
+
+// NewCaseMapper returns a case mapper for the characters of the converter's code page
+func NewCaseMapper(converter EncodingConverter) *CaseMapper {
+	return dbase.NewCaseMapper(converter)
+}
@@ -0,0 +1,16 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// Equal compares two field values using the comparison semantics of dBase for the column type:
+//   - C, V and M text values are equal if they only differ in trailing spaces or null bytes
+//   - D values are compared by date, T values by millisecond, nil and empty dates are equal
+//   - N, F, B and Y values are equal if they are equal when rounded to the decimals of the column
+//   - nil is equal to the empty value of the column type
+//
+// If column is nil the comparison is derived from the types of the values.
+func Equal(a, b interface{}, column *Column) bool {
+	return dbase.Equal(a, b, column)
+}
@@ -0,0 +1,15 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// ReferenceReader reads the rows of a table through a reference implementation, e.g. the Visual FoxPro ODBC driver
+// used by the tests of this package on Windows. Deleted rows must be left out, like SET DELETED ON in FoxPro.
+type ReferenceReader = dbase.ReferenceReader
+
+// ConformanceReport is the result of CheckConformance
+type ConformanceReport = dbase.ConformanceReport
+
+// Divergence is a value decoded differently than by the reference
+type Divergence = dbase.Divergence
@@ -0,0 +1,9 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// CompanionReport contains the result of the consistency check of the memo and index file against the table,
+// e.g. to find a table that was restored without its memo file
+type CompanionReport = dbase.CompanionReport
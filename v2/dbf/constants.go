@@ -0,0 +1,220 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// Supported and testet file versions - other files may work but are not tested
+// The file version check has to be bypassed when opening a file type that is not supported
+// https://learn.microsoft.com/en-us/previous-versions/visualstudio/foxpro/st4a0s68(v=vs.71)
+type FileVersion = dbase.FileVersion
+
+// Supported and testet file types - other file types may work but are not tested
+const (
+	FoxPro              = dbase.FoxPro
+	FoxProAutoincrement = dbase.FoxProAutoincrement
+	FoxProVar           = dbase.FoxProVar
+)
+
+// Not tested
+const (
+	FoxBase         = dbase.FoxBase
+	FoxBase2        = dbase.FoxBase2
+	FoxBasePlus     = dbase.FoxBasePlus
+	DBaseSQLTable   = dbase.DBaseSQLTable
+	FoxBasePlusMemo = dbase.FoxBasePlusMemo
+	DBaseMemo       = dbase.DBaseMemo
+	DBaseSQLMemo    = dbase.DBaseSQLMemo
+	FoxPro2Memo     = dbase.FoxPro2Memo
+	DBase7          = dbase.DBase7
+	DBase5          = dbase.DBase5
+	DBaseSQLSystem  = dbase.DBaseSQLSystem
+	DBase7Memo      = dbase.DBase7Memo
+	HiPerSixMemo    = dbase.HiPerSixMemo
+)
+
+// Product names of the file versions
+const (
+	VisualFoxPro = dbase.VisualFoxPro // Visual FoxPro table
+	FoxPro2      = dbase.FoxPro2      // FoxPro 2.x table with memo, FoxPro 2.x tables without memo use FoxBasePlus
+	DBase3       = dbase.DBase3       // dBase III table without memo
+	DBase3Memo   = dbase.DBase3Memo
+	DBase4Memo   = dbase.DBase4Memo
+)
+
+// Table file extenstions
+type FileExtension = dbase.FileExtension
+
+const (
+	DBC = dbase.DBC // Database file extension
+	DCT = dbase.DCT // Database container file extension
+	DBF = dbase.DBF // Table file extension
+	FPT = dbase.FPT // Memo file extension
+	CDX = dbase.CDX // Structural compound index file extension
+	DCX = dbase.DCX // Database container index file extension
+	SCX = dbase.SCX // Form file extension
+	LBX = dbase.LBX // Label file extension
+	MNX = dbase.MNX // Menu file extension
+	PJX = dbase.PJX // Project file extension
+	RPX = dbase.RPX // Report file extension
+	VCX = dbase.VCX // Visual class library file extension
+)
+
+// Important byte marker for the dbase file
+type Marker = dbase.Marker
+
+const (
+	Null      = dbase.Null
+	Blank     = dbase.Blank
+	ColumnEnd = dbase.ColumnEnd
+	Active    = dbase.Active
+	Deleted   = dbase.Deleted
+	EOFMarker = dbase.EOFMarker
+)
+
+// LineEnding defines the line ending of memo text stored in the memo file
+type LineEnding = dbase.LineEnding
+
+const (
+	LineEndingKeep = dbase.LineEndingKeep // Line endings are not converted
+	LineEndingLF   = dbase.LineEndingLF   // CRLF and CR are converted to LF on read, text is written with LF
+	LineEndingCRLF = dbase.LineEndingCRLF // CRLF and CR are converted to LF on read, LF is converted to CRLF on write
+)
+
+// TrimMode defines which side of string values is trimmed in ToMap, ToJSON, ToStruct and the exports
+type TrimMode = dbase.TrimMode
+
+const (
+	TrimDefault = dbase.TrimDefault // Inherit the mode, TrimSpaces is used if no mode is set
+	TrimNone    = dbase.TrimNone    // Values are not trimmed, e.g. to preserve space padded codes
+	TrimLeft    = dbase.TrimLeft    // Leading characters are trimmed
+	TrimRight   = dbase.TrimRight   // Trailing characters are trimmed
+	TrimBoth    = dbase.TrimBoth    // Leading and trailing characters are trimmed
+)
+
+// NilPolicy defines which empty fields are decoded to nil instead of the zero value of the column type.
+// The policies can be combined, e.g. NilEmptyText | NilEmptyDate.
+type NilPolicy = dbase.NilPolicy
+
+const (
+	NilNone        = dbase.NilNone        // Empty fields are decoded to "", 0 and the zero time
+	NilEmptyText   = dbase.NilEmptyText   // Blank C fields and empty V and M fields are decoded to nil
+	NilEmptyNumber = dbase.NilEmptyNumber // Blank N and F fields are decoded to nil
+	NilEmptyDate   = dbase.NilEmptyDate   // Blank D and zero T fields are decoded to nil
+	NilEmpty       = dbase.NilEmpty       // All empty fields are decoded to nil
+)
+
+// CastType is the Go type the values of a column are cast to on read, see Config.Schema
+type CastType = dbase.CastType
+
+const (
+	CastNone   = dbase.CastNone   // Values keep the type of the column
+	CastString = dbase.CastString // Values are cast to string
+	CastInt    = dbase.CastInt    // Values are cast to int64
+	CastFloat  = dbase.CastFloat  // Values are cast to float64
+	CastBool   = dbase.CastBool   // Values are cast to bool
+	CastTime   = dbase.CastTime   // Values are cast to time.Time
+	CastBytes  = dbase.CastBytes  // Values are cast to []byte
+)
+
+// CastPolicy defines how casts that lose information are handled, e.g. the fraction of a number cast to int64
+type CastPolicy = dbase.CastPolicy
+
+const (
+	CastStrict   = dbase.CastStrict   // Casts that lose information fail
+	CastTruncate = dbase.CastTruncate // Fractions are truncated toward zero
+	CastRound    = dbase.CastRound    // Fractions are rounded half away from zero
+)
+
+// KeyCase defines the casing of column names used as JSON keys
+type KeyCase = dbase.KeyCase
+
+const (
+	KeyCaseKeep  = dbase.KeyCaseKeep  // Column names are used as stored, usually upper case
+	KeyCaseLower = dbase.KeyCaseLower // Column names are converted to lower case
+	KeyCaseUpper = dbase.KeyCaseUpper // Column names are converted to upper case
+	KeyCaseCamel = dbase.KeyCaseCamel // Column names are converted to camel case, underscores separate the words
+)
+
+// DuplicatePolicy defines the keys of columns whose name is already used by a previous column in ToMap, ToJSON and the exports
+type DuplicatePolicy = dbase.DuplicatePolicy
+
+const (
+	DuplicateOverwrite = dbase.DuplicateOverwrite // In ToMap the value of the last column wins, ordered results and exports use the keys of DuplicateSuffix
+	DuplicateError     = dbase.DuplicateError     // Converting rows of tables with duplicate column names fails with ErrDuplicateColumn
+	DuplicateSuffix    = dbase.DuplicateSuffix    // Duplicates get the number of the occurrence as suffix, e.g. NAME_2 and NAME_3
+	DuplicateKeepFirst = dbase.DuplicateKeepFirst // Duplicates are left out, the value of the first column is kept
+)
+
+// Warehouse defines the data warehouse of the load-file exporters and WarehouseDDL
+type Warehouse = dbase.Warehouse
+
+const (
+	WarehouseBigQuery  = dbase.WarehouseBigQuery  // Google BigQuery, exported as "bigquery-csv" and "bigquery-jsonl"
+	WarehouseSnowflake = dbase.WarehouseSnowflake // Snowflake, exported as "snowflake-csv" and "snowflake-jsonl"
+)
+
+// SupportLevel defines how far a feature is supported for the files of a product, see SupportMatrix
+type SupportLevel = dbase.SupportLevel
+
+const (
+	SupportNone     = dbase.SupportNone     // The feature is not implemented for the format
+	SupportUntested = dbase.SupportUntested // The format is implemented but not verified with files of the product
+	SupportTested   = dbase.SupportTested   // Verified with files created by the product
+)
+
+// Limits of the 16 bit length fields of the header
+const (
+	MaxRowLength    = dbase.MaxRowLength    // Maximum length of a row in bytes, including the deletion flag
+	MaxHeaderLength = dbase.MaxHeaderLength // Maximum length of the header in bytes, the position of the first row
+)
+
+// RedactedValue replaces the values of redacted columns in the output, see File.RedactColumns
+const RedactedValue = dbase.RedactedValue
+
+// EpochSeconds can be added to Config.DateLayouts to accept numbers and numeric strings as seconds since the Unix epoch for D and T columns
+const EpochSeconds = dbase.EpochSeconds
+
+// DefaultDateLayouts are the layouts accepted for string values of D and T columns if Config.DateLayouts is empty
+var DefaultDateLayouts = dbase.DefaultDateLayouts
+
+// Table flags inidicate the type of the table
+// https://learn.microsoft.com/en-us/previous-versions/visualstudio/foxpro/st4a0s68(v=vs.71)
+type TableFlag = dbase.TableFlag
+
+const (
+	StructuralFlag = dbase.StructuralFlag
+	MemoFlag       = dbase.MemoFlag
+	DatabaseFlag   = dbase.DatabaseFlag
+)
+
+// Column flags indicate wether a column is hidden, can be null, is binary or is autoincremented
+type ColumnFlag = dbase.ColumnFlag
+
+const (
+	HiddenFlag        = dbase.HiddenFlag
+	NullableFlag      = dbase.NullableFlag
+	BinaryFlag        = dbase.BinaryFlag
+	AutoincrementFlag = dbase.AutoincrementFlag
+)
+
+// DataType defines the possible types of a column
+type DataType = dbase.DataType
+
+const (
+	Character = dbase.Character // C - Character (string)
+	Currency  = dbase.Currency  // Y - Currency (float64)
+	Double    = dbase.Double    // B - Double (float64)
+	Date      = dbase.Date      // D - Date (time.Time)
+	DateTime  = dbase.DateTime  // T - DateTime (time.Time)
+	Float     = dbase.Float     // F - Float (float64)
+	Integer   = dbase.Integer   // I - Integer (int32)
+	Logical   = dbase.Logical   // L - Logical (bool)
+	Memo      = dbase.Memo      // M - Memo (string)
+	Numeric   = dbase.Numeric   // N - Numeric (int64)
+	Blob      = dbase.Blob      // W - Blob ([]byte)
+	General   = dbase.General   // G - General (string)
+	Picture   = dbase.Picture   // P - Picture (string)
+	Varbinary = dbase.Varbinary // Q - Varbinary ([]byte)
+	Varchar   = dbase.Varchar   // V - Varchar (string)
+)
@@ -0,0 +1,12 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// UniqueKey is a key expression whose keys must be unique in the table.
+// Rows written through the handle are rejected with a ConstraintError if another row has the same key.
+type UniqueKey = dbase.UniqueKey
+
+// ConstraintError is returned when a written row has the same key as another row of the table
+type ConstraintError = dbase.ConstraintError
@@ -0,0 +1,13 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+type Database = dbase.Database
+
+// Open a database and all related tables
+// Only works with default IO implementation
+func OpenDatabase(config *Config) (*Database, error) {
+	return dbase.OpenDatabase(config)
+}
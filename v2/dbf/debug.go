@@ -0,0 +1,15 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import (
+	"io"
+
+	"github.com/Valentin-Kaiser/go-dbase/dbase"
+)
+
+// Debug the dbase package
+// If debug is true, debug messages will be printed to the defined io.Writter (default: os.Stdout)
+func Debug(enabled bool, out io.Writer) {
+	dbase.Debug(enabled, out)
+}
@@ -0,0 +1,55 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// Family is the product family that created a table file
+type Family = dbase.Family
+
+const (
+	FamilyUnknown       = dbase.FamilyUnknown
+	FamilyDBase3        = dbase.FamilyDBase3
+	FamilyDBase4        = dbase.FamilyDBase4
+	FamilyDBase5        = dbase.FamilyDBase5
+	FamilyDBase7        = dbase.FamilyDBase7
+	FamilyFoxBase       = dbase.FamilyFoxBase
+	FamilyFoxPro2       = dbase.FamilyFoxPro2
+	FamilyVisualFoxPro  = dbase.FamilyVisualFoxPro
+	FamilyClipper       = dbase.FamilyClipper
+	FamilyHiPerSix      = dbase.FamilyHiPerSix
+	FamilyDBaseSQLTable = dbase.FamilyDBaseSQLTable
+)
+
+// MemoType is the format of the memo file belonging to a table
+type MemoType = dbase.MemoType
+
+const (
+	MemoNone = dbase.MemoNone
+	MemoDBT  = dbase.MemoDBT // dBase and Clipper memo file
+	MemoFPT  = dbase.MemoFPT // FoxPro memo file
+	MemoSMT  = dbase.MemoSMT // HiPer-Six memo file
+)
+
+// IndexType is the format of an index file belonging to a table
+type IndexType = dbase.IndexType
+
+const (
+	IndexCDX = dbase.IndexCDX // FoxPro compound index
+	IndexIDX = dbase.IndexIDX // FoxPro single index
+	IndexMDX = dbase.IndexMDX // dBase IV multiple index
+	IndexNDX = dbase.IndexNDX // dBase III index
+	IndexNTX = dbase.IndexNTX // Clipper index
+)
+
+// Capabilities lists which features of this package are available for a detected table
+type Capabilities = dbase.Capabilities
+
+// Detection is the result of Detect
+type Detection = dbase.Detection
+
+// Detect reads the header of the table at path and looks for related memo and index files.
+// It returns the product family, memo and index formats and which features of this package are supported for the table.
+func Detect(path string) (*Detection, error) {
+	return dbase.Detect(path)
+}
@@ -0,0 +1,14 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// DefaultDictionaryLimit is the default maximum number of distinct values counted exactly by ColumnDictionary
+const DefaultDictionaryLimit = dbase.DefaultDictionaryLimit
+
+// ColumnDictionary contains the distinct values of a column and how often they occur, e.g. to build lookup tables
+type ColumnDictionary = dbase.ColumnDictionary
+
+// DictionaryEntry is a distinct value of a column and its count
+type DictionaryEntry = dbase.DictionaryEntry
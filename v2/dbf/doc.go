@@ -0,0 +1,8 @@
+// Package dbf re-exports the dbase package of the version 1 module for the version 2 API.
+//
+// The implementation lives in github.com/Valentin-Kaiser/go-dbase/dbase, the types of this package are aliases of its types,
+// so values can be passed between both packages and to the version 2 API in github.com/Valentin-Kaiser/go-dbase/v2/dbase.
+// Package variables like DefaultDateLayouts are copies, assign the variables of the dbase package to change the defaults.
+package dbf
+
+//go:generate go run ../../internal/shimgen ../../dbase github.com/Valentin-Kaiser/go-dbase/dbase dbf
@@ -0,0 +1,14 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// SchemaDrift describes how the columns of a table differ from the reference schema of the config.
+// If Config.Reference is set, the output of rows (ToMap, ToJSON, ToStruct, the exports and views) contains the reference columns in reference order:
+// columns are mapped by name, missing columns are filled with defaults and extra columns are ignored.
+// Values of changed columns are cast to the Go type of the reference column, unless Config.Schema declares a type.
+type SchemaDrift = dbase.SchemaDrift
+
+// ColumnChange is a column of the table that differs from the reference column of the same name
+type ColumnChange = dbase.ColumnChange
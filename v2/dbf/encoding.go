@@ -0,0 +1,42 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import (
+	"golang.org/x/text/encoding/charmap"
+
+	"github.com/Valentin-Kaiser/go-dbase/dbase"
+)
+
+// EncodingConverter is the interface as passed to Open
+type EncodingConverter = dbase.EncodingConverter
+
+type DefaultConverter = dbase.DefaultConverter
+
+func NewDefaultConverter(encoding *charmap.Charmap) DefaultConverter {
+	return dbase.NewDefaultConverter(encoding)
+}
+
+// NewDefaultConverterFromCodePage returns a new EncodingConverter from a code page mark
+func ConverterFromCodePage(codePageMark byte) DefaultConverter {
+	return dbase.ConverterFromCodePage(codePageMark)
+}
+
+// CodePageName returns the name of the code page of a code page mark, e.g. "Windows ANSI"
+func CodePageName(codePageMark byte) string {
+	return dbase.CodePageName(codePageMark)
+}
+
+// UnicodeConverter converts UTF-8 or UTF-16 text, which some applications store regardless of the table code page
+type UnicodeConverter = dbase.UnicodeConverter
+
+// NewUTF8Converter returns a converter for UTF-8 text, a leading byte order mark is removed on decode
+func NewUTF8Converter() UnicodeConverter {
+	return dbase.NewUTF8Converter()
+}
+
+// NewUTF16Converter returns a converter for UTF-16 text.
+// A byte order mark overrides the endianness on decode and is written on encode.
+func NewUTF16Converter(bigEndian bool) UnicodeConverter {
+	return dbase.NewUTF16Converter(bigEndian)
+}
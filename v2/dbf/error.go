@@ -0,0 +1,60 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+var (
+	// Returned when the end of a dBase database file is reached
+	ErrEOF = dbase.ErrEOF
+	// Returned when the row pointer is attempted to be moved before the first row
+	ErrBOF = dbase.ErrBOF
+	// Returned when the read of a row or column did not finish
+	ErrIncomplete = dbase.ErrIncomplete
+	// Returned when a file operation is attempted on a non existent file
+	ErrNoFPT = dbase.ErrNoFPT
+	ErrNoDBF = dbase.ErrNoDBF
+	// Returned on open if the table has memo columns but its memo file is missing, see Config.SkipMissingMemo
+	ErrMissingMemoFile = dbase.ErrMissingMemoFile
+	// Returned when an invalid column position is used (x<1 or x>number of columns)
+	ErrInvalidPosition = dbase.ErrInvalidPosition
+	ErrInvalidEncoding = dbase.ErrInvalidEncoding
+	// Returned in strict mode when a map or JSON row contains unknown keys or lacks required columns
+	ErrSchemaMismatch = dbase.ErrSchemaMismatch
+	// Returned by Ping if the table or memo file can not be read anymore
+	ErrFileUnavailable = dbase.ErrFileUnavailable
+	// Returned by Ping if the file at the table path was removed or replaced by another file
+	ErrFileReplaced = dbase.ErrFileReplaced
+	// Returned by Ping if the header does not match the structure read when the table was opened
+	ErrHeaderChanged = dbase.ErrHeaderChanged
+	// Returned when a system column like _NullFlags is written through the field API, system columns are maintained by the table
+	ErrSystemColumn = dbase.ErrSystemColumn
+	// Returned when the header can not describe the columns, e.g. the columns do not fit into the row length of a corrupt or crafted file
+	ErrInvalidHeader = dbase.ErrInvalidHeader
+	// Returned when a memo address or the length of a memo block points outside of the memo file
+	ErrInvalidMemo = dbase.ErrInvalidMemo
+	// Returned when an index file can not be read because its nodes or tag headers are corrupt
+	ErrInvalidIndex = dbase.ErrInvalidIndex
+	// Returned by CreateIndex and Pack if the table has a structural index, which is maintained by FoxPro and not by this package
+	ErrStructuralIndex = dbase.ErrStructuralIndex
+	// Returned by CreateIndex if rows of a candidate tag have the same key and wrapped by ConstraintError if a written row violates a unique key
+	ErrDuplicateKey = dbase.ErrDuplicateKey
+	// Returned if a row that can not be decoded returns different data when it is read again, so the read and not the file is corrupt
+	ErrIntegrity = dbase.ErrIntegrity
+	// Returned by Rows and RowsWithErrors if the collected rows exceed Config.MemoryLimit and by OpenArchive and Restore if the archived data exceeds Config.MaxArchiveSize
+	ErrMemoryLimit = dbase.ErrMemoryLimit
+	// Wrapped by LockError if a row, the header or the memo file stays locked by another process, see Config.LockRetry
+	ErrLocked = dbase.ErrLocked
+	// Returned when rows of a table with duplicate column names are converted with DuplicateError, see Config.DuplicateNames
+	ErrDuplicateColumn = dbase.ErrDuplicateColumn
+	// Returned if the IO implementation of the table does not support an operation, see RawIO and OpenAll
+	ErrUnsupported = dbase.ErrUnsupported
+)
+
+// Error is a wrapper for errors that occur in the dbase package
+type Error = dbase.Error
+
+// GetErrorTrace returns the context and the error produced by the dbase package as a string
+func GetErrorTrace(err error) error {
+	return dbase.GetErrorTrace(err)
+}
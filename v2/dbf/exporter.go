@@ -0,0 +1,36 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import (
+	"io"
+
+	"github.com/Valentin-Kaiser/go-dbase/dbase"
+)
+
+// Exporter writes the rows of a table in an output format.
+// Additional formats (e.g. Avro, ORC or protobuf) can be added by implementing Exporter and registering it with RegisterExporter.
+type Exporter = dbase.Exporter
+
+// ExportSchema describes the exported columns
+type ExportSchema = dbase.ExportSchema
+
+// ExporterFactory returns a new exporter writing to w
+type ExporterFactory = dbase.ExporterFactory
+
+// RegisterExporter registers the exporter for the format name, e.g. "avro". Names are case-insensitive.
+// The built-in formats are "avro", "csv", "jsonl", "postgres", "postgres-binary", "protobuf", "toml" and "yaml",
+// and the load files "bigquery-csv", "bigquery-jsonl", "snowflake-csv" and "snowflake-jsonl".
+func RegisterExporter(name string, factory ExporterFactory) error {
+	return dbase.RegisterExporter(name, factory)
+}
+
+// Exporters returns the names of all registered formats in alphabetical order
+func Exporters() []string {
+	return dbase.Exporters()
+}
+
+// NewExporter returns a new exporter of the registered format writing to w
+func NewExporter(name string, w io.Writer) (Exporter, error) {
+	return dbase.NewExporter(name, w)
+}
@@ -0,0 +1,12 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// ReadHeader reads only the 32 byte header of the table file at path.
+// The columns and the memo file are not read, so this is the cheapest way to get the rows count,
+// the last modification date or the file type of many files.
+func ReadHeader(path string) (*Header, error) {
+	return dbase.ReadHeader(path)
+}
@@ -0,0 +1,47 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import (
+	"io"
+
+	"github.com/Valentin-Kaiser/go-dbase/dbase"
+)
+
+// Importer reads the records of a source format to append them to a table with an Appender.
+// Additional sources (e.g. Excel or SQL) can be added by implementing Importer and registering it with RegisterImporter.
+type Importer = dbase.Importer
+
+// ImportRecord is a record read by an importer
+type ImportRecord = dbase.ImportRecord
+
+// ImporterFactory returns a new importer reading from r
+type ImporterFactory = dbase.ImporterFactory
+
+// RegisterImporter registers the importer for the format name, e.g. "xlsx". Names are case-insensitive.
+// The built-in formats are "csv" and "jsonl", they read the output of the exporters of the same name.
+func RegisterImporter(name string, factory ImporterFactory) error {
+	return dbase.RegisterImporter(name, factory)
+}
+
+// Importers returns the names of all registered formats in alphabetical order
+func Importers() []string {
+	return dbase.Importers()
+}
+
+// NewImporter returns a new importer of the registered format reading from r
+func NewImporter(name string, r io.Reader) (Importer, error) {
+	return dbase.NewImporter(name, r)
+}
+
+// ImportProgress contains the number of processed records of an import
+type ImportProgress = dbase.ImportProgress
+
+// Appender appends records to a table. The values are converted to the column types and mapped like RowFromMap,
+// so defaults and StrictMapping apply. Rejected records are written to the quarantine file of the table, if set.
+type Appender = dbase.Appender
+
+// NewAppender returns an appender for the table
+func NewAppender(file *File) *Appender {
+	return dbase.NewAppender(file)
+}
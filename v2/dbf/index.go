@@ -0,0 +1,13 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// IndexInfo describes the compound index (CDX) of a table.
+// Only the tag metadata is read, the index is not used or maintained by this package.
+// Tags of index snapshots, which are not structural, can be written with CreateIndex.
+type IndexInfo = dbase.IndexInfo
+
+// IndexTag describes one tag of a compound index
+type IndexTag = dbase.IndexTag
@@ -0,0 +1,11 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// MaxIndexKeyLength is the maximum length of the keys of a compact index tag
+const MaxIndexKeyLength = dbase.MaxIndexKeyLength
+
+// IndexOptions configures a tag created by CreateIndex
+type IndexOptions = dbase.IndexOptions
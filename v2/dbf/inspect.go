@@ -0,0 +1,17 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// InspectionReport contains the structure, companion files and validation results of a table, see InspectJSON
+type InspectionReport = dbase.InspectionReport
+
+// ColumnInspection describes a column of an inspected table
+type ColumnInspection = dbase.ColumnInspection
+
+// MemoInspection describes the memo file of an inspected table
+type MemoInspection = dbase.MemoInspection
+
+// CompanionFile is a file next to the table with the same base name
+type CompanionFile = dbase.CompanionFile
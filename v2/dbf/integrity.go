@@ -0,0 +1,11 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// IntegrityReport contains the result of the referential integrity check of a relation set
+type IntegrityReport = dbase.IntegrityReport
+
+// RelationIntegrity contains the result of the referential integrity check of one relation
+type RelationIntegrity = dbase.RelationIntegrity
@@ -0,0 +1,46 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// File is the main struct to handle a dBase file.
+// Each file type is basically a Table or a Memo file.
+type File = dbase.File
+
+// IO is the interface to work with the DBF file.
+// Three implementations are available:
+// - WindowsIO (for direct file access with Windows)
+// - UnixIO (for direct file access with Unix)
+// - GenericIO (for any custom file access implementing io.ReadWriteSeeker)
+// The IO interface can be implemented for any custom file access.
+type IO = dbase.IO
+
+// FieldWriter is implemented by IO implementations that write a single field of a row in place, like the default implementations.
+// UpdateField uses ReadRow and WriteRow of other implementations, which writes the memos of the row again.
+type FieldWriter = dbase.FieldWriter
+
+// RawIO is implemented by IO implementations with access to the bytes of the table and memo file, like the default implementations.
+// Without it ReadRaw reads only ranges of the rows with ReadRow, and Size and WriteRaw fail with ErrUnsupported.
+// Checks, repairs, backups and snapshots of the files require RawIO.
+type RawIO = dbase.RawIO
+
+// MemoReader is implemented by IO implementations that read memos without decoding text, like the default implementations.
+// Column encodings and DetectMemoBOM apply to memos of other implementations only if ReadMemo returns the text undecoded.
+type MemoReader = dbase.MemoReader
+
+// Truncater is implemented by IO implementations that can change the size of the table and memo file, like the default implementations.
+// Packing and repairing tables fail with ErrUnsupported without it.
+type Truncater = dbase.Truncater
+
+// Opens a dBase database file (and the memo file if needed).
+// The config parameter is required to specify the file path, encoding, file handles (IO) and others.
+// If IO is nil, the default implementation is used depending on the OS.
+func OpenTable(config *Config) (*File, error) {
+	return dbase.OpenTable(config)
+}
+
+// Check if the file version is tested
+func ValidateFileVersion(version byte, untested bool) error {
+	return dbase.ValidateFileVersion(version, untested)
+}
@@ -0,0 +1,9 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// GenericIO implements the IO interface for generic io.ReadWriteSeeker.
+// Handle is the main file handle, relatedHandle is the memo file handle.
+type GenericIO = dbase.GenericIO
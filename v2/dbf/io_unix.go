@@ -0,0 +1,13 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+//go:build !windows
+// +build !windows
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+var DefaultIO = dbase.DefaultIO
+
+// UnixIO implements the IO interface for unix systems.
+type UnixIO = dbase.UnixIO
@@ -0,0 +1,12 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// LockRetry configures how locks held by other processes are retried with Config.SharedWrite, like SET REPROCESS in FoxPro.
+// The wait between two attempts starts at Delay and doubles up to MaxDelay. The zero value retries for up to 5 seconds.
+type LockRetry = dbase.LockRetry
+
+// LockError is returned if a lock held by another process was not released within the retries of Config.LockRetry
+type LockError = dbase.LockError
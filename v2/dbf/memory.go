@@ -0,0 +1,8 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// MemoryEstimate is the predicted memory of rows read into memory, e.g. with Rows
+type MemoryEstimate = dbase.MemoryEstimate
@@ -0,0 +1,11 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// PackProgress is reported by Pack after every chunk
+type PackProgress = dbase.PackProgress
+
+// PackConfig configures Pack
+type PackConfig = dbase.PackConfig
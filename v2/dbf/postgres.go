@@ -0,0 +1,25 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import (
+	"io"
+
+	"github.com/Valentin-Kaiser/go-dbase/dbase"
+)
+
+// PostgresCopyOptions configures ToPostgresCopy
+type PostgresCopyOptions = dbase.PostgresCopyOptions
+
+// ToPostgresCopy streams the rows in the format of the PostgreSQL COPY command, so they can be piped into
+// COPY table FROM STDIN (psql \copy) or the CopyFrom of pgconn. The columns are written in the order of the export,
+// hidden columns are left out. Empty dates and nil values (see NilPolicy) are NULL.
+//
+// The text format escapes backslashes, tabs and line breaks, dates are written as 2006-01-02, date times as
+// 2006-01-02 15:04:05.000 in UTC and binary values as bytea in hex format. With Binary set the values are
+// encoded for these column types, which the target table must use:
+// I integer, N without decimals bigint, N with decimals and Y numeric, F and B double precision, L boolean,
+// D date, T timestamp, C, V and M text and Q, W, G and P bytea.
+func ToPostgresCopy(file *File, w io.Writer, opts *PostgresCopyOptions) error {
+	return dbase.ToPostgresCopy(file, w, opts)
+}
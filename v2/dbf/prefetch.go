@@ -0,0 +1,10 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// Prefetcher reads rows ahead in a background goroutine, hiding disk latency for random access patterns
+// like reading rows in index order. Row positions are requested in advance and returned by Next in the same order.
+// While the prefetcher is running the file must not be used by other goroutines, as the file handles are shared.
+type Prefetcher = dbase.Prefetcher
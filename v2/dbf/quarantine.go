@@ -0,0 +1,22 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// RejectedRow is a row written to a quarantine file
+type RejectedRow = dbase.RejectedRow
+
+// Quarantine appends rejected rows to a quarantine file (usually with the extension .rej), so they can be inspected and repaired later.
+// Every rejected row is written as one JSON object per line.
+type Quarantine = dbase.Quarantine
+
+// OpenQuarantine opens or creates the quarantine file at path, rejected rows are appended to existing ones
+func OpenQuarantine(path string) (*Quarantine, error) {
+	return dbase.OpenQuarantine(path)
+}
+
+// ReadQuarantine reads all rejected rows of the quarantine file at path
+func ReadQuarantine(path string) ([]*RejectedRow, error) {
+	return dbase.ReadQuarantine(path)
+}
@@ -0,0 +1,18 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// Relation links the rows of a parent table to the rows of a child table by key columns, like SET RELATION in FoxPro
+type Relation = dbase.Relation
+
+// RelationSet contains related tables and the relations between them.
+// The child rows of a relation are found with an index of the child key that is built in memory on first use.
+// Index files of the tables are not used, as they are not read by this package.
+type RelationSet = dbase.RelationSet
+
+// NewRelationSet returns an empty relation set
+func NewRelationSet() *RelationSet {
+	return dbase.NewRelationSet()
+}
@@ -0,0 +1,23 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// ReportTemplate is a template executed by Report, *text/template.Template and *html/template.Template implement it
+type ReportTemplate = dbase.ReportTemplate
+
+// ReportData is the data the template of Report is executed with
+type ReportData = dbase.ReportData
+
+// ReportRow is a row of a report with the totals of the numeric values up to this row
+type ReportRow = dbase.ReportRow
+
+// ReportFuncs returns the helper functions for report templates, add them with Funcs before parsing the template:
+//   - date formats a time with the layout, the zero time as empty text: {{date .Get "HIRED" "02.01.2006"}}
+//   - number formats a number with the decimals and a thousands separator: {{number (.Get "AMOUNT") 2}}
+//   - padLeft and padRight pad a value with spaces to the width, for fixed width reports: {{padLeft 10 (.Get "ID")}}
+//   - trim, upper and lower change text values
+func ReportFuncs() map[string]interface{} {
+	return dbase.ReportFuncs()
+}
@@ -0,0 +1,13 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// ColumnIndex resolves the positions of columns by name once per table, instead of for each row
+type ColumnIndex = dbase.ColumnIndex
+
+// NewColumnIndex returns the index of the columns with the names
+func NewColumnIndex(names ...string) *ColumnIndex {
+	return dbase.NewColumnIndex(names...)
+}
@@ -0,0 +1,19 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// SchemaCache is a least recently used cache of parsed column descriptors keyed by a fingerprint of the descriptor bytes.
+// Set it as Config.SchemaCache to share it between tables, so repeated opens of tables with identical structure
+// read the descriptors in one block and reuse the parsed columns instead of decoding every descriptor again.
+// Every table receives its own copy of the cached columns. The cache is safe for concurrent use.
+type SchemaCache = dbase.SchemaCache
+
+// SchemaCacheStats contains the counters of the schema cache
+type SchemaCacheStats = dbase.SchemaCacheStats
+
+// NewSchemaCache returns a schema cache limited to maxSchemas distinct schemas (0 = unlimited)
+func NewSchemaCache(maxSchemas int) *SchemaCache {
+	return dbase.NewSchemaCache(maxSchemas)
+}
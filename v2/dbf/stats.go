@@ -0,0 +1,10 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// Stats contains the counters of a file handle since it was opened or since ResetStats.
+// The counters are updated atomically, so Stats can be called while other goroutines read or write the table,
+// e.g. by a metrics collector polling it.
+type Stats = dbase.Stats
@@ -0,0 +1,17 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// Support describes how far this package supports the files created by a product
+type Support = dbase.Support
+
+// SupportMatrix returns which features are known to work for the files of the dBase products.
+// Tested features are verified by the tests of this package with files created by the product in testdata,
+// written files are verified by reading them back. Untested features are implemented for the file format
+// but have not been verified, and tables of untested file versions require Config.Untested.
+// The returned slice is a copy and can be modified.
+func SupportMatrix() []*Support {
+	return dbase.SupportMatrix()
+}
@@ -0,0 +1,64 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// Configures the file you want to open.
+// The filename is mandatory. The other fields are optional and are false by default.
+// If Converter and InterpretCodePage are both not set the package will try to interpret the code page mark.
+// To open untested files set Untested to true. Tested files are defined in the constants.go file.
+type Config = dbase.Config
+
+// Containing DBF header information like dBase FileType, last change and rows count.
+// https://docs.microsoft.com/en-us/previous-versions/visualstudio/foxpro/st4a0s68(v=vs.80)#table-header-record-structure
+type Header = dbase.Header
+
+// The raw header of the Memo file.
+type MemoHeader = dbase.MemoHeader
+
+// Table is a struct containing the table columns, modifications and the row pointer
+type Table = dbase.Table
+
+// Column is a struct containing the column information
+type Column = dbase.Column
+
+// Row is a struct containing the row Position, deleted flag and data fields
+type Row = dbase.Row
+
+// Field is a row data field
+type Field = dbase.Field
+
+// Modification allows to change the column name or value type
+type Modification = dbase.Modification
+
+// Create a new DBF file with the specified version, configuration and columns.
+// Column names must be unique regardless of case, the config is copied so the caller's config is not changed.
+// CreateTable chooses the version, converter and memo block size for Visual FoxPro tables.
+func New(version FileVersion, config *Config, columns []*Column, memoBlockSize uint16, io IO) (*File, error) {
+	return dbase.New(version, config, columns, memoBlockSize, io)
+}
+
+// CreateTable creates a new Visual FoxPro table at config.Filename with the columns.
+// Without config.Converter the text is encoded as Windows-1252. The file version is chosen by the columns,
+// FoxProVar for tables with varchar, varbinary or nullable columns, FoxProAutoincrement for tables with autoincrement columns
+// and FoxPro otherwise. Memos are written in blocks of 64 bytes, use New for other block sizes and file versions.
+// The file name is upper-cased like by FoxPro, the directories are kept. The table is created by New,
+// so column names must be unique regardless of case and the caller's config is not changed.
+func CreateTable(config *Config, columns ...*Column) (*File, error) {
+	return dbase.CreateTable(config, columns...)
+}
+
+// Create a new table column with the given name, type and length
+func NewColumn(name string, dataType DataType, length uint8, decimals uint8, nullable bool) (*Column, error) {
+	return dbase.NewColumn(name, dataType, length, decimals, nullable)
+}
+
+// RowError is the error of a row that could not be read
+type RowError = dbase.RowError
+
+// RowsResult contains the rows that were read and the errors of the rows that could not be read
+type RowsResult = dbase.RowsResult
+
+// KeyValue is a key and value pair of a row
+type KeyValue = dbase.KeyValue
@@ -0,0 +1,11 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// Throttle limits the throughput of full table scans, so maintenance jobs on a shared file server
+// do not starve other applications working with the same files.
+// It applies to Validate, Analyze, UnusedColumns, the exports, StreamBatches, CheckIntegrity, Pack and the relation index.
+// A zero value of a limit disables it, if both limits are set the stricter one applies.
+type Throttle = dbase.Throttle
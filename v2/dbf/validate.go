@@ -0,0 +1,18 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// EOFState describes the end of file marker (0x1A) found after the last row
+type EOFState = dbase.EOFState
+
+const (
+	EOFValid        = dbase.EOFValid        // Exactly one marker directly after the last row
+	EOFMissing      = dbase.EOFMissing      // No marker and no data after the last row
+	EOFDuplicated   = dbase.EOFDuplicated   // More than one marker after the last row
+	EOFTrailingData = dbase.EOFTrailingData // Data after the last row that is not covered by the rows count
+)
+
+// ValidationReport contains the result of the structural validation of a table file
+type ValidationReport = dbase.ValidationReport
@@ -0,0 +1,25 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import (
+	"time"
+
+	"github.com/Valentin-Kaiser/go-dbase/dbase"
+)
+
+// Version is a dated backup copy of a table
+type Version = dbase.Version
+
+// ListVersions returns the backup copies of a table in dir sorted from oldest to newest.
+// Each backup is expected in its own subdirectory, named after the date of the backup (e.g. 2023-01-31 or 20230131_235900).
+// If the name of a subdirectory is not a date, the modification time of the table file is used.
+func ListVersions(dir string, table string) ([]Version, error) {
+	return dbase.ListVersions(dir, table)
+}
+
+// OpenVersioned opens the newest backup copy of the table in dir that is not newer than asOf.
+// The memo file is opened from the same backup directory. The filename of the config is replaced.
+func OpenVersioned(dir string, table string, asOf time.Time, config *Config) (*File, error) {
+	return dbase.OpenVersioned(dir, table, asOf, config)
+}
@@ -0,0 +1,12 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// RowView is an ordered representation of a row for text/template and html/template.
+// Fields can be ranged over in column order or addressed by name, e.g. {{range .Fields}}{{.Name}}={{.Value}}{{end}} or {{.Get "PRODNAME"}}.
+type RowView = dbase.RowView
+
+// FieldView is a field of a RowView
+type FieldView = dbase.FieldView
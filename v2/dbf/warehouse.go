@@ -0,0 +1,10 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// ParseWarehouse returns the warehouse of the name, e.g. "snowflake". Names are case-insensitive.
+func ParseWarehouse(name string) (Warehouse, error) {
+	return dbase.ParseWarehouse(name)
+}
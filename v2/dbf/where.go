@@ -0,0 +1,8 @@
+// Code generated by shimgen. DO NOT EDIT.
+
+package dbf
+
+import "github.com/Valentin-Kaiser/go-dbase/dbase"
+
+// RowFilter selects the rows of CountWhere and ExistsWhere
+type RowFilter = dbase.RowFilter
@@ -0,0 +1,14 @@
+module github.com/Valentin-Kaiser/go-dbase/v2
+
+go 1.19
+
+require (
+	github.com/Valentin-Kaiser/go-dbase v0.0.0-00010101000000-000000000000
+	golang.org/x/text v0.11.0
+)
+
+require golang.org/x/sys v0.10.0 // indirect
+
+// The dbf package re-exports the dbase package of the version 1 module in the parent directory.
+// The replace directive keeps both modules on the same revision, also without go.work.
+replace github.com/Valentin-Kaiser/go-dbase => ../